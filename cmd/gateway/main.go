@@ -2,12 +2,17 @@ package main
 
 import (
 	"context"
+	"fmt"
 	"io"
 	"log"
 	"net/http"
 	"os"
+	"strconv"
+	"time"
 
+	"github.com/OmarElGabry/go-textnow/internal/gateway"
 	"github.com/OmarElGabry/go-textnow/internal/pkg/config"
+	"github.com/OmarElGabry/go-textnow/internal/pkg/token"
 
 	"google.golang.org/grpc"
 
@@ -28,7 +33,13 @@ func main() {
 	ctx, cancel := context.WithCancel(ctx)
 	defer cancel()
 
-	mux := runtime.NewServeMux()
+	// errors are replied by default, along with the hint of when to retry a rate limited request
+	runtime.HTTPError = gateway.HTTPError
+
+	// the idempotency key of a request is passed to the services, and a replayed response is told apart
+	mux := runtime.NewServeMux(
+		runtime.WithIncomingHeaderMatcher(gateway.IncomingHeaderMatcher),
+		runtime.WithOutgoingHeaderMatcher(gateway.OutgoingHeaderMatcher))
 	opts := []grpc.DialOption{grpc.WithInsecure()}
 
 	// phonebook
@@ -57,6 +68,50 @@ func main() {
 		io.WriteString(w, "hello from the gateway: "+hostname)
 	})
 
+	// export of assigned phone numbers as CSV
+	// it calls phonebook service directly, and so it needs its own connection
+	pBConn, err := grpc.Dial("phonebook-service:"+config("GRPC_SERVER_PORT"), opts...)
+	if err != nil {
+		log.Fatalf("gateway: failed to connect to phonebook service: %v", err)
+	}
+	defer pBConn.Close()
+
+	pB := phonebook.NewPhoneBookServiceClient(pBConn)
+
+	// "gateway token ..." issues a token for the live messages and exits
+	if len(os.Args) > 1 && os.Args[1] == "token" {
+		runToken(pB, []byte(config("LIVE_TOKEN_SECRET")), os.Args[2:])
+		return
+	}
+
+	gatewaymux.HandleFunc("/phonebook/assignments.csv", gateway.ExportAssignments(pB))
+
+	// live messages and inbound messages call sms service directly, and so they need their own connection
+	smsConn, err := grpc.Dial("sms-service:"+config("GRPC_SERVER_PORT"), opts...)
+	if err != nil {
+		log.Fatalf("gateway: failed to connect to sms service: %v", err)
+	}
+	defer smsConn.Close()
+
+	smsClient := sms.NewSMSServiceClient(smsConn)
+
+	// live messages over Server-Sent Events and WebSocket
+	// they are enabled only if there is a secret to verify the tokens
+	if secret := config("LIVE_TOKEN_SECRET"); secret != "" {
+		gatewaymux.HandleFunc("/sms/live/sse", gateway.LiveSSE(smsClient, []byte(secret)))
+		gatewaymux.HandleFunc("/sms/live/ws", gateway.LiveWebSocket(smsClient, []byte(secret)))
+	} else {
+		log.Println("gateway: LIVE_TOKEN_SECRET is not set, live messages are disabled")
+	}
+
+	// inbound messages delivered by the carriers
+	// they are enabled only if there is a secret shared with the carriers to verify the requests
+	if secret := config("INBOUND_WEBHOOK_SECRET"); secret != "" {
+		gatewaymux.HandleFunc("/sms/inbound", gateway.Inbound(smsClient, []byte(secret)))
+	} else {
+		log.Println("gateway: INBOUND_WEBHOOK_SECRET is not set, inbound messages are disabled")
+	}
+
 	// wrap the grpc mux
 	gatewaymux.Handle("/", mux)
 
@@ -75,3 +130,56 @@ func main() {
 	<-c
 	s.Shutdown(ctx)
 }
+
+// runToken runs the "token" command, which prints a token of the live messages for a user:
+//
+//	token <user_id> <phone_number> [ttl]	signs it by LIVE_TOKEN_SECRET, expiring after the ttl (defaults to 24h)
+//
+// The phone number has to be assigned to the user, since the token lets them read its messages.
+// It stands in for the service the users log in to, i.e. to issue the tokens of the tests and the internal tools.
+func runToken(pB phonebook.PhoneBookServiceClient, secret []byte, args []string) {
+	if len(args) < 2 {
+		log.Fatalf("Usage: token <user_id> <phone_number> [ttl]")
+	}
+
+	if len(secret) == 0 {
+		log.Fatalf("LIVE_TOKEN_SECRET is not set")
+	}
+
+	userID, err := strconv.Atoi(args[0])
+	if err != nil || userID < 1 {
+		log.Fatalf("Invalid user id: %s", args[0])
+	}
+
+	ttl := 24 * time.Hour
+	if len(args) > 2 {
+		ttl, err = time.ParseDuration(args[2])
+		if err != nil || ttl <= 0 {
+			log.Fatalf("Invalid ttl: %s", args[2])
+		}
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	owner, err := pB.FindOne(ctx, &phonebook.FindOneRequest{PhoneNumber: args[1]})
+	if err != nil {
+		log.Fatalf("Failed to find phone number %s: %v", args[1], err)
+	}
+
+	if owner.GetUserId() != int32(userID) {
+		log.Fatalf("Phone number %s isn't assigned to user %d", args[1], userID)
+	}
+
+	tk, err := token.Sign(secret, token.Claims{
+		UserID:      int32(userID),
+		PhoneNumber: args[1],
+		ExpiresAt:   time.Now().Add(ttl).Unix(),
+	})
+
+	if err != nil {
+		log.Fatalf("Failed to sign the token: %v", err)
+	}
+
+	fmt.Println(tk)
+}
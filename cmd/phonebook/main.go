@@ -1,16 +1,22 @@
 package main
 
 import (
+	"context"
 	"fmt"
 	"log"
 	"net"
 	"os"
+	"strconv"
+	"time"
 
 	"github.com/OmarElGabry/go-textnow/internal/pkg/redis"
 
 	// mysql driver
 	"github.com/OmarElGabry/go-textnow/internal/phonebook"
 	"github.com/OmarElGabry/go-textnow/internal/pkg/config"
+	"github.com/OmarElGabry/go-textnow/internal/pkg/idempotency"
+	"github.com/OmarElGabry/go-textnow/internal/pkg/lru"
+	"github.com/OmarElGabry/go-textnow/internal/pkg/migrate"
 	"github.com/OmarElGabry/go-textnow/internal/pkg/validator"
 	_ "github.com/go-sql-driver/mysql"
 
@@ -37,8 +43,66 @@ func main() {
 		log.Fatalf("Failed to connect to db: %v", err)
 	}
 
+	migrator, err := migrate.New(db.DB, phonebook.Migrations)
+	if err != nil {
+		log.Fatalf("Invalid migrations: %v", err)
+	}
+
+	// "phonebook migrate ..." runs the migrations and exits
+	if len(os.Args) > 1 && os.Args[1] == "migrate" {
+		runMigrate(migrator, os.Args[2:])
+		db.Close()
+		return
+	}
+
+	// refuse to run against a schema that is too old
+	err = migrator.Check(context.Background())
+	if err != nil {
+		log.Fatalf("Failed to check the database schema: %v", err)
+	}
+
 	// connect to redis cache
 	cache, err := redis.NewCache()
+	if err != nil {
+		log.Fatalf("Failed to connect to redis: %v", err)
+	}
+
+	// move the phone numbers of the area code keys of the previous format (see MigrateCacheKeys)
+	migrated, err := phonebook.MigrateCacheKeys(cache)
+	if err != nil {
+		log.Fatalf("Failed to migrate the cache keys: %v", err)
+	}
+
+	if migrated > 0 {
+		log.Printf("Migrated %d area code key(s) in the cache", migrated)
+	}
+
+	// WatchAvailability relies on keyspace notifications of Set commands
+	err = cache.EnableKeyspaceEvents("s")
+	if err != nil {
+		log.Printf("Couldn't enable keyspace notifications, make sure they are enabled in redis: %v", err)
+	}
+
+	// in-process cache in front of redis for FindOne (disabled if size is 0)
+	var local *lru.Cache
+	if config("FINDONE_CACHE_SIZE") != "" {
+		size, err := strconv.Atoi(config("FINDONE_CACHE_SIZE"))
+		if err != nil {
+			log.Fatalf("Invalid FINDONE_CACHE_SIZE: %v", err)
+		}
+
+		ttl := 5 * time.Second
+		if config("FINDONE_CACHE_TTL") != "" {
+			ttl, err = time.ParseDuration(config("FINDONE_CACHE_TTL"))
+			if err != nil {
+				log.Fatalf("Invalid FINDONE_CACHE_TTL: %v", err)
+			}
+		}
+
+		if size > 0 {
+			local = lru.New(size, ttl)
+		}
+	}
 
 	// metrics and tracing
 	// 	jaeger only supports tracing
@@ -59,10 +123,19 @@ func main() {
 
 	// create new server and register metrics and tracing handler
 	opts := []grpc.ServerOption{ /*grpc.StatsHandler(&ocgrpc.ServerHandler{})*/ }
-	opts = append(opts, validator.Middlewares()...)
+	// the retries of the idempotent methods get the response of the first request,
+	// for the idempotency window of the keys (defaults to 24h)
+	idempotencyTTL, err := idempotency.TTLFromEnv()
+	if err != nil {
+		log.Fatalf("Couldn't read the idempotency window: %v", err)
+	}
+
+	idempotent := idempotency.New(
+		idempotency.NewRedisStore(cache, idempotency.DefaultLockTimeout, idempotencyTTL), phonebook.IdempotentMethods)
+	opts = append(opts, validator.Middlewares(idempotent.Unary())...)
 
 	s := grpc.NewServer(opts...)
-	srv := phonebook.NewPhoneBookServiceServer(db, cache)
+	srv := phonebook.NewPhoneBookServiceServer(db, cache, local)
 	phonebook.RegisterPhoneBookServiceServer(s, srv)
 
 	// graceful shutdown
@@ -80,3 +153,54 @@ func main() {
 	lis.Close()
 	db.Close()
 }
+
+// runMigrate runs the "migrate" command:
+//
+//	migrate up		applies all the pending migrations
+//	migrate down [n]	reverts the last n migrations (defaults to 1)
+//	migrate version		prints the current and the latest version
+func runMigrate(migrator *migrate.Migrator, args []string) {
+	ctx := context.Background()
+
+	if len(args) == 0 {
+		log.Fatalf("Usage: migrate up | down [n] | version")
+	}
+
+	switch args[0] {
+	case "up":
+		applied, err := migrator.Up(ctx)
+		if err != nil {
+			log.Fatalf("Failed to apply migrations (%d applied): %v", applied, err)
+		}
+
+		log.Printf("Applied %d migration(s)", applied)
+
+	case "down":
+		n := 1
+		if len(args) > 1 {
+			var err error
+			n, err = strconv.Atoi(args[1])
+			if err != nil || n < 1 {
+				log.Fatalf("Invalid number of migrations to revert: %s", args[1])
+			}
+		}
+
+		reverted, err := migrator.Down(ctx, n)
+		if err != nil {
+			log.Fatalf("Failed to revert migrations (%d reverted): %v", reverted, err)
+		}
+
+		log.Printf("Reverted %d migration(s)", reverted)
+
+	case "version":
+		version, err := migrator.Version(ctx)
+		if err != nil {
+			log.Fatalf("Failed to get the schema version: %v", err)
+		}
+
+		log.Printf("Schema version is %d, latest is %d", version, migrator.Latest())
+
+	default:
+		log.Fatalf("Unknown migrate command %q. Usage: migrate up | down [n] | version", args[0])
+	}
+}
@@ -2,19 +2,31 @@ package main
 
 import (
 	"context"
+	"fmt"
+	"io"
 	"log"
 	"net"
 	"os"
+	"strconv"
+	"strings"
+	"time"
 
 	"github.com/OmarElGabry/go-textnow/internal/phonebook"
+	"github.com/OmarElGabry/go-textnow/internal/pkg/carrier"
 	"github.com/OmarElGabry/go-textnow/internal/pkg/config"
+	"github.com/OmarElGabry/go-textnow/internal/pkg/contentfilter"
+	"github.com/OmarElGabry/go-textnow/internal/pkg/idempotency"
 	"github.com/OmarElGabry/go-textnow/internal/pkg/mongodb"
+	"github.com/OmarElGabry/go-textnow/internal/pkg/ratelimit"
+	"github.com/OmarElGabry/go-textnow/internal/pkg/redis"
+	"github.com/OmarElGabry/go-textnow/internal/pkg/smpp"
 	"github.com/OmarElGabry/go-textnow/internal/pkg/validator"
 
 	"github.com/OmarElGabry/go-textnow/internal/sms"
 	"google.golang.org/grpc"
 
 	"go.opencensus.io/plugin/ocgrpc"
+	"go.opencensus.io/stats/view"
 )
 
 func main() {
@@ -29,7 +41,23 @@ func main() {
 		log.Fatalf("Failed to connect to db: %v", err)
 	}
 
-	dbCollection := client.Database(config("MONGODB_DBNAME")).Collection("sms")
+	db := client.Database(config("MONGODB_DBNAME"))
+
+	// the idempotency window of the keys (defaults to 24h), of the messages and of the idempotent methods
+	idempotencyTTL, err := idempotency.TTLFromEnv()
+	if err != nil {
+		log.Fatalf("Couldn't read the idempotency window: %v", err)
+	}
+
+	if err := sms.CreateIndexes(context.Background(), db, idempotencyTTL); err != nil {
+		log.Fatalf("Failed to create the indexes: %v", err)
+	}
+
+	// connect to redis cache
+	cache, err := redis.NewCache()
+	if err != nil {
+		log.Fatalf("Failed to connect to redis: %v", err)
+	}
 
 	// connect to phonebook server
 	// and register metrics and tracing handler
@@ -41,6 +69,32 @@ func main() {
 
 	pB := phonebook.NewPhoneBookServiceClient(conn)
 
+	// the carriers, and the rules to route the messages to them by the destination phone number
+	carriers, err := newCarrierRouter(config)
+	if err != nil {
+		log.Fatalf("Invalid carriers configuration: %v", err)
+	}
+
+	// the rate limits of the senders, by the phone number and by the account
+	limits, err := newRateLimits(config)
+	if err != nil {
+		log.Fatalf("Invalid rate limits configuration: %v", err)
+	}
+
+	// the rules of the content filter, reloaded whenever the file changes
+	contentFilter, err := newContentFilter(config)
+	if err != nil {
+		log.Fatalf("Invalid content filter configuration: %v", err)
+	}
+
+	// the hosts of the internal network the webhooks are allowed to be sent to (none by default)
+	var webhookHosts []string
+	for _, host := range strings.Split(config("WEBHOOK_ALLOWED_HOSTS"), ",") {
+		if host = strings.TrimSpace(host); host != "" {
+			webhookHosts = append(webhookHosts, host)
+		}
+	}
+
 	// metrics and tracing
 	// 	jaeger only supports tracing
 	// je, err := tracing.NewJaegerExporter("sms")
@@ -52,6 +106,11 @@ func main() {
 	// trace.RegisterExporter(je)
 	// trace.ApplyConfig(trace.Config{DefaultSampler: trace.AlwaysSample()})
 
+	// the metrics of the sent messages, exported along with the other metrics once an exporter is registered
+	if err := view.Register(sms.Views...); err != nil {
+		log.Fatalf("Failed to register the metrics views: %v", err)
+	}
+
 	// spin up the gRPC server
 	lis, err := net.Listen("tcp", ":"+config("GRPC_SERVER_PORT"))
 	if err != nil {
@@ -61,10 +120,17 @@ func main() {
 	// create new server and register metrics and tracing handler
 	// make sure to put stats handler first
 	opts := []grpc.ServerOption{ /*grpc.StatsHandler(&ocgrpc.ServerHandler{})*/ }
-	opts = append(opts, validator.Middlewares()...)
+	// the retries of the idempotent methods get the response of the first request
+	idempotent := idempotency.New(
+		idempotency.NewMongoStore(db.Collection("idempotency"), idempotency.DefaultLockTimeout, idempotencyTTL), sms.IdempotentMethods)
+	opts = append(opts, validator.Middlewares(idempotent.Unary())...)
 
 	s := grpc.NewServer(opts...)
-	srv := sms.NewSMSServiceServer(dbCollection, pB)
+	srv := sms.NewSMSServiceServer(db, cache, pB, carriers, limits, contentFilter, sms.AutoReplies{
+		OptOut: config("OPT_OUT_REPLY"),
+		OptIn:  config("OPT_IN_REPLY"),
+		Help:   config("HELP_REPLY"),
+	}, idempotencyTTL, webhookHosts)
 	sms.RegisterSMSServiceServer(s, srv)
 
 	// graceful shutdown
@@ -81,5 +147,114 @@ func main() {
 	log.Println("SMS server stoped! ...")
 	s.Stop()
 	lis.Close()
+
+	if contentFilter != nil {
+		contentFilter.Close()
+	}
+
+	// i.e. unbind from the SMSC
+	for _, c := range carriers.Carriers() {
+		if closer, ok := c.(io.Closer); ok {
+			closer.Close()
+		}
+	}
+
 	client.Disconnect(context.TODO())
 }
+
+// newCarrierRouter creates the carriers, and the router by the routing rules in CARRIER_ROUTES.
+//
+// The fake carrier delivers every off-net message without sending it anywhere.
+// Its latency and failure rate can be set by FAKE_CARRIER_LATENCY and FAKE_CARRIER_FAILURE_RATE.
+// The SMPP carrier ("smpp") is created only if SMPP_ADDR is set.
+func newCarrierRouter(config config.Config) (*carrier.Router, error) {
+	var err error
+	carriers := []carrier.Carrier{}
+
+	latency := time.Duration(0)
+	if config("FAKE_CARRIER_LATENCY") != "" {
+		latency, err = time.ParseDuration(config("FAKE_CARRIER_LATENCY"))
+		if err != nil {
+			return nil, fmt.Errorf("invalid FAKE_CARRIER_LATENCY: %v", err)
+		}
+	}
+
+	failureRate := 0.0
+	if config("FAKE_CARRIER_FAILURE_RATE") != "" {
+		failureRate, err = strconv.ParseFloat(config("FAKE_CARRIER_FAILURE_RATE"), 64)
+		if err != nil {
+			return nil, fmt.Errorf("invalid FAKE_CARRIER_FAILURE_RATE: %v", err)
+		}
+	}
+
+	carriers = append(carriers, carrier.NewFake("fake", latency, failureRate))
+
+	if config("SMPP_ADDR") != "" {
+		window := 0
+		if config("SMPP_WINDOW") != "" {
+			if window, err = strconv.Atoi(config("SMPP_WINDOW")); err != nil {
+				return nil, fmt.Errorf("invalid SMPP_WINDOW: %v", err)
+			}
+		}
+
+		enquireLinkInterval := time.Duration(0)
+		if config("SMPP_ENQUIRE_LINK_INTERVAL") != "" {
+			if enquireLinkInterval, err = time.ParseDuration(config("SMPP_ENQUIRE_LINK_INTERVAL")); err != nil {
+				return nil, fmt.Errorf("invalid SMPP_ENQUIRE_LINK_INTERVAL: %v", err)
+			}
+		}
+
+		carriers = append(carriers, carrier.NewSMPP("smpp", smpp.Config{
+			Addr:                config("SMPP_ADDR"),
+			SystemID:            config("SMPP_SYSTEM_ID"),
+			Password:            config("SMPP_PASSWORD"),
+			SystemType:          config("SMPP_SYSTEM_TYPE"),
+			Window:              window,
+			EnquireLinkInterval: enquireLinkInterval,
+		}))
+	}
+
+	routes := config("CARRIER_ROUTES")
+	if routes == "" {
+		routes = "*=fake"
+	}
+
+	return carrier.NewRouter(routes, carriers...)
+}
+
+// newRateLimits parses the rate limits of sending SMSs in RATE_LIMIT_PER_NUMBER and RATE_LIMIT_PER_ACCOUNT,
+// i.e. "10/s,300/m,10000/d". They aren't limited if they are not set.
+func newRateLimits(config config.Config) (sms.RateLimits, error) {
+	perNumber, err := ratelimit.ParseRules(config("RATE_LIMIT_PER_NUMBER"))
+	if err != nil {
+		return sms.RateLimits{}, fmt.Errorf("invalid RATE_LIMIT_PER_NUMBER: %v", err)
+	}
+
+	perAccount, err := ratelimit.ParseRules(config("RATE_LIMIT_PER_ACCOUNT"))
+	if err != nil {
+		return sms.RateLimits{}, fmt.Errorf("invalid RATE_LIMIT_PER_ACCOUNT: %v", err)
+	}
+
+	return sms.RateLimits{PerNumber: perNumber, PerAccount: perAccount}, nil
+}
+
+// newContentFilter loads the rules of the content filter from the file in CONTENT_FILTER_FILE,
+// and checks it for changes every CONTENT_FILTER_RELOAD_INTERVAL (defaults to 10s).
+// There is no content filter if the file is not set.
+func newContentFilter(config config.Config) (*contentfilter.Watcher, error) {
+	if config("CONTENT_FILTER_FILE") == "" {
+		return nil, nil
+	}
+
+	interval := 10 * time.Second
+	if config("CONTENT_FILTER_RELOAD_INTERVAL") != "" {
+		var err error
+		if interval, err = time.ParseDuration(config("CONTENT_FILTER_RELOAD_INTERVAL")); err != nil {
+			return nil, fmt.Errorf("invalid CONTENT_FILTER_RELOAD_INTERVAL: %v", err)
+		}
+	}
+
+	return contentfilter.Watch(config("CONTENT_FILTER_FILE"), interval, func(err error) {
+		log.Printf("Failed to reload the content filter, the previous rules are kept: %v", err)
+	})
+}
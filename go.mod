@@ -24,6 +24,7 @@ require (
 	go.mongodb.org/mongo-driver v1.1.1
 	go.opencensus.io v0.22.1
 	go.uber.org/zap v1.10.0
+	golang.org/x/net v0.0.0-20190620200207-3b0461eec859
 	google.golang.org/genproto v0.0.0-20190911173649-1774047e7e51
 	google.golang.org/grpc v1.23.1
 	gopkg.in/DataDog/dd-trace-go.v1 v1.17.0 // indirect
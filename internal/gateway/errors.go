@@ -0,0 +1,36 @@
+package gateway
+
+import (
+	"context"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/golang/protobuf/ptypes"
+	"github.com/grpc-ecosystem/grpc-gateway/runtime"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc/status"
+)
+
+// HTTPError replies to the request with the error the same way the gateway does by default,
+// and when the error tells how long to wait before retrying (RetryInfo, i.e. of a rate limited sms),
+// it sets "Retry-After" header as well, in seconds (rounded up).
+//
+// The details of the error are in the response body, i.e.
+// {"details": [{"@type": "type.googleapis.com/google.rpc.RetryInfo", "retryDelay": "1.500s"}]}
+func HTTPError(ctx context.Context, mux *runtime.ServeMux, marshaler runtime.Marshaler,
+	w http.ResponseWriter, req *http.Request, err error) {
+	for _, detail := range status.Convert(err).Details() {
+		info, ok := detail.(*errdetails.RetryInfo)
+		if !ok {
+			continue
+		}
+
+		if delay, err := ptypes.Duration(info.GetRetryDelay()); err == nil {
+			seconds := int64((delay + time.Second - 1) / time.Second)
+			w.Header().Set("Retry-After", strconv.FormatInt(seconds, 10))
+		}
+	}
+
+	runtime.DefaultHTTPError(ctx, mux, marshaler, w, req, err)
+}
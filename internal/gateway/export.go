@@ -0,0 +1,84 @@
+package gateway
+
+import (
+	"encoding/csv"
+	"net/http"
+	"strconv"
+
+	"github.com/OmarElGabry/go-textnow/internal/phonebook"
+
+	"github.com/golang/protobuf/ptypes"
+	"github.com/grpc-ecosystem/grpc-gateway/runtime"
+	"github.com/grpc-ecosystem/grpc-gateway/utilities"
+	"google.golang.org/grpc/status"
+)
+
+// exportPageSize is the page size used to go through all the assignments
+const exportPageSize = 1000
+
+// ExportAssignments returns a handler that exports the assigned phone numbers as CSV.
+//
+// It accepts the same query parameters as "/phonebook/assignments" (except for the page size and cursor),
+// and goes through all the pages of ListAssignments, writing every page as soon as it is received.
+func ExportAssignments(pB phonebook.PhoneBookServiceClient) http.HandlerFunc {
+	return func(w http.ResponseWriter, req *http.Request) {
+		if req.Method != http.MethodGet {
+			http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+			return
+		}
+
+		// parse the query parameters the same way the gateway does
+		var listReq phonebook.ListAssignmentsRequest
+		filter := &utilities.DoubleArray{Encoding: map[string]int{}, Base: []int(nil), Check: []int(nil)}
+		if err := runtime.PopulateQueryParameters(&listReq, req.URL.Query(), filter); err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+
+		listReq.PageSize = exportPageSize
+		listReq.Cursor = ""
+
+		// 1) Get the first page before writing anything,
+		// 	so that an invalid request is reported with the right status code.
+		res, err := pB.ListAssignments(req.Context(), &listReq)
+		if err != nil {
+			s := status.Convert(err)
+			http.Error(w, s.Message(), runtime.HTTPStatusFromCode(s.Code()))
+			return
+		}
+
+		w.Header().Set("Content-Type", "text/csv")
+		w.Header().Set("Content-Disposition", `attachment; filename="assignments.csv"`)
+
+		writer := csv.NewWriter(w)
+		writer.Write([]string{"user_id", "phone_number", "assigned_at"})
+
+		// 2) Write page by page until there is no next page.
+		// 	Once the body is written, errors can only be reported by cutting the response short.
+		for {
+			for _, assignment := range res.GetAssignments() {
+				assignedAt := ""
+				if assignment.GetAssignedAt() != nil {
+					assignedAt = ptypes.TimestampString(assignment.GetAssignedAt())
+				}
+
+				writer.Write([]string{
+					strconv.Itoa(int(assignment.GetUserId())),
+					assignment.GetPhoneNumber(),
+					assignedAt,
+				})
+			}
+
+			writer.Flush()
+			if writer.Error() != nil || res.GetNextCursor() == "" {
+				return
+			}
+
+			listReq.Cursor = res.GetNextCursor()
+			res, err = pB.ListAssignments(req.Context(), &listReq)
+			if err != nil {
+				panic(http.ErrAbortHandler)
+			}
+		}
+	}
+}
@@ -0,0 +1,29 @@
+package gateway
+
+import (
+	"net/textproto"
+
+	"github.com/OmarElGabry/go-textnow/internal/pkg/idempotency"
+
+	"github.com/grpc-ecosystem/grpc-gateway/runtime"
+)
+
+// IncomingHeaderMatcher passes "Idempotency-Key" header of a request to the services as the idempotency key
+// (see idempotency.MetadataKey), along with the headers the gateway passes by default.
+func IncomingHeaderMatcher(key string) (string, bool) {
+	if textproto.CanonicalMIMEHeaderKey(key) == "Idempotency-Key" {
+		return idempotency.MetadataKey, true
+	}
+
+	return runtime.DefaultHeaderMatcher(key)
+}
+
+// OutgoingHeaderMatcher sets "Idempotent-Replayed" header of a response that is replayed to a retry,
+// while the rest of the metadata of the services is prefixed by "Grpc-Metadata-" as by default.
+func OutgoingHeaderMatcher(key string) (string, bool) {
+	if key == idempotency.ReplayedKey {
+		return "Idempotent-Replayed", true
+	}
+
+	return runtime.MetadataHeaderPrefix + key, true
+}
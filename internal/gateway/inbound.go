@@ -0,0 +1,69 @@
+package gateway
+
+import (
+	"bytes"
+	"io/ioutil"
+	"net/http"
+	"time"
+
+	"github.com/OmarElGabry/go-textnow/internal/pkg/signature"
+	"github.com/OmarElGabry/go-textnow/internal/sms"
+
+	"github.com/golang/protobuf/jsonpb"
+	"github.com/grpc-ecosystem/grpc-gateway/runtime"
+	"google.golang.org/grpc/status"
+)
+
+const (
+	// inboundMaxBodySize is the max size of the body of an inbound message, a few segments at most
+	inboundMaxBodySize = 64 * 1024
+
+	// inboundSignatureTolerance is how old a signed request can be, an older one could be replayed
+	inboundSignatureTolerance = 5 * time.Minute
+)
+
+// Inbound returns a handler that accepts the messages delivered by the carriers, sent to the phone numbers
+// on the platform. It is the webhook the carriers are configured to POST the inbound messages to.
+//
+// The body is the JSON of ReceiveRequest, i.e. {"from_phone_number": "+16135550199", "to_phone_number": "+16135550172",
+// "content": "hi", "carrier": "twilio", "carrier_message_id": "SM123"}. It must be signed by HMAC-SHA256 with the secret
+// shared with the carriers, in "X-Textnow-Signature" header, the same way the webhooks of the platform are signed.
+//
+// It bridges to Receive of SMS service, which normalizes the phone numbers and checks the recipient exists.
+// A message received before (by its carrier message id) is acknowledged again, so that the carrier stops retrying it.
+func Inbound(smsClient sms.SMSServiceClient, secret []byte) http.HandlerFunc {
+	return func(w http.ResponseWriter, req *http.Request) {
+		if req.Method != http.MethodPost {
+			http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+			return
+		}
+
+		body, err := ioutil.ReadAll(http.MaxBytesReader(w, req.Body, inboundMaxBodySize))
+		if err != nil {
+			http.Error(w, "Request body is too large", http.StatusRequestEntityTooLarge)
+			return
+		}
+
+		if err := signature.Verify(secret, req.Header.Get(signature.Header), body, inboundSignatureTolerance); err != nil {
+			http.Error(w, "Invalid signature: "+err.Error(), http.StatusUnauthorized)
+			return
+		}
+
+		var receiveReq sms.ReceiveRequest
+		unmarshaler := jsonpb.Unmarshaler{AllowUnknownFields: true}
+		if err := unmarshaler.Unmarshal(bytes.NewReader(body), &receiveReq); err != nil {
+			http.Error(w, "Invalid request body: "+err.Error(), http.StatusBadRequest)
+			return
+		}
+
+		res, err := smsClient.Receive(req.Context(), &receiveReq)
+		if err != nil {
+			s := status.Convert(err)
+			http.Error(w, s.Message(), runtime.HTTPStatusFromCode(s.Code()))
+			return
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		marshaler.Marshal(w, res)
+	}
+}
@@ -0,0 +1,281 @@
+package gateway
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/OmarElGabry/go-textnow/internal/pkg/token"
+	"github.com/OmarElGabry/go-textnow/internal/sms"
+
+	"github.com/golang/protobuf/jsonpb"
+	"github.com/grpc-ecosystem/grpc-gateway/runtime"
+	"golang.org/x/net/websocket"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+const (
+	// liveHeartbeatInterval is how often a heartbeat is sent when there are no events,
+	// so that proxies and load balancers don't close an idle connection
+	liveHeartbeatInterval = 15 * time.Second
+
+	// liveBufferSize is the max number of events waiting to be written to a client
+	liveBufferSize = 64
+
+	// liveWriteTimeout is how long a write to a WebSocket client can take
+	liveWriteTimeout = 10 * time.Second
+)
+
+// errSlowClient is reported to a client that doesn't keep up with its events
+var errSlowClient = status.Error(codes.ResourceExhausted, "Client is too slow, reconnect with the cursor of the last event")
+
+// marshaler is the same JSON marshaler the gateway uses for the responses
+var marshaler = jsonpb.Marshaler{OrigName: true}
+
+// LiveSSE returns a handler that pushes the new messages to a phone number,
+// and the status changes of the messages from it, as Server-Sent Events.
+//
+// It bridges to Subscribe of SMS service. Every event has the cursor as its id,
+// and so a browser (EventSource) resumes from it on reconnect by "Last-Event-ID" header.
+// See liveRequest for the query parameters and authentication.
+func LiveSSE(smsClient sms.SMSServiceClient, secret []byte) http.HandlerFunc {
+	return func(w http.ResponseWriter, req *http.Request) {
+		if req.Method != http.MethodGet {
+			http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+			return
+		}
+
+		subReq, err := liveRequest(req, secret)
+		if err != nil {
+			s := status.Convert(err)
+			http.Error(w, s.Message(), runtime.HTTPStatusFromCode(s.Code()))
+			return
+		}
+
+		if subReq.Cursor == "" {
+			subReq.Cursor = req.Header.Get("Last-Event-ID")
+		}
+
+		flusher, ok := w.(http.Flusher)
+		if !ok {
+			http.Error(w, "Streaming is not supported", http.StatusInternalServerError)
+			return
+		}
+
+		ctx, cancel := context.WithCancel(req.Context())
+		defer cancel()
+
+		events, errs := subscribe(ctx, cancel, smsClient, subReq)
+
+		w.Header().Set("Content-Type", "text/event-stream")
+		w.Header().Set("Cache-Control", "no-cache")
+		w.Header().Set("X-Accel-Buffering", "no") // disable buffering by nginx
+		w.WriteHeader(http.StatusOK)
+		flusher.Flush()
+
+		ticker := time.NewTicker(liveHeartbeatInterval)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case event, ok := <-events:
+				if !ok {
+					fmt.Fprintf(w, "event: error\ndata: %s\n\n", errorJSON(<-errs))
+					flusher.Flush()
+					return
+				}
+
+				data, err := marshaler.MarshalToString(event)
+				if err != nil {
+					return
+				}
+
+				fmt.Fprintf(w, "id: %s\ndata: %s\n\n", event.GetCursor(), data)
+				flusher.Flush()
+
+			case <-ticker.C:
+				// a comment line, ignored by EventSource
+				fmt.Fprint(w, ": heartbeat\n\n")
+				flusher.Flush()
+
+			case <-req.Context().Done():
+				return
+			}
+		}
+	}
+}
+
+// LiveWebSocket returns a handler that pushes the same events as LiveSSE over a WebSocket.
+//
+// Every event is a text frame of the event as JSON. A heartbeat is {"heartbeat":true},
+// and an error is {"error":{...}} right before the connection is closed.
+// Messages from the client are ignored.
+func LiveWebSocket(smsClient sms.SMSServiceClient, secret []byte) http.HandlerFunc {
+	return func(w http.ResponseWriter, req *http.Request) {
+		if req.Method != http.MethodGet {
+			http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+			return
+		}
+
+		// authenticate before the upgrade, so that it is rejected with the right status code
+		subReq, err := liveRequest(req, secret)
+		if err != nil {
+			s := status.Convert(err)
+			http.Error(w, s.Message(), runtime.HTTPStatusFromCode(s.Code()))
+			return
+		}
+
+		server := websocket.Server{
+			// the client is authenticated by the token rather than by its origin
+			Handshake: func(*websocket.Config, *http.Request) error { return nil },
+			Handler: func(ws *websocket.Conn) {
+				liveWebSocket(ws, smsClient, subReq)
+			},
+		}
+
+		server.ServeHTTP(w, req)
+	}
+}
+
+func liveWebSocket(ws *websocket.Conn, smsClient sms.SMSServiceClient, subReq *sms.SubscribeRequest) {
+	defer ws.Close()
+
+	ctx, cancel := context.WithCancel(ws.Request().Context())
+	defer cancel()
+
+	// read (and ignore) the messages from the client, to know when it closes the connection
+	go func() {
+		defer cancel()
+
+		var msg []byte
+		for websocket.Message.Receive(ws, &msg) == nil {
+		}
+	}()
+
+	// the events channel is closed once the client is gone, since the stream is canceled
+	events, errs := subscribe(ctx, cancel, smsClient, subReq)
+
+	ticker := time.NewTicker(liveHeartbeatInterval)
+	defer ticker.Stop()
+
+	write := func(data string) error {
+		ws.SetWriteDeadline(time.Now().Add(liveWriteTimeout))
+		return websocket.Message.Send(ws, data)
+	}
+
+	for {
+		select {
+		case event, ok := <-events:
+			if !ok {
+				write(`{"error":` + errorJSON(<-errs) + `}`)
+				return
+			}
+
+			data, err := marshaler.MarshalToString(event)
+			if err != nil {
+				return
+			}
+
+			if err := write(data); err != nil {
+				return
+			}
+
+		case <-ticker.C:
+			if err := write(`{"heartbeat":true}`); err != nil {
+				return
+			}
+		}
+	}
+}
+
+// liveRequest authenticates the request, and returns the Subscribe request for it.
+//
+// Query parameters:
+//   - "phone_number": the phone number to subscribe to.
+//   - "cursor": the cursor of the last received event, to resume from (optional).
+//   - "token": the token of the user (see token.Sign). It can be passed in
+//     "Authorization: Bearer <token>" header instead, but browsers can't set headers
+//     on EventSource and WebSocket.
+//
+// The token must be issued for the same phone number.
+func liveRequest(req *http.Request, secret []byte) (*sms.SubscribeRequest, error) {
+	query := req.URL.Query()
+	phoneNumber := query.Get("phone_number")
+	if phoneNumber == "" {
+		return nil, status.Error(codes.InvalidArgument, "Missing phone_number")
+	}
+
+	t := query.Get("token")
+	if t == "" {
+		t = strings.TrimPrefix(req.Header.Get("Authorization"), "Bearer ")
+	}
+
+	claims, err := token.Verify(secret, t)
+	if err != nil {
+		return nil, status.Error(codes.Unauthenticated, err.Error())
+	}
+
+	if claims.PhoneNumber != phoneNumber {
+		return nil, status.Error(codes.PermissionDenied, "Token isn't for this phone number")
+	}
+
+	return &sms.SubscribeRequest{PhoneNumber: phoneNumber, Cursor: query.Get("cursor")}, nil
+}
+
+// subscribe opens the Subscribe stream, and receives its events in the background.
+//
+// The events are buffered up to liveBufferSize while they are written to the client.
+// If the buffer is full, the client is too slow: the stream is closed rather than buffering
+// without a limit, and the client has to reconnect with the cursor of the last event it got.
+//
+// When the stream ends, the events channel is closed, and the error is sent on the errors channel.
+func subscribe(ctx context.Context, cancel context.CancelFunc, smsClient sms.SMSServiceClient,
+	subReq *sms.SubscribeRequest) (<-chan *sms.SubscribeResponse, <-chan error) {
+
+	events := make(chan *sms.SubscribeResponse, liveBufferSize)
+	errs := make(chan error, 1)
+
+	go func() {
+		defer close(events)
+
+		stream, err := smsClient.Subscribe(ctx, subReq)
+		if err != nil {
+			errs <- err
+			return
+		}
+
+		for {
+			event, err := stream.Recv()
+			if err != nil {
+				errs <- err
+				return
+			}
+
+			select {
+			case events <- event:
+			default:
+				errs <- errSlowClient
+				cancel()
+				return
+			}
+		}
+	}()
+
+	return events, errs
+}
+
+// errorJSON formats an error the same way the gateway does
+func errorJSON(err error) string {
+	s := status.Convert(err)
+	buf, _ := json.Marshal(map[string]interface{}{
+		"code":      s.Code(),
+		"http_code": runtime.HTTPStatusFromCode(s.Code()),
+		"message":   s.Message(),
+	})
+
+	return string(buf)
+}
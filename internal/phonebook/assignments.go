@@ -0,0 +1,163 @@
+package phonebook
+
+import (
+	context "context"
+	"fmt"
+	"strings"
+
+	"github.com/OmarElGabry/go-textnow/internal/pkg/cursor"
+	"github.com/OmarElGabry/go-textnow/internal/pkg/mysql"
+	"github.com/OmarElGabry/go-textnow/internal/pkg/phonenumber"
+
+	"github.com/golang/protobuf/ptypes"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+const (
+	defaultPageSize = 50
+
+	// mysqlDatetime is the layout of DATETIME values in MySQL
+	mysqlDatetime = "2006-01-02 15:04:05"
+)
+
+// sortColumns maps every sort option to the SQL expression rows are ordered by.
+//
+// Phone numbers assigned before "assigned_at" column was added have it as NULL,
+// and so they are treated as the oldest assignments.
+var sortColumns = map[ListAssignmentsRequest_SortBy]string{
+	ListAssignmentsRequest_USER_ID:      "user_id",
+	ListAssignmentsRequest_PHONE_NUMBER: "phone_number",
+	ListAssignmentsRequest_ASSIGNED_AT:  "COALESCE(assigned_at, '1970-01-01 00:00:00')",
+}
+
+// assignmentsPosition is the position of the last assignment in a page.
+//
+// Value is the value of the sort column, while UserID breaks the ties
+// since it is unique and so every row has a distinct position.
+type assignmentsPosition struct {
+	Value  string `json:"v"`
+	UserID int32  `json:"id"`
+}
+
+// ListAssignments method lists the assigned phone numbers, page by page.
+//
+// It uses keyset pagination: the next page starts right after the last row of the
+// previous one, and so the query doesn't get slower as the client goes through the pages.
+func (s *server) ListAssignments(ctx context.Context, req *ListAssignmentsRequest) (*ListAssignmentsResponse, error) {
+	sortColumn, ok := sortColumns[req.GetSortBy()]
+	if !ok {
+		return nil, status.Error(codes.InvalidArgument, "Unknown sort option")
+	}
+
+	pageSize := int(req.GetPageSize())
+	if pageSize == 0 {
+		pageSize = defaultPageSize
+	}
+
+	// 1) Filters
+	conditions := []string{"phone_number IS NOT NULL"}
+	args := []interface{}{}
+
+	if areaCode := req.GetAreaCode(); areaCode > 0 {
+		prefix, err := phonenumber.AreaCodePrefix(areaCode)
+		if err != nil {
+			return nil, status.Error(codes.InvalidArgument, "Invalid area_code: "+err.Error())
+		}
+
+		conditions = append(conditions, "phone_number LIKE ?")
+		args = append(args, prefix+"%")
+	}
+
+	if userID := req.GetUserId(); userID > 0 {
+		conditions = append(conditions, "user_id=?")
+		args = append(args, userID)
+	}
+
+	if req.GetAssignedAfter() != nil {
+		after, err := ptypes.Timestamp(req.GetAssignedAfter())
+		if err != nil {
+			return nil, status.Error(codes.InvalidArgument, "Invalid assigned_after: "+err.Error())
+		}
+
+		conditions = append(conditions, "assigned_at>=?")
+		args = append(args, after.UTC().Format(mysqlDatetime))
+	}
+
+	if req.GetAssignedBefore() != nil {
+		before, err := ptypes.Timestamp(req.GetAssignedBefore())
+		if err != nil {
+			return nil, status.Error(codes.InvalidArgument, "Invalid assigned_before: "+err.Error())
+		}
+
+		conditions = append(conditions, "assigned_at<?")
+		args = append(args, before.UTC().Format(mysqlDatetime))
+	}
+
+	// 2) Start after the position of the cursor (if any)
+	// 	Row constructor comparison (a, b) > (x, y) compares "a" first, then "b" if equal.
+	operator, direction := ">", "ASC"
+	if req.GetDescending() {
+		operator, direction = "<", "DESC"
+	}
+
+	if req.GetCursor() != "" {
+		var position assignmentsPosition
+		if err := cursor.Decode(req.GetCursor(), &position); err != nil {
+			return nil, status.Error(codes.InvalidArgument, "Invalid cursor")
+		}
+
+		conditions = append(conditions, fmt.Sprintf("(%s, user_id) %s (?, ?)", sortColumn, operator))
+		args = append(args, position.Value, position.UserID)
+	}
+
+	// 3) Query one more row than the page size to know if there is a next page
+	query := fmt.Sprintf(
+		"SELECT user_id, phone_number, assigned_at, %s FROM phonebook WHERE %s ORDER BY %s %s, user_id %s LIMIT ?",
+		sortColumn, strings.Join(conditions, " AND "), sortColumn, direction, direction)
+	args = append(args, pageSize+1)
+
+	rows, err := s.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, fmt.Sprintf("Internal error: %v", err))
+	}
+	defer rows.Close()
+
+	assignments := []*Assignment{}
+	positions := []assignmentsPosition{}
+	for rows.Next() {
+		var assignment Assignment
+		var assignedAt mysql.NullTime
+		var position assignmentsPosition
+
+		err := rows.Scan(&assignment.UserId, &assignment.PhoneNumber, &assignedAt, &position.Value)
+		if err != nil {
+			return nil, status.Errorf(codes.Internal, fmt.Sprintf("Internal error: %v", err))
+		}
+
+		// DATETIME has no time zone, it is always stored (and parsed) in UTC
+		if assignedAt.Valid {
+			assignment.AssignedAt, _ = ptypes.TimestampProto(assignedAt.Time)
+		}
+
+		position.UserID = assignment.UserId
+		assignments = append(assignments, &assignment)
+		positions = append(positions, position)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, status.Errorf(codes.Internal, fmt.Sprintf("Internal error: %v", err))
+	}
+
+	// 4) Encode the position of the last row in the page as the next cursor
+	nextCursor := ""
+	if len(assignments) > pageSize {
+		assignments = assignments[:pageSize]
+		nextCursor, err = cursor.Encode(positions[pageSize-1])
+		if err != nil {
+			return nil, status.Errorf(codes.Internal, fmt.Sprintf("Internal error: %v", err))
+		}
+	}
+
+	return &ListAssignmentsResponse{Assignments: assignments, NextCursor: nextCursor}, nil
+}
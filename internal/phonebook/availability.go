@@ -0,0 +1,77 @@
+package phonebook
+
+import (
+	"fmt"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// WatchAvailability method notifies the subscriber whenever the number of available
+// phone numbers in an area code rises to (or above) the given threshold.
+//
+// It relies on Redis keyspace notifications of the area code key, and so it catches
+// any change to the available phone numbers, whether by Reserve, Assign, or provisioning.
+func (s *server) WatchAvailability(req *WatchAvailabilityRequest, stream PhoneBookService_WatchAvailabilityServer) error {
+	areaCode := req.GetAreaCode()
+	key := areaCodeKey(areaCode)
+
+	threshold := int64(req.GetThreshold())
+	if threshold == 0 {
+		threshold = reserveCount
+	}
+
+	// 1) Subscribe before reading the current count,
+	// 	otherwise we could miss a change that happens in between.
+	pubsub := s.cache.Subscribe(s.cache.KeyspaceChannel(key))
+	defer pubsub.Close()
+
+	// wait for confirmation that subscription is created
+	if _, err := pubsub.Receive(); err != nil {
+		return status.Errorf(codes.Internal, fmt.Sprintf("Failed to watch area code %d: %v", areaCode, err))
+	}
+
+	events := pubsub.Channel()
+
+	// 2) Notify only when the count crosses the threshold, and not on every change above it.
+	// 	Once the count drops below the threshold, the subscriber will be notified again.
+	notified := false
+	check := func() error {
+		available, err := s.cache.SCard(key).Result()
+		if err != nil {
+			return status.Errorf(codes.Internal, fmt.Sprintf("Internal error: %v", err))
+		}
+
+		if available < threshold {
+			notified = false
+			return nil
+		}
+
+		if notified {
+			return nil
+		}
+
+		notified = true
+		return stream.Send(&WatchAvailabilityResponse{AreaCode: areaCode, Available: available})
+	}
+
+	if err := check(); err != nil {
+		return err
+	}
+
+	for {
+		select {
+		case <-stream.Context().Done():
+			// subscriber has gone away
+			return nil
+		case _, ok := <-events:
+			if !ok {
+				return status.Error(codes.Unavailable, "Lost connection to the cache")
+			}
+
+			if err := check(); err != nil {
+				return err
+			}
+		}
+	}
+}
@@ -0,0 +1,95 @@
+package phonebook
+
+import (
+	"strconv"
+	"strings"
+
+	"github.com/OmarElGabry/go-textnow/internal/pkg/redis"
+
+	uuid "github.com/satori/go.uuid"
+)
+
+// Before the keys had hash tags (for Redis Cluster), the Set of available phone numbers in an area code
+// was "areacode-613", and the Set of a reservation was "refid-<uuid>", with the area code key as one of its members.
+//
+// These are only on a single Redis server or Sentinel, since Redis Cluster wasn't supported back then.
+// TODO: remove once all the deployments have migrated their keys.
+const (
+	legacyAreaCodePrefix = "areacode-"
+	legacyRefIDPrefix    = "refid-"
+)
+
+// MigrateCacheKeys moves the phone numbers of every legacy area code Set ("areacode-613")
+// to the one of the new format ("areacode-{613}"), and returns the number of migrated Sets.
+//
+// It is safe to run many times, and while the previous version of the service is still running:
+// the phone numbers a previous replica adds back to a legacy Set are moved by the next run.
+func MigrateCacheKeys(cache *redis.Cache) (int, error) {
+	if cache.IsCluster() {
+		return 0, nil
+	}
+
+	migrated := 0
+	var cursor uint64
+	for {
+		keys, next, err := cache.Scan(cursor, legacyAreaCodePrefix+"*", 100).Result()
+		if err != nil {
+			return migrated, err
+		}
+
+		for _, key := range keys {
+			areaCode, err := strconv.Atoi(strings.TrimPrefix(key, legacyAreaCodePrefix))
+			if err != nil {
+				continue // the new format, or not an area code key at all
+			}
+
+			if err := migrateKeyScript.Run(cache, []string{key, areaCodeKey(int32(areaCode))}).Err(); err != nil {
+				return migrated, err
+			}
+
+			migrated++
+		}
+
+		if cursor = next; cursor == 0 {
+			return migrated, nil
+		}
+	}
+}
+
+// migrateKeyScript merges the legacy Set into the new one, and deletes it, atomically.
+//	KEYS[1]: legacy area code key, KEYS[2]: area code key
+var migrateKeyScript = redis.NewScript(`
+redis.call("SUNIONSTORE", KEYS[2], KEYS[1], KEYS[2])
+redis.call("DEL", KEYS[1])
+return 1
+`)
+
+// legacyReservation returns the area code, the key of the Set, and the area code key member of it
+// of a reservation made before the deploy, whose refID is a plain uuid.
+// It is only looked up for a refID that isn't in the format of newRefID.
+func (s *server) legacyReservation(refID string) (int32, string, string, bool) {
+	if _, err := uuid.FromString(refID); err != nil {
+		return 0, "", "", false
+	}
+
+	key := legacyRefIDPrefix + refID
+	members, err := s.cache.SMembers(key).Result()
+	if err != nil {
+		return 0, "", "", false
+	}
+
+	for _, member := range members {
+		if !strings.HasPrefix(member, legacyAreaCodePrefix) {
+			continue
+		}
+
+		areaCode, err := strconv.Atoi(strings.TrimPrefix(member, legacyAreaCodePrefix))
+		if err != nil {
+			continue
+		}
+
+		return int32(areaCode), key, member, true
+	}
+
+	return 0, "", "", false
+}
@@ -0,0 +1,15 @@
+package phonebook
+
+import (
+	"github.com/OmarElGabry/go-textnow/internal/pkg/idempotency"
+)
+
+// IdempotentMethods are the methods that opt into the idempotency interceptor (see idempotency.New),
+// and so a retry of a request gets the response of the first one.
+//
+// Both only read the key from the metadata. The reference id of Assign isn't a key of its own: a request
+// with the wrong phone number would take it, and then a retry with the right one would conflict with it.
+var IdempotentMethods = map[string]idempotency.KeyFunc{
+	"/phonebook.PhoneBookService/Reserve": nil,
+	"/phonebook.PhoneBookService/Assign":  nil,
+}
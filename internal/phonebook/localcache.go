@@ -0,0 +1,41 @@
+package phonebook
+
+import (
+	"fmt"
+
+	"github.com/OmarElGabry/go-textnow/internal/pkg/logger"
+)
+
+// invalidationChannel is the Redis channel that phone numbers are published to when their
+// ownership changes, so that every replica removes them from its in-process cache.
+const invalidationChannel = "phonebook-invalidate"
+
+// invalidate removes the given phone number from the in-process cache of every replica.
+//
+// It is published even if the in-process cache of this replica is disabled,
+// since it could be enabled on the other replicas.
+func (s *server) invalidate(phoneNumber string) {
+	if s.local != nil {
+		s.local.Remove(phoneNumber)
+	}
+
+	err := s.cache.Publish(invalidationChannel, phoneNumber).Err()
+	if err != nil {
+		logger.Error(fmt.Sprintf("Failed to publish invalidation of %s error: %v", phoneNumber, err))
+	}
+}
+
+// subscribeInvalidations removes the phone numbers published to the invalidation channel
+// from the in-process cache. It runs for as long as the server does.
+//
+// The subscription reconnects on its own if the connection to Redis is lost, but the
+// invalidations published meanwhile are missed. That's why the entries have a short TTL,
+// it bounds for how long a stale entry can live.
+func (s *server) subscribeInvalidations() {
+	pubsub := s.cache.Subscribe(invalidationChannel)
+	defer pubsub.Close()
+
+	for msg := range pubsub.Channel() {
+		s.local.Remove(msg.Payload)
+	}
+}
@@ -0,0 +1,41 @@
+package phonebook
+
+import (
+	"github.com/OmarElGabry/go-textnow/internal/pkg/migrate"
+)
+
+// Migrations are the schema changes of the phonebook database, in order.
+//
+// A new migration is appended at the end with the next version number.
+// Once released, a migration must never be edited; add a new one instead.
+var Migrations = []migrate.Migration{
+	{
+		Version: 1,
+		Name:    "create_phonebook",
+		// "IF NOT EXISTS" adopts databases created before the migrations were introduced
+		Up: []string{
+			"CREATE TABLE IF NOT EXISTS `phonebook` (" +
+				"`user_id` int(11) NOT NULL AUTO_INCREMENT, " +
+				"`phone_number` varchar(48) DEFAULT NULL, " +
+				"PRIMARY KEY (`user_id`), " +
+				"UNIQUE KEY `phone_number` (`phone_number`)" +
+				") ENGINE=InnoDB DEFAULT CHARSET=utf8 COLLATE=utf8_general_ci",
+		},
+		Down: []string{
+			"DROP TABLE `phonebook`",
+		},
+	},
+	{
+		Version: 2,
+		Name:    "add_phonebook_assigned_at",
+		// databases created from the bootstrap script have the column already
+		Exists: "SELECT COUNT(*) FROM information_schema.COLUMNS " +
+			"WHERE TABLE_SCHEMA = DATABASE() AND TABLE_NAME = 'phonebook' AND COLUMN_NAME = 'assigned_at'",
+		Up: []string{
+			"ALTER TABLE `phonebook` ADD COLUMN `assigned_at` datetime DEFAULT NULL, ADD KEY `assigned_at` (`assigned_at`)",
+		},
+		Down: []string{
+			"ALTER TABLE `phonebook` DROP KEY `assigned_at`, DROP COLUMN `assigned_at`",
+		},
+	},
+}
@@ -3,11 +3,14 @@ package phonebook
 import (
 	context "context"
 	"database/sql"
+	"errors"
 	"fmt"
 	"strconv"
 	"strings"
+	"time"
 
 	"github.com/OmarElGabry/go-textnow/internal/pkg/logger"
+	"github.com/OmarElGabry/go-textnow/internal/pkg/lru"
 	"github.com/OmarElGabry/go-textnow/internal/pkg/mysql"
 	"github.com/OmarElGabry/go-textnow/internal/pkg/redis"
 
@@ -16,25 +19,76 @@ import (
 	"google.golang.org/grpc/status"
 )
 
+// reserveCount is the number of phone numbers Reserve returns to choose from
+const reserveCount = 5
+
+// errNotExists is returned by the loader of the in-process cache when the phone number doesn't exist
+var errNotExists = errors.New("phone number doesn't exist")
+
 type server struct {
 	db    *mysql.DB
 	cache *redis.Cache
+	local *lru.Cache // optional in-process cache in front of Redis for FindOne
 	// mu    sync.Mutex
 }
 
 // NewPhoneBookServiceServer creates and returns a new PhoneBook service server
-func NewPhoneBookServiceServer(db *mysql.DB, cache *redis.Cache) PhoneBookServiceServer {
-	return &server{db: db, cache: cache}
+//
+// The in-process cache "local" is optional, and can be nil.
+func NewPhoneBookServiceServer(db *mysql.DB, cache *redis.Cache, local *lru.Cache) PhoneBookServiceServer {
+	s := &server{db: db, cache: cache, local: local}
+
+	if local != nil {
+		go s.subscribeInvalidations()
+	}
+
+	return s
 }
 
 // FindOne method finds if the given phone number exists or not
+//
+// If the in-process cache is enabled, it is checked first. On a miss, concurrent lookups
+// of the same phone number are coalesced into a single lookup in Redis (and the database).
 func (s *server) FindOne(ctx context.Context, req *FindOneRequest) (*FindOneResponse, error) {
 	phoneNumber := req.GetPhoneNumber()
 
-	_, err := s.cache.Get(phoneNumber).Result()
-	if err == s.cache.ErrNotExists {
+	if s.local == nil {
+		return s.findOne(phoneNumber)
+	}
+
+	res, err := s.local.GetOrLoad(phoneNumber, func() (interface{}, error) {
+		res, err := s.findOne(phoneNumber)
+		if err == nil && !res.GetExists() {
+			// same as Redis, phone numbers that don't exist are not cached,
+			// they could be added to the database by other means than Assign.
+			// errors are not cached, but still shared with the coalesced lookups.
+			return nil, errNotExists
+		}
+
+		return res, err
+	})
+
+	if err == errNotExists {
+		return &FindOneResponse{Exists: false}, nil
+	}
+
+	if err != nil {
+		return nil, err
+	}
+
+	return res.(*FindOneResponse), nil
+}
+
+// findOne finds if the given phone number exists in Redis, or in the database on a cache miss.
+//
+// The cached value is the user the phone number is assigned to (see ownerValue).
+// Values cached before the user was recorded are treated as a cache miss, and so replaced.
+func (s *server) findOne(phoneNumber string) (*FindOneResponse, error) {
+
+	value, err := s.cache.Get(phoneNumber).Result()
+	userID, ok := parseOwnerValue(value)
+	if err == s.cache.ErrNotExists || (err == nil && !ok) {
 		row := s.db.QueryRow("SELECT user_id FROM phonebook WHERE phone_number=?", phoneNumber)
-		var userID int
 		err := row.Scan(&userID)
 
 		switch {
@@ -47,7 +101,7 @@ func (s *server) FindOne(ctx context.Context, req *FindOneRequest) (*FindOneResp
 		// keys will be evicted according to "allkeys-lru" policy
 		// redis checks the memory usage, and if it is greater than the maxmemory limit,
 		// it evicts keys according to that policy.
-		_, err = s.cache.Set(phoneNumber, true, 0).Result()
+		_, err = s.cache.Set(phoneNumber, ownerValue(userID), 0).Result()
 		if err != nil {
 			logger.Error(fmt.Sprintf("Failed to set key in Redis error: %v", err))
 		}
@@ -56,38 +110,52 @@ func (s *server) FindOne(ctx context.Context, req *FindOneRequest) (*FindOneResp
 		return nil, status.Errorf(codes.Internal, fmt.Sprintf("Internal error: %v", err))
 	}
 
-	return &FindOneResponse{Exists: true}, nil
+	return &FindOneResponse{Exists: true, UserId: int32(userID)}, nil
+}
+
+// ownerValue is the value of a phone number in Redis, the user it is assigned to
+func ownerValue(userID int) string {
+	return "user:" + strconv.Itoa(userID)
+}
+
+// parseOwnerValue parses the value of a phone number in Redis, see ownerValue
+func parseOwnerValue(value string) (int, bool) {
+	if !strings.HasPrefix(value, "user:") {
+		return 0, false
+	}
+
+	userID, err := strconv.Atoi(strings.TrimPrefix(value, "user:"))
+	return userID, err == nil
 }
 
 // Reserve method reservers 5 (unassigned) phone numbers and allow the user to choose one of them.
 func (s *server) Reserve(ctx context.Context, req *ReserveRequest) (*ReserveResponse, error) {
 	areaCode := req.GetAreaCode()
-	refID := uuid.NewV4().String()
-	areaCodeKey := "areacode-" + strconv.Itoa(int(areaCode))
-
-	// 1) Get 5 phone numbers by areaCode
-	// Redis is actually single-threaded, and so only one command at a time is executed.
-	// s.mu.Lock()
-	phoneNumbers, err := s.cache.SPopN(areaCodeKey, 5).Result()
-	// s.mu.Unlock()
+	refID := newRefID(areaCode)
 
+	// 1) Get 5 phone numbers by areaCode, and add them to refID set to be fetched later in Assign()
+	// Redis is actually single-threaded, and the script is executed atomically.
+	// And so, two requests can't reserve the same phone numbers.
+	res, err := reserveScript.Run(s.cache,
+		[]string{areaCodeKey(areaCode), refIDKey(areaCode, refID)}, reserveCount).Result()
 	if err != nil {
 		return nil, status.Errorf(codes.Internal, fmt.Sprintf("Failed to reserve phone numbers: %v", err))
 	}
 
-	if len(phoneNumbers) < 5 {
-		// re-insert the phone numbers back, no longer going to use them
-		s.cache.SAdd(areaCodeKey, phoneNumbers)
+	members, _ := res.([]interface{})
+	phoneNumbers := []string{}
+	for _, member := range members {
+		if phoneNumber, ok := member.(string); ok {
+			phoneNumbers = append(phoneNumbers, phoneNumber)
+		}
+	}
 
+	// 2) The script re-inserts the phone numbers back if there are not enough of them
+	if len(phoneNumbers) < reserveCount {
 		logger.Warn(fmt.Sprintf("Cache is running out of available phone numbers for area code %d", areaCode))
 		return nil, status.Errorf(codes.FailedPrecondition, "Not enough available phone numbers!")
 	}
 
-	// 2) Add them to refID set to be fetched later in Assign()
-	// 	To get the areaCodeKey in Assign()
-	//	A small trick is to add "areaCodeKey" at the end
-	s.cache.SAdd("refid-"+refID, append(phoneNumbers, areaCodeKey))
-
 	return &ReserveResponse{PhoneNumbers: phoneNumbers, RefId: refID}, nil
 }
 
@@ -98,63 +166,163 @@ func (s *server) Reserve(ctx context.Context, req *ReserveRequest) (*ReserveResp
 func (s *server) Assign(ctx context.Context, req *AssignRequest) (*AssignResponse, error) {
 	phoneNumber := req.GetPhoneNumber()
 	userID := req.GetUserId()
-	refIDKey := "refid-" + req.GetRefId()
+	refID := req.GetRefId()
 
-	// 1) Check if the selected phone number & refID exists
-	// 	Also keep the un-selected (skipped) numbers aside.
-	// 	Remember that for every key "refid-refID", the last value is the areaCodeKey
-	phoneNumbersAndAreaCode, err := s.cache.SMembers(refIDKey).Result()
-	if err != nil {
-		return nil, status.Errorf(codes.Internal, fmt.Sprintf("Internal error: %v", err))
+	// 1) Get the area code the phone numbers were reserved in,
+	// 	or of a reservation made before the area code was part of the refID (see legacyReservation).
+	areaCode, ok := parseRefID(refID)
+	key, skip := refIDKey(areaCode, refID), ""
+	if !ok {
+		areaCode, key, skip, ok = s.legacyReservation(refID)
 	}
 
-	found := false
-	skippedNumbers := []string{}
-	var areaCodeKey string
-	for _, pNumberOrAreaCode := range phoneNumbersAndAreaCode {
-		if pNumberOrAreaCode == phoneNumber {
-			found = true
-		} else if strings.HasPrefix(pNumberOrAreaCode, "areacode-") {
-			areaCodeKey = pNumberOrAreaCode
-		} else {
-			skippedNumbers = append(skippedNumbers, pNumberOrAreaCode)
-		}
-	}
-
-	if !found {
+	if !ok {
 		return nil, status.Error(codes.InvalidArgument, "Phone number and/or reference id is wrong")
 	}
 
-	// 2) We no longer going to use that refIDKey key
-	_, err = s.cache.Del(refIDKey).Result()
+	// 2) Check if the selected phone number & refID exists,
+	// 	and if so, add the un-selected (skipped) numbers back to the areaCodeKey and so available for selection.
+	// 	We no longer going to use that refIDKey key, and so it is deleted.
+	found, err := assignScript.Run(s.cache,
+		[]string{key, areaCodeKey(areaCode)}, phoneNumber, skip).Int64()
 	if err != nil {
 		return nil, status.Errorf(codes.Internal, fmt.Sprintf("Internal error: %v", err))
 	}
 
-	// 3) Add the skipped numbers back to the areaCodeKey and so available for selection
-	// Remember that phone numbers that are already exist in the Set are ignored.
-	_, err = s.cache.SAdd(areaCodeKey, skippedNumbers).Result()
-	if err != nil {
-		logger.Error(
-			fmt.Sprintf("Failed to re-insert skipped numbers %v to %s after %s has been deleted",
-				skippedNumbers, areaCodeKey, phoneNumber))
-		return nil, status.Errorf(codes.Internal, fmt.Sprintf("Internal error: %v", err))
+	if found == 0 {
+		return nil, status.Error(codes.InvalidArgument, "Phone number and/or reference id is wrong")
 	}
 
-	// 4) Assign the selected number to the user
+	// 3) Assign the selected number to the user
 	// We use database for "phonebook": A table of users and their info.
-	_, err = s.db.Exec("UPDATE phonebook SET phone_number=? WHERE user_id=?", phoneNumber, userID)
+	previous, err := s.assign(userID, phoneNumber)
 	if err != nil {
 		return nil, status.Errorf(codes.Internal,
 			fmt.Sprintf("Failed to assign the phone number: %v", err))
 	}
 
-	// 5) Update the cache so that subsequent request result in cache hit
+	// 4) Update the cache so that subsequent request result in cache hit
 	// We could, however, store it in the cache, and have an async queue to update the database.
-	_, err = s.cache.Set(phoneNumber, true, 0).Result()
+	_, err = s.cache.Set(phoneNumber, ownerValue(int(userID)), 0).Result()
 	if err != nil {
 		logger.Error(fmt.Sprintf("Failed to set key in Redis error: %v", err))
 	}
 
+	// 5) The number the user had before is released, and so it no longer has an owner
+	if previous != "" && previous != phoneNumber {
+		if err := s.cache.Del(previous).Err(); err != nil {
+			logger.Error(fmt.Sprintf("Failed to delete key in Redis error: %v", err))
+		}
+
+		s.invalidate(previous)
+	}
+
+	// 6) The phone number could have been cached with its previous owner by the in-process cache of any replica
+	s.invalidate(phoneNumber)
+
 	return &AssignResponse{Assigned: true}, nil
 }
+
+// assign assigns the phone number to the user, and returns the number the user had before, if any.
+// The previous one is read in the same transaction, and so it is the one the update released.
+func (s *server) assign(userID int32, phoneNumber string) (string, error) {
+	tx, err := s.db.Begin()
+	if err != nil {
+		return "", err
+	}
+	defer tx.Rollback()
+
+	var previous sql.NullString
+	err = tx.QueryRow("SELECT phone_number FROM phonebook WHERE user_id=? FOR UPDATE", userID).Scan(&previous)
+	if err != nil && err != sql.ErrNoRows {
+		return "", err
+	}
+
+	_, err = tx.Exec("UPDATE phonebook SET phone_number=?, assigned_at=? WHERE user_id=?",
+		phoneNumber, time.Now().UTC().Format(mysqlDatetime), userID)
+	if err != nil {
+		return "", err
+	}
+
+	return previous.String, tx.Commit()
+}
+
+// reserveScript pops phone numbers from the area code Set and adds them to the refID Set,
+// or none at all if there are not enough of them.
+//	KEYS[1]: area code key, KEYS[2]: refID key, ARGV[1]: number of phone numbers
+//
+// "replicate_commands" replicates the effects rather than the script itself,
+// which is required before any write when the script calls a random command like SPOP.
+var reserveScript = redis.NewScript(`
+redis.replicate_commands()
+local phoneNumbers = redis.call("SPOP", KEYS[1], ARGV[1])
+if #phoneNumbers < tonumber(ARGV[1]) then
+	if #phoneNumbers > 0 then
+		redis.call("SADD", KEYS[1], unpack(phoneNumbers))
+	end
+	return {}
+end
+redis.call("SADD", KEYS[2], unpack(phoneNumbers))
+return phoneNumbers
+`)
+
+// assignScript deletes the refID Set if the selected phone number is a member of it,
+// and adds the rest of the members (the skipped numbers) back to the area code Set.
+// It returns 1 if the selected phone number was found, 0 otherwise.
+//	KEYS[1]: refID key, KEYS[2]: area code key, ARGV[1]: selected phone number,
+//	ARGV[2]: a member that isn't a phone number (the area code key of a legacy reservation), or empty
+var assignScript = redis.NewScript(`
+redis.replicate_commands()
+if redis.call("SISMEMBER", KEYS[1], ARGV[1]) == 0 then
+	return 0
+end
+local phoneNumbers = redis.call("SMEMBERS", KEYS[1])
+redis.call("DEL", KEYS[1])
+for _, phoneNumber in ipairs(phoneNumbers) do
+	if phoneNumber ~= ARGV[1] and phoneNumber ~= ARGV[2] then
+		redis.call("SADD", KEYS[2], phoneNumber)
+	end
+end
+return 1
+`)
+
+// areaCodeKey returns the key of the Set of available phone numbers in an area code.
+//
+// The area code is a hash tag, and so on Redis Cluster, all the keys of the same
+// area code are in the same slot. This allows the scripts to touch all of them at once.
+func areaCodeKey(areaCode int32) string {
+	return "areacode-{" + strconv.Itoa(int(areaCode)) + "}"
+}
+
+// refIDKey returns the key of the Set of phone numbers reserved under a refID
+func refIDKey(areaCode int32, refID string) string {
+	return "refid-{" + strconv.Itoa(int(areaCode)) + "}-" + refID
+}
+
+// newRefID generates a new refID for phone numbers reserved in an area code.
+//
+// It is prefixed with the area code, so that Assign knows
+// the keys of the area code without any extra lookup.
+func newRefID(areaCode int32) string {
+	return strconv.Itoa(int(areaCode)) + "-" + uuid.NewV4().String()
+}
+
+// parseRefID returns the area code a refID was generated for,
+// if it is in the "<area code>-<uuid>" format of newRefID
+func parseRefID(refID string) (int32, bool) {
+	i := strings.Index(refID, "-")
+	if i <= 0 {
+		return 0, false
+	}
+
+	areaCode, err := strconv.Atoi(refID[:i])
+	if err != nil || areaCode <= 0 {
+		return 0, false
+	}
+
+	if _, err := uuid.FromString(refID[i+1:]); err != nil {
+		return 0, false
+	}
+
+	return int32(areaCode), true
+}
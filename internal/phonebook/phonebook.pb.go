@@ -4,7 +4,8 @@
 // PhoneBook Service
 //
 // PhoneBook Service API consists of 3 services to find, reserve,
-// and assign a phone number.
+// and assign a phone number, and a stream to watch for available numbers.
+// It also has an admin service to list the assigned phone numbers.
 
 package phonebook
 
@@ -12,6 +13,7 @@ import (
 	context "context"
 	fmt "fmt"
 	proto "github.com/golang/protobuf/proto"
+	timestamp "github.com/golang/protobuf/ptypes/timestamp"
 	_ "github.com/mwitkow/go-proto-validators"
 	_ "google.golang.org/genproto/googleapis/api/annotations"
 	grpc "google.golang.org/grpc"
@@ -31,6 +33,34 @@ var _ = math.Inf
 // proto package needs to be updated.
 const _ = proto.ProtoPackageIsVersion3 // please upgrade the proto package
 
+type ListAssignmentsRequest_SortBy int32
+
+const (
+	ListAssignmentsRequest_USER_ID      ListAssignmentsRequest_SortBy = 0
+	ListAssignmentsRequest_PHONE_NUMBER ListAssignmentsRequest_SortBy = 1
+	ListAssignmentsRequest_ASSIGNED_AT  ListAssignmentsRequest_SortBy = 2
+)
+
+var ListAssignmentsRequest_SortBy_name = map[int32]string{
+	0: "USER_ID",
+	1: "PHONE_NUMBER",
+	2: "ASSIGNED_AT",
+}
+
+var ListAssignmentsRequest_SortBy_value = map[string]int32{
+	"USER_ID":      0,
+	"PHONE_NUMBER": 1,
+	"ASSIGNED_AT":  2,
+}
+
+func (x ListAssignmentsRequest_SortBy) String() string {
+	return proto.EnumName(ListAssignmentsRequest_SortBy_name, int32(x))
+}
+
+func (ListAssignmentsRequest_SortBy) EnumDescriptor() ([]byte, []int) {
+	return fileDescriptor_34db5399df65ad55, []int{8, 0}
+}
+
 // ---- Find
 type FindOneRequest struct {
 	// Further validation can include regex.
@@ -73,7 +103,10 @@ func (m *FindOneRequest) GetPhoneNumber() string {
 }
 
 type FindOneResponse struct {
-	Exists               bool     `protobuf:"varint,1,opt,name=exists,proto3" json:"exists,omitempty"`
+	Exists bool `protobuf:"varint,1,opt,name=exists,proto3" json:"exists,omitempty"`
+	// The user it is assigned to, only set if it exists.
+	// SMS service finds the account that owns a phone number by it (i.e. for the webhooks and the rate limits).
+	UserId               int32    `protobuf:"varint,2,opt,name=user_id,json=userId,proto3" json:"user_id,omitempty"`
 	XXX_NoUnkeyedLiteral struct{} `json:"-"`
 	XXX_unrecognized     []byte   `json:"-"`
 	XXX_sizecache        int32    `json:"-"`
@@ -111,6 +144,13 @@ func (m *FindOneResponse) GetExists() bool {
 	return false
 }
 
+func (m *FindOneResponse) GetUserId() int32 {
+	if m != nil {
+		return m.UserId
+	}
+	return 0
+}
+
 // ---- Reserve
 type ReserveRequest struct {
 	AreaCode             int32    `protobuf:"varint,1,opt,name=area_code,json=areaCode,proto3" json:"area_code,omitempty"`
@@ -293,48 +333,382 @@ func (m *AssignResponse) GetAssigned() bool {
 	return false
 }
 
+// ---- WatchAvailability
+type WatchAvailabilityRequest struct {
+	AreaCode int32 `protobuf:"varint,1,opt,name=area_code,json=areaCode,proto3" json:"area_code,omitempty"`
+	// Minimum number of available phone numbers to be notified about.
+	// Defaults to 5, the amount of phone numbers Reserve needs.
+	Threshold            int32    `protobuf:"varint,2,opt,name=threshold,proto3" json:"threshold,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *WatchAvailabilityRequest) Reset()         { *m = WatchAvailabilityRequest{} }
+func (m *WatchAvailabilityRequest) String() string { return proto.CompactTextString(m) }
+func (*WatchAvailabilityRequest) ProtoMessage()    {}
+func (*WatchAvailabilityRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_34db5399df65ad55, []int{6}
+}
+
+func (m *WatchAvailabilityRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_WatchAvailabilityRequest.Unmarshal(m, b)
+}
+func (m *WatchAvailabilityRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_WatchAvailabilityRequest.Marshal(b, m, deterministic)
+}
+func (m *WatchAvailabilityRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_WatchAvailabilityRequest.Merge(m, src)
+}
+func (m *WatchAvailabilityRequest) XXX_Size() int {
+	return xxx_messageInfo_WatchAvailabilityRequest.Size(m)
+}
+func (m *WatchAvailabilityRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_WatchAvailabilityRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_WatchAvailabilityRequest proto.InternalMessageInfo
+
+func (m *WatchAvailabilityRequest) GetAreaCode() int32 {
+	if m != nil {
+		return m.AreaCode
+	}
+	return 0
+}
+
+func (m *WatchAvailabilityRequest) GetThreshold() int32 {
+	if m != nil {
+		return m.Threshold
+	}
+	return 0
+}
+
+type WatchAvailabilityResponse struct {
+	AreaCode             int32    `protobuf:"varint,1,opt,name=area_code,json=areaCode,proto3" json:"area_code,omitempty"`
+	Available            int64    `protobuf:"varint,2,opt,name=available,proto3" json:"available,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *WatchAvailabilityResponse) Reset()         { *m = WatchAvailabilityResponse{} }
+func (m *WatchAvailabilityResponse) String() string { return proto.CompactTextString(m) }
+func (*WatchAvailabilityResponse) ProtoMessage()    {}
+func (*WatchAvailabilityResponse) Descriptor() ([]byte, []int) {
+	return fileDescriptor_34db5399df65ad55, []int{7}
+}
+
+func (m *WatchAvailabilityResponse) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_WatchAvailabilityResponse.Unmarshal(m, b)
+}
+func (m *WatchAvailabilityResponse) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_WatchAvailabilityResponse.Marshal(b, m, deterministic)
+}
+func (m *WatchAvailabilityResponse) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_WatchAvailabilityResponse.Merge(m, src)
+}
+func (m *WatchAvailabilityResponse) XXX_Size() int {
+	return xxx_messageInfo_WatchAvailabilityResponse.Size(m)
+}
+func (m *WatchAvailabilityResponse) XXX_DiscardUnknown() {
+	xxx_messageInfo_WatchAvailabilityResponse.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_WatchAvailabilityResponse proto.InternalMessageInfo
+
+func (m *WatchAvailabilityResponse) GetAreaCode() int32 {
+	if m != nil {
+		return m.AreaCode
+	}
+	return 0
+}
+
+func (m *WatchAvailabilityResponse) GetAvailable() int64 {
+	if m != nil {
+		return m.Available
+	}
+	return 0
+}
+
+// ---- ListAssignments
+type ListAssignmentsRequest struct {
+	// Filters, all are optional.
+	AreaCode       int32                         `protobuf:"varint,1,opt,name=area_code,json=areaCode,proto3" json:"area_code,omitempty"`
+	UserId         int32                         `protobuf:"varint,2,opt,name=user_id,json=userId,proto3" json:"user_id,omitempty"`
+	AssignedAfter  *timestamp.Timestamp          `protobuf:"bytes,3,opt,name=assigned_after,json=assignedAfter,proto3" json:"assigned_after,omitempty"`
+	AssignedBefore *timestamp.Timestamp          `protobuf:"bytes,4,opt,name=assigned_before,json=assignedBefore,proto3" json:"assigned_before,omitempty"`
+	SortBy         ListAssignmentsRequest_SortBy `protobuf:"varint,5,opt,name=sort_by,json=sortBy,proto3,enum=phonebook.ListAssignmentsRequest_SortBy" json:"sort_by,omitempty"`
+	Descending     bool                          `protobuf:"varint,6,opt,name=descending,proto3" json:"descending,omitempty"`
+	// Defaults to 50, and at most 1000.
+	PageSize int32 `protobuf:"varint,7,opt,name=page_size,json=pageSize,proto3" json:"page_size,omitempty"`
+	// The "next_cursor" of the previous page. The filters and sorting
+	// must be the same as in the request of the previous page.
+	Cursor               string   `protobuf:"bytes,8,opt,name=cursor,proto3" json:"cursor,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *ListAssignmentsRequest) Reset()         { *m = ListAssignmentsRequest{} }
+func (m *ListAssignmentsRequest) String() string { return proto.CompactTextString(m) }
+func (*ListAssignmentsRequest) ProtoMessage()    {}
+func (*ListAssignmentsRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_34db5399df65ad55, []int{8}
+}
+
+func (m *ListAssignmentsRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_ListAssignmentsRequest.Unmarshal(m, b)
+}
+func (m *ListAssignmentsRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_ListAssignmentsRequest.Marshal(b, m, deterministic)
+}
+func (m *ListAssignmentsRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_ListAssignmentsRequest.Merge(m, src)
+}
+func (m *ListAssignmentsRequest) XXX_Size() int {
+	return xxx_messageInfo_ListAssignmentsRequest.Size(m)
+}
+func (m *ListAssignmentsRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_ListAssignmentsRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_ListAssignmentsRequest proto.InternalMessageInfo
+
+func (m *ListAssignmentsRequest) GetAreaCode() int32 {
+	if m != nil {
+		return m.AreaCode
+	}
+	return 0
+}
+
+func (m *ListAssignmentsRequest) GetUserId() int32 {
+	if m != nil {
+		return m.UserId
+	}
+	return 0
+}
+
+func (m *ListAssignmentsRequest) GetAssignedAfter() *timestamp.Timestamp {
+	if m != nil {
+		return m.AssignedAfter
+	}
+	return nil
+}
+
+func (m *ListAssignmentsRequest) GetAssignedBefore() *timestamp.Timestamp {
+	if m != nil {
+		return m.AssignedBefore
+	}
+	return nil
+}
+
+func (m *ListAssignmentsRequest) GetSortBy() ListAssignmentsRequest_SortBy {
+	if m != nil {
+		return m.SortBy
+	}
+	return ListAssignmentsRequest_USER_ID
+}
+
+func (m *ListAssignmentsRequest) GetDescending() bool {
+	if m != nil {
+		return m.Descending
+	}
+	return false
+}
+
+func (m *ListAssignmentsRequest) GetPageSize() int32 {
+	if m != nil {
+		return m.PageSize
+	}
+	return 0
+}
+
+func (m *ListAssignmentsRequest) GetCursor() string {
+	if m != nil {
+		return m.Cursor
+	}
+	return ""
+}
+
+type Assignment struct {
+	UserId      int32  `protobuf:"varint,1,opt,name=user_id,json=userId,proto3" json:"user_id,omitempty"`
+	PhoneNumber string `protobuf:"bytes,2,opt,name=phone_number,json=phoneNumber,proto3" json:"phone_number,omitempty"`
+	// Not set for phone numbers assigned before the assignment date was recorded.
+	AssignedAt           *timestamp.Timestamp `protobuf:"bytes,3,opt,name=assigned_at,json=assignedAt,proto3" json:"assigned_at,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}             `json:"-"`
+	XXX_unrecognized     []byte               `json:"-"`
+	XXX_sizecache        int32                `json:"-"`
+}
+
+func (m *Assignment) Reset()         { *m = Assignment{} }
+func (m *Assignment) String() string { return proto.CompactTextString(m) }
+func (*Assignment) ProtoMessage()    {}
+func (*Assignment) Descriptor() ([]byte, []int) {
+	return fileDescriptor_34db5399df65ad55, []int{9}
+}
+
+func (m *Assignment) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_Assignment.Unmarshal(m, b)
+}
+func (m *Assignment) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_Assignment.Marshal(b, m, deterministic)
+}
+func (m *Assignment) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_Assignment.Merge(m, src)
+}
+func (m *Assignment) XXX_Size() int {
+	return xxx_messageInfo_Assignment.Size(m)
+}
+func (m *Assignment) XXX_DiscardUnknown() {
+	xxx_messageInfo_Assignment.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_Assignment proto.InternalMessageInfo
+
+func (m *Assignment) GetUserId() int32 {
+	if m != nil {
+		return m.UserId
+	}
+	return 0
+}
+
+func (m *Assignment) GetPhoneNumber() string {
+	if m != nil {
+		return m.PhoneNumber
+	}
+	return ""
+}
+
+func (m *Assignment) GetAssignedAt() *timestamp.Timestamp {
+	if m != nil {
+		return m.AssignedAt
+	}
+	return nil
+}
+
+type ListAssignmentsResponse struct {
+	Assignments          []*Assignment `protobuf:"bytes,1,rep,name=assignments,proto3" json:"assignments,omitempty"`
+	NextCursor           string        `protobuf:"bytes,2,opt,name=next_cursor,json=nextCursor,proto3" json:"next_cursor,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}      `json:"-"`
+	XXX_unrecognized     []byte        `json:"-"`
+	XXX_sizecache        int32         `json:"-"`
+}
+
+func (m *ListAssignmentsResponse) Reset()         { *m = ListAssignmentsResponse{} }
+func (m *ListAssignmentsResponse) String() string { return proto.CompactTextString(m) }
+func (*ListAssignmentsResponse) ProtoMessage()    {}
+func (*ListAssignmentsResponse) Descriptor() ([]byte, []int) {
+	return fileDescriptor_34db5399df65ad55, []int{10}
+}
+
+func (m *ListAssignmentsResponse) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_ListAssignmentsResponse.Unmarshal(m, b)
+}
+func (m *ListAssignmentsResponse) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_ListAssignmentsResponse.Marshal(b, m, deterministic)
+}
+func (m *ListAssignmentsResponse) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_ListAssignmentsResponse.Merge(m, src)
+}
+func (m *ListAssignmentsResponse) XXX_Size() int {
+	return xxx_messageInfo_ListAssignmentsResponse.Size(m)
+}
+func (m *ListAssignmentsResponse) XXX_DiscardUnknown() {
+	xxx_messageInfo_ListAssignmentsResponse.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_ListAssignmentsResponse proto.InternalMessageInfo
+
+func (m *ListAssignmentsResponse) GetAssignments() []*Assignment {
+	if m != nil {
+		return m.Assignments
+	}
+	return nil
+}
+
+func (m *ListAssignmentsResponse) GetNextCursor() string {
+	if m != nil {
+		return m.NextCursor
+	}
+	return ""
+}
+
 func init() {
+	proto.RegisterEnum("phonebook.ListAssignmentsRequest_SortBy", ListAssignmentsRequest_SortBy_name, ListAssignmentsRequest_SortBy_value)
 	proto.RegisterType((*FindOneRequest)(nil), "phonebook.FindOneRequest")
 	proto.RegisterType((*FindOneResponse)(nil), "phonebook.FindOneResponse")
 	proto.RegisterType((*ReserveRequest)(nil), "phonebook.ReserveRequest")
 	proto.RegisterType((*ReserveResponse)(nil), "phonebook.ReserveResponse")
 	proto.RegisterType((*AssignRequest)(nil), "phonebook.AssignRequest")
 	proto.RegisterType((*AssignResponse)(nil), "phonebook.AssignResponse")
+	proto.RegisterType((*WatchAvailabilityRequest)(nil), "phonebook.WatchAvailabilityRequest")
+	proto.RegisterType((*WatchAvailabilityResponse)(nil), "phonebook.WatchAvailabilityResponse")
+	proto.RegisterType((*ListAssignmentsRequest)(nil), "phonebook.ListAssignmentsRequest")
+	proto.RegisterType((*Assignment)(nil), "phonebook.Assignment")
+	proto.RegisterType((*ListAssignmentsResponse)(nil), "phonebook.ListAssignmentsResponse")
 }
 
 func init() { proto.RegisterFile("phonebook.proto", fileDescriptor_34db5399df65ad55) }
 
 var fileDescriptor_34db5399df65ad55 = []byte{
-	// 454 bytes of a gzipped FileDescriptorProto
-	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0x74, 0x92, 0xcf, 0x6e, 0xd3, 0x40,
-	0x10, 0xc6, 0x65, 0x57, 0x75, 0x92, 0xa1, 0x4d, 0x60, 0x25, 0x4a, 0x6a, 0x5a, 0x5a, 0x2d, 0x12,
-	0x6a, 0x2b, 0x12, 0x4b, 0x20, 0x71, 0x28, 0x27, 0x8a, 0x84, 0xd4, 0x03, 0x7f, 0x64, 0x2e, 0x48,
-	0x1c, 0x22, 0x27, 0x3b, 0x71, 0x56, 0x69, 0x76, 0xc2, 0xae, 0x9d, 0x22, 0x10, 0x17, 0x5e, 0x81,
-	0xb7, 0xe1, 0x35, 0x78, 0x00, 0x24, 0xc4, 0x83, 0x20, 0xaf, 0xdd, 0xc5, 0x86, 0xf4, 0x66, 0xcf,
-	0x7c, 0xfb, 0xfd, 0x76, 0xbe, 0x59, 0xe8, 0x2d, 0x67, 0xa4, 0x70, 0x4c, 0x34, 0x1f, 0x2e, 0x35,
-	0x65, 0xc4, 0x3a, 0xae, 0x10, 0xee, 0xa5, 0x44, 0xe9, 0x05, 0x46, 0xc9, 0x52, 0x46, 0x89, 0x52,
-	0x94, 0x25, 0x99, 0x24, 0x65, 0x4a, 0x61, 0xf8, 0x24, 0x95, 0xd9, 0x2c, 0x1f, 0x0f, 0x27, 0xb4,
-	0x88, 0x16, 0x97, 0x32, 0x9b, 0xd3, 0x65, 0x94, 0xd2, 0xc0, 0x36, 0x07, 0xab, 0xe4, 0x42, 0x8a,
-	0x24, 0x23, 0x6d, 0x22, 0xf7, 0x59, 0x9e, 0xe3, 0x4f, 0xa1, 0xfb, 0x42, 0x2a, 0xf1, 0x5a, 0x61,
-	0x8c, 0x1f, 0x72, 0x34, 0x19, 0x3b, 0x86, 0x2d, 0x0b, 0x1d, 0xa9, 0x7c, 0x31, 0x46, 0xdd, 0xf7,
-	0x0e, 0xbd, 0xa3, 0xce, 0x59, 0xf0, 0xeb, 0xe7, 0x81, 0xff, 0xce, 0x8b, 0x6f, 0xd8, 0xde, 0x2b,
-	0xdb, 0xe2, 0xc7, 0xd0, 0x73, 0x87, 0xcd, 0x92, 0x94, 0x41, 0xb6, 0x03, 0x01, 0x7e, 0x94, 0x26,
-	0x33, 0xf6, 0x5c, 0x3b, 0xae, 0xfe, 0xf8, 0x00, 0xba, 0x31, 0x1a, 0xd4, 0x2b, 0xc7, 0xb9, 0x0b,
-	0x9d, 0x44, 0x63, 0x32, 0x9a, 0x90, 0x40, 0x2b, 0xde, 0x8c, 0xdb, 0x45, 0xe1, 0x39, 0x09, 0xe4,
-	0x2f, 0xa1, 0xe7, 0xe4, 0x95, 0xf3, 0x7d, 0xd8, 0xae, 0xdf, 0xab, 0x00, 0x6c, 0x1c, 0x75, 0xe2,
-	0xad, 0xda, 0x85, 0x0c, 0xbb, 0x0d, 0x81, 0xc6, 0xe9, 0x48, 0x8a, 0xbe, 0x5f, 0x5c, 0x3b, 0xde,
-	0xd4, 0x38, 0x3d, 0x17, 0xfc, 0x13, 0x6c, 0x3f, 0x33, 0x46, 0xa6, 0xea, 0x0a, 0x7e, 0x00, 0xad,
-	0xdc, 0xa0, 0x2e, 0x84, 0x16, 0xed, 0xe6, 0x0b, 0x8a, 0xf2, 0xb9, 0xf8, 0x2f, 0x05, 0xff, 0xda,
-	0x14, 0xd8, 0xbe, 0x63, 0x6e, 0x34, 0x44, 0x15, 0xfb, 0x21, 0x74, 0xaf, 0xd8, 0xd5, 0x24, 0x21,
-	0xb4, 0x13, 0x5b, 0x41, 0x51, 0xa5, 0xe4, 0xfe, 0x1f, 0x7d, 0xf7, 0xe1, 0xe6, 0x9b, 0xc2, 0xfc,
-	0x8c, 0x68, 0xfe, 0x16, 0xf5, 0x4a, 0x4e, 0x90, 0xcd, 0xa0, 0x55, 0xe5, 0xcc, 0x76, 0x87, 0x7f,
-	0x9f, 0x48, 0x73, 0x71, 0x61, 0xb8, 0xae, 0x55, 0x22, 0xf9, 0x83, 0xaf, 0x3f, 0x7e, 0x7f, 0xf3,
-	0x0f, 0xd9, 0xbd, 0xc8, 0x69, 0xa2, 0xa9, 0x54, 0x22, 0xfa, 0x5c, 0x9f, 0xf2, 0x0b, 0x1b, 0x41,
-	0xab, 0xca, 0xbd, 0x41, 0x6a, 0xae, 0xae, 0x41, 0xfa, 0x67, 0x4d, 0x7c, 0xdf, 0x92, 0xee, 0x70,
-	0x56, 0x23, 0xe9, 0x52, 0x73, 0xea, 0x9d, 0xb0, 0xf7, 0x10, 0x94, 0x69, 0xb0, 0x7e, 0xcd, 0xa4,
-	0xb1, 0x9c, 0x70, 0x77, 0x4d, 0xa7, 0x72, 0xdf, 0xb3, 0xee, 0x3b, 0xfc, 0x56, 0xcd, 0xbd, 0xcc,
-	0xee, 0xd4, 0x3b, 0x19, 0x07, 0xf6, 0x4d, 0x3f, 0xfe, 0x13, 0x00, 0x00, 0xff, 0xff, 0x33, 0x6b,
-	0x6a, 0xeb, 0x47, 0x03, 0x00, 0x00,
+	// 906 bytes of a gzipped FileDescriptorProto
+	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0x8c, 0x54, 0xdd, 0x6e, 0x1b, 0x45,
+	0x14, 0xee, 0x3a, 0xcd, 0xda, 0x3e, 0x4e, 0x6c, 0x77, 0xa4, 0x26, 0x9b, 0x25, 0x8d, 0xd3, 0x4d,
+	0x85, 0x4c, 0xd5, 0x78, 0x51, 0x90, 0x00, 0xb5, 0x57, 0x76, 0x1a, 0x20, 0x12, 0x4d, 0xab, 0x75,
+	0x0b, 0x48, 0x5c, 0xac, 0xd6, 0xde, 0xb1, 0x3d, 0x8a, 0xbd, 0x63, 0x66, 0xc6, 0xce, 0x4f, 0xd5,
+	0x1b, 0x24, 0x78, 0x01, 0x9e, 0x87, 0xa7, 0xe0, 0x9e, 0x48, 0x11, 0x37, 0x3c, 0x05, 0x68, 0x67,
+	0x67, 0x7f, 0xdc, 0x75, 0x93, 0xfa, 0xc6, 0x3b, 0xe7, 0xef, 0xfb, 0xe6, 0x3b, 0x73, 0x0e, 0xd4,
+	0xa6, 0x23, 0x1a, 0xe0, 0x1e, 0xa5, 0xa7, 0xad, 0x29, 0xa3, 0x82, 0xa2, 0x72, 0x62, 0x30, 0xb7,
+	0x87, 0x94, 0x0e, 0xc7, 0xd8, 0xf6, 0xa6, 0xc4, 0xf6, 0x82, 0x80, 0x0a, 0x4f, 0x10, 0x1a, 0xf0,
+	0x28, 0xd0, 0x6c, 0x28, 0xaf, 0x3c, 0xf5, 0x66, 0x03, 0x5b, 0x90, 0x09, 0xe6, 0xc2, 0x9b, 0x4c,
+	0x55, 0xc0, 0x97, 0x43, 0x22, 0x46, 0xb3, 0x5e, 0xab, 0x4f, 0x27, 0xf6, 0xe4, 0x8c, 0x88, 0x53,
+	0x7a, 0x66, 0x0f, 0xe9, 0xbe, 0x74, 0xee, 0xcf, 0xbd, 0x31, 0xf1, 0x3d, 0x41, 0x19, 0xb7, 0x93,
+	0xcf, 0x28, 0xcf, 0x7a, 0x06, 0xd5, 0x6f, 0x48, 0xe0, 0xbf, 0x0c, 0xb0, 0x83, 0x7f, 0x99, 0x61,
+	0x2e, 0xd0, 0x67, 0xb0, 0x26, 0x59, 0xb9, 0xc1, 0x6c, 0xd2, 0xc3, 0xcc, 0xd0, 0x76, 0xb5, 0x66,
+	0xb9, 0xa3, 0x5f, 0x5f, 0x35, 0x0a, 0x3f, 0x69, 0x4e, 0x45, 0xfa, 0x4e, 0xa4, 0xcb, 0xea, 0x40,
+	0x2d, 0x49, 0xe6, 0x53, 0x1a, 0x70, 0x8c, 0x36, 0x40, 0xc7, 0xe7, 0x84, 0x0b, 0x2e, 0xf3, 0x4a,
+	0x8e, 0x3a, 0xa1, 0x4d, 0x28, 0xce, 0x38, 0x66, 0x2e, 0xf1, 0x8d, 0xc2, 0xae, 0xd6, 0x5c, 0x75,
+	0xf4, 0xf0, 0x78, 0xec, 0x5b, 0xfb, 0x50, 0x75, 0x30, 0xc7, 0x6c, 0x9e, 0x10, 0xf8, 0x04, 0xca,
+	0x1e, 0xc3, 0x9e, 0xdb, 0xa7, 0x3e, 0x96, 0x55, 0x56, 0x9d, 0x52, 0x68, 0x38, 0xa4, 0x3e, 0xb6,
+	0x5e, 0x40, 0x2d, 0x09, 0x57, 0x90, 0x7b, 0xb0, 0x9e, 0x25, 0x1c, 0x22, 0xaf, 0x34, 0xcb, 0xce,
+	0x5a, 0x86, 0x29, 0x47, 0xf7, 0x41, 0x67, 0x78, 0x10, 0xc3, 0x97, 0x9d, 0x55, 0x86, 0x07, 0xc7,
+	0xbe, 0x75, 0x09, 0xeb, 0x6d, 0xce, 0xc9, 0x30, 0x88, 0xc1, 0x1b, 0x29, 0x4f, 0x09, 0x9d, 0x5c,
+	0x5c, 0xf1, 0xcd, 0xc9, 0x53, 0xf8, 0xa0, 0x3c, 0xe8, 0x41, 0x82, 0xb9, 0xb2, 0x10, 0xa4, 0xb0,
+	0x9f, 0x40, 0x35, 0xc6, 0x56, 0x37, 0x31, 0xa1, 0xe4, 0x49, 0x0b, 0xf6, 0x95, 0x7c, 0xc9, 0xd9,
+	0x0a, 0xc0, 0xf8, 0xd1, 0x13, 0xfd, 0x51, 0x7b, 0xee, 0x91, 0xb1, 0xd7, 0x23, 0x63, 0x22, 0x2e,
+	0x62, 0xd2, 0x7b, 0x39, 0xc5, 0x22, 0xac, 0xfa, 0x9d, 0x54, 0x39, 0xb4, 0x0f, 0x65, 0x31, 0x62,
+	0x98, 0x8f, 0xe8, 0x58, 0xf5, 0xa0, 0x53, 0xbb, 0xbe, 0x6a, 0x54, 0xea, 0xff, 0xc5, 0x3f, 0xcd,
+	0x49, 0x23, 0xac, 0x1f, 0x60, 0x6b, 0x09, 0x9e, 0x22, 0x7a, 0x53, 0x8b, 0xd0, 0x36, 0x94, 0xbd,
+	0x28, 0x69, 0x8c, 0x25, 0xd0, 0x8a, 0x93, 0x1a, 0xac, 0xbf, 0x57, 0x60, 0xe3, 0x7b, 0xc2, 0x45,
+	0x74, 0xf5, 0x09, 0x0e, 0x04, 0x8f, 0xaf, 0xf1, 0x24, 0x7f, 0x8d, 0x1c, 0xc3, 0x14, 0xa6, 0xf9,
+	0xde, 0x8b, 0xca, 0xc7, 0xc6, 0x2d, 0x6b, 0x43, 0x35, 0x96, 0xd1, 0xf5, 0x06, 0x02, 0x33, 0xd9,
+	0x8f, 0xca, 0x81, 0xd9, 0x8a, 0xa6, 0xaa, 0x15, 0x4f, 0x55, 0xeb, 0x75, 0x3c, 0x55, 0xce, 0x7a,
+	0x9c, 0xd1, 0x0e, 0x13, 0xd0, 0x21, 0xd4, 0x92, 0x12, 0x3d, 0x3c, 0xa0, 0x0c, 0x1b, 0x77, 0x6f,
+	0xad, 0x91, 0xa0, 0x76, 0x64, 0x06, 0x6a, 0x43, 0x91, 0x53, 0x26, 0xdc, 0xde, 0x85, 0xb1, 0xba,
+	0xab, 0x35, 0xab, 0x07, 0xcd, 0x56, 0xba, 0x10, 0x96, 0x6b, 0xd2, 0xea, 0x52, 0x26, 0x3a, 0x17,
+	0x8e, 0xce, 0xe5, 0x3f, 0xda, 0x01, 0xf0, 0x31, 0xef, 0xe3, 0xc0, 0x27, 0xc1, 0xd0, 0xd0, 0xe5,
+	0x1b, 0xc9, 0x58, 0x90, 0x0d, 0xe5, 0xa9, 0x37, 0xc4, 0x2e, 0x27, 0x97, 0xd8, 0x28, 0x4a, 0x59,
+	0xd0, 0xf5, 0x55, 0xa3, 0x9a, 0x91, 0xc5, 0xf8, 0xb7, 0xe8, 0x94, 0xc2, 0xa0, 0x2e, 0xb9, 0x94,
+	0xf3, 0xda, 0x9f, 0x31, 0x4e, 0x99, 0x51, 0x92, 0x73, 0xa1, 0x4e, 0xd6, 0xd7, 0xa0, 0x47, 0xd0,
+	0xa8, 0x02, 0xc5, 0x37, 0xdd, 0x23, 0xc7, 0x3d, 0x7e, 0x5e, 0xbf, 0x83, 0xea, 0xb0, 0xf6, 0xea,
+	0xbb, 0x97, 0x27, 0x47, 0xee, 0xc9, 0x9b, 0x17, 0x9d, 0x23, 0xa7, 0xae, 0xa1, 0x1a, 0x54, 0xda,
+	0xdd, 0xee, 0xf1, 0xb7, 0x27, 0x47, 0xcf, 0xdd, 0xf6, 0xeb, 0x7a, 0xc1, 0xfa, 0x4d, 0x03, 0x48,
+	0x2f, 0x92, 0x1d, 0x7c, 0x2d, 0x3b, 0xf8, 0xe8, 0xe1, 0xb2, 0x41, 0x5a, 0x1c, 0xa0, 0x67, 0x50,
+	0x49, 0x1b, 0x27, 0x3e, 0xa2, 0x6b, 0x90, 0x74, 0x4d, 0x58, 0x1c, 0x36, 0x73, 0x9a, 0xaa, 0xe7,
+	0xfb, 0x55, 0x5c, 0x57, 0x9a, 0xe5, 0xbe, 0xa8, 0x1c, 0xdc, 0xcf, 0x34, 0x23, 0x4d, 0x72, 0xb2,
+	0x91, 0xa8, 0x01, 0x95, 0x00, 0x9f, 0x0b, 0x57, 0x49, 0x16, 0x51, 0x86, 0xd0, 0x74, 0x28, 0x2d,
+	0x07, 0x7f, 0xde, 0x85, 0xfa, 0xab, 0xb0, 0x4c, 0x87, 0xd2, 0xd3, 0x2e, 0x66, 0x73, 0xd2, 0xc7,
+	0x68, 0x04, 0x45, 0xb5, 0x26, 0xd1, 0x56, 0x06, 0x64, 0x71, 0xef, 0x9a, 0xe6, 0x32, 0x57, 0x44,
+	0xd8, 0xfa, 0xf4, 0xd7, 0xbf, 0xfe, 0xf9, 0xa3, 0xb0, 0x8b, 0x76, 0xec, 0x24, 0xc6, 0x1e, 0x90,
+	0xc0, 0xb7, 0xdf, 0x66, 0x25, 0x7c, 0x87, 0x5c, 0x28, 0xaa, 0xed, 0xb8, 0x80, 0xb4, 0xb8, 0x60,
+	0x4d, 0x73, 0x99, 0x4b, 0x21, 0x3d, 0x90, 0x48, 0x9b, 0x4f, 0xb5, 0xc7, 0x16, 0xca, 0x80, 0x31,
+	0x55, 0xf5, 0x67, 0xd0, 0x23, 0x6d, 0x90, 0x91, 0x93, 0x2b, 0x2e, 0xbf, 0xb5, 0xc4, 0xa3, 0xaa,
+	0x6f, 0xcb, 0xea, 0x1b, 0xd6, 0xbd, 0x4c, 0xe9, 0x48, 0xdf, 0xa7, 0xda, 0x63, 0xf4, 0xbb, 0x06,
+	0xf7, 0x72, 0x3b, 0x07, 0xed, 0x65, 0xca, 0x7d, 0x68, 0x03, 0x9a, 0x8f, 0x6e, 0x0e, 0x52, 0xf0,
+	0x8f, 0x24, 0xfc, 0x0e, 0xda, 0xce, 0xc0, 0x9f, 0x85, 0xd1, 0xf6, 0xdb, 0x64, 0xf1, 0xbc, 0xfb,
+	0x5c, 0x43, 0xe7, 0x50, 0x7b, 0xef, 0xe9, 0xa0, 0x87, 0xb7, 0x8e, 0xaa, 0x69, 0xdd, 0x14, 0xa2,
+	0x18, 0xec, 0x48, 0x06, 0x06, 0xda, 0xc8, 0x09, 0x20, 0xe3, 0x7a, 0xba, 0x7c, 0xd4, 0x5f, 0xfc,
+	0x3f, 0x00, 0xd4, 0x04, 0x13, 0x0a, 0x2a, 0x08, 0x00, 0x00,
 }
 
 // Reference imports to suppress errors if they are not otherwise used.
@@ -351,7 +725,7 @@ const _ = grpc.SupportPackageIsVersion4
 type PhoneBookServiceClient interface {
 	// FindOne method finds if the given phone number exists or not
 	FindOne(ctx context.Context, in *FindOneRequest, opts ...grpc.CallOption) (*FindOneResponse, error)
-	// Reserve method reservers 5 (unassigned) phone numbers
+	// Reserve method reserves 5 (unassigned) phone numbers
 	//  and allow the user to choose one of them.
 	//
 	// The refID (random hash) is used identify the reserved numbers
@@ -361,6 +735,18 @@ type PhoneBookServiceClient interface {
 	//
 	// It is called immediately after Reserve method to carry on the phone number assignment.
 	Assign(ctx context.Context, in *AssignRequest, opts ...grpc.CallOption) (*AssignResponse, error)
+	// WatchAvailability method notifies the subscriber whenever the number of available
+	//  phone numbers in an area code rises to (or above) the given threshold.
+	//
+	// It is used instead of polling when Reserve fails with "Not enough available phone numbers".
+	//
+	// For HTTP API, newline-delimited JSON is used for streaming.
+	WatchAvailability(ctx context.Context, in *WatchAvailabilityRequest, opts ...grpc.CallOption) (PhoneBookService_WatchAvailabilityClient, error)
+	// ListAssignments method lists the assigned phone numbers, page by page.
+	//
+	// It is used by support staff to browse the phonebook.
+	// The same query is available as a CSV export by the gateway.
+	ListAssignments(ctx context.Context, in *ListAssignmentsRequest, opts ...grpc.CallOption) (*ListAssignmentsResponse, error)
 }
 
 type phoneBookServiceClient struct {
@@ -398,11 +784,52 @@ func (c *phoneBookServiceClient) Assign(ctx context.Context, in *AssignRequest,
 	return out, nil
 }
 
+func (c *phoneBookServiceClient) WatchAvailability(ctx context.Context, in *WatchAvailabilityRequest, opts ...grpc.CallOption) (PhoneBookService_WatchAvailabilityClient, error) {
+	stream, err := c.cc.NewStream(ctx, &_PhoneBookService_serviceDesc.Streams[0], "/phonebook.PhoneBookService/WatchAvailability", opts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &phoneBookServiceWatchAvailabilityClient{stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+type PhoneBookService_WatchAvailabilityClient interface {
+	Recv() (*WatchAvailabilityResponse, error)
+	grpc.ClientStream
+}
+
+type phoneBookServiceWatchAvailabilityClient struct {
+	grpc.ClientStream
+}
+
+func (x *phoneBookServiceWatchAvailabilityClient) Recv() (*WatchAvailabilityResponse, error) {
+	m := new(WatchAvailabilityResponse)
+	if err := x.ClientStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+func (c *phoneBookServiceClient) ListAssignments(ctx context.Context, in *ListAssignmentsRequest, opts ...grpc.CallOption) (*ListAssignmentsResponse, error) {
+	out := new(ListAssignmentsResponse)
+	err := c.cc.Invoke(ctx, "/phonebook.PhoneBookService/ListAssignments", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
 // PhoneBookServiceServer is the server API for PhoneBookService service.
 type PhoneBookServiceServer interface {
 	// FindOne method finds if the given phone number exists or not
 	FindOne(context.Context, *FindOneRequest) (*FindOneResponse, error)
-	// Reserve method reservers 5 (unassigned) phone numbers
+	// Reserve method reserves 5 (unassigned) phone numbers
 	//  and allow the user to choose one of them.
 	//
 	// The refID (random hash) is used identify the reserved numbers
@@ -412,6 +839,18 @@ type PhoneBookServiceServer interface {
 	//
 	// It is called immediately after Reserve method to carry on the phone number assignment.
 	Assign(context.Context, *AssignRequest) (*AssignResponse, error)
+	// WatchAvailability method notifies the subscriber whenever the number of available
+	//  phone numbers in an area code rises to (or above) the given threshold.
+	//
+	// It is used instead of polling when Reserve fails with "Not enough available phone numbers".
+	//
+	// For HTTP API, newline-delimited JSON is used for streaming.
+	WatchAvailability(*WatchAvailabilityRequest, PhoneBookService_WatchAvailabilityServer) error
+	// ListAssignments method lists the assigned phone numbers, page by page.
+	//
+	// It is used by support staff to browse the phonebook.
+	// The same query is available as a CSV export by the gateway.
+	ListAssignments(context.Context, *ListAssignmentsRequest) (*ListAssignmentsResponse, error)
 }
 
 // UnimplementedPhoneBookServiceServer can be embedded to have forward compatible implementations.
@@ -427,6 +866,12 @@ func (*UnimplementedPhoneBookServiceServer) Reserve(ctx context.Context, req *Re
 func (*UnimplementedPhoneBookServiceServer) Assign(ctx context.Context, req *AssignRequest) (*AssignResponse, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method Assign not implemented")
 }
+func (*UnimplementedPhoneBookServiceServer) WatchAvailability(req *WatchAvailabilityRequest, srv PhoneBookService_WatchAvailabilityServer) error {
+	return status.Errorf(codes.Unimplemented, "method WatchAvailability not implemented")
+}
+func (*UnimplementedPhoneBookServiceServer) ListAssignments(ctx context.Context, req *ListAssignmentsRequest) (*ListAssignmentsResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ListAssignments not implemented")
+}
 
 func RegisterPhoneBookServiceServer(s *grpc.Server, srv PhoneBookServiceServer) {
 	s.RegisterService(&_PhoneBookService_serviceDesc, srv)
@@ -486,6 +931,45 @@ func _PhoneBookService_Assign_Handler(srv interface{}, ctx context.Context, dec
 	return interceptor(ctx, in, info, handler)
 }
 
+func _PhoneBookService_WatchAvailability_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(WatchAvailabilityRequest)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(PhoneBookServiceServer).WatchAvailability(m, &phoneBookServiceWatchAvailabilityServer{stream})
+}
+
+type PhoneBookService_WatchAvailabilityServer interface {
+	Send(*WatchAvailabilityResponse) error
+	grpc.ServerStream
+}
+
+type phoneBookServiceWatchAvailabilityServer struct {
+	grpc.ServerStream
+}
+
+func (x *phoneBookServiceWatchAvailabilityServer) Send(m *WatchAvailabilityResponse) error {
+	return x.ServerStream.SendMsg(m)
+}
+
+func _PhoneBookService_ListAssignments_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ListAssignmentsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(PhoneBookServiceServer).ListAssignments(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/phonebook.PhoneBookService/ListAssignments",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(PhoneBookServiceServer).ListAssignments(ctx, req.(*ListAssignmentsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
 var _PhoneBookService_serviceDesc = grpc.ServiceDesc{
 	ServiceName: "phonebook.PhoneBookService",
 	HandlerType: (*PhoneBookServiceServer)(nil),
@@ -502,7 +986,17 @@ var _PhoneBookService_serviceDesc = grpc.ServiceDesc{
 			MethodName: "Assign",
 			Handler:    _PhoneBookService_Assign_Handler,
 		},
+		{
+			MethodName: "ListAssignments",
+			Handler:    _PhoneBookService_ListAssignments_Handler,
+		},
+	},
+	Streams: []grpc.StreamDesc{
+		{
+			StreamName:    "WatchAvailability",
+			Handler:       _PhoneBookService_WatchAvailability_Handler,
+			ServerStreams: true,
+		},
 	},
-	Streams:  []grpc.StreamDesc{},
 	Metadata: "phonebook.proto",
 }
@@ -4,7 +4,8 @@
 // PhoneBook Service
 //
 // PhoneBook Service API consists of 3 services to find, reserve,
-// and assign a phone number.
+// and assign a phone number, and a stream to watch for available numbers.
+// It also has an admin service to list the assigned phone numbers.
 
 package phonebook
 
@@ -13,6 +14,7 @@ import (
 	math "math"
 	proto "github.com/golang/protobuf/proto"
 	_ "google.golang.org/genproto/googleapis/api/annotations"
+	_ "github.com/golang/protobuf/ptypes/timestamp"
 	_ "github.com/mwitkow/go-proto-validators"
 	github_com_mwitkow_go_proto_validators "github.com/mwitkow/go-proto-validators"
 )
@@ -49,3 +51,58 @@ func (this *AssignRequest) Validate() error {
 func (this *AssignResponse) Validate() error {
 	return nil
 }
+func (this *WatchAvailabilityRequest) Validate() error {
+	if !(this.AreaCode > 0) {
+		return github_com_mwitkow_go_proto_validators.FieldError("AreaCode", fmt.Errorf(`value '%v' must be greater than '0'`, this.AreaCode))
+	}
+	if !(this.Threshold > -1) {
+		return github_com_mwitkow_go_proto_validators.FieldError("Threshold", fmt.Errorf(`value '%v' must be greater than '-1'`, this.Threshold))
+	}
+	return nil
+}
+func (this *WatchAvailabilityResponse) Validate() error {
+	return nil
+}
+func (this *ListAssignmentsRequest) Validate() error {
+	if !(this.AreaCode > -1) {
+		return github_com_mwitkow_go_proto_validators.FieldError("AreaCode", fmt.Errorf(`value '%v' must be greater than '-1'`, this.AreaCode))
+	}
+	if !(this.UserId > -1) {
+		return github_com_mwitkow_go_proto_validators.FieldError("UserId", fmt.Errorf(`value '%v' must be greater than '-1'`, this.UserId))
+	}
+	if this.AssignedAfter != nil {
+		if err := github_com_mwitkow_go_proto_validators.CallValidatorIfExists(this.AssignedAfter); err != nil {
+			return github_com_mwitkow_go_proto_validators.FieldError("AssignedAfter", err)
+		}
+	}
+	if this.AssignedBefore != nil {
+		if err := github_com_mwitkow_go_proto_validators.CallValidatorIfExists(this.AssignedBefore); err != nil {
+			return github_com_mwitkow_go_proto_validators.FieldError("AssignedBefore", err)
+		}
+	}
+	if !(this.PageSize > -1) {
+		return github_com_mwitkow_go_proto_validators.FieldError("PageSize", fmt.Errorf(`value '%v' must be greater than '-1'`, this.PageSize))
+	}
+	if !(this.PageSize < 1001) {
+		return github_com_mwitkow_go_proto_validators.FieldError("PageSize", fmt.Errorf(`value '%v' must be less than '1001'`, this.PageSize))
+	}
+	return nil
+}
+func (this *Assignment) Validate() error {
+	if this.AssignedAt != nil {
+		if err := github_com_mwitkow_go_proto_validators.CallValidatorIfExists(this.AssignedAt); err != nil {
+			return github_com_mwitkow_go_proto_validators.FieldError("AssignedAt", err)
+		}
+	}
+	return nil
+}
+func (this *ListAssignmentsResponse) Validate() error {
+	for _, item := range this.Assignments {
+		if item != nil {
+			if err := github_com_mwitkow_go_proto_validators.CallValidatorIfExists(item); err != nil {
+				return github_com_mwitkow_go_proto_validators.FieldError("Assignments", err)
+			}
+		}
+	}
+	return nil
+}
@@ -0,0 +1,83 @@
+package carrier
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/OmarElGabry/go-textnow/internal/pkg/gsm"
+)
+
+// ErrNoRoute is returned when there is no carrier for the destination of a message
+var ErrNoRoute = errors.New("no carrier for the destination")
+
+// Carrier hands the messages over to a network for delivery, i.e. a mobile operator or an aggregator
+type Carrier interface {
+	// Name is the name of the carrier the routing rules refer to
+	Name() string
+
+	// Send sends a message, and returns once the carrier has accepted (or rejected) it.
+	//
+	// A message that is split into segments could be sent segment by segment (i.e. SMPP), and so it isn't all or none:
+	// if a segment is rejected, the ones before it have been accepted already, and they can't be recalled.
+	// The error is then a *PartialError, with how many of the segments were sent.
+	Send(ctx context.Context, msg *Message) (*Receipt, error)
+}
+
+// PartialError is returned by Send when some of the segments of a message were sent, but not all of them
+type PartialError struct {
+	Sent  int // the number of segments sent, the first ones
+	Total int
+	Err   error // why the next one wasn't sent
+}
+
+func (e *PartialError) Error() string {
+	return fmt.Sprintf("%d of %d segments were sent: %v", e.Sent, e.Total, e.Err)
+}
+
+// Message is a message to be sent by a carrier
+type Message struct {
+	ID       string // the message id, unique for every message
+	From     string
+	To       string
+	Encoding gsm.Encoding
+
+	// Segments are the parts of the content, or the whole content if it fits in one sms.
+	// The segments of a concatenated sms share the reference number.
+	Segments   []string
+	SegmentRef uint8
+}
+
+// Receipt is what a carrier returns for a message it has accepted
+type Receipt struct {
+	// CarrierMessageID is the id the carrier knows the message by, if any
+	CarrierMessageID string
+
+	// Delivered tells if the message has been delivered already, otherwise it is on its way
+	Delivered bool
+}
+
+// Report is the delivery report of a message a carrier has accepted, but not delivered right away
+type Report struct {
+	Carrier          string
+	CarrierMessageID string
+	Delivered        bool
+	Reason           string // why it isn't delivered
+}
+
+// Inbound is a message received by a carrier, sent to a phone number on the platform
+type Inbound struct {
+	Carrier string
+	From    string
+	To      string
+	Content string
+}
+
+// Listener is a carrier that reports back: the delivery reports of the messages it has sent,
+// and the inbound messages it has received
+type Listener interface {
+	Carrier
+
+	// Listen sets the handlers of the reports and the inbound messages, before any message is sent
+	Listen(onReport func(*Report), onInbound func(*Inbound))
+}
@@ -0,0 +1,67 @@
+package carrier
+
+import (
+	"context"
+	"errors"
+	"math/rand"
+	"sync"
+	"time"
+
+	uuid "github.com/satori/go.uuid"
+)
+
+// ErrFakeFailure is the failure injected by the fake carrier
+var ErrFakeFailure = errors.New("carrier failed to send the message")
+
+// Fake is a carrier that delivers every message right away, after the given latency,
+// unless it fails it by the given failure rate. It is used to test the delivery offline.
+type Fake struct {
+	name        string
+	latency     time.Duration
+	failureRate float64 // from 0 (never) to 1 (always)
+
+	mu   sync.Mutex
+	rand *rand.Rand
+}
+
+// NewFake creates a fake carrier
+func NewFake(name string, latency time.Duration, failureRate float64) *Fake {
+	return &Fake{
+		name:        name,
+		latency:     latency,
+		failureRate: failureRate,
+		rand:        rand.New(rand.NewSource(time.Now().UnixNano())),
+	}
+}
+
+// Name returns the name of the carrier
+func (f *Fake) Name() string {
+	return f.name
+}
+
+// Send waits for the latency, then delivers the message or fails it.
+//
+// The id of a delivered message is a uuid, and so it is unique across the replicas and the restarts,
+// like the ids of a real carrier, which the delivery reports are matched to the messages by.
+func (f *Fake) Send(ctx context.Context, msg *Message) (*Receipt, error) {
+	if f.latency > 0 {
+		timer := time.NewTimer(f.latency)
+		defer timer.Stop()
+
+		select {
+		case <-timer.C:
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		}
+	}
+
+	f.mu.Lock()
+	failed := f.rand.Float64() < f.failureRate
+	f.mu.Unlock()
+
+	if failed {
+		return nil, ErrFakeFailure
+	}
+
+	return &Receipt{CarrierMessageID: f.name + "-" + uuid.NewV4().String(), Delivered: true}, nil
+}
@@ -0,0 +1,76 @@
+package carrier
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// Router picks the carrier of a message by the prefix of its destination phone number
+type Router struct {
+	routes   []route // the longest prefix first
+	carriers []Carrier
+}
+
+type route struct {
+	prefix  string
+	carrier Carrier
+}
+
+// NewRouter creates a router by the given rules, where every rule routes a prefix to a carrier by its name.
+//
+// The rules are comma-separated "<prefix>=<carrier>", i.e. "+1613=local,+1=national,*=international",
+// where "*" matches any phone number. The longest matching prefix wins.
+func NewRouter(rules string, carriers ...Carrier) (*Router, error) {
+	byName := map[string]Carrier{}
+	for _, c := range carriers {
+		byName[c.Name()] = c
+	}
+
+	r := &Router{carriers: carriers}
+	for _, rule := range strings.Split(rules, ",") {
+		rule = strings.TrimSpace(rule)
+		if rule == "" {
+			continue
+		}
+
+		parts := strings.SplitN(rule, "=", 2)
+		if len(parts) != 2 {
+			return nil, fmt.Errorf("invalid routing rule %q", rule)
+		}
+
+		prefix, name := strings.TrimSpace(parts[0]), strings.TrimSpace(parts[1])
+		c, ok := byName[name]
+		if !ok {
+			return nil, fmt.Errorf("unknown carrier %q in routing rule %q", name, rule)
+		}
+
+		if prefix == "*" {
+			prefix = ""
+		}
+
+		r.routes = append(r.routes, route{prefix: prefix, carrier: c})
+	}
+
+	sort.SliceStable(r.routes, func(i, j int) bool {
+		return len(r.routes[i].prefix) > len(r.routes[j].prefix)
+	})
+
+	return r, nil
+}
+
+// Route returns the carrier of the given destination phone number
+func (r *Router) Route(to string) (Carrier, error) {
+	for _, route := range r.routes {
+		if strings.HasPrefix(to, route.prefix) {
+			return route.carrier, nil
+		}
+	}
+
+	return nil, ErrNoRoute
+}
+
+// Carriers returns all the carriers, whether they are routed to or not
+func (r *Router) Carriers() []Carrier {
+	return r.carriers
+}
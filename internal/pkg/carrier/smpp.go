@@ -0,0 +1,201 @@
+package carrier
+
+import (
+	"context"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/OmarElGabry/go-textnow/internal/pkg/gsm"
+	"github.com/OmarElGabry/go-textnow/internal/pkg/smpp"
+)
+
+// inboundPartsExpiry is how long the segments of an inbound concatenated message wait for the rest
+const inboundPartsExpiry = 5 * time.Minute
+
+// SMPP is a carrier that sends the messages to an SMSC over SMPP 3.4.
+//
+// A message is accepted once the SMSC responds to submit_sm, and it is delivered once
+// its delivery receipt comes back (a report). The receipt is asked for the last segment only,
+// and so the carrier message id is the one of the last segment.
+type SMPP struct {
+	name   string
+	client *smpp.Client
+
+	mu        sync.Mutex
+	onReport  func(*Report)
+	onInbound func(*Inbound)
+	parts     map[string]*inboundParts // the segments of the inbound concatenated messages, by their reference
+}
+
+// inboundParts are the segments of an inbound concatenated message received so far
+type inboundParts struct {
+	segments []string
+	received int
+	expiry   time.Time
+}
+
+// NewSMPP creates an SMPP carrier, and binds to the SMSC in the background
+func NewSMPP(name string, config smpp.Config) *SMPP {
+	c := &SMPP{name: name, parts: map[string]*inboundParts{}}
+
+	config.OnDeliver = c.deliver
+	c.client = smpp.Dial(config)
+
+	return c
+}
+
+// Name returns the name of the carrier
+func (c *SMPP) Name() string {
+	return c.name
+}
+
+// Send submits the segments of the message, in order
+func (c *SMPP) Send(ctx context.Context, msg *Message) (*Receipt, error) {
+	coding := smpp.CodingDefault
+	if msg.Encoding == gsm.UCS2 {
+		coding = smpp.CodingUCS2
+	}
+
+	var id string
+	for i, segment := range msg.Segments {
+		sm := &smpp.Message{
+			Source:       strings.TrimPrefix(msg.From, "+"),
+			Destination:  strings.TrimPrefix(msg.To, "+"),
+			DataCoding:   coding,
+			ShortMessage: gsm.Encode(segment, msg.Encoding),
+		}
+
+		if len(msg.Segments) > 1 {
+			sm.ESMClass |= smpp.ESMClassUDHI
+			sm.ShortMessage = append(gsm.UDH(msg.SegmentRef, len(msg.Segments), i+1), sm.ShortMessage...)
+		}
+
+		if i == len(msg.Segments)-1 {
+			sm.RegisteredDelivery = 1
+		}
+
+		var err error
+		if id, err = c.client.Submit(ctx, sm); err != nil {
+			if i > 0 {
+				return nil, &PartialError{Sent: i, Total: len(msg.Segments), Err: err}
+			}
+
+			return nil, err
+		}
+	}
+
+	return &Receipt{CarrierMessageID: id}, nil
+}
+
+// Listen sets the handlers of the delivery receipts and the inbound messages
+func (c *SMPP) Listen(onReport func(*Report), onInbound func(*Inbound)) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.onReport, c.onInbound = onReport, onInbound
+}
+
+// Close unbinds from the SMSC
+func (c *SMPP) Close() error {
+	return c.client.Close()
+}
+
+// deliver handles a deliver_sm: a delivery receipt is reported, and an inbound message is passed on
+// once all of its segments are received
+func (c *SMPP) deliver(m *smpp.Message) {
+	c.mu.Lock()
+	onReport, onInbound := c.onReport, c.onInbound
+	c.mu.Unlock()
+
+	if m.IsReceipt() {
+		receipt, err := smpp.ParseReceipt(string(m.ShortMessage))
+		if err != nil || onReport == nil {
+			return
+		}
+
+		report := &Report{Carrier: c.name, CarrierMessageID: receipt.ID, Delivered: receipt.Delivered()}
+		if !report.Delivered {
+			report.Reason = "Carrier couldn't deliver the message: " + receipt.Stat + " (error " + receipt.Err + ")"
+		}
+
+		onReport(report)
+		return
+	}
+
+	encoding := gsm.GSM7
+	if m.DataCoding == smpp.CodingUCS2 {
+		encoding = gsm.UCS2
+	}
+
+	data, udh := m.UserData()
+	content, ok := c.join("+"+m.Source, "+"+m.Destination, udh, gsm.Decode(data, encoding))
+	if !ok || onInbound == nil {
+		return
+	}
+
+	onInbound(&Inbound{Carrier: c.name, From: "+" + m.Source, To: "+" + m.Destination, Content: content})
+}
+
+// join puts the segment of a concatenated message together with the ones received before it.
+// It returns the content once all the segments are received, or right away if it isn't a segment.
+func (c *SMPP) join(from, to string, udh []byte, segment string) (string, bool) {
+	ref, total, seq, ok := parseConcatenation(udh)
+	if !ok || total <= 1 {
+		return segment, true
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	now := time.Now()
+	for key, p := range c.parts {
+		if now.After(p.expiry) {
+			delete(c.parts, key)
+		}
+	}
+
+	key := from + "|" + to + "|" + ref
+	p, exists := c.parts[key]
+	if !exists || len(p.segments) != total {
+		p = &inboundParts{segments: make([]string, total), expiry: now.Add(inboundPartsExpiry)}
+		c.parts[key] = p
+	}
+
+	if p.segments[seq-1] == "" {
+		p.received++
+	}
+	p.segments[seq-1] = segment
+
+	if p.received < total {
+		return "", false
+	}
+
+	delete(c.parts, key)
+	return strings.Join(p.segments, ""), true
+}
+
+// parseConcatenation finds the information element of a concatenated message in a User Data Header,
+// of an 8-bit (0x00) or a 16-bit (0x08) reference number
+func parseConcatenation(udh []byte) (ref string, total, seq int, ok bool) {
+	for i := 1; i+1 < len(udh); i += 2 + int(udh[i+1]) {
+		id, length := udh[i], int(udh[i+1])
+		if i+2+length > len(udh) {
+			break
+		}
+
+		data := udh[i+2 : i+2+length]
+		switch {
+		case id == 0x00 && length == 3:
+			ref, total, seq = string(data[:1]), int(data[1]), int(data[2])
+		case id == 0x08 && length == 4:
+			ref, total, seq = string(data[:2]), int(data[2]), int(data[3])
+		default:
+			continue
+		}
+
+		return ref, total, seq, seq >= 1 && seq <= total
+	}
+
+	return "", 0, 0, false
+}
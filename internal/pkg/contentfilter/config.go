@@ -0,0 +1,197 @@
+package contentfilter
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"strconv"
+	"sync"
+	"time"
+)
+
+// config is the configuration file of a filter, i.e.
+//
+//	{
+//		"rules": [
+//			{"name": "banned-words", "type": "regex", "action": "REJECT", "pattern": "(?i)\\bcasino\\b"},
+//			{"name": "phishing", "type": "domains", "action": "REJECT", "domains": ["evil.example"]},
+//			{"name": "shorteners", "type": "domains", "action": "REWRITE", "domains": ["bit.ly"], "replacement": "[link removed]"}
+//		],
+//		"allow": {"42": ["bit.ly"]}
+//	}
+//
+// where "allow" has the domains every account (by user id) is allowed to send.
+type config struct {
+	Rules []ruleConfig        `json:"rules"`
+	Allow map[string][]string `json:"allow"`
+}
+
+type ruleConfig struct {
+	Name        string   `json:"name"`
+	Type        string   `json:"type"`
+	Action      Action   `json:"action"`
+	Pattern     string   `json:"pattern"`
+	Domains     []string `json:"domains"`
+	Replacement string   `json:"replacement"`
+}
+
+// Parse parses the configuration file of a filter (see config)
+func Parse(data []byte) (*Filter, error) {
+	var c config
+	if err := json.Unmarshal(data, &c); err != nil {
+		return nil, err
+	}
+
+	rules := make([]Rule, 0, len(c.Rules))
+	names := map[string]bool{}
+
+	for i, rc := range c.Rules {
+		if rc.Name == "" {
+			return nil, fmt.Errorf("rule %d has no name", i+1)
+		}
+
+		if names[rc.Name] {
+			return nil, fmt.Errorf("rule %q is defined twice", rc.Name)
+		}
+		names[rc.Name] = true
+
+		switch rc.Action {
+		case Reject, Flag, Rewrite:
+		default:
+			return nil, fmt.Errorf("invalid action %q of rule %q, want one of REJECT, FLAG and REWRITE", rc.Action, rc.Name)
+		}
+
+		switch rc.Type {
+		case "regex":
+			rule, err := NewRegex(rc.Name, rc.Action, rc.Pattern, rc.Replacement)
+			if err != nil {
+				return nil, err
+			}
+			rules = append(rules, rule)
+
+		case "domains":
+			if len(rc.Domains) == 0 {
+				return nil, fmt.Errorf("rule %q has no domains", rc.Name)
+			}
+			rules = append(rules, NewDomains(rc.Name, rc.Action, rc.Domains, rc.Replacement))
+
+		default:
+			return nil, fmt.Errorf("invalid type %q of rule %q, want one of regex and domains", rc.Type, rc.Name)
+		}
+	}
+
+	allow := map[int32][]string{}
+	for account, domains := range c.Allow {
+		userID, err := strconv.ParseInt(account, 10, 32)
+		if err != nil {
+			return nil, fmt.Errorf("invalid account %q in allow", account)
+		}
+
+		allow[int32(userID)] = normalizeDomains(domains)
+	}
+
+	return New(rules, allow), nil
+}
+
+// Load loads the filter from its configuration file
+func Load(path string) (*Filter, error) {
+	data, err := ioutil.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	return Parse(data)
+}
+
+// Watcher holds the filter of a configuration file, and reloads it whenever the file changes.
+//
+// The file is polled by its modification time, since it is usually mounted (i.e. a ConfigMap),
+// where it is replaced rather than written to. A file that can't be loaded keeps the filter as it is.
+type Watcher struct {
+	path    string
+	onError func(error)
+
+	mu      sync.RWMutex
+	filter  *Filter
+	modTime time.Time
+
+	done chan struct{}
+}
+
+// Watch loads the filter from the configuration file, then checks it for changes every interval.
+// The errors of reloading it are passed to onError.
+func Watch(path string, interval time.Duration, onError func(error)) (*Watcher, error) {
+	w := &Watcher{path: path, onError: onError, done: make(chan struct{})}
+	if err := w.reload(); err != nil {
+		return nil, err
+	}
+
+	go w.run(interval)
+
+	return w, nil
+}
+
+// Filter returns the current filter. A nil watcher has no filter, which lets every message through.
+func (w *Watcher) Filter() *Filter {
+	if w == nil {
+		return nil
+	}
+
+	w.mu.RLock()
+	defer w.mu.RUnlock()
+
+	return w.filter
+}
+
+// Close stops watching the file
+func (w *Watcher) Close() error {
+	close(w.done)
+	return nil
+}
+
+func (w *Watcher) run(interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-w.done:
+			return
+		case <-ticker.C:
+			if err := w.reload(); err != nil && w.onError != nil {
+				w.onError(err)
+			}
+		}
+	}
+}
+
+// reload loads the file if it is modified since it was last loaded.
+// A file that can't be loaded is reported once, until it is modified again.
+func (w *Watcher) reload() error {
+	info, err := os.Stat(w.path)
+	if err != nil {
+		return err
+	}
+
+	w.mu.RLock()
+	unchanged := info.ModTime().Equal(w.modTime)
+	w.mu.RUnlock()
+
+	if unchanged {
+		return nil
+	}
+
+	filter, err := Load(w.path)
+
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	w.modTime = info.ModTime()
+	if err != nil {
+		return fmt.Errorf("%s: %v", w.path, err)
+	}
+
+	w.filter = filter
+	return nil
+}
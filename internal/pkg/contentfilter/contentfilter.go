@@ -0,0 +1,216 @@
+package contentfilter
+
+import (
+	"fmt"
+	"net/url"
+	"regexp"
+	"strings"
+)
+
+// Action is what a rule does to a message that matches it
+type Action string
+
+const (
+	// Reject stops the message from being sent
+	Reject Action = "REJECT"
+
+	// Flag lets the message through, but records that it matched
+	Flag Action = "FLAG"
+
+	// Rewrite replaces what matched, i.e. with "[removed]"
+	Rewrite Action = "REWRITE"
+)
+
+// Rule checks the content of a message. Adding a rule is implementing the interface,
+// and registering its type in the configuration file (see Parse).
+//
+// The allowed domains are the ones the account of the sender is allowed to send, whatever the rules say.
+type Rule interface {
+	Name() string
+	Action() Action
+
+	// Match returns the parts of the content that match, none if it doesn't match
+	Match(content string, allowed []string) []string
+
+	// Rewrite returns the content with the matched parts replaced
+	Rewrite(content string, allowed []string) string
+}
+
+// Decision is a rule that matched a message, and what it did
+type Decision struct {
+	Rule    string
+	Action  Action
+	Matches []string
+}
+
+// Result is the outcome of filtering a message: its content (rewritten, if any rule did),
+// and the decisions of the rules that matched it, in order.
+// If it is rejected, the rule that rejected it is the last decision.
+type Result struct {
+	Content   string
+	Decisions []Decision
+	Rejected  bool
+}
+
+// Filter is a pipeline of rules that every message goes through in order.
+// A rewrite changes the content the rules after it see, and a reject stops the pipeline.
+type Filter struct {
+	rules []Rule
+	allow map[int32][]string
+}
+
+// New creates a filter of the given rules, and the domains allowed for every account (by user id)
+func New(rules []Rule, allow map[int32][]string) *Filter {
+	return &Filter{rules: rules, allow: allow}
+}
+
+// Apply filters the content of a message sent by the given account.
+// A nil filter lets every message through as is.
+func (f *Filter) Apply(content string, account int32) *Result {
+	res := &Result{Content: content}
+	if f == nil {
+		return res
+	}
+
+	allowed := f.allow[account]
+	for _, rule := range f.rules {
+		matches := rule.Match(res.Content, allowed)
+		if len(matches) == 0 {
+			continue
+		}
+
+		res.Decisions = append(res.Decisions, Decision{Rule: rule.Name(), Action: rule.Action(), Matches: matches})
+
+		switch rule.Action() {
+		case Reject:
+			res.Rejected = true
+			return res
+		case Rewrite:
+			res.Content = rule.Rewrite(res.Content, allowed)
+		}
+	}
+
+	return res
+}
+
+// regexRule matches a regular expression, i.e. banned words or a pattern the carriers reject
+type regexRule struct {
+	name        string
+	action      Action
+	re          *regexp.Regexp
+	replacement string
+}
+
+// NewRegex creates a rule that matches the given regular expression.
+// A rewrite replaces every match with the replacement, which can refer to the groups, i.e. "$1".
+func NewRegex(name string, action Action, pattern, replacement string) (Rule, error) {
+	re, err := regexp.Compile(pattern)
+	if err != nil {
+		return nil, fmt.Errorf("invalid pattern of rule %q: %v", name, err)
+	}
+
+	return &regexRule{name: name, action: action, re: re, replacement: replacement}, nil
+}
+
+func (r *regexRule) Name() string {
+	return r.name
+}
+
+func (r *regexRule) Action() Action {
+	return r.action
+}
+
+func (r *regexRule) Match(content string, allowed []string) []string {
+	return r.re.FindAllString(content, -1)
+}
+
+func (r *regexRule) Rewrite(content string, allowed []string) string {
+	return r.re.ReplaceAllString(content, r.replacement)
+}
+
+// urlPattern matches the links in a message, with or without a scheme, i.e. "https://bit.ly/x" or "bit.ly/x"
+var urlPattern = regexp.MustCompile(`(?i)\b(?:https?://)?(?:[a-z0-9](?:[a-z0-9-]*[a-z0-9])?\.)+[a-z]{2,}(?::\d+)?(?:/[^\s]*)?`)
+
+// domainsRule matches the links to a list of domains (a deny list), i.e. phishing or link shorteners
+type domainsRule struct {
+	name        string
+	action      Action
+	domains     []string
+	replacement string
+}
+
+// NewDomains creates a rule that matches the links to the given domains, or any of their subdomains.
+// A rewrite replaces every link with the replacement.
+func NewDomains(name string, action Action, domains []string, replacement string) Rule {
+	return &domainsRule{name: name, action: action, domains: normalizeDomains(domains), replacement: replacement}
+}
+
+func (r *domainsRule) Name() string {
+	return r.name
+}
+
+func (r *domainsRule) Action() Action {
+	return r.action
+}
+
+func (r *domainsRule) Match(content string, allowed []string) []string {
+	var matches []string
+	for _, link := range urlPattern.FindAllString(content, -1) {
+		if r.denied(link, allowed) {
+			matches = append(matches, link)
+		}
+	}
+
+	return matches
+}
+
+func (r *domainsRule) Rewrite(content string, allowed []string) string {
+	return urlPattern.ReplaceAllStringFunc(content, func(link string) string {
+		if r.denied(link, allowed) {
+			return r.replacement
+		}
+
+		return link
+	})
+}
+
+// denied tells if the link is to a domain in the list, and not allowed for the account
+func (r *domainsRule) denied(link string, allowed []string) bool {
+	host := hostOf(link)
+	return inDomains(host, r.domains) && !inDomains(host, allowed)
+}
+
+// hostOf returns the host of a link, in lower case and without the port
+func hostOf(link string) string {
+	if !strings.Contains(link, "://") {
+		link = "http://" + link
+	}
+
+	u, err := url.Parse(link)
+	if err != nil {
+		return ""
+	}
+
+	return strings.ToLower(u.Hostname())
+}
+
+// normalizeDomains returns the domains in lower case, the way the hosts are compared to them
+func normalizeDomains(domains []string) []string {
+	res := make([]string, len(domains))
+	for i, d := range domains {
+		res[i] = strings.ToLower(strings.TrimSpace(d))
+	}
+
+	return res
+}
+
+// inDomains tells if the host is one of the domains, or a subdomain of any of them
+func inDomains(host string, domains []string) bool {
+	for _, d := range domains {
+		if host == d || strings.HasSuffix(host, "."+d) {
+			return true
+		}
+	}
+
+	return false
+}
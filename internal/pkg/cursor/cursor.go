@@ -0,0 +1,38 @@
+package cursor
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+)
+
+// ErrInvalid is returned when a cursor can't be decoded
+var ErrInvalid = errors.New("invalid cursor")
+
+// Encode encodes the position of the last item of a page into an opaque cursor.
+//
+// The cursor is returned to the client, who passes it back to get the next page.
+// It is the position of the last item rather than an offset, and so pages don't
+// shift when items are inserted or deleted in between (known as keyset pagination).
+func Encode(position interface{}) (string, error) {
+	buf, err := json.Marshal(position)
+	if err != nil {
+		return "", err
+	}
+
+	return base64.RawURLEncoding.EncodeToString(buf), nil
+}
+
+// Decode decodes the given cursor into the position (pointer) it was encoded from
+func Decode(cursor string, position interface{}) error {
+	buf, err := base64.RawURLEncoding.DecodeString(cursor)
+	if err != nil {
+		return ErrInvalid
+	}
+
+	if err := json.Unmarshal(buf, position); err != nil {
+		return ErrInvalid
+	}
+
+	return nil
+}
@@ -0,0 +1,271 @@
+package gsm
+
+import (
+	"bytes"
+	"strings"
+	"unicode/utf16"
+)
+
+// Encoding is the character encoding of an SMS
+type Encoding int
+
+const (
+	// GSM7 is the GSM 03.38 7-bit default alphabet, a character is a septet (7 bits).
+	// Characters in the extension table take two septets: an escape and the character.
+	GSM7 Encoding = iota
+
+	// UCS2 is used when any character is not in GSM7, a character is 16 bits.
+	// Characters outside the Basic Multilingual Plane (i.e. emojis) take two (a surrogate pair).
+	UCS2
+)
+
+// The max length of an SMS, in septets for GSM7 or 16-bit units for UCS2.
+//
+// A concatenated SMS has a User Data Header of 6 bytes (the reference number,
+// and the count and order of the segments), and so every segment has less room.
+const (
+	GSM7SingleLength  = 160
+	GSM7SegmentLength = 153
+	UCS2SingleLength  = 70
+	UCS2SegmentLength = 67
+)
+
+// String returns the name of the encoding
+func (e Encoding) String() string {
+	if e == UCS2 {
+		return "UCS2"
+	}
+
+	return "GSM7"
+}
+
+// basic is the GSM 03.38 default alphabet, in the order of its code points
+const basic = "@£$¥èéùìòÇ\nØø\rÅåΔ_ΦΓΛΩΠΨΣΘΞ\x1bÆæßÉ !\"#¤%&'()*+,-./0123456789:;<=>?" +
+	"¡ABCDEFGHIJKLMNOPQRSTUVWXYZÄÖÑÜ§¿abcdefghijklmnopqrstuvwxyzäöñüà"
+
+// extension is the GSM 03.38 extension table, its characters are escaped
+const extension = "\f^{}\\[~]|€"
+
+// Detect returns GSM7 if all the characters of the text are in the GSM 03.38 alphabet,
+// UCS2 otherwise
+func Detect(text string) Encoding {
+	for _, r := range text {
+		if r == '\x1b' || (!strings.ContainsRune(basic, r) && !strings.ContainsRune(extension, r)) {
+			return UCS2
+		}
+	}
+
+	return GSM7
+}
+
+// Length returns the length of the text in the given encoding,
+// in septets for GSM7 or 16-bit units for UCS2
+func Length(text string, enc Encoding) int {
+	length := 0
+	for _, r := range text {
+		length += runeLength(r, enc)
+	}
+
+	return length
+}
+
+// Split detects the encoding of the text, and splits it into the segments of a concatenated SMS,
+// or returns the text as is if it fits in a single SMS.
+//
+// A character is never split across two segments: neither an escaped GSM7 character,
+// nor a UCS2 surrogate pair. And so a segment could be a unit shorter than the max.
+func Split(text string) (Encoding, []string) {
+	enc := Detect(text)
+
+	single, segment := GSM7SingleLength, GSM7SegmentLength
+	if enc == UCS2 {
+		single, segment = UCS2SingleLength, UCS2SegmentLength
+	}
+
+	if Length(text, enc) <= single {
+		return enc, []string{text}
+	}
+
+	segments := []string{}
+	start, length := 0, 0
+	for i, r := range text {
+		n := runeLength(r, enc)
+		if length+n > segment {
+			segments = append(segments, text[start:i])
+			start, length = i, 0
+		}
+
+		length += n
+	}
+
+	return enc, append(segments, text[start:])
+}
+
+// UDH returns the User Data Header of a segment of a concatenated SMS.
+//
+// The reference number is the same for all the segments of an SMS, and so the phone
+// puts them together. The total and the sequence (starting from 1) are at most 255.
+func UDH(ref uint8, total, seq int) []byte {
+	// length of the header, then the information element: id (8-bit reference), its length, and data
+	return []byte{0x05, 0x00, 0x03, ref, byte(total), byte(seq)}
+}
+
+// Encode returns the bytes of the text in the given encoding: a septet per byte for GSM7
+// (unpacked, as SMPP carries it), or 16-bit big-endian units for UCS2.
+// A character that is not in GSM7 is encoded as "?".
+func Encode(text string, enc Encoding) []byte {
+	b := []byte{}
+	if enc == UCS2 {
+		for _, u := range utf16.Encode([]rune(text)) {
+			b = append(b, byte(u>>8), byte(u))
+		}
+
+		return b
+	}
+
+	for _, r := range text {
+		if i := indexRune(extension, r); i >= 0 {
+			b = append(b, 0x1b, extensionCodes[i])
+		} else if i := indexRune(basic, r); i >= 0 {
+			b = append(b, byte(i))
+		} else {
+			b = append(b, '?')
+		}
+	}
+
+	return b
+}
+
+// Decode returns the text of the bytes in the given encoding, the reverse of Encode
+func Decode(b []byte, enc Encoding) string {
+	if enc == UCS2 {
+		units := make([]uint16, len(b)/2)
+		for i := range units {
+			units[i] = uint16(b[2*i])<<8 | uint16(b[2*i+1])
+		}
+
+		return string(utf16.Decode(units))
+	}
+
+	var sb strings.Builder
+	alphabet := []rune(basic)
+	for i := 0; i < len(b); i++ {
+		if b[i] == 0x1b && i+1 < len(b) {
+			if j := bytes.IndexByte(extensionCodes, b[i+1]); j >= 0 {
+				sb.WriteRune([]rune(extension)[j])
+				i++
+				continue
+			}
+		}
+
+		if int(b[i]) < len(alphabet) {
+			sb.WriteRune(alphabet[b[i]])
+		} else {
+			sb.WriteByte('?')
+		}
+	}
+
+	return sb.String()
+}
+
+// extensionCodes are the code points of the extension table (after the escape), in its order
+var extensionCodes = []byte{0x0a, 0x14, 0x28, 0x29, 0x2f, 0x3c, 0x3d, 0x3e, 0x40, 0x65}
+
+// indexRune returns the code point of the rune in the alphabet, or -1 if it is not in it
+func indexRune(alphabet string, r rune) int {
+	i := 0
+	for _, c := range alphabet {
+		if c == r {
+			return i
+		}
+
+		i++
+	}
+
+	return -1
+}
+
+func runeLength(r rune, enc Encoding) int {
+	// outside the Basic Multilingual Plane, it is a surrogate pair
+	if enc == UCS2 {
+		if r > 0xFFFF {
+			return 2
+		}
+
+		return 1
+	}
+
+	if strings.ContainsRune(extension, r) {
+		return 2
+	}
+
+	return 1
+}
+
+// Substitution is a character replaced by Transliterate, and how many times
+type Substitution struct {
+	From  string
+	To    string
+	Count int
+}
+
+// lookalikes maps the characters that are not in GSM7 to their GSM7 look-alikes
+var lookalikes = map[rune]string{
+	// quotes and apostrophes
+	'‘': "'", '’': "'", '‚': "'", '‛': "'", '′': "'", '`': "'", '´': "'", '‹': "'", '›': "'",
+	'“': "\"", '”': "\"", '„': "\"", '‟': "\"", '″': "\"", '«': "\"", '»': "\"",
+	// dashes and punctuation
+	'‐': "-", '‑': "-", '‒': "-", '–': "-", '—': "-", '―': "-", '−': "-",
+	'\u00a0': " ", '\u2002': " ", '\u2003': " ", '\u2009': " ", '\u200a': " ", '\u202f': " ", // spaces
+	'…': "...", '•': "-", '·': ".",
+	// accented letters (the ones in GSM7 are kept, i.e. "é")
+	'á': "a", 'â': "a", 'ã': "a", 'ā': "a", 'Á': "A", 'Â': "A", 'Ã': "A", 'À': "A",
+	'ê': "e", 'ë': "e", 'ē': "e", 'È': "E", 'Ê': "E", 'Ë': "E",
+	'í': "i", 'î': "i", 'ï': "i", 'Í': "I", 'Î': "I", 'Ï': "I", 'Ì': "I",
+	'ó': "o", 'ô': "o", 'õ': "o", 'Ó': "O", 'Ô': "O", 'Õ': "O", 'Ò': "O",
+	'ú': "u", 'û': "u", 'Ú': "U", 'Û': "U", 'Ù': "U",
+	'ç': "Ç", 'ý': "y", 'ÿ': "y", 'Ý': "Y",
+}
+
+// Transliterate replaces the characters that are not in GSM7 with their GSM7 look-alikes
+// (i.e. curly quotes with straight ones), so that the text is sent in GSM7 rather than UCS2,
+// which fits more than twice the characters in an sms. It returns the text and the substitutions.
+//
+// If any character has no look-alike (i.e. an emoji), the text has to be sent in UCS2 anyway,
+// and so it is returned as is, without any substitutions.
+func Transliterate(text string) (string, []Substitution) {
+	if Detect(text) == GSM7 {
+		return text, nil
+	}
+
+	var b strings.Builder
+	counts := map[rune]int{}
+	order := []rune{}
+
+	for _, r := range text {
+		to, ok := lookalikes[r]
+		if !ok {
+			b.WriteRune(r)
+			continue
+		}
+
+		if counts[r] == 0 {
+			order = append(order, r)
+		}
+
+		counts[r]++
+		b.WriteString(to)
+	}
+
+	result := b.String()
+	if Detect(result) != GSM7 {
+		return text, nil
+	}
+
+	substitutions := make([]Substitution, len(order))
+	for i, r := range order {
+		substitutions[i] = Substitution{From: string(r), To: lookalikes[r], Count: counts[r]}
+	}
+
+	return result, substitutions
+}
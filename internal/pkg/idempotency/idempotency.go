@@ -0,0 +1,299 @@
+package idempotency
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"time"
+
+	"github.com/OmarElGabry/go-textnow/internal/pkg/config"
+	"github.com/OmarElGabry/go-textnow/internal/pkg/logger"
+
+	"github.com/golang/protobuf/proto"
+	"github.com/golang/protobuf/ptypes"
+	anypb "github.com/golang/protobuf/ptypes/any"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+
+	spb "google.golang.org/genproto/googleapis/rpc/status"
+)
+
+// MetadataKey is the metadata the idempotency key is read from,
+// i.e. "Idempotency-Key" header of a request to the gateway.
+const MetadataKey = "idempotency-key"
+
+// ReplayedKey is the header metadata set on the response of a retry, which is the response of the first request
+const ReplayedKey = "idempotent-replayed"
+
+const (
+	// DefaultLockTimeout is how long a request in progress is locked,
+	// it should be longer than any of the requests of the methods take.
+	DefaultLockTimeout = time.Minute
+
+	// DefaultTTL is how long a key is kept, a retry after that is handled as a new request
+	DefaultTTL = 24 * time.Hour
+)
+
+// TTLFromEnv returns the idempotency window of the keys, "IDEMPOTENCY_KEY_TTL" (i.e. "24h"),
+// or DefaultTTL if it isn't set.
+func TTLFromEnv() (time.Duration, error) {
+	config, err := config.Load()
+	if err != nil {
+		return 0, err
+	}
+
+	if config("IDEMPOTENCY_KEY_TTL") == "" {
+		return DefaultTTL, nil
+	}
+
+	ttl, err := time.ParseDuration(config("IDEMPOTENCY_KEY_TTL"))
+	if err != nil {
+		return 0, fmt.Errorf("invalid IDEMPOTENCY_KEY_TTL: %v", err)
+	}
+
+	return ttl, nil
+}
+
+// ErrInProgress is returned to a retry while the first request is still in progress
+var ErrInProgress = status.Error(codes.Aborted, "Request with the same idempotency key is in progress")
+
+// ErrConflict is returned to a request that reuses the key of another one with a different payload
+var ErrConflict = status.Error(codes.AlreadyExists, "Idempotency key is reused with a different request")
+
+// State is the state of a request by its idempotency key
+type State string
+
+const (
+	// InProgress is a request that is being handled
+	InProgress State = "IN_PROGRESS"
+
+	// Completed is a request that has been handled, and its response or error is recorded
+	Completed State = "COMPLETED"
+)
+
+// Record is a request by its idempotency key, and the hash of its payload (see Hash).
+// Once it is completed, it has either the response (a google.protobuf.Any)
+// or the error (a google.rpc.Status) of the request, marshaled.
+type Record struct {
+	Key         string `bson:"_id" json:"key"`
+	RequestHash string `bson:"requestHash,omitempty" json:"requestHash,omitempty"`
+	State       State  `bson:"state" json:"state"`
+	Response    []byte `bson:"response,omitempty" json:"response,omitempty"`
+	Error       []byte `bson:"error,omitempty" json:"error,omitempty"`
+}
+
+// Store records the requests by their idempotency keys.
+//
+// A request in progress is locked for a while, and if it isn't completed by then
+// (i.e. the replica has crashed), a retry is handled as a new request.
+// A key expires after a while (the ttl of the store), and so does its record.
+type Store interface {
+	// Begin records the key as in progress along with the hash of the request,
+	// unless it is recorded already (or locked), in which case it returns the record and false.
+	Begin(ctx context.Context, key, requestHash string) (*Record, bool, error)
+
+	// Complete records the response or error of the request
+	Complete(ctx context.Context, rec *Record) error
+
+	// Release deletes the key, and so a retry is handled as a new request
+	Release(ctx context.Context, key string) error
+}
+
+// KeyFunc reads the idempotency key of a request, i.e. a field of it.
+// It returns an empty string if the request has none.
+type KeyFunc func(req interface{}) string
+
+// Interceptor makes the RPCs that opt into it idempotent: a retry of a request,
+// by the same idempotency key, gets the response or error of the first one, rather than being handled again.
+// A request that reuses the key with a different payload fails with ErrConflict.
+//
+// The key is read from the metadata (see MetadataKey), or else from the request by the KeyFunc of the method.
+// A request without a key is handled as usual.
+type Interceptor struct {
+	store   Store
+	methods map[string]KeyFunc
+}
+
+// New creates an interceptor of the given methods (by their full name, i.e. "/sms.SMSService/SendOne"),
+// with the function that reads the key from the request, nil if it is only read from the metadata.
+func New(store Store, methods map[string]KeyFunc) *Interceptor {
+	return &Interceptor{store: store, methods: methods}
+}
+
+// Unary returns the unary server interceptor.
+//
+// An error is recorded only if it is of the request itself (OutOfRange or Unimplemented),
+// and so it is returned again on a retry. InvalidArgument isn't, since it could be of the state
+// the request is checked against (i.e. a phone number that isn't reserved), rather than of the request.
+//
+// Any other error (i.e. NotFound or Unavailable) could go away, and so the key is released,
+// and a retry is handled again.
+func (i *Interceptor) Unary() grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+		keyFunc, ok := i.methods[info.FullMethod]
+		if !ok {
+			return handler(ctx, req)
+		}
+
+		key := fromMetadata(ctx)
+		if key == "" && keyFunc != nil {
+			key = keyFunc(req)
+		}
+
+		if key == "" {
+			return handler(ctx, req)
+		}
+
+		// the same key of different methods is a different request
+		key = info.FullMethod + ":" + key
+
+		msg, ok := req.(proto.Message)
+		if !ok {
+			return handler(ctx, req)
+		}
+
+		hash, err := Hash(msg)
+		if err != nil {
+			return nil, status.Error(codes.Internal, "Internal error "+err.Error())
+		}
+
+		rec, started, err := i.store.Begin(ctx, key, hash)
+		if err != nil {
+			return nil, status.Error(codes.Internal, "Internal error "+err.Error())
+		}
+
+		if !started {
+			// the records stored before the hash was recorded have none
+			if rec.RequestHash != "" && rec.RequestHash != hash {
+				return nil, ErrConflict
+			}
+
+			return replay(ctx, rec)
+		}
+
+		resp, err := handler(ctx, req)
+		if err != nil && !final(err) {
+			if rErr := i.store.Release(ctx, key); rErr != nil {
+				logger.Error(fmt.Sprintf("Failed to release idempotency key %s error: %v", key, rErr))
+			}
+
+			return resp, err
+		}
+
+		// the request is done anyway, if it isn't recorded, a retry is handled again once the lock expires
+		rec, rErr := record(key, hash, resp, err)
+		if rErr == nil {
+			rErr = i.store.Complete(ctx, rec)
+		}
+
+		if rErr != nil {
+			logger.Error(fmt.Sprintf("Failed to complete idempotency key %s error: %v", key, rErr))
+		}
+
+		return resp, err
+	}
+}
+
+// Hash returns the hash (SHA-256, hex) of the payload of a request. The maps are encoded
+// in the order of their keys, and so the same payload has the same hash.
+func Hash(req proto.Message) (string, error) {
+	var buf proto.Buffer
+	buf.SetDeterministic(true)
+	if err := buf.Marshal(req); err != nil {
+		return "", err
+	}
+
+	sum := sha256.Sum256(buf.Bytes())
+	return hex.EncodeToString(sum[:]), nil
+}
+
+// fromMetadata reads the idempotency key from the metadata of the request
+func fromMetadata(ctx context.Context) string {
+	md, ok := metadata.FromIncomingContext(ctx)
+	if !ok {
+		return ""
+	}
+
+	if values := md.Get(MetadataKey); len(values) > 0 {
+		return values[0]
+	}
+
+	return ""
+}
+
+// final tells if the error is of the request itself, and so it is the same on a retry
+func final(err error) bool {
+	switch status.Code(err) {
+	case codes.OutOfRange, codes.Unimplemented:
+		return true
+	}
+
+	return false
+}
+
+// record returns the completed record of the response or error of a request
+func record(key, requestHash string, resp interface{}, err error) (*Record, error) {
+	rec := &Record{Key: key, RequestHash: requestHash, State: Completed}
+
+	if err != nil {
+		data, mErr := proto.Marshal(status.Convert(err).Proto())
+		if mErr != nil {
+			return nil, mErr
+		}
+
+		rec.Error = data
+		return rec, nil
+	}
+
+	msg, ok := resp.(proto.Message)
+	if !ok {
+		return nil, fmt.Errorf("response %T is not a proto message", resp)
+	}
+
+	packed, err := ptypes.MarshalAny(msg)
+	if err != nil {
+		return nil, err
+	}
+
+	data, err := proto.Marshal(packed)
+	if err != nil {
+		return nil, err
+	}
+
+	rec.Response = data
+	return rec, nil
+}
+
+// replay returns the recorded response or error of the first request
+func replay(ctx context.Context, rec *Record) (interface{}, error) {
+	if rec.State != Completed {
+		return nil, ErrInProgress
+	}
+
+	// the client can tell it is the response of the first request
+	grpc.SetHeader(ctx, metadata.Pairs(ReplayedKey, "true"))
+
+	if rec.Error != nil {
+		var st spb.Status
+		if err := proto.Unmarshal(rec.Error, &st); err != nil {
+			return nil, status.Error(codes.Internal, "Internal error "+err.Error())
+		}
+
+		return nil, status.ErrorProto(&st)
+	}
+
+	var packed anypb.Any
+	if err := proto.Unmarshal(rec.Response, &packed); err != nil {
+		return nil, status.Error(codes.Internal, "Internal error "+err.Error())
+	}
+
+	var resp ptypes.DynamicAny
+	if err := ptypes.UnmarshalAny(&packed, &resp); err != nil {
+		return nil, status.Error(codes.Internal, "Internal error "+err.Error())
+	}
+
+	return resp.Message, nil
+}
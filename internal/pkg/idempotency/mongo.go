@@ -0,0 +1,113 @@
+package idempotency
+
+import (
+	"context"
+	"time"
+
+	"github.com/OmarElGabry/go-textnow/internal/pkg/mongodb"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// MongoStore stores the records in a MongoDB collection, a document for every key.
+//
+// A document expires at its "expiresAt", the ttl after the key is first used.
+// The collection must have a TTL index on it (see Index), which MongoDB deletes the expired documents by.
+type MongoStore struct {
+	c           *mongo.Collection
+	lockTimeout time.Duration
+	ttl         time.Duration
+}
+
+// Index is the TTL index of the collection of a MongoStore
+var Index = mongo.IndexModel{
+	Keys:    bson.D{{Key: "expiresAt", Value: 1}},
+	Options: options.Index().SetExpireAfterSeconds(0),
+}
+
+// NewMongoStore creates a store of the given collection, where a request in progress is locked for lockTimeout,
+// and a key is kept for ttl.
+func NewMongoStore(c *mongo.Collection, lockTimeout, ttl time.Duration) *MongoStore {
+	return &MongoStore{c: c, lockTimeout: lockTimeout, ttl: ttl}
+}
+
+// Begin inserts the key as in progress. The key is the id of the document,
+// and so only one of the concurrent requests of the same key inserts it.
+//
+// MongoDB deletes the expired documents once a minute, and so an expired key that isn't deleted yet
+// is taken over as a new request.
+func (s *MongoStore) Begin(ctx context.Context, key, requestHash string) (*Record, bool, error) {
+	now := time.Now().UTC()
+	_, err := s.c.InsertOne(ctx, bson.M{
+		"_id":         key,
+		"requestHash": requestHash,
+		"state":       InProgress,
+		"lockedUntil": now.Add(s.lockTimeout),
+		"expiresAt":   now.Add(s.ttl),
+		"createdAt":   now,
+		"updatedAt":   now,
+	})
+
+	if err == nil {
+		return nil, true, nil
+	}
+
+	if !mongodb.IsDuplicateKey(err) {
+		return nil, false, err
+	}
+
+	// take over a request in progress whose lock has expired, or a key that has expired
+	res, err := s.c.UpdateOne(ctx,
+		bson.M{"_id": key, "$or": bson.A{
+			bson.M{"state": InProgress, "lockedUntil": bson.M{"$lt": now}},
+			bson.M{"expiresAt": bson.M{"$lt": now}},
+		}},
+		bson.M{
+			"$set": bson.M{
+				"requestHash": requestHash,
+				"state":       InProgress,
+				"lockedUntil": now.Add(s.lockTimeout),
+				"expiresAt":   now.Add(s.ttl),
+				"createdAt":   now,
+				"updatedAt":   now,
+			},
+			"$unset": bson.M{"response": "", "error": ""},
+		})
+	if err != nil {
+		return nil, false, err
+	}
+
+	if res.ModifiedCount == 1 {
+		return nil, true, nil
+	}
+
+	var rec Record
+	if err := s.c.FindOne(ctx, bson.M{"_id": key}).Decode(&rec); err != nil {
+		return nil, false, err
+	}
+
+	return &rec, false, nil
+}
+
+// Complete stores the response or error of the request, and unlocks it
+func (s *MongoStore) Complete(ctx context.Context, rec *Record) error {
+	_, err := s.c.UpdateOne(ctx, bson.M{"_id": rec.Key}, bson.M{
+		"$set": bson.M{
+			"state":     rec.State,
+			"response":  rec.Response,
+			"error":     rec.Error,
+			"updatedAt": time.Now().UTC(),
+		},
+		"$unset": bson.M{"lockedUntil": ""},
+	})
+
+	return err
+}
+
+// Release deletes the document of the key
+func (s *MongoStore) Release(ctx context.Context, key string) error {
+	_, err := s.c.DeleteOne(ctx, bson.M{"_id": key})
+	return err
+}
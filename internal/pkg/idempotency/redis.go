@@ -0,0 +1,77 @@
+package idempotency
+
+import (
+	"context"
+	"encoding/json"
+	"time"
+
+	"github.com/OmarElGabry/go-textnow/internal/pkg/redis"
+)
+
+// RedisStore stores the records in Redis, a key for every idempotency key.
+// A completed record expires after the ttl, and so does its key.
+type RedisStore struct {
+	cache       *redis.Cache
+	lockTimeout time.Duration
+	ttl         time.Duration
+}
+
+// NewRedisStore creates a store where a request in progress is locked for lockTimeout,
+// and a completed one is kept for ttl.
+func NewRedisStore(cache *redis.Cache, lockTimeout, ttl time.Duration) *RedisStore {
+	return &RedisStore{cache: cache, lockTimeout: lockTimeout, ttl: ttl}
+}
+
+func redisKey(key string) string {
+	return "idempotency-" + key
+}
+
+// Begin sets the key as in progress if it doesn't exist (SET NX), and so only one of
+// the concurrent requests of the same key sets it. The lock is the expiry of the key.
+func (s *RedisStore) Begin(ctx context.Context, key, requestHash string) (*Record, bool, error) {
+	data, err := json.Marshal(&Record{Key: key, RequestHash: requestHash, State: InProgress})
+	if err != nil {
+		return nil, false, err
+	}
+
+	set, err := s.cache.SetNX(redisKey(key), data, s.lockTimeout).Result()
+	if err != nil {
+		return nil, false, err
+	}
+
+	if set {
+		return nil, true, nil
+	}
+
+	data, err = s.cache.Get(redisKey(key)).Bytes()
+	if err == s.cache.ErrNotExists {
+		// it has expired or been released since, a retry of the client would begin it
+		return &Record{Key: key, RequestHash: requestHash, State: InProgress}, false, nil
+	}
+
+	if err != nil {
+		return nil, false, err
+	}
+
+	var rec Record
+	if err := json.Unmarshal(data, &rec); err != nil {
+		return nil, false, err
+	}
+
+	return &rec, false, nil
+}
+
+// Complete stores the response or error of the request for the ttl
+func (s *RedisStore) Complete(ctx context.Context, rec *Record) error {
+	data, err := json.Marshal(rec)
+	if err != nil {
+		return err
+	}
+
+	return s.cache.Set(redisKey(rec.Key), data, s.ttl).Err()
+}
+
+// Release deletes the key
+func (s *RedisStore) Release(ctx context.Context, key string) error {
+	return s.cache.Del(redisKey(key)).Err()
+}
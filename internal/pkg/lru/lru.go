@@ -0,0 +1,167 @@
+package lru
+
+import (
+	"container/list"
+	"sync"
+	"time"
+)
+
+// Cache is an in-process cache with a bounded size, where entries expire after a TTL.
+// When full, the least recently used entry is evicted. It is safe for concurrent use.
+type Cache struct {
+	mu    sync.Mutex
+	size  int
+	ttl   time.Duration
+	ll    *list.List // front is the most recently used
+	items map[string]*list.Element
+
+	// in-flight loads of GetOrLoad by key
+	calls map[string]*call
+}
+
+type entry struct {
+	key       string
+	value     interface{}
+	expiresAt time.Time
+}
+
+// call is an in-flight (or completed) load that concurrent callers wait for
+type call struct {
+	wg    sync.WaitGroup
+	value interface{}
+	err   error
+
+	// set when the key is removed while loading,
+	// and so the loaded value might be stale and mustn't be cached.
+	stale bool
+}
+
+// New creates and returns a cache of at most size entries, where entries expire after ttl
+func New(size int, ttl time.Duration) *Cache {
+	return &Cache{
+		size:  size,
+		ttl:   ttl,
+		ll:    list.New(),
+		items: map[string]*list.Element{},
+		calls: map[string]*call{},
+	}
+}
+
+// Get returns the value of the given key if it exists and hasn't expired
+func (c *Cache) Get(key string) (interface{}, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	return c.get(key)
+}
+
+// Add adds or updates the value of the given key
+func (c *Cache) Add(key string, value interface{}) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.add(key, value)
+}
+
+// Remove removes the given key.
+//
+// If the key is being loaded by GetOrLoad, the loaded value won't be cached,
+// and the next call will load it again.
+func (c *Cache) Remove(key string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if el, ok := c.items[key]; ok {
+		c.ll.Remove(el)
+		delete(c.items, key)
+	}
+
+	if cl, ok := c.calls[key]; ok {
+		cl.stale = true
+		delete(c.calls, key)
+	}
+}
+
+// Len returns the number of entries, including the expired ones that haven't been evicted yet
+func (c *Cache) Len() int {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	return c.ll.Len()
+}
+
+// GetOrLoad returns the value of the given key, or loads it by calling load on a cache miss.
+//
+// Concurrent calls for the same key are coalesced: only one of them calls load,
+// while the rest wait for it and share its result. And so, a burst of requests
+// for the same key results in one call to the backend. Errors are not cached.
+func (c *Cache) GetOrLoad(key string, load func() (interface{}, error)) (interface{}, error) {
+	c.mu.Lock()
+	if value, ok := c.get(key); ok {
+		c.mu.Unlock()
+		return value, nil
+	}
+
+	if cl, ok := c.calls[key]; ok {
+		c.mu.Unlock()
+		cl.wg.Wait()
+		return cl.value, cl.err
+	}
+
+	cl := &call{}
+	cl.wg.Add(1)
+	c.calls[key] = cl
+	c.mu.Unlock()
+
+	cl.value, cl.err = load()
+
+	c.mu.Lock()
+	if !cl.stale {
+		delete(c.calls, key)
+		if cl.err == nil {
+			c.add(key, cl.value)
+		}
+	}
+	c.mu.Unlock()
+
+	cl.wg.Done()
+	return cl.value, cl.err
+}
+
+// get must be called while holding the lock
+func (c *Cache) get(key string) (interface{}, bool) {
+	el, ok := c.items[key]
+	if !ok {
+		return nil, false
+	}
+
+	e := el.Value.(*entry)
+	if time.Now().After(e.expiresAt) {
+		c.ll.Remove(el)
+		delete(c.items, key)
+		return nil, false
+	}
+
+	c.ll.MoveToFront(el)
+	return e.value, true
+}
+
+// add must be called while holding the lock
+func (c *Cache) add(key string, value interface{}) {
+	expiresAt := time.Now().Add(c.ttl)
+
+	if el, ok := c.items[key]; ok {
+		el.Value = &entry{key, value, expiresAt}
+		c.ll.MoveToFront(el)
+		return
+	}
+
+	c.items[key] = c.ll.PushFront(&entry{key, value, expiresAt})
+
+	// evict the least recently used
+	for c.ll.Len() > c.size {
+		el := c.ll.Back()
+		c.ll.Remove(el)
+		delete(c.items, el.Value.(*entry).key)
+	}
+}
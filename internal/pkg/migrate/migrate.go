@@ -0,0 +1,252 @@
+package migrate
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+)
+
+// ErrSchemaTooOld is returned by Check when there are migrations that haven't been applied yet
+var ErrSchemaTooOld = errors.New("database schema is too old, run the pending migrations first")
+
+// Migration is a numbered schema change.
+//
+// Up has the SQL statements that apply the change, while Down has the ones that revert it.
+// Statements are executed one by one, since the driver doesn't allow multiple statements in one call.
+//
+// Exists is an optional query that returns a count, non-zero if the change is in the database already
+// (i.e. it was created before the migration was introduced). If so, Up is skipped, but the migration is still recorded as applied.
+type Migration struct {
+	Version int
+	Name    string
+	Up      []string
+	Down    []string
+	Exists  string
+}
+
+// Migrator applies and reverts migrations, and keeps track of the applied ones
+// in a table, by default "schema_migrations".
+type Migrator struct {
+	// Table is the table of the applied migrations
+	Table string
+
+	db         *sql.DB
+	migrations []Migration
+}
+
+// New creates and returns a Migrator for the given migrations.
+//
+// Migrations must be numbered in order starting from 1, with no gaps.
+// They are embedded in the binary, and so the binary knows the schema version it expects.
+func New(db *sql.DB, migrations []Migration) (*Migrator, error) {
+	for i, m := range migrations {
+		if m.Version != i+1 {
+			return nil, fmt.Errorf("migration %q has version %d; want %d", m.Name, m.Version, i+1)
+		}
+	}
+
+	return &Migrator{Table: "schema_migrations", db: db, migrations: migrations}, nil
+}
+
+// Latest returns the version of the last migration, the version the binary expects
+func (m *Migrator) Latest() int {
+	return len(m.migrations)
+}
+
+// Version returns the version of the last applied migration, or 0 if none has been applied.
+//
+// It only reads the database: a database without the table of the applied migrations is at version 0.
+func (m *Migrator) Version(ctx context.Context) (int, error) {
+	var count int
+	row := m.db.QueryRowContext(ctx, "SELECT COUNT(*) FROM information_schema.tables "+
+		"WHERE table_schema = DATABASE() AND table_name = ?", m.Table)
+	if err := row.Scan(&count); err != nil {
+		return 0, err
+	}
+
+	if count == 0 {
+		return 0, nil
+	}
+
+	var version int
+	row = m.db.QueryRowContext(ctx, fmt.Sprintf("SELECT COALESCE(MAX(version), 0) FROM `%s`", m.Table))
+	if err := row.Scan(&version); err != nil {
+		return 0, err
+	}
+
+	return version, nil
+}
+
+// Check returns ErrSchemaTooOld if the database is behind the latest migration.
+//
+// A database that is ahead is fine, it happens when the previous version
+// of the binary is still running during a rollout.
+func (m *Migrator) Check(ctx context.Context) error {
+	version, err := m.Version(ctx)
+	if err != nil {
+		return err
+	}
+
+	if version < m.Latest() {
+		return fmt.Errorf("%v: version is %d; want %d", ErrSchemaTooOld, version, m.Latest())
+	}
+
+	return nil
+}
+
+// Up applies all the pending migrations, and returns the number of applied ones
+func (m *Migrator) Up(ctx context.Context) (int, error) {
+	unlock, err := m.lock(ctx)
+	if err != nil {
+		return 0, err
+	}
+	defer unlock()
+
+	if err := m.createTable(ctx); err != nil {
+		return 0, err
+	}
+
+	version, err := m.Version(ctx)
+	if err != nil {
+		return 0, err
+	}
+
+	// a database that is ahead has nothing to apply
+	pending := []Migration{}
+	if version < m.Latest() {
+		pending = m.migrations[version:]
+	}
+
+	applied := 0
+	for _, migration := range pending {
+		exists, err := m.exists(ctx, migration)
+		if err != nil {
+			return applied, err
+		}
+
+		if !exists {
+			if err := m.exec(ctx, migration, migration.Up); err != nil {
+				return applied, err
+			}
+		}
+
+		_, err = m.db.ExecContext(ctx,
+			fmt.Sprintf("INSERT INTO `%s` (version, name, applied_at) VALUES (?, ?, UTC_TIMESTAMP())", m.Table),
+			migration.Version, migration.Name)
+		if err != nil {
+			return applied, err
+		}
+
+		applied++
+	}
+
+	return applied, nil
+}
+
+// Down reverts the last n applied migrations, and returns the number of reverted ones
+func (m *Migrator) Down(ctx context.Context, n int) (int, error) {
+	unlock, err := m.lock(ctx)
+	if err != nil {
+		return 0, err
+	}
+	defer unlock()
+
+	version, err := m.Version(ctx)
+	if err != nil {
+		return 0, err
+	}
+
+	if version > m.Latest() {
+		return 0, fmt.Errorf("database version %d is newer than the latest migration %d", version, m.Latest())
+	}
+
+	reverted := 0
+	for ; reverted < n && version > 0; version-- {
+		migration := m.migrations[version-1]
+		if err := m.exec(ctx, migration, migration.Down); err != nil {
+			return reverted, err
+		}
+
+		_, err := m.db.ExecContext(ctx,
+			fmt.Sprintf("DELETE FROM `%s` WHERE version=?", m.Table), migration.Version)
+		if err != nil {
+			return reverted, err
+		}
+
+		reverted++
+	}
+
+	return reverted, nil
+}
+
+// exec executes the statements of a migration one by one.
+//
+// MySQL commits DDL statements (i.e. CREATE, ALTER) implicitly, and so they can't be
+// wrapped in a transaction. If a statement fails, the migration is partially applied
+// and has to be fixed manually. Keep every migration to one statement when possible.
+func (m *Migrator) exec(ctx context.Context, migration Migration, statements []string) error {
+	for _, statement := range statements {
+		if _, err := m.db.ExecContext(ctx, statement); err != nil {
+			return fmt.Errorf("migration %d (%s) failed: %v", migration.Version, migration.Name, err)
+		}
+	}
+
+	return nil
+}
+
+// exists tells if the change of a migration is in the database already, by its Exists query
+func (m *Migrator) exists(ctx context.Context, migration Migration) (bool, error) {
+	if migration.Exists == "" {
+		return false, nil
+	}
+
+	var count int
+	if err := m.db.QueryRowContext(ctx, migration.Exists).Scan(&count); err != nil {
+		return false, fmt.Errorf("migration %d (%s) failed: %v", migration.Version, migration.Name, err)
+	}
+
+	return count > 0, nil
+}
+
+// createTable creates the table of the applied migrations if not exists
+func (m *Migrator) createTable(ctx context.Context) error {
+	_, err := m.db.ExecContext(ctx, fmt.Sprintf("CREATE TABLE IF NOT EXISTS `%s` ("+
+		"`version` int(11) NOT NULL, "+
+		"`name` varchar(255) NOT NULL, "+
+		"`applied_at` datetime NOT NULL, "+
+		"PRIMARY KEY (`version`)"+
+		") ENGINE=InnoDB DEFAULT CHARSET=utf8 COLLATE=utf8_general_ci", m.Table))
+
+	return err
+}
+
+// lock acquires a named lock so that only one process at a time runs the migrations,
+// i.e. when many replicas are deployed at the same time. It returns a function to release it.
+//
+// Named locks belong to a connection, and so the lock is acquired on a dedicated one.
+func (m *Migrator) lock(ctx context.Context) (func(), error) {
+	conn, err := m.db.Conn(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	name := m.Table + "_lock"
+
+	var acquired sql.NullInt64
+	err = conn.QueryRowContext(ctx, "SELECT GET_LOCK(?, 60)", name).Scan(&acquired)
+	if err != nil {
+		conn.Close()
+		return nil, err
+	}
+
+	if acquired.Int64 != 1 {
+		conn.Close()
+		return nil, errors.New("timed out waiting for another process to finish the migrations")
+	}
+
+	return func() {
+		conn.ExecContext(context.Background(), "SELECT RELEASE_LOCK(?)", name)
+		conn.Close()
+	}, nil
+}
@@ -29,3 +29,20 @@ func NewDB(connStr string) (*DB, error) {
 
 	return &DB{dbClient}, nil
 }
+
+// IsDuplicateKey checks if the error is of a write that violates a unique index.
+// It is a write error of InsertOne and UpdateOne, and a command error of FindOneAndUpdate.
+func IsDuplicateKey(err error) bool {
+	switch e := err.(type) {
+	case mongo.WriteException:
+		for _, we := range e.WriteErrors {
+			if we.Code == 11000 {
+				return true
+			}
+		}
+	case mongo.CommandError:
+		return e.Code == 11000
+	}
+
+	return false
+}
@@ -3,7 +3,7 @@ package mysql
 import (
 	"database/sql"
 
-	_ "github.com/go-sql-driver/mysql" // mysql
+	"github.com/go-sql-driver/mysql"
 )
 
 // DB MySQL
@@ -29,3 +29,7 @@ func NewDB(connStr string) (*DB, error) {
 
 	return &DB{db}, nil
 }
+
+// NullTime represents a DATETIME column that may be NULL.
+// It can be scanned without having "parseTime=true" in the connection string.
+type NullTime = mysql.NullTime
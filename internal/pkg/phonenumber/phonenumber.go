@@ -0,0 +1,68 @@
+package phonenumber
+
+import (
+	"errors"
+	"strconv"
+	"strings"
+)
+
+var (
+	// ErrInvalid is returned when a phone number can't be normalized
+	ErrInvalid = errors.New("invalid phone number")
+
+	// ErrInvalidAreaCode is returned when an area code isn't a North American one
+	ErrInvalidAreaCode = errors.New("invalid area code")
+)
+
+// Normalize returns the phone number in E.164 format, i.e. "+16135550172", the format the phone numbers are stored in.
+//
+// Carriers send the phone numbers in different formats: with or without the "+", with the international
+// call prefix "00", or formatted i.e. "(613) 555-0172". A number of 10 digits without a country code
+// is a North American one, the area the phone numbers of the platform are in.
+func Normalize(number string) (string, error) {
+	number = strings.TrimSpace(number)
+
+	international := false
+	switch {
+	case strings.HasPrefix(number, "+"):
+		international, number = true, number[1:]
+	case strings.HasPrefix(number, "00"):
+		international, number = true, number[2:]
+	}
+
+	var digits strings.Builder
+	for _, r := range number {
+		switch {
+		case r >= '0' && r <= '9':
+			digits.WriteRune(r)
+		case r == ' ' || r == '-' || r == '.' || r == '(' || r == ')':
+			// separators
+		default:
+			return "", ErrInvalid
+		}
+	}
+
+	// E.164 numbers have at most 15 digits, and the country code doesn't start with 0
+	d := digits.String()
+	if !international && len(d) == 10 {
+		d = "1" + d
+	} else if !international && len(d) < 11 {
+		return "", ErrInvalid
+	}
+
+	if len(d) < 8 || len(d) > 15 || d[0] == '0' {
+		return "", ErrInvalid
+	}
+
+	return "+" + d, nil
+}
+
+// AreaCodePrefix returns the prefix of the phone numbers (in E.164 format) of a North American area code,
+// i.e. "+1613" for 613. An area code is 3 digits, and doesn't start with 0 or 1.
+func AreaCodePrefix(areaCode int32) (string, error) {
+	if areaCode < 200 || areaCode > 999 {
+		return "", ErrInvalidAreaCode
+	}
+
+	return "+1" + strconv.Itoa(int(areaCode)), nil
+}
@@ -0,0 +1,137 @@
+package phonenumber
+
+import "strings"
+
+// TimeZone returns the time zone of a phone number in E.164 format, i.e. "America/Toronto" for "+16135550172",
+// or an empty string if it can't be told.
+//
+// A North American number is told by its area code, and any other by its country code.
+// An area or a country that spans many time zones (i.e. 867 in the north of Canada, or Russia) is unknown.
+func TimeZone(number string) string {
+	if !strings.HasPrefix(number, "+") {
+		return ""
+	}
+
+	digits := number[1:]
+	if strings.HasPrefix(digits, "1") {
+		if len(digits) != 11 {
+			return ""
+		}
+
+		return areaCodes[digits[1:4]]
+	}
+
+	// country codes are 1 to 3 digits, and none is a prefix of another
+	for n := 1; n <= 3 && n < len(digits); n++ {
+		if tz, ok := countryCodes[digits[:n]]; ok {
+			return tz
+		}
+	}
+
+	return ""
+}
+
+// zones returns the time zone of each of the codes
+func zones(tz string, codes ...string) map[string]string {
+	m := make(map[string]string, len(codes))
+	for _, code := range codes {
+		m[code] = tz
+	}
+
+	return m
+}
+
+// merge merges the maps of codes into one
+func merge(maps ...map[string]string) map[string]string {
+	res := map[string]string{}
+	for _, m := range maps {
+		for code, tz := range m {
+			res[code] = tz
+		}
+	}
+
+	return res
+}
+
+// areaCodes are the time zones of the North American area codes
+var areaCodes = merge(
+	// Canada
+	zones("America/St_Johns", "709", "879"),
+	zones("America/Halifax", "902", "782", "506", "428"),
+	zones("America/Toronto",
+		"416", "647", "437", "905", "289", "365", "742", "613", "343", "753", "705", "249", "683", "519", "226", "548", "382", "807",
+		"514", "438", "450", "579", "418", "581", "367", "819", "873", "354", "263", "468"),
+	zones("America/Winnipeg", "204", "431", "584"),
+	zones("America/Regina", "306", "639", "474"),
+	zones("America/Edmonton", "403", "587", "780", "825", "368"),
+	zones("America/Vancouver", "604", "778", "236", "250", "672", "257"),
+
+	// United States
+	zones("America/New_York",
+		"212", "646", "332", "718", "347", "929", "917", "516", "631", "914", "518", "315", "585", "716", "607", // NY
+		"201", "973", "908", "732", "609", "856", "862", "551", "848", // NJ
+		"215", "267", "445", "610", "484", "717", "412", "724", "814", "570", // PA
+		"202", "301", "240", "410", "443", "302", "703", "571", "804", "757", "540", "434", "304", "681", // DC, MD, DE, VA, WV
+		"617", "857", "508", "774", "781", "339", "978", "351", "413", // MA
+		"203", "475", "860", "959", "401", "603", "802", "207", // CT, RI, NH, VT, ME
+		"305", "786", "954", "754", "561", "407", "321", "689", "813", "656", "727", "904", "941", "239", "352", "386", "772", "863", // FL
+		"404", "470", "678", "770", "943", "706", "762", "912", "478", "229", // GA
+		"704", "980", "919", "984", "336", "743", "252", "910", "828", "803", "839", "843", "854", "864", // NC, SC
+		"216", "440", "330", "234", "614", "380", "513", "937", "419", "567", "740", "220", // OH
+		"313", "248", "947", "586", "734", "810", "517", "616", "269", "989", // MI
+		"317", "463", "765", "502", "859", "865", "423"), // IN, KY, TN
+	zones("America/Chicago",
+		"312", "773", "872", "630", "331", "708", "847", "224", "815", "779", "217", "447", "309", "618", // IL
+		"214", "469", "972", "945", "817", "682", "713", "281", "832", "346", "512", "737", "210", "726", // TX
+		"361", "254", "936", "409", "903", "430", "940", "979", "830",
+		"612", "651", "763", "952", "218", "320", "507", "414", "262", "608", "920", "715", "534", // MN, WI
+		"314", "557", "636", "816", "573", "417", "660", "975", "615", "629", "901", "731", "931", "270", "364", // MO, TN, KY
+		"504", "225", "318", "337", "985", "205", "659", "251", "256", "938", "334", "601", "769", "662", "228", // LA, AL, MS
+		"501", "479", "870", "405", "572", "918", "539", "580", "316", "785", "913", "620", "402", "531", // AR, OK, KS, NE
+		"515", "319", "563", "641", "712"), // IA
+	zones("America/Denver", "303", "720", "983", "719", "970", "505", "575", "801", "385", "435", "406", "307", "915"),
+	zones("America/Boise", "208", "986"),
+	zones("America/Phoenix", "602", "480", "623", "520", "928"),
+	zones("America/Los_Angeles",
+		"213", "323", "310", "424", "818", "747", "626", "562", "714", "657", "949", "909", "951", "805", "820", "760", "442", // CA
+		"619", "858", "415", "628", "510", "341", "408", "669", "650", "925", "707", "916", "279", "209", "559", "530", "831", "661",
+		"206", "253", "360", "425", "564", "509", "503", "971", "541", "458", "702", "725", "775"), // WA, OR, NV
+	zones("America/Anchorage", "907"),
+	zones("Pacific/Honolulu", "808"),
+	zones("America/Puerto_Rico", "787", "939"),
+)
+
+// countryCodes are the time zones of the countries outside of North America that have only one
+var countryCodes = map[string]string{
+	"20":  "Africa/Cairo",
+	"27":  "Africa/Johannesburg",
+	"30":  "Europe/Athens",
+	"31":  "Europe/Amsterdam",
+	"32":  "Europe/Brussels",
+	"33":  "Europe/Paris",
+	"34":  "Europe/Madrid",
+	"39":  "Europe/Rome",
+	"41":  "Europe/Zurich",
+	"43":  "Europe/Vienna",
+	"44":  "Europe/London",
+	"45":  "Europe/Copenhagen",
+	"46":  "Europe/Stockholm",
+	"47":  "Europe/Oslo",
+	"48":  "Europe/Warsaw",
+	"49":  "Europe/Berlin",
+	"63":  "Asia/Manila",
+	"64":  "Pacific/Auckland",
+	"65":  "Asia/Singapore",
+	"81":  "Asia/Tokyo",
+	"82":  "Asia/Seoul",
+	"86":  "Asia/Shanghai",
+	"90":  "Europe/Istanbul",
+	"91":  "Asia/Kolkata",
+	"234": "Africa/Lagos",
+	"351": "Europe/Lisbon",
+	"353": "Europe/Dublin",
+	"852": "Asia/Hong_Kong",
+	"966": "Asia/Riyadh",
+	"971": "Asia/Dubai",
+	"972": "Asia/Jerusalem",
+}
@@ -0,0 +1,85 @@
+package quiethours
+
+import (
+	"errors"
+	"fmt"
+	"time"
+)
+
+// ErrEmpty is returned when the quiet hours start and end at the same time
+var ErrEmpty = errors.New("quiet hours must not be empty")
+
+// Window is the quiet hours of every day, from Start to End (since midnight) in the local time of the recipient.
+// A window that ends before it starts (i.e. 21:00 to 08:00) spans midnight.
+type Window struct {
+	Start time.Duration
+	End   time.Duration
+}
+
+// Parse parses the start and end of the quiet hours, both "HH:MM", i.e. "21:00" and "08:00"
+func Parse(start, end string) (Window, error) {
+	s, err := parseClock(start)
+	if err != nil {
+		return Window{}, err
+	}
+
+	e, err := parseClock(end)
+	if err != nil {
+		return Window{}, err
+	}
+
+	if s == e {
+		return Window{}, ErrEmpty
+	}
+
+	return Window{Start: s, End: e}, nil
+}
+
+// parseClock parses "HH:MM" to the duration since midnight
+func parseClock(clock string) (time.Duration, error) {
+	t, err := time.Parse("15:04", clock)
+	if err != nil || len(clock) != len("15:04") {
+		return 0, fmt.Errorf("invalid time %q, want HH:MM", clock)
+	}
+
+	return time.Duration(t.Hour())*time.Hour + time.Duration(t.Minute())*time.Minute, nil
+}
+
+// String returns the window as "HH:MM-HH:MM"
+func (w Window) String() string {
+	return clock(w.Start) + "-" + clock(w.End)
+}
+
+func clock(d time.Duration) string {
+	return fmt.Sprintf("%02d:%02d", int(d/time.Hour), int(d%time.Hour/time.Minute))
+}
+
+// Hold tells if the time is in the quiet hours, and if so, when they end.
+// The time is in the location of the recipient, and so is the end.
+//
+// The end is the wall clock time of the day it falls on, and so it is right
+// across the changes of daylight saving time.
+func (w Window) Hold(t time.Time) (time.Time, bool) {
+	h, m, s := t.Clock()
+	now := time.Duration(h)*time.Hour + time.Duration(m)*time.Minute + time.Duration(s)*time.Second
+
+	var quiet bool
+	if w.Start < w.End {
+		quiet = now >= w.Start && now < w.End
+	} else {
+		quiet = now >= w.Start || now < w.End
+	}
+
+	if !quiet {
+		return time.Time{}, false
+	}
+
+	// the quiet hours that span midnight end tomorrow, if they started today
+	y, mon, d := t.Date()
+	if w.Start > w.End && now >= w.Start {
+		d++
+	}
+
+	end := time.Date(y, mon, d, int(w.End/time.Hour), int(w.End%time.Hour/time.Minute), 0, 0, t.Location())
+	return end, true
+}
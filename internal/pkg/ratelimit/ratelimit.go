@@ -0,0 +1,194 @@
+package ratelimit
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/OmarElGabry/go-textnow/internal/pkg/redis"
+)
+
+// ErrExceedsLimit is returned when more tokens are asked for than a bucket of a rule can ever hold,
+// and so waiting doesn't help
+var ErrExceedsLimit = errors.New("more than the limit at once")
+
+// Rule is a limit of a number of tokens (i.e. messages) per period
+type Rule struct {
+	Count  int64
+	Period time.Duration
+}
+
+// String returns the rule as it is parsed, i.e. "10/s"
+func (r Rule) String() string {
+	for unit, period := range periods {
+		if r.Period == period {
+			return fmt.Sprintf("%d/%s", r.Count, unit)
+		}
+	}
+
+	return fmt.Sprintf("%d/%s", r.Count, r.Period)
+}
+
+// periods are the units of the rules
+var periods = map[string]time.Duration{
+	"s": time.Second,
+	"m": time.Minute,
+	"h": time.Hour,
+	"d": 24 * time.Hour,
+}
+
+// ParseRules parses comma-separated rules of "<count>/<unit>", where the unit is
+// one of "s", "m", "h" and "d", i.e. "10/s,300/m,10000/d". An empty string has no rules.
+func ParseRules(s string) ([]Rule, error) {
+	rules := []Rule{}
+	if strings.TrimSpace(s) == "" {
+		return rules, nil
+	}
+
+	for _, part := range strings.Split(s, ",") {
+		fields := strings.Split(strings.TrimSpace(part), "/")
+		if len(fields) != 2 {
+			return nil, fmt.Errorf("invalid rule %q, want <count>/<unit>", part)
+		}
+
+		count, err := strconv.ParseInt(fields[0], 10, 64)
+		if err != nil || count <= 0 {
+			return nil, fmt.Errorf("invalid count of rule %q", part)
+		}
+
+		period, ok := periods[fields[1]]
+		if !ok {
+			return nil, fmt.Errorf("invalid unit of rule %q, want one of s, m, h and d", part)
+		}
+
+		rules = append(rules, Rule{Count: count, Period: period})
+	}
+
+	return rules, nil
+}
+
+// Bucket is a set of token buckets under the same key, one for every rule
+type Bucket struct {
+	Key   string
+	Rules []Rule
+}
+
+// Limiter takes tokens from buckets in Redis, and so the limits hold across the replicas.
+//
+// Every bucket of a rule holds up to Count tokens, and is refilled at Count tokens per Period.
+// A bucket that isn't used for a Period is full again, and so it expires.
+type Limiter struct {
+	cache *redis.Cache
+}
+
+// NewLimiter creates a new limiter
+func NewLimiter(cache *redis.Cache) *Limiter {
+	return &Limiter{cache: cache}
+}
+
+// Take takes n tokens from every rule of the given buckets, either from all of them or none at all.
+// If any of them doesn't have enough tokens, it returns how long to wait until it does.
+//
+// On Redis Cluster, the keys of the buckets must share the same hash tag, i.e. "{user:42}",
+// since they are all taken at once.
+func (l *Limiter) Take(n int64, buckets ...Bucket) (time.Duration, error) {
+	keys, args := []string{}, []interface{}{n}
+	for _, b := range buckets {
+		for _, r := range b.Rules {
+			if n > r.Count {
+				return 0, ErrExceedsLimit
+			}
+
+			keys = append(keys, fmt.Sprintf("ratelimit-%s-%d", b.Key, r.Period/time.Second))
+			args = append(args, r.Count, int64(r.Period/time.Millisecond))
+		}
+	}
+
+	if len(keys) == 0 {
+		return 0, nil
+	}
+
+	wait, err := takeScript.Run(l.cache, keys, args...).Int64()
+	if err != nil {
+		return 0, err
+	}
+
+	return time.Duration(wait) * time.Millisecond, nil
+}
+
+// Give gives n tokens back to every rule of the given buckets, i.e. the ones taken for messages
+// that aren't sent after all. A bucket is never filled above the Count of its rule.
+func (l *Limiter) Give(n int64, buckets ...Bucket) error {
+	keys, args := []string{}, []interface{}{n}
+	for _, b := range buckets {
+		for _, r := range b.Rules {
+			keys = append(keys, fmt.Sprintf("ratelimit-%s-%d", b.Key, r.Period/time.Second))
+			args = append(args, r.Count, int64(r.Period/time.Millisecond))
+		}
+	}
+
+	if len(keys) == 0 {
+		return nil
+	}
+
+	return giveScript.Run(l.cache, keys, args...).Err()
+}
+
+// takeScript refills the buckets by the time since they were last taken from, then takes the tokens
+// if every bucket has enough of them. It returns 0 if they are taken, or the milliseconds to wait otherwise.
+//	KEYS: the buckets, ARGV[1]: number of tokens, ARGV[2i], ARGV[2i+1]: count and period (ms) of KEYS[i]
+//
+// The time is Redis's, so that the replicas don't have to agree on it.
+// "replicate_commands" replicates the effects rather than the script itself,
+// which is required before any write when the script calls a random command like TIME.
+var takeScript = redis.NewScript(`
+redis.replicate_commands()
+local time = redis.call("TIME")
+local now = tonumber(time[1]) * 1000 + math.floor(tonumber(time[2]) / 1000)
+local n = tonumber(ARGV[1])
+local wait = 0
+local tokens = {}
+for i, key in ipairs(KEYS) do
+	local count, period = tonumber(ARGV[2 * i]), tonumber(ARGV[2 * i + 1])
+	local bucket = redis.call("HMGET", key, "tokens", "ts")
+	local level = tonumber(bucket[1]) or count
+	local ts = tonumber(bucket[2]) or now
+	level = math.min(count, level + math.max(0, now - ts) * count / period)
+	tokens[i] = level
+	if level < n then
+		wait = math.max(wait, math.ceil((n - level) * period / count))
+	end
+end
+if wait > 0 then
+	return wait
+end
+for i, key in ipairs(KEYS) do
+	redis.call("HMSET", key, "tokens", tostring(tokens[i] - n), "ts", now)
+	redis.call("PEXPIRE", key, ARGV[2 * i + 1])
+end
+return 0
+`)
+
+// giveScript refills the buckets, as takeScript does, then adds the tokens back to them.
+// A bucket that doesn't exist is full already.
+//	KEYS: the buckets, ARGV[1]: number of tokens, ARGV[2i], ARGV[2i+1]: count and period (ms) of KEYS[i]
+var giveScript = redis.NewScript(`
+redis.replicate_commands()
+local time = redis.call("TIME")
+local now = tonumber(time[1]) * 1000 + math.floor(tonumber(time[2]) / 1000)
+local n = tonumber(ARGV[1])
+for i, key in ipairs(KEYS) do
+	local count, period = tonumber(ARGV[2 * i]), tonumber(ARGV[2 * i + 1])
+	local bucket = redis.call("HMGET", key, "tokens", "ts")
+	if bucket[1] then
+		local level = tonumber(bucket[1])
+		local ts = tonumber(bucket[2]) or now
+		level = math.min(count, level + math.max(0, now - ts) * count / period + n)
+		redis.call("HMSET", key, "tokens", tostring(level), "ts", now)
+		redis.call("PEXPIRE", key, ARGV[2 * i + 1])
+	end
+end
+return 0
+`)
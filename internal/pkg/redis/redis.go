@@ -1,35 +1,163 @@
 package redis
 
 import (
+	"fmt"
 	"log"
+	"strconv"
+	"strings"
 
 	"github.com/OmarElGabry/go-textnow/internal/pkg/config"
 	"github.com/go-redis/redis"
 )
 
 // Cache Redis
+//
+// It is either a single Redis server, a master monitored by Sentinel,
+// or a Redis Cluster. See NewCache for how it is selected.
 type Cache struct {
-	*redis.Client
+	redis.UniversalClient
 	ErrNotExists error
+
+	db int
+}
+
+// Script is a Lua script that is executed atomically by Redis
+type Script = redis.Script
+
+// NewScript creates a new Lua script.
+//
+// On Redis Cluster, all the keys the script touches must be in the same slot,
+// and so they have to share the same hash tag, i.e. "areacode-{613}" and "refid-{613}-...".
+func NewScript(src string) *Script {
+	return redis.NewScript(src)
 }
 
-// NewCache creates and returns connection to Redis
+// NewCache creates and returns connection to Redis.
+//
+// The type of the connection is selected by "REDIS_MODE":
+//	- "single" (default): a single server at "REDIS_ADDR"
+//	- "sentinel": the master named "REDIS_MASTER_NAME" monitored by the Sentinels at "REDIS_ADDR".
+//		On failover, the connection is switched to the new master.
+//	- "cluster": a Redis Cluster, where "REDIS_ADDR" are some of its nodes to discover the rest from.
+//
+// "REDIS_ADDR" is a comma-separated list of addresses.
+// "REDIS_DB" selects the database, except for the cluster which only has database 0.
 func NewCache() (*Cache, error) {
 	config, err := config.Load()
 	if err != nil {
 		log.Fatalf("Couldn't load env variables: %v", err)
 	}
 
-	client := redis.NewClient(&redis.Options{
-		Addr:     config("REDIS_ADDR"),
-		Password: config("REDIS_PASSWORD"),
-		DB:       0, // use default DB
-	})
+	addrs := strings.Split(config("REDIS_ADDR"), ",")
+	for i := range addrs {
+		addrs[i] = strings.TrimSpace(addrs[i])
+	}
+
+	db := 0 // use default DB
+	if config("REDIS_DB") != "" {
+		db, err = strconv.Atoi(config("REDIS_DB"))
+		if err != nil {
+			return nil, fmt.Errorf("invalid REDIS_DB: %v", err)
+		}
+	}
+
+	var client redis.UniversalClient
+	switch mode := config("REDIS_MODE"); mode {
+	case "", "single":
+		client = redis.NewClient(&redis.Options{
+			Addr:     addrs[0],
+			Password: config("REDIS_PASSWORD"),
+			DB:       db,
+		})
+
+	case "sentinel":
+		client = redis.NewFailoverClient(&redis.FailoverOptions{
+			MasterName:    config("REDIS_MASTER_NAME"),
+			SentinelAddrs: addrs,
+			Password:      config("REDIS_PASSWORD"),
+			DB:            db,
+		})
+
+	case "cluster":
+		if db != 0 {
+			return nil, fmt.Errorf("redis cluster only supports database 0, got REDIS_DB=%d", db)
+		}
+
+		client = redis.NewClusterClient(&redis.ClusterOptions{
+			Addrs:    addrs,
+			Password: config("REDIS_PASSWORD"),
+		})
+
+	default:
+		return nil, fmt.Errorf("unknown REDIS_MODE %q", mode)
+	}
 
 	_, err = client.Ping().Result()
 	if err != nil {
 		return nil, err
 	}
 
-	return &Cache{client, redis.Nil}, nil
+	return &Cache{UniversalClient: client, ErrNotExists: redis.Nil, db: db}, nil
+}
+
+// IsCluster tells if the connection is to a Redis Cluster
+func (c *Cache) IsCluster() bool {
+	_, ok := c.UniversalClient.(*redis.ClusterClient)
+	return ok
+}
+
+// KeyspaceChannel returns the channel Redis publishes the keyspace notifications
+// of the given key to. Notifications must be enabled, see EnableKeyspaceEvents.
+//
+// On Redis Cluster, notifications are published only by the node that owns the key.
+// A subscription is made to the node that owns the slot of the channel name,
+// and so the key must have a hash tag for both to be the same node.
+func (c *Cache) KeyspaceChannel(key string) string {
+	return fmt.Sprintf("__keyspace@%d__:%s", c.db, key)
+}
+
+// EnableKeyspaceEvents enables keyspace notifications for the given classes of commands
+// (i.e. "s" for Set commands) while keeping the classes that are already enabled.
+//
+// Managed Redis services may disallow the CONFIG command,
+// in which case notifications have to be enabled in the server configuration instead.
+// The same goes for Sentinel, where a replica promoted to master doesn't have them
+// enabled unless it is in its configuration.
+func (c *Cache) EnableKeyspaceEvents(classes string) error {
+	// every master in the cluster publishes notifications of its own keys
+	if cluster, ok := c.UniversalClient.(*redis.ClusterClient); ok {
+		return cluster.ForEachMaster(func(client *redis.Client) error {
+			return enableKeyspaceEvents(client, classes)
+		})
+	}
+
+	return enableKeyspaceEvents(c.UniversalClient, classes)
+}
+
+func enableKeyspaceEvents(client redis.Cmdable, classes string) error {
+	res, err := client.ConfigGet("notify-keyspace-events").Result()
+	if err != nil {
+		return err
+	}
+
+	// result is a [parameter, value] pair
+	current := ""
+	if len(res) == 2 {
+		current, _ = res[1].(string)
+	}
+
+	flags := current
+	for _, class := range "K" + classes {
+		// "A" is an alias for all the classes (except for "K", "E" and "m")
+		if strings.ContainsRune(flags, class) || (class != 'K' && strings.ContainsRune(flags, 'A')) {
+			continue
+		}
+		flags += string(class)
+	}
+
+	if flags == current {
+		return nil
+	}
+
+	return client.ConfigSet("notify-keyspace-events", flags).Err()
 }
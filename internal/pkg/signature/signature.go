@@ -0,0 +1,80 @@
+package signature
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Header is the HTTP header the signature of a webhook payload is sent in
+const Header = "X-Textnow-Signature"
+
+var (
+	// ErrInvalid is returned when a signature is malformed, or doesn't match the payload
+	ErrInvalid = errors.New("invalid signature")
+
+	// ErrExpired is returned when a signature is older than the tolerance, it could be replayed
+	ErrExpired = errors.New("signature has expired")
+)
+
+// Sign signs the given payload by HMAC-SHA256 with the given secret, along with the time it is sent at.
+//
+// The signature is "t=<unix time in seconds>,v1=<hex encoded HMAC of "<time>.<payload>">".
+// The time is signed too, so that the receiver can reject an old payload that is replayed.
+func Sign(secret []byte, timestamp time.Time, payload []byte) string {
+	t := strconv.FormatInt(timestamp.Unix(), 10)
+	return "t=" + t + ",v1=" + hex.EncodeToString(MAC(secret, []byte(t+"."), payload))
+}
+
+// Verify checks the given signature of the payload, and that it was signed within the tolerance
+func Verify(secret []byte, signature string, payload []byte, tolerance time.Duration) error {
+	var t, v1 string
+	for _, part := range strings.Split(signature, ",") {
+		switch {
+		case strings.HasPrefix(part, "t="):
+			t = strings.TrimPrefix(part, "t=")
+		case strings.HasPrefix(part, "v1="):
+			v1 = strings.TrimPrefix(part, "v1=")
+		}
+	}
+
+	timestamp, err := strconv.ParseInt(t, 10, 64)
+	if err != nil {
+		return ErrInvalid
+	}
+
+	mac, err := hex.DecodeString(v1)
+	if err != nil {
+		return ErrInvalid
+	}
+
+	if !Match(secret, mac, []byte(t+"."), payload) {
+		return ErrInvalid
+	}
+
+	if time.Since(time.Unix(timestamp, 0)) > tolerance {
+		return ErrExpired
+	}
+
+	return nil
+}
+
+// MAC returns the HMAC-SHA256 of the given parts of data, one after another, with the given secret
+func MAC(secret []byte, data ...[]byte) []byte {
+	mac := hmac.New(sha256.New, secret)
+	for _, part := range data {
+		mac.Write(part)
+	}
+
+	return mac.Sum(nil)
+}
+
+// Match tells if the given mac is the one of the data (see MAC).
+// It compares in constant time, so that the mac can't be guessed byte by byte.
+func Match(secret, mac []byte, data ...[]byte) bool {
+	return hmac.Equal(mac, MAC(secret, data...))
+}
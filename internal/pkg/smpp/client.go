@@ -0,0 +1,388 @@
+package smpp
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"sync"
+	"time"
+)
+
+// ErrNotBound is returned when there is no bound session to send a request on
+var ErrNotBound = errors.New("smpp: not bound")
+
+// ErrClosed is returned when the session is closed while waiting for a response
+var ErrClosed = errors.New("smpp: session closed")
+
+// Config is the configuration of a client
+type Config struct {
+	Addr       string // host:port of the SMSC
+	SystemID   string
+	Password   string
+	SystemType string
+
+	// Window is the max number of submit_sm sent and waiting for their responses (defaults to 10).
+	// Any more wait until a response comes back, so that the SMSC isn't flooded.
+	Window int
+
+	// EnquireLinkInterval is how often the session is checked by enquire_link (defaults to 30s).
+	// It keeps the session open through firewalls, and finds out if the SMSC is gone.
+	EnquireLinkInterval time.Duration
+
+	// ResponseTimeout is how long a request waits for its response (defaults to 10s)
+	ResponseTimeout time.Duration
+
+	// ReconnectDelay is how long to wait before binding again after the session is lost (defaults to 5s)
+	ReconnectDelay time.Duration
+
+	// OnDeliver handles every deliver_sm: a delivery receipt, or an inbound message.
+	// It is called on its own goroutine once the deliver_sm is acknowledged.
+	OnDeliver func(*Message)
+}
+
+// Client is an ESME (External Short Messaging Entity): it binds to an SMSC as a transceiver,
+// and so it both submits messages, and receives the delivery receipts and the inbound messages.
+//
+// It keeps the session bound for as long as it is open, and binds again whenever it is lost.
+type Client struct {
+	config Config
+	window chan struct{}
+
+	mu      sync.Mutex
+	session *session
+	lastErr error // of the last bind, or why the last session was lost
+
+	closed chan struct{}
+	done   chan struct{}
+}
+
+// Dial creates a client and binds it in the background.
+// Until it is bound, Submit returns ErrNotBound.
+func Dial(config Config) *Client {
+	if config.Window == 0 {
+		config.Window = 10
+	}
+
+	if config.EnquireLinkInterval == 0 {
+		config.EnquireLinkInterval = 30 * time.Second
+	}
+
+	if config.ResponseTimeout == 0 {
+		config.ResponseTimeout = 10 * time.Second
+	}
+
+	if config.ReconnectDelay == 0 {
+		config.ReconnectDelay = 5 * time.Second
+	}
+
+	c := &Client{
+		config: config,
+		window: make(chan struct{}, config.Window),
+		closed: make(chan struct{}),
+		done:   make(chan struct{}),
+	}
+
+	go c.run()
+
+	return c
+}
+
+// Bound tells if the client has a bound session
+func (c *Client) Bound() bool {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	return c.session != nil
+}
+
+// Submit sends a submit_sm, and returns the message id the SMSC has given it.
+// It waits for a free slot in the window first.
+func (c *Client) Submit(ctx context.Context, m *Message) (string, error) {
+	select {
+	case c.window <- struct{}{}:
+		defer func() { <-c.window }()
+	case <-ctx.Done():
+		return "", ctx.Err()
+	}
+
+	s, err := c.bound()
+	if err != nil {
+		return "", err
+	}
+
+	res, err := s.request(ctx, SubmitSM, m.Marshal(), c.config.ResponseTimeout)
+	if err != nil {
+		return "", err
+	}
+
+	d := decoder{b: res.Body}
+	id := d.cstring()
+	if d.err != nil {
+		return "", d.err
+	}
+
+	return id, nil
+}
+
+// Close unbinds the session (if any), and stops binding again
+func (c *Client) Close() error {
+	select {
+	case <-c.closed:
+		return nil
+	default:
+		close(c.closed)
+	}
+
+	<-c.done
+	return nil
+}
+
+// bound returns the bound session
+func (c *Client) bound() (*session, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if c.session == nil {
+		if c.lastErr != nil {
+			return nil, fmt.Errorf("%v: %v", ErrNotBound, c.lastErr)
+		}
+
+		return nil, ErrNotBound
+	}
+
+	return c.session, nil
+}
+
+// run binds a session, and binds again whenever it is lost, until the client is closed
+func (c *Client) run() {
+	defer close(c.done)
+
+	for {
+		s, err := c.bind()
+
+		c.mu.Lock()
+		c.session, c.lastErr = s, err
+		c.mu.Unlock()
+
+		if err == nil {
+			select {
+			case <-s.done:
+				c.mu.Lock()
+				c.session, c.lastErr = nil, s.err
+				c.mu.Unlock()
+			case <-c.closed:
+				c.mu.Lock()
+				c.session = nil
+				c.mu.Unlock()
+
+				s.unbind(c.config.ResponseTimeout)
+				return
+			}
+		}
+
+		select {
+		case <-time.After(c.config.ReconnectDelay):
+		case <-c.closed:
+			return
+		}
+	}
+}
+
+// bind connects to the SMSC and binds as a transceiver
+func (c *Client) bind() (*session, error) {
+	conn, err := net.DialTimeout("tcp", c.config.Addr, c.config.ResponseTimeout)
+	if err != nil {
+		return nil, err
+	}
+
+	s := newSession(conn, c.handle)
+
+	bind := &Bind{SystemID: c.config.SystemID, Password: c.config.Password, SystemType: c.config.SystemType}
+	if _, err := s.request(context.Background(), BindTransceiver, bind.Marshal(), c.config.ResponseTimeout); err != nil {
+		s.close(err)
+		return nil, err
+	}
+
+	go s.enquireLinks(c.config.EnquireLinkInterval, c.config.ResponseTimeout)
+
+	return s, nil
+}
+
+// handle handles a request from the SMSC
+func (c *Client) handle(s *session, p *PDU) {
+	switch p.CommandID {
+	case DeliverSM:
+		m, err := UnmarshalMessage(p.Body)
+		if err != nil {
+			s.respond(p, DeliverSMResp, StatusSysErr, nil)
+			return
+		}
+
+		// the message id of deliver_sm_resp is unused, it is an empty string
+		s.respond(p, DeliverSMResp, StatusOK, []byte{0})
+
+		if c.config.OnDeliver != nil {
+			go c.config.OnDeliver(m)
+		}
+	case EnquireLink:
+		s.respond(p, EnquireLinkResp, StatusOK, nil)
+	default:
+		s.respond(p, GenericNack, StatusInvalidCmdID, nil)
+	}
+}
+
+// session is a connection bound to the SMSC.
+//
+// A request waits for its response by its sequence number, and so many requests
+// are sent on the same connection at the same time (windowing).
+type session struct {
+	conn   net.Conn
+	handle func(*session, *PDU)
+
+	writeMu sync.Mutex
+
+	mu       sync.Mutex
+	sequence uint32
+	pending  map[uint32]chan *PDU
+
+	closeOnce sync.Once
+	done      chan struct{}
+	err       error // why it is closed
+}
+
+func newSession(conn net.Conn, handle func(*session, *PDU)) *session {
+	s := &session{
+		conn:    conn,
+		handle:  handle,
+		pending: map[uint32]chan *PDU{},
+		done:    make(chan struct{}),
+	}
+
+	go s.read()
+
+	return s
+}
+
+// request sends a request and waits for its response.
+// A response of a status other than OK is returned as a StatusError.
+func (s *session) request(ctx context.Context, id CommandID, body []byte, timeout time.Duration) (*PDU, error) {
+	ch := make(chan *PDU, 1)
+
+	s.mu.Lock()
+	s.sequence++
+	if s.sequence > 0x7FFFFFFF {
+		s.sequence = 1
+	}
+	seq := s.sequence
+	s.pending[seq] = ch
+	s.mu.Unlock()
+
+	defer func() {
+		s.mu.Lock()
+		delete(s.pending, seq)
+		s.mu.Unlock()
+	}()
+
+	if err := s.write(&PDU{CommandID: id, Sequence: seq, Body: body}); err != nil {
+		s.close(err)
+		return nil, err
+	}
+
+	timer := time.NewTimer(timeout)
+	defer timer.Stop()
+
+	select {
+	case res := <-ch:
+		if res.Status != StatusOK {
+			return res, StatusError(res.Status)
+		}
+
+		return res, nil
+	case <-timer.C:
+		return nil, fmt.Errorf("smpp: no response to 0x%08X in %v", uint32(id), timeout)
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	case <-s.done:
+		return nil, ErrClosed
+	}
+}
+
+// respond responds to a request by its sequence number
+func (s *session) respond(req *PDU, id CommandID, status uint32, body []byte) {
+	if err := s.write(&PDU{CommandID: id, Status: status, Sequence: req.Sequence, Body: body}); err != nil {
+		s.close(err)
+	}
+}
+
+func (s *session) write(p *PDU) error {
+	s.writeMu.Lock()
+	defer s.writeMu.Unlock()
+
+	return writePDU(s.conn, p)
+}
+
+// read reads the PDUs until the connection is closed: the responses are handed
+// to the requests waiting for them, the requests (other than unbind) are handled
+func (s *session) read() {
+	for {
+		p, err := readPDU(s.conn)
+		if err != nil {
+			s.close(err)
+			return
+		}
+
+		switch {
+		case p.IsResponse():
+			s.mu.Lock()
+			ch, ok := s.pending[p.Sequence]
+			s.mu.Unlock()
+
+			// a response to a request that isn't waiting anymore (i.e. timed out) is dropped
+			if ok {
+				select {
+				case ch <- p:
+				default:
+				}
+			}
+		case p.CommandID == Unbind:
+			s.respond(p, UnbindResp, StatusOK, nil)
+			s.close(errors.New("smpp: unbound by the smsc"))
+			return
+		default:
+			s.handle(s, p)
+		}
+	}
+}
+
+// enquireLinks checks the session every interval, and closes it if there is no response
+func (s *session) enquireLinks(interval, timeout time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ticker.C:
+			if _, err := s.request(context.Background(), EnquireLink, nil, timeout); err != nil {
+				s.close(fmt.Errorf("smpp: enquire_link failed: %v", err))
+				return
+			}
+		case <-s.done:
+			return
+		}
+	}
+}
+
+// unbind asks the SMSC to end the session, then closes it
+func (s *session) unbind(timeout time.Duration) {
+	s.request(context.Background(), Unbind, nil, timeout)
+	s.close(ErrClosed)
+}
+
+func (s *session) close(err error) {
+	s.closeOnce.Do(func() {
+		s.err = err
+		s.conn.Close()
+		close(s.done)
+	})
+}
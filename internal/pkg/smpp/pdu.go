@@ -0,0 +1,279 @@
+package smpp
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+)
+
+// CommandID is the command of a PDU (Protocol Data Unit)
+type CommandID uint32
+
+// The commands of SMPP 3.4 used by the client. A response has the high bit set.
+const (
+	GenericNack         CommandID = 0x80000000
+	BindTransceiver     CommandID = 0x00000009
+	BindTransceiverResp CommandID = 0x80000009
+	SubmitSM            CommandID = 0x00000004
+	SubmitSMResp        CommandID = 0x80000004
+	DeliverSM           CommandID = 0x00000005
+	DeliverSMResp       CommandID = 0x80000005
+	Unbind              CommandID = 0x00000006
+	UnbindResp          CommandID = 0x80000006
+	EnquireLink         CommandID = 0x00000015
+	EnquireLinkResp     CommandID = 0x80000015
+)
+
+// The command statuses, the ones other than OK are errors
+const (
+	StatusOK           uint32 = 0x00000000
+	StatusInvalidCmdID uint32 = 0x00000003
+	StatusBindFailed   uint32 = 0x0000000D
+	StatusInvalidPwd   uint32 = 0x0000000E
+	StatusThrottled    uint32 = 0x00000058
+	StatusSysErr       uint32 = 0x00000008
+)
+
+// The data coding of a short message
+const (
+	CodingDefault byte = 0x00 // the SMSC default alphabet, GSM 03.38
+	CodingUCS2    byte = 0x08
+)
+
+// The ESM class of a short message
+const (
+	// ESMClassReceipt marks a deliver_sm as an SMSC delivery receipt (the message type bits)
+	ESMClassReceipt byte = 0x04
+
+	// ESMClassUDHI tells that the short message starts with a User Data Header,
+	// i.e. of a segment of a concatenated message
+	ESMClassUDHI byte = 0x40
+)
+
+const (
+	headerLength = 16
+
+	// maxPDULength guards against reading a corrupt length, a PDU is a few hundred bytes at most
+	maxPDULength = 64 * 1024
+
+	interfaceVersion = 0x34
+)
+
+// ErrMalformed is returned when a PDU can't be parsed
+var ErrMalformed = errors.New("smpp: malformed pdu")
+
+// StatusError is the command status of a response other than OK
+type StatusError uint32
+
+func (e StatusError) Error() string {
+	return fmt.Sprintf("smpp: command status 0x%08X", uint32(e))
+}
+
+// PDU is an SMPP packet: a header of the command, its status and sequence number, and its body
+type PDU struct {
+	CommandID CommandID
+	Status    uint32
+	Sequence  uint32
+	Body      []byte
+}
+
+// IsResponse tells if the PDU is a response to a request of the same sequence number
+func (p *PDU) IsResponse() bool {
+	return p.CommandID&GenericNack != 0
+}
+
+// readPDU reads a PDU, the length comes first and includes the header
+func readPDU(r io.Reader) (*PDU, error) {
+	header := make([]byte, headerLength)
+	if _, err := io.ReadFull(r, header); err != nil {
+		return nil, err
+	}
+
+	length := binary.BigEndian.Uint32(header[0:4])
+	if length < headerLength || length > maxPDULength {
+		return nil, ErrMalformed
+	}
+
+	p := &PDU{
+		CommandID: CommandID(binary.BigEndian.Uint32(header[4:8])),
+		Status:    binary.BigEndian.Uint32(header[8:12]),
+		Sequence:  binary.BigEndian.Uint32(header[12:16]),
+		Body:      make([]byte, length-headerLength),
+	}
+
+	if _, err := io.ReadFull(r, p.Body); err != nil {
+		return nil, err
+	}
+
+	return p, nil
+}
+
+// writePDU writes a PDU as a single write, so that it isn't interleaved with another one
+func writePDU(w io.Writer, p *PDU) error {
+	b := make([]byte, headerLength, headerLength+len(p.Body))
+	binary.BigEndian.PutUint32(b[0:4], uint32(headerLength+len(p.Body)))
+	binary.BigEndian.PutUint32(b[4:8], uint32(p.CommandID))
+	binary.BigEndian.PutUint32(b[8:12], p.Status)
+	binary.BigEndian.PutUint32(b[12:16], p.Sequence)
+
+	_, err := w.Write(append(b, p.Body...))
+	return err
+}
+
+// Bind is the body of bind_transceiver
+type Bind struct {
+	SystemID   string
+	Password   string
+	SystemType string
+}
+
+// Marshal returns the body of the PDU
+func (b *Bind) Marshal() []byte {
+	var e encoder
+	e.cstring(b.SystemID)
+	e.cstring(b.Password)
+	e.cstring(b.SystemType)
+	e.WriteByte(interfaceVersion)
+	e.WriteByte(0) // addr_ton
+	e.WriteByte(0) // addr_npi
+	e.cstring("")  // address_range
+	return e.Bytes()
+}
+
+// UnmarshalBind parses the body of bind_transceiver
+func UnmarshalBind(body []byte) (*Bind, error) {
+	d := decoder{b: body}
+	b := &Bind{SystemID: d.cstring(), Password: d.cstring(), SystemType: d.cstring()}
+	return b, d.err
+}
+
+// Message is the body of submit_sm and deliver_sm, which share the same layout.
+// The addresses are international (TON 1) and ISDN (NPI 1), i.e. "16135550172".
+type Message struct {
+	Source             string
+	Destination        string
+	ESMClass           byte
+	RegisteredDelivery byte // 1 to ask for a delivery receipt
+	DataCoding         byte
+	ShortMessage       []byte // along with the User Data Header (if any)
+}
+
+// Marshal returns the body of the PDU
+func (m *Message) Marshal() []byte {
+	var e encoder
+	e.cstring("") // service_type
+	e.WriteByte(1)
+	e.WriteByte(1)
+	e.cstring(m.Source)
+	e.WriteByte(1)
+	e.WriteByte(1)
+	e.cstring(m.Destination)
+	e.WriteByte(m.ESMClass)
+	e.WriteByte(0) // protocol_id
+	e.WriteByte(0) // priority_flag
+	e.cstring("")  // schedule_delivery_time
+	e.cstring("")  // validity_period
+	e.WriteByte(m.RegisteredDelivery)
+	e.WriteByte(0) // replace_if_present_flag
+	e.WriteByte(m.DataCoding)
+	e.WriteByte(0) // sm_default_msg_id
+	e.WriteByte(byte(len(m.ShortMessage)))
+	e.Write(m.ShortMessage)
+	return e.Bytes()
+}
+
+// UnmarshalMessage parses the body of submit_sm or deliver_sm.
+// The optional parameters (TLVs) after the short message are ignored.
+func UnmarshalMessage(body []byte) (*Message, error) {
+	d := decoder{b: body}
+	m := &Message{}
+
+	d.cstring() // service_type
+	d.byte()
+	d.byte()
+	m.Source = d.cstring()
+	d.byte()
+	d.byte()
+	m.Destination = d.cstring()
+	m.ESMClass = d.byte()
+	d.byte()    // protocol_id
+	d.byte()    // priority_flag
+	d.cstring() // schedule_delivery_time
+	d.cstring() // validity_period
+	m.RegisteredDelivery = d.byte()
+	d.byte() // replace_if_present_flag
+	m.DataCoding = d.byte()
+	d.byte() // sm_default_msg_id
+	m.ShortMessage = d.bytes(int(d.byte()))
+
+	return m, d.err
+}
+
+// IsReceipt tells if a deliver_sm is a delivery receipt, rather than an inbound message
+func (m *Message) IsReceipt() bool {
+	return m.ESMClass&0x3C == ESMClassReceipt
+}
+
+// UserData returns the short message without its User Data Header (if any), and the header
+func (m *Message) UserData() (data []byte, udh []byte) {
+	if m.ESMClass&ESMClassUDHI == 0 || len(m.ShortMessage) == 0 {
+		return m.ShortMessage, nil
+	}
+
+	n := int(m.ShortMessage[0]) + 1
+	if n > len(m.ShortMessage) {
+		return m.ShortMessage, nil
+	}
+
+	return m.ShortMessage[n:], m.ShortMessage[:n]
+}
+
+// encoder writes the fields of a body
+type encoder struct {
+	bytes.Buffer
+}
+
+// cstring writes a null-terminated string
+func (e *encoder) cstring(s string) {
+	e.WriteString(s)
+	e.WriteByte(0)
+}
+
+// decoder reads the fields of a body, the first error sticks and the rest are zero values
+type decoder struct {
+	b   []byte
+	err error
+}
+
+func (d *decoder) byte() byte {
+	if b := d.bytes(1); len(b) == 1 {
+		return b[0]
+	}
+
+	return 0
+}
+
+func (d *decoder) bytes(n int) []byte {
+	if d.err != nil || n > len(d.b) {
+		d.err = ErrMalformed
+		return nil
+	}
+
+	b := d.b[:n]
+	d.b = d.b[n:]
+	return b
+}
+
+func (d *decoder) cstring() string {
+	i := bytes.IndexByte(d.b, 0)
+	if d.err != nil || i < 0 {
+		d.err = ErrMalformed
+		return ""
+	}
+
+	s := string(d.b[:i])
+	d.b = d.b[i+1:]
+	return s
+}
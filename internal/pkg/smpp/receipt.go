@@ -0,0 +1,62 @@
+package smpp
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Receipt is a delivery receipt of a message, sent by the SMSC as the short message of a deliver_sm.
+//
+// Its format isn't part of SMPP 3.4 itself, but it is the one in its appendix, which most SMSCs follow:
+// "id:IIIIIIIIII sub:SSS dlvrd:DDD submit date:YYMMDDhhmm done date:YYMMDDhhmm stat:DDDDDDD err:E text:..."
+type Receipt struct {
+	ID   string // the message id of submit_sm_resp
+	Stat string // the final state, i.e. DELIVRD, UNDELIV, EXPIRED, REJECTD
+	Err  string // the network specific error code, if any
+	Text string // the first characters of the message, if any
+}
+
+// receiptFields are the fields of a receipt, in order
+var receiptFields = []string{"id", "sub", "dlvrd", "submit date", "done date", "stat", "err", "text"}
+
+// ParseReceipt parses the short message of a delivery receipt
+func ParseReceipt(text string) (*Receipt, error) {
+	fields := map[string]string{}
+
+	rest := text
+	for i, field := range receiptFields {
+		start := strings.Index(strings.ToLower(rest), field+":")
+		if start < 0 {
+			continue
+		}
+
+		value := rest[start+len(field)+1:]
+
+		// the value ends where the next field (that is there) starts
+		end := len(value)
+		for _, next := range receiptFields[i+1:] {
+			if j := strings.Index(strings.ToLower(value), " "+next+":"); j >= 0 && j < end {
+				end = j
+			}
+		}
+
+		fields[field] = strings.TrimSpace(value[:end])
+		rest = value[end:]
+	}
+
+	if fields["id"] == "" || fields["stat"] == "" {
+		return nil, fmt.Errorf("smpp: invalid delivery receipt %q", text)
+	}
+
+	return &Receipt{
+		ID:   fields["id"],
+		Stat: strings.ToUpper(fields["stat"]),
+		Err:  fields["err"],
+		Text: fields["text"],
+	}, nil
+}
+
+// Delivered tells if the message has been delivered, otherwise it has failed
+func (r *Receipt) Delivered() bool {
+	return r.Stat == "DELIVRD"
+}
@@ -0,0 +1,237 @@
+package smpp
+
+import (
+	"fmt"
+	"net"
+	"strconv"
+	"sync"
+	"time"
+)
+
+// Simulator is an SMSC to test the client against, without a real carrier.
+//
+// It accepts bind_transceiver of its system id and password, responds to submit_sm with a message id,
+// and sends a delivery receipt if asked for. Inbound messages are sent to the bound sessions by Deliver.
+type Simulator struct {
+	SystemID string
+	Password string
+
+	// ResponseDelay delays every submit_sm_resp, i.e. to test windowing
+	ResponseDelay time.Duration
+
+	// ReceiptDelay is how long after submit_sm_resp the delivery receipt is sent
+	ReceiptDelay time.Duration
+
+	// Undeliverable tells if a message to the destination fails, with an UNDELIV receipt
+	Undeliverable func(destination string) bool
+
+	// Reject tells if a submit_sm is rejected, with ESME_RSYSERR
+	Reject func(m *Message) bool
+
+	listener net.Listener
+
+	mu             sync.Mutex
+	sessions       map[*session]bool
+	submitted      []*Message
+	outstanding    int // submit_sm received, and not responded to yet
+	maxOutstanding int
+	enquireLinks   int
+	sequence       int
+}
+
+// NewSimulator creates a simulator that accepts the given system id and password
+func NewSimulator(systemID, password string) *Simulator {
+	return &Simulator{SystemID: systemID, Password: password, sessions: map[*session]bool{}}
+}
+
+// Start listens on the given address, i.e. "127.0.0.1:0" for any free port
+func (sim *Simulator) Start(addr string) error {
+	l, err := net.Listen("tcp", addr)
+	if err != nil {
+		return err
+	}
+
+	sim.listener = l
+	go sim.accept()
+
+	return nil
+}
+
+// Addr returns the address it listens on
+func (sim *Simulator) Addr() string {
+	return sim.listener.Addr().String()
+}
+
+// Close stops listening, and closes all the sessions
+func (sim *Simulator) Close() error {
+	err := sim.listener.Close()
+	sim.Disconnect()
+	return err
+}
+
+// Disconnect closes all the sessions without an unbind, as if the connection was lost
+func (sim *Simulator) Disconnect() {
+	sim.mu.Lock()
+	defer sim.mu.Unlock()
+
+	for s := range sim.sessions {
+		s.close(ErrClosed)
+		delete(sim.sessions, s)
+	}
+}
+
+// Submitted returns the messages received by submit_sm, in order
+func (sim *Simulator) Submitted() []*Message {
+	sim.mu.Lock()
+	defer sim.mu.Unlock()
+
+	return append([]*Message{}, sim.submitted...)
+}
+
+// MaxOutstanding returns the max number of submit_sm that were waiting for their responses at the same time
+func (sim *Simulator) MaxOutstanding() int {
+	sim.mu.Lock()
+	defer sim.mu.Unlock()
+
+	return sim.maxOutstanding
+}
+
+// EnquireLinks returns the number of enquire_link received
+func (sim *Simulator) EnquireLinks() int {
+	sim.mu.Lock()
+	defer sim.mu.Unlock()
+
+	return sim.enquireLinks
+}
+
+// Deliver sends an inbound message by deliver_sm to one of the bound sessions
+func (sim *Simulator) Deliver(m *Message) error {
+	sim.mu.Lock()
+	var s *session
+	for s = range sim.sessions {
+		break
+	}
+	sim.mu.Unlock()
+
+	if s == nil {
+		return ErrNotBound
+	}
+
+	return sim.deliver(s, m)
+}
+
+func (sim *Simulator) accept() {
+	for {
+		conn, err := sim.listener.Accept()
+		if err != nil {
+			return
+		}
+
+		go sim.serve(conn)
+	}
+}
+
+// serve reads the PDUs of a connection, the first one must be a bind
+func (sim *Simulator) serve(conn net.Conn) {
+	p, err := readPDU(conn)
+	if err != nil || p.CommandID != BindTransceiver {
+		conn.Close()
+		return
+	}
+
+	bind, err := UnmarshalBind(p.Body)
+	if err != nil || bind.SystemID != sim.SystemID || bind.Password != sim.Password {
+		writePDU(conn, &PDU{CommandID: BindTransceiverResp, Status: StatusInvalidPwd, Sequence: p.Sequence})
+		conn.Close()
+		return
+	}
+
+	s := newSession(conn, sim.handle)
+	s.respond(p, BindTransceiverResp, StatusOK, append([]byte("simulator"), 0))
+
+	sim.mu.Lock()
+	sim.sessions[s] = true
+	sim.mu.Unlock()
+
+	<-s.done
+
+	sim.mu.Lock()
+	delete(sim.sessions, s)
+	sim.mu.Unlock()
+}
+
+// handle handles a request of a session, other than unbind.
+// The responses to deliver_sm are dropped, since it isn't retried.
+func (sim *Simulator) handle(s *session, p *PDU) {
+	switch p.CommandID {
+	case SubmitSM:
+		go sim.submit(s, p)
+	case EnquireLink:
+		sim.mu.Lock()
+		sim.enquireLinks++
+		sim.mu.Unlock()
+
+		s.respond(p, EnquireLinkResp, StatusOK, nil)
+	default:
+		s.respond(p, GenericNack, StatusInvalidCmdID, nil)
+	}
+}
+
+// submit responds to a submit_sm after the response delay, then sends its delivery receipt (if asked for)
+func (sim *Simulator) submit(s *session, p *PDU) {
+	m, err := UnmarshalMessage(p.Body)
+	if err != nil || (sim.Reject != nil && sim.Reject(m)) {
+		s.respond(p, SubmitSMResp, StatusSysErr, nil)
+		return
+	}
+
+	sim.mu.Lock()
+	sim.sequence++
+	id := "sim-" + strconv.Itoa(sim.sequence)
+	sim.submitted = append(sim.submitted, m)
+	sim.outstanding++
+	if sim.outstanding > sim.maxOutstanding {
+		sim.maxOutstanding = sim.outstanding
+	}
+	sim.mu.Unlock()
+
+	time.Sleep(sim.ResponseDelay)
+
+	sim.mu.Lock()
+	sim.outstanding--
+	sim.mu.Unlock()
+
+	s.respond(p, SubmitSMResp, StatusOK, append([]byte(id), 0))
+
+	if m.RegisteredDelivery&1 == 0 {
+		return
+	}
+
+	time.Sleep(sim.ReceiptDelay)
+
+	stat, errCode := "DELIVRD", "000"
+	if sim.Undeliverable != nil && sim.Undeliverable(m.Destination) {
+		stat, errCode = "UNDELIV", "001"
+	}
+
+	date := time.Now().UTC().Format("0601021504")
+	receipt := fmt.Sprintf("id:%s sub:001 dlvrd:001 submit date:%s done date:%s stat:%s err:%s text:", id, date, date, stat, errCode)
+
+	sim.deliver(s, &Message{
+		Source:       m.Destination,
+		Destination:  m.Source,
+		ESMClass:     ESMClassReceipt,
+		DataCoding:   CodingDefault,
+		ShortMessage: []byte(receipt),
+	})
+}
+
+// deliver sends a deliver_sm without waiting for its response
+func (sim *Simulator) deliver(s *session, m *Message) error {
+	s.mu.Lock()
+	s.sequence++
+	seq := s.sequence
+	s.mu.Unlock()
+
+	return s.write(&PDU{CommandID: DeliverSM, Sequence: seq, Body: m.Marshal()})
+}
@@ -0,0 +1,73 @@
+package token
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"strings"
+	"time"
+
+	"github.com/OmarElGabry/go-textnow/internal/pkg/signature"
+)
+
+var (
+	// ErrInvalid is returned when a token is malformed, or its signature doesn't match
+	ErrInvalid = errors.New("invalid token")
+
+	// ErrExpired is returned when a token has expired
+	ErrExpired = errors.New("token has expired")
+)
+
+// Claims are what a token says about its holder
+type Claims struct {
+	UserID      int32  `json:"sub"`
+	PhoneNumber string `json:"phone"`
+	ExpiresAt   int64  `json:"exp"` // unix time in seconds
+}
+
+// Sign creates a token of the given claims, signed by HMAC-SHA256 with the given secret.
+//
+// The token is "<claims>.<signature>", both base64url encoded. It is issued by the
+// service the user logs in to, and so it has to share the same secret with the gateway.
+func Sign(secret []byte, claims Claims) (string, error) {
+	buf, err := json.Marshal(claims)
+	if err != nil {
+		return "", err
+	}
+
+	payload := base64.RawURLEncoding.EncodeToString(buf)
+	return payload + "." + base64.RawURLEncoding.EncodeToString(signature.MAC(secret, []byte(payload))), nil
+}
+
+// Verify checks the signature and the expiry of the given token, and returns its claims
+func Verify(secret []byte, token string) (*Claims, error) {
+	parts := strings.Split(token, ".")
+	if len(parts) != 2 {
+		return nil, ErrInvalid
+	}
+
+	mac, err := base64.RawURLEncoding.DecodeString(parts[1])
+	if err != nil {
+		return nil, ErrInvalid
+	}
+
+	if !signature.Match(secret, mac, []byte(parts[0])) {
+		return nil, ErrInvalid
+	}
+
+	buf, err := base64.RawURLEncoding.DecodeString(parts[0])
+	if err != nil {
+		return nil, ErrInvalid
+	}
+
+	var claims Claims
+	if err := json.Unmarshal(buf, &claims); err != nil {
+		return nil, ErrInvalid
+	}
+
+	if time.Now().Unix() >= claims.ExpiresAt {
+		return nil, ErrExpired
+	}
+
+	return &claims, nil
+}
@@ -1,15 +1,21 @@
 package validator
 
 import (
+	grpc_middleware "github.com/grpc-ecosystem/go-grpc-middleware"
 	grpc_validator "github.com/grpc-ecosystem/go-grpc-middleware/validator"
 	"google.golang.org/grpc"
 )
 
 // Middlewares returns middlewares (unary and stream)
-// for validation of user input values
-func Middlewares() []grpc.ServerOption {
+// for validation of user input values.
+//
+// The given unary interceptors (i.e. idempotency) run after the validation,
+// and so an invalid request never reaches them.
+func Middlewares(unary ...grpc.UnaryServerInterceptor) []grpc.ServerOption {
+	unary = append([]grpc.UnaryServerInterceptor{grpc_validator.UnaryServerInterceptor()}, unary...)
+
 	opts := []grpc.ServerOption{
-		grpc.UnaryInterceptor(grpc_validator.UnaryServerInterceptor()),
+		grpc.UnaryInterceptor(grpc_middleware.ChainUnaryServer(unary...)),
 		grpc.StreamInterceptor(grpc_validator.StreamServerInterceptor()),
 	}
 
@@ -0,0 +1,151 @@
+package sms
+
+import (
+	context "context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/OmarElGabry/go-textnow/internal/pkg/logger"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// AutoReplies are the messages sent back to a recipient that replies with a keyword:
+// to confirm it has opted out (STOP) or in (START), or to tell who the sender is (HELP).
+// No message is sent back for an empty one.
+type AutoReplies struct {
+	OptOut string
+	OptIn  string
+	Help   string
+}
+
+// keyword is what a recipient asks for by replying with a keyword
+type keyword int
+
+const (
+	noKeyword keyword = iota
+	optOutKeyword
+	optInKeyword
+	helpKeyword
+)
+
+// keywords are the keywords the carriers and regulators expect to be supported
+var keywords = map[string]keyword{
+	"STOP":        optOutKeyword,
+	"STOPALL":     optOutKeyword,
+	"UNSUBSCRIBE": optOutKeyword,
+	"CANCEL":      optOutKeyword,
+	"END":         optOutKeyword,
+	"QUIT":        optOutKeyword,
+	"START":       optInKeyword,
+	"YES":         optInKeyword,
+	"UNSTOP":      optInKeyword,
+	"HELP":        helpKeyword,
+	"INFO":        helpKeyword,
+}
+
+// parseKeyword returns the keyword of a message that has nothing but a keyword in it,
+// in any case, and surrounded by spaces or punctuation, i.e. " Stop. ", along with the word itself
+func parseKeyword(content string) (string, keyword) {
+	word := strings.ToUpper(strings.Trim(content, " \t\r\n.!?"))
+	return word, keywords[word]
+}
+
+// consent is a document in the "consents" collection, one for every phone number on the platform (From)
+// and recipient (To) where the recipient has replied with an opt-out or opt-in keyword.
+// A recipient that has never replied with any has opted in.
+type consent struct {
+	From      string    `bson:"from"`
+	To        string    `bson:"to"`
+	OptedOut  bool      `bson:"optedOut"`
+	Keyword   string    `bson:"keyword"` // the last one, i.e. "STOP"
+	UpdatedAt time.Time `bson:"updatedAt"`
+}
+
+// autoReplyKey marks the context of an auto-reply, which is sent even to a recipient that has opted out
+type autoReplyKey struct{}
+
+// checkConsent checks the recipient hasn't opted out of the messages of the sender,
+// and fails with FailedPrecondition otherwise
+func (s *server) checkConsent(ctx context.Context, from, to string) error {
+	if ctx.Value(autoReplyKey{}) != nil {
+		return nil
+	}
+
+	var c consent
+	err := s.consents.FindOne(ctx, bson.M{"from": from, "to": to}).Decode(&c)
+	if err == mongo.ErrNoDocuments {
+		return nil
+	}
+
+	if err != nil {
+		return status.Error(codes.Internal, "Internal error "+err.Error())
+	}
+
+	if c.OptedOut {
+		return status.Errorf(codes.FailedPrecondition, "Recipient has opted out of the messages of %s (replied %s)", from, c.Keyword)
+	}
+
+	return nil
+}
+
+// handleKeyword handles an inbound message that is a keyword, from a recipient (the sender of the message)
+// to a phone number on the platform (its recipient): it records the consent of an opt-out or opt-in keyword,
+// then sends the auto-reply back in the background.
+//
+// The message has been stored already, and so a failure is logged rather than returned.
+func (s *server) handleKeyword(ctx context.Context, m *message) {
+	word, k := parseKeyword(m.Content)
+	if k == noKeyword {
+		return
+	}
+
+	from, to := m.To, m.From // of the messages the keyword is about
+	reply := s.autoReplies.Help
+
+	if k == optOutKeyword || k == optInKeyword {
+		optedOut := k == optOutKeyword
+		if optedOut {
+			reply = s.autoReplies.OptOut
+		} else {
+			reply = s.autoReplies.OptIn
+		}
+
+		upsert := true // create it if not exists
+		_, err := s.consents.UpdateOne(ctx, bson.M{"from": from, "to": to}, bson.M{"$set": bson.M{
+			"optedOut":  optedOut,
+			"keyword":   word,
+			"updatedAt": time.Now().UTC(),
+		}}, &options.UpdateOptions{Upsert: &upsert})
+
+		if err != nil {
+			logger.Error(fmt.Sprintf("Failed to store the consent of %s to %s error: %v", to, from, err))
+			return
+		}
+	}
+
+	if reply == "" {
+		return
+	}
+
+	// the reply is idempotent by the inbound message, and so it is sent once
+	go func() {
+		_, err := s.SendOne(context.WithValue(context.Background(), autoReplyKey{}, true), &SendOneRequest{Sms: &SMS{
+			IdempotencyKey:  "auto-reply-" + m.ID.Hex(),
+			FromPhoneNumber: from,
+			ToPhoneNumber:   to,
+			Content:         reply,
+			Priority:        Priority_URGENT, // a reply to the recipient can't wait for the morning
+		}})
+
+		if err != nil {
+			logger.Error(fmt.Sprintf("Failed to send the auto-reply from %s to %s error: %v", from, to, err))
+		}
+	}()
+}
@@ -0,0 +1,71 @@
+package sms
+
+import (
+	context "context"
+	"time"
+
+	"github.com/OmarElGabry/go-textnow/internal/pkg/contentfilter"
+
+	"go.mongodb.org/mongo-driver/bson"
+)
+
+// filterDecision is a rule of the content filter that matched the content of a message,
+// and what it did to it, stored on the message. Action is stored by its name, i.e. "FLAG".
+type filterDecision struct {
+	Rule    string   `bson:"rule"`
+	Action  string   `bson:"action"`
+	Matches []string `bson:"matches,omitempty"`
+}
+
+// filterContent filters the content of an sms sent by the given account by the current rules of the content filter.
+// Without a content filter, every sms is let through as is.
+func (s *server) filterContent(content string, userID int32) ([]filterDecision, *contentfilter.Result) {
+	res := s.contentFilter.Filter().Apply(content, userID)
+
+	decisions := make([]filterDecision, len(res.Decisions))
+	for i, d := range res.Decisions {
+		decisions[i] = filterDecision{Rule: d.Rule, Action: string(d.Action), Matches: d.Matches}
+	}
+
+	return decisions, res
+}
+
+// reject stores a message rejected by the content filter as FAILED, along with the decisions,
+// and notifies the sender. Like a failure of the carrier, it is final.
+func (s *server) reject(ctx context.Context, filter bson.M, msg *message) error {
+	rejectedBy := msg.FilterDecisions[len(msg.FilterDecisions)-1].Rule
+	msg.Status, msg.UpdatedAt = Status_FAILED.String(), time.Now().UTC()
+	msg.FailureReason = "Content is rejected by rule " + rejectedBy
+
+	_, err := s.db.UpdateOne(ctx, filter, bson.M{"$set": bson.M{
+		"from":            msg.From,
+		"to":              msg.To,
+		"content":         msg.Content,
+		"status":          msg.Status,
+		"failureReason":   msg.FailureReason,
+		"encoding":        msg.Encoding,
+		"originalContent": msg.OriginalContent,
+		"templateId":      msg.TemplateID,
+		"route":           msg.Route,
+		"filterDecisions": msg.FilterDecisions,
+		"createdAt":       msg.CreatedAt,
+		"updatedAt":       msg.UpdatedAt,
+	}})
+
+	if err != nil {
+		return err
+	}
+
+	s.publishEvent(ctx, msg.From, SubscribeResponse_STATUS, msg)
+
+	return nil
+}
+
+// toProto converts the decision to the one defined in .proto file
+func (d *filterDecision) toProto() *FilterDecision {
+	return &FilterDecision{
+		Rule:    d.Rule,
+		Action:  FilterDecision_Action(FilterDecision_Action_value[d.Action]),
+		Matches: d.Matches,
+	}
+}
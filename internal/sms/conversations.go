@@ -0,0 +1,208 @@
+package sms
+
+import (
+	context "context"
+	"time"
+
+	"github.com/OmarElGabry/go-textnow/internal/pkg/cursor"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+const (
+	defaultConversationsPageSize = 20
+	defaultThreadPageSize        = 50
+)
+
+// unsentStatuses are the statuses of the messages that are not part of the conversations
+var unsentStatuses = []string{Status_SCHEDULED.String(), Status_CANCELED.String()}
+
+// messagesPosition is the position of the last message in a page.
+//
+// Messages are ordered by their creation time, while the id breaks the ties.
+type messagesPosition struct {
+	CreatedAt time.Time `json:"t"`
+	ID        string    `json:"id"`
+}
+
+// conversation is the result of grouping the messages by the other phone number
+type conversation struct {
+	PhoneNumber string  `bson:"_id"`
+	Last        message `bson:"last"`
+}
+
+// ListConversations method lists the conversations of a phone number, page by page.
+//
+// The messages from or to the phone number are grouped by the other phone number,
+// where every group (conversation) is represented by its latest message.
+// Messages stored before the timestamps were recorded, and the ones that are yet to be sent
+// (scheduled or canceled) are not listed.
+func (s *server) ListConversations(ctx context.Context, req *ListConversationsRequest) (*ListConversationsResponse, error) {
+	phoneNumber := req.GetPhoneNumber()
+	pageSize := int(req.GetPageSize())
+	if pageSize == 0 {
+		pageSize = defaultConversationsPageSize
+	}
+
+	pipeline := []bson.M{
+		{"$match": bson.M{
+			"$or":       []bson.M{{"from": phoneNumber}, {"to": phoneNumber}},
+			"createdAt": bson.M{"$exists": true},
+			"status":    bson.M{"$nin": unsentStatuses},
+		}},
+		{"$sort": bson.D{{Key: "createdAt", Value: -1}, {Key: "_id", Value: -1}}},
+		{"$group": bson.M{
+			"_id":  bson.M{"$cond": []interface{}{bson.M{"$eq": []string{"$from", phoneNumber}}, "$to", "$from"}},
+			"last": bson.M{"$first": "$$ROOT"},
+		}},
+	}
+
+	// start after the position of the cursor (if any)
+	if req.GetCursor() != "" {
+		before, err := beforePosition(req.GetCursor(), "last.")
+		if err != nil {
+			return nil, err
+		}
+
+		pipeline = append(pipeline, bson.M{"$match": before})
+	}
+
+	// query one more conversation than the page size to know if there is a next page
+	pipeline = append(pipeline,
+		bson.M{"$sort": bson.D{{Key: "last.createdAt", Value: -1}, {Key: "last._id", Value: -1}}},
+		bson.M{"$limit": pageSize + 1})
+
+	cur, err := s.db.Aggregate(ctx, pipeline)
+	if err != nil {
+		return nil, status.Error(codes.Internal, "Internal error "+err.Error())
+	}
+	defer cur.Close(ctx)
+
+	conversations := []*Conversation{}
+	lasts := []*message{}
+	for cur.Next(ctx) {
+		var c conversation
+		if err := cur.Decode(&c); err != nil {
+			return nil, status.Error(codes.Internal, "Internal error "+err.Error())
+		}
+
+		conversations = append(conversations, &Conversation{PhoneNumber: c.PhoneNumber, LastMessage: c.Last.toProto()})
+		lasts = append(lasts, &c.Last)
+	}
+
+	if err := cur.Err(); err != nil {
+		return nil, status.Error(codes.Internal, "Internal error "+err.Error())
+	}
+
+	nextCursor := ""
+	if len(conversations) > pageSize {
+		conversations = conversations[:pageSize]
+		if nextCursor, err = encodePosition(lasts[pageSize-1]); err != nil {
+			return nil, err
+		}
+	}
+
+	return &ListConversationsResponse{Conversations: conversations, NextCursor: nextCursor}, nil
+}
+
+// GetThread method gets the messages between two phone numbers, page by page
+func (s *server) GetThread(ctx context.Context, req *GetThreadRequest) (*GetThreadResponse, error) {
+	phoneNumber, otherPhoneNumber := req.GetPhoneNumber(), req.GetOtherPhoneNumber()
+	pageSize := int(req.GetPageSize())
+	if pageSize == 0 {
+		pageSize = defaultThreadPageSize
+	}
+
+	filter := bson.M{
+		"$or": []bson.M{
+			{"from": phoneNumber, "to": otherPhoneNumber},
+			{"from": otherPhoneNumber, "to": phoneNumber},
+		},
+		"createdAt": bson.M{"$exists": true},
+		"status":    bson.M{"$nin": unsentStatuses},
+	}
+
+	// start after the position of the cursor (if any)
+	if req.GetCursor() != "" {
+		before, err := beforePosition(req.GetCursor(), "")
+		if err != nil {
+			return nil, err
+		}
+
+		filter = bson.M{"$and": []bson.M{filter, before}}
+	}
+
+	// query one more message than the page size to know if there is a next page
+	opts := options.Find().
+		SetSort(bson.D{{Key: "createdAt", Value: -1}, {Key: "_id", Value: -1}}).
+		SetLimit(int64(pageSize + 1))
+
+	cur, err := s.db.Find(ctx, filter, opts)
+	if err != nil {
+		return nil, status.Error(codes.Internal, "Internal error "+err.Error())
+	}
+	defer cur.Close(ctx)
+
+	messages := []*message{}
+	for cur.Next(ctx) {
+		var m message
+		if err := cur.Decode(&m); err != nil {
+			return nil, status.Error(codes.Internal, "Internal error "+err.Error())
+		}
+
+		messages = append(messages, &m)
+	}
+
+	if err := cur.Err(); err != nil {
+		return nil, status.Error(codes.Internal, "Internal error "+err.Error())
+	}
+
+	nextCursor := ""
+	if len(messages) > pageSize {
+		messages = messages[:pageSize]
+		if nextCursor, err = encodePosition(messages[pageSize-1]); err != nil {
+			return nil, err
+		}
+	}
+
+	res := &GetThreadResponse{Messages: make([]*Message, len(messages)), NextCursor: nextCursor}
+	for i, m := range messages {
+		res.Messages[i] = m.toProto()
+	}
+
+	return res, nil
+}
+
+// encodePosition encodes the position of the given message as a cursor
+func encodePosition(m *message) (string, error) {
+	c, err := cursor.Encode(messagesPosition{CreatedAt: m.CreatedAt, ID: m.ID.Hex()})
+	if err != nil {
+		return "", status.Error(codes.Internal, "Internal error "+err.Error())
+	}
+
+	return c, nil
+}
+
+// beforePosition decodes the given cursor into a filter of the messages that come after it,
+// which are the older ones. The prefix is the path of the message fields in the documents.
+func beforePosition(c string, prefix string) (bson.M, error) {
+	var position messagesPosition
+	if err := cursor.Decode(c, &position); err != nil {
+		return nil, status.Error(codes.InvalidArgument, "Invalid cursor")
+	}
+
+	id, err := primitive.ObjectIDFromHex(position.ID)
+	if err != nil {
+		return nil, status.Error(codes.InvalidArgument, "Invalid cursor")
+	}
+
+	return bson.M{"$or": []bson.M{
+		{prefix + "createdAt": bson.M{"$lt": position.CreatedAt}},
+		{prefix + "createdAt": position.CreatedAt, prefix + "_id": bson.M{"$lt": id}},
+	}}, nil
+}
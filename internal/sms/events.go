@@ -0,0 +1,220 @@
+package sms
+
+import (
+	context "context"
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/OmarElGabry/go-textnow/internal/pkg/cursor"
+	"github.com/OmarElGabry/go-textnow/internal/pkg/logger"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+const (
+	// eventsPollInterval is how often the events are checked even without a notification,
+	// in case one is missed (i.e. the connection to Redis was lost for a moment).
+	eventsPollInterval = 5 * time.Second
+
+	// eventsGapTimeout is how long a subscriber waits for a missing event.
+	//
+	// Events are numbered before they are inserted, and so an event could be inserted
+	// after the one next to it. If the missing event doesn't show up (its insert failed), it is skipped.
+	eventsGapTimeout = 2 * time.Second
+)
+
+// event is a document in the "events" collection.
+//
+// Every phone number has its own sequence of events, and so a subscriber
+// knows where to resume from by the sequence of the last event it received.
+type event struct {
+	ID          primitive.ObjectID `bson:"_id"`
+	PhoneNumber string             `bson:"phoneNumber"`
+	Seq         int64              `bson:"seq"`
+	Type        string             `bson:"type"`
+	Message     message            `bson:"message"`
+	CreatedAt   time.Time          `bson:"createdAt"`
+}
+
+// eventsPosition is the position of the last event the client received
+type eventsPosition struct {
+	Seq int64 `json:"seq"`
+}
+
+// Subscribe method pushes the new messages to a phone number,
+// and the status changes of the messages from it, as they happen.
+//
+// Events are stored in MongoDB, and every new one is announced on a Redis channel
+// of its phone number, so that the subscribers on all replicas know about it.
+//  1. Subscribe to the channel first, so that no announcement is missed.
+//  2. Send the events after the cursor (if any), which were missed while disconnected.
+//  3. On every announcement, send the events after the last sent one.
+func (s *server) Subscribe(req *SubscribeRequest, stream SMSService_SubscribeServer) error {
+	ctx := stream.Context()
+	phoneNumber := req.GetPhoneNumber()
+
+	// 1) Subscribe to the channel of the phone number
+	pubsub := s.cache.Subscribe(eventsChannel(phoneNumber))
+	defer pubsub.Close()
+
+	// wait for the confirmation of the subscription
+	if _, err := pubsub.Receive(); err != nil {
+		return status.Error(codes.Internal, "Internal error "+err.Error())
+	}
+
+	// 2) Start after the cursor, or after the last event if there is no cursor
+	var last int64
+	if req.GetCursor() != "" {
+		var position eventsPosition
+		if err := cursor.Decode(req.GetCursor(), &position); err != nil {
+			return status.Error(codes.InvalidArgument, "Invalid cursor")
+		}
+
+		last = position.Seq
+	} else {
+		seq, err := s.lastEventSeq(ctx, phoneNumber)
+		if err != nil {
+			return status.Error(codes.Internal, "Internal error "+err.Error())
+		}
+
+		last = seq
+	}
+
+	// 3) Send the events after the last sent one, on every announcement
+	ticker := time.NewTicker(eventsPollInterval)
+	defer ticker.Stop()
+
+	ch := pubsub.Channel()
+	for {
+		seq, err := s.sendEvents(ctx, stream, phoneNumber, last)
+		if err != nil {
+			return err
+		}
+
+		last = seq
+
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-ch:
+		case <-ticker.C:
+		}
+	}
+}
+
+// sendEvents sends the events of a phone number after the given sequence in order,
+// and returns the sequence of the last sent one
+func (s *server) sendEvents(ctx context.Context, stream SMSService_SubscribeServer, phoneNumber string, last int64) (int64, error) {
+	cur, err := s.events.Find(ctx, bson.M{"phoneNumber": phoneNumber, "seq": bson.M{"$gt": last}},
+		options.Find().SetSort(bson.M{"seq": 1}))
+	if err != nil {
+		return last, status.Error(codes.Internal, "Internal error "+err.Error())
+	}
+	defer cur.Close(ctx)
+
+	for cur.Next(ctx) {
+		var e event
+		if err := cur.Decode(&e); err != nil {
+			return last, status.Error(codes.Internal, "Internal error "+err.Error())
+		}
+
+		// the event before this one is missing, wait for it a while
+		if e.Seq != last+1 && time.Since(e.CreatedAt) < eventsGapTimeout {
+			break
+		}
+
+		c, err := cursor.Encode(eventsPosition{Seq: e.Seq})
+		if err != nil {
+			return last, status.Error(codes.Internal, "Internal error "+err.Error())
+		}
+
+		err = stream.Send(&SubscribeResponse{
+			Type:    SubscribeResponse_Type(SubscribeResponse_Type_value[e.Type]),
+			Message: e.Message.toProto(),
+			Cursor:  c,
+		})
+
+		if err != nil {
+			return last, err
+		}
+
+		last = e.Seq
+	}
+
+	if err := cur.Err(); err != nil {
+		return last, status.Error(codes.Internal, "Internal error "+err.Error())
+	}
+
+	return last, nil
+}
+
+// publishEvent stores an event for the given phone number, and announces it to the subscribers,
+// and queues it to be sent to the webhooks of the account that owns the phone number.
+//
+// The message has been sent already, and so a failure is logged rather than returned.
+func (s *server) publishEvent(ctx context.Context, phoneNumber string, eventType SubscribeResponse_Type, m *message) {
+	seq, err := s.nextEventSeq(ctx, phoneNumber)
+	if err != nil {
+		logger.Error(fmt.Sprintf("Failed to number an event of %s error: %v", phoneNumber, err))
+		return
+	}
+
+	_, err = s.events.InsertOne(ctx, &event{
+		ID:          primitive.NewObjectID(),
+		PhoneNumber: phoneNumber,
+		Seq:         seq,
+		Type:        eventType.String(),
+		Message:     *m,
+		CreatedAt:   time.Now().UTC(),
+	})
+
+	if err != nil {
+		logger.Error(fmt.Sprintf("Failed to store an event of %s error: %v", phoneNumber, err))
+		return
+	}
+
+	// subscribers poll for the events anyway, and so a failure only delays them
+	err = s.cache.Publish(eventsChannel(phoneNumber), strconv.FormatInt(seq, 10)).Err()
+	if err != nil {
+		logger.Error(fmt.Sprintf("Failed to announce an event of %s error: %v", phoneNumber, err))
+	}
+
+	// the account that owns the phone number: the recipient of a new message, and the sender of a status change
+	userID := m.FromUserID
+	if eventType == SubscribeResponse_MESSAGE {
+		userID = m.ToUserID
+	}
+
+	s.enqueueDeliveries(ctx, phoneNumber, userID, eventType, m)
+}
+
+// nextEventSeq increments and returns the sequence of events of the given phone number
+func (s *server) nextEventSeq(ctx context.Context, phoneNumber string) (int64, error) {
+	return s.nextSeq(ctx, "events-"+phoneNumber)
+}
+
+// lastEventSeq returns the sequence of the last event of the given phone number, or 0 if none
+func (s *server) lastEventSeq(ctx context.Context, phoneNumber string) (int64, error) {
+	var counter struct {
+		Seq int64 `bson:"seq"`
+	}
+
+	err := s.counters.FindOne(ctx, bson.M{"_id": "events-" + phoneNumber}).Decode(&counter)
+	if err == mongo.ErrNoDocuments {
+		return 0, nil
+	}
+
+	return counter.Seq, err
+}
+
+// eventsChannel returns the Redis channel the events of the given phone number are announced on
+func eventsChannel(phoneNumber string) string {
+	return "sms-events-" + phoneNumber
+}
@@ -0,0 +1,22 @@
+package sms
+
+import (
+	"github.com/OmarElGabry/go-textnow/internal/pkg/idempotency"
+)
+
+// IdempotentMethods are the methods that opt into the idempotency interceptor (see idempotency.New),
+// and so a retry of a request gets the response of the first one.
+// SendOne reads the key from the sms, while the others only from the metadata.
+//
+// SendOne is idempotent by itself as well (see isIdempotent), for the SMSs of SendMany,
+// which are sent by calling it directly rather than through the interceptor.
+//
+// CreateWebhook doesn't opt in, since the interceptor would store its response,
+// and so the signing secret of the webhook, as it is.
+var IdempotentMethods = map[string]idempotency.KeyFunc{
+	"/sms.SMSService/SendOne": func(req interface{}) string {
+		return req.(*SendOneRequest).GetSms().GetIdempotencyKey()
+	},
+	"/sms.SMSService/CreateTemplate":   nil,
+	"/sms.SMSService/ReplayDeliveries": nil,
+}
@@ -0,0 +1,133 @@
+package sms
+
+import (
+	context "context"
+	"fmt"
+	"time"
+
+	"github.com/OmarElGabry/go-textnow/internal/pkg/carrier"
+	"github.com/OmarElGabry/go-textnow/internal/pkg/gsm"
+	"github.com/OmarElGabry/go-textnow/internal/pkg/logger"
+	"github.com/OmarElGabry/go-textnow/internal/pkg/mongodb"
+	"github.com/OmarElGabry/go-textnow/internal/pkg/phonenumber"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// Receive method stores a message received by a carrier, sent to a phone number on the platform.
+//
+// The message is stored in the same collection as the ones sent by the users, as INBOUND,
+// and it is DELIVERED once it is stored. And so it is in the conversations of the recipient,
+// and the recipient is notified of it like any other new message.
+//
+// Carriers retry a message until they get a response, and so a message of the same
+// carrier message id is stored once, by the same upsert as the idempotency key of SendOne.
+//
+// A message that is a keyword (STOP, START or HELP) is stored like any other, then it is handled (see handleKeyword).
+func (s *server) Receive(ctx context.Context, req *ReceiveRequest) (*ReceiveResponse, error) {
+	from, err := phonenumber.Normalize(req.GetFromPhoneNumber())
+	if err != nil {
+		return nil, status.Error(codes.InvalidArgument, "Invalid from phone number")
+	}
+
+	to, err := phonenumber.Normalize(req.GetToPhoneNumber())
+	if err != nil {
+		return nil, status.Error(codes.InvalidArgument, "Invalid to phone number")
+	}
+
+	// only the recipient has to be on the platform
+	toUserID, err := s.findOwner(ctx, to)
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now().UTC()
+	m := &message{
+		From:             from,
+		To:               to,
+		Content:          req.GetContent(),
+		Status:           Status_DELIVERED.String(),
+		Direction:        Direction_INBOUND.String(),
+		Route:            Route_OFF_NET.String(),
+		Encoding:         gsm.Detect(req.GetContent()).String(),
+		Carrier:          req.GetCarrier(),
+		CarrierMessageID: req.GetCarrierMessageId(),
+		ToUserID:         toUserID,
+		CreatedAt:        now,
+		UpdatedAt:        now,
+	}
+
+	fields := bson.M{
+		"from":      m.From,
+		"to":        m.To,
+		"content":   m.Content,
+		"status":    m.Status,
+		"direction": m.Direction,
+		"route":     m.Route,
+		"encoding":  m.Encoding,
+		"carrier":   m.Carrier,
+		"toUserId":  m.ToUserID,
+		"createdAt": m.CreatedAt,
+		"updatedAt": m.UpdatedAt,
+	}
+
+	if m.CarrierMessageID == "" {
+		m.ID = primitive.NewObjectID()
+		fields["_id"] = m.ID
+
+		if _, err := s.db.InsertOne(ctx, fields); err != nil {
+			return nil, status.Error(codes.Internal, "Internal error "+err.Error())
+		}
+	} else {
+		filter := bson.M{
+			"direction":        Direction_INBOUND.String(),
+			"carrier":          m.Carrier,
+			"carrierMessageId": m.CarrierMessageID,
+		}
+
+		upsert := true // create it if not exists
+		res, err := s.db.UpdateOne(ctx, filter, bson.M{"$setOnInsert": fields}, &options.UpdateOptions{Upsert: &upsert})
+		if err != nil && !mongodb.IsDuplicateKey(err) {
+			return nil, status.Error(codes.Internal, "Internal error "+err.Error())
+		}
+
+		// already exists, or inserted by a concurrent retry of the carrier
+		if err != nil || res.UpsertedCount == 0 {
+			var existing message
+			if err := s.db.FindOne(ctx, filter).Decode(&existing); err != nil {
+				return nil, status.Error(codes.Internal, "Internal error "+err.Error())
+			}
+
+			return &ReceiveResponse{MessageId: existing.ID.Hex(), Duplicate: true}, nil
+		}
+
+		m.ID = res.UpsertedID.(primitive.ObjectID)
+	}
+
+	s.publishEvent(ctx, m.To, SubscribeResponse_MESSAGE, m)
+
+	// i.e. STOP, which opts the sender out of the messages of the recipient
+	s.handleKeyword(ctx, m)
+
+	return &ReceiveResponse{MessageId: m.ID.Hex()}, nil
+}
+
+// handleInbound receives a message received by a carrier that reports back (i.e. over SMPP)
+func (s *server) handleInbound(in *carrier.Inbound) {
+	_, err := s.Receive(context.Background(), &ReceiveRequest{
+		FromPhoneNumber: in.From,
+		ToPhoneNumber:   in.To,
+		Content:         in.Content,
+		Carrier:         in.Carrier,
+	})
+
+	if err != nil {
+		logger.Error(fmt.Sprintf("Failed to receive a message from %s to %s by %s carrier error: %v",
+			in.From, in.To, in.Carrier, err))
+	}
+}
@@ -0,0 +1,156 @@
+package sms
+
+import (
+	context "context"
+	"time"
+
+	"github.com/OmarElGabry/go-textnow/internal/pkg/idempotency"
+	"github.com/OmarElGabry/go-textnow/internal/pkg/mongodb"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// indexes are the indexes of every collection by its name
+var indexes = map[string][]mongo.IndexModel{
+	"sms": {
+		// ListConversations, where the messages are either from or to the phone number
+		{Keys: bson.D{{Key: "from", Value: 1}, {Key: "createdAt", Value: -1}}},
+		{Keys: bson.D{{Key: "to", Value: 1}, {Key: "createdAt", Value: -1}}},
+		// GetThread
+		{Keys: bson.D{{Key: "from", Value: 1}, {Key: "to", Value: 1}, {Key: "createdAt", Value: -1}, {Key: "_id", Value: -1}}},
+		// runScheduler
+		{Keys: bson.D{{Key: "status", Value: 1}, {Key: "sendAt", Value: 1}}},
+		// ListScheduled
+		{Keys: bson.D{{Key: "from", Value: 1}, {Key: "status", Value: 1}, {Key: "sendAt", Value: 1}, {Key: "_id", Value: 1}}},
+		// handleReport
+		{Keys: bson.D{{Key: "carrier", Value: 1}, {Key: "carrierMessageId", Value: 1}}},
+		// Receive, an inbound message is stored once
+		{Keys: bson.D{{Key: "direction", Value: 1}, {Key: "carrier", Value: 1}, {Key: "carrierMessageId", Value: 1}},
+			Options: options.Index().SetUnique(true).SetPartialFilterExpression(bson.M{
+				"direction":        Direction_INBOUND.String(),
+				"carrierMessageId": bson.M{"$exists": true},
+			})},
+	},
+	"events": {
+		// Subscribe
+		{Keys: bson.D{{Key: "phoneNumber", Value: 1}, {Key: "seq", Value: 1}}, Options: options.Index().SetUnique(true)},
+		// events are kept for a week, a client that has been disconnected for longer misses them
+		{Keys: bson.D{{Key: "createdAt", Value: 1}}, Options: options.Index().SetExpireAfterSeconds(7 * 24 * 60 * 60)},
+	},
+	"templates": {
+		// template names are unique within an account, and ListTemplates is ordered by them
+		{Keys: bson.D{{Key: "userId", Value: 1}, {Key: "name", Value: 1}}, Options: options.Index().SetUnique(true)},
+	},
+	"webhooks": {
+		// enqueueDeliveries
+		{Keys: bson.D{{Key: "userId", Value: 1}, {Key: "eventTypes", Value: 1}}},
+	},
+	"deliveries": {
+		// runDeliveries
+		{Keys: bson.D{{Key: "state", Value: 1}, {Key: "nextAttemptAt", Value: 1}}},
+		// ReplayDeliveries
+		{Keys: bson.D{{Key: "webhookId", Value: 1}, {Key: "state", Value: 1}}},
+		// delivered ones are kept for a week, the failed ones are kept until they are replayed
+		{Keys: bson.D{{Key: "createdAt", Value: 1}}, Options: options.Index().
+			SetExpireAfterSeconds(7 * 24 * 60 * 60).
+			SetPartialFilterExpression(bson.M{"state": deliveryDelivered})},
+	},
+	"consents": {
+		// checkConsent, a recipient has one consent for every sender
+		{Keys: bson.D{{Key: "from", Value: 1}, {Key: "to", Value: 1}}, Options: options.Index().SetUnique(true)},
+	},
+	"messageKeys": {
+		// isIdempotent, the keys of the messages expire once the idempotency window is over
+		{Keys: bson.D{{Key: "expiresAt", Value: 1}}, Options: options.Index().SetExpireAfterSeconds(0)},
+	},
+	"idempotency": {
+		// the idempotency interceptor, the keys expire once the idempotency window is over
+		idempotency.Index,
+	},
+	"tracking": {
+		// resumeTracking
+		{Keys: bson.D{{Key: "state", Value: 1}, {Key: "updatedAt", Value: 1}}},
+	},
+}
+
+// CreateIndexes creates the indexes of the SMS service collections.
+//
+// It is called when the service boots. Creating an index that already exists does nothing.
+// The keys of the messages sent before "messageKeys" existed are backfilled first (see backfillMessageKeys).
+func CreateIndexes(ctx context.Context, db *mongo.Database, idempotencyTTL time.Duration) error {
+	if err := backfillMessageKeys(ctx, db, idempotencyTTL); err != nil {
+		return err
+	}
+
+	for collection, models := range indexes {
+		if _, err := db.Collection(collection).Indexes().CreateMany(ctx, models); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// backfillMessageKeys inserts the idempotency keys of the messages in the "sms" collection
+// that are still in their idempotency window into "messageKeys", so that a retry of them isn't sent again.
+//
+// The messages used to hold their keys themselves, and the ones stored before "createdAt" was recorded
+// were created at the time of their id. A key of many messages is of the latest one.
+// It does nothing once the TTL index of "messageKeys" exists, since it is only created after it.
+func backfillMessageKeys(ctx context.Context, db *mongo.Database, idempotencyTTL time.Duration) error {
+	cur, err := db.Collection("messageKeys").Indexes().List(ctx)
+	if err != nil {
+		return err
+	}
+
+	var existing []struct {
+		Name string `bson:"name"`
+	}
+	if err := cur.All(ctx, &existing); err != nil {
+		return err
+	}
+
+	for _, index := range existing {
+		if index.Name == "expiresAt_1" {
+			return nil
+		}
+	}
+
+	since := time.Now().UTC().Add(-idempotencyTTL)
+	filter := bson.M{"idempotencyKey": bson.M{"$exists": true}, "$or": bson.A{
+		bson.M{"createdAt": bson.M{"$gt": since}},
+		bson.M{"createdAt": bson.M{"$exists": false}, "_id": bson.M{"$gt": primitive.NewObjectIDFromTimestamp(since)}},
+	}}
+
+	cur, err = db.Collection("sms").Find(ctx, filter, options.Find().SetSort(bson.M{"_id": -1}))
+	if err != nil {
+		return err
+	}
+	defer cur.Close(ctx)
+
+	for cur.Next(ctx) {
+		var m struct {
+			ID             primitive.ObjectID `bson:"_id"`
+			IdempotencyKey string             `bson:"idempotencyKey"`
+			CreatedAt      time.Time          `bson:"createdAt"`
+		}
+		if err := cur.Decode(&m); err != nil {
+			return err
+		}
+
+		if m.CreatedAt.IsZero() {
+			m.CreatedAt = m.ID.Timestamp()
+		}
+
+		// the latest message of a key is inserted first, or a replica has just inserted a new one
+		key := messageKey{Key: m.IdempotencyKey, MessageID: m.ID, ExpiresAt: m.CreatedAt.Add(idempotencyTTL)}
+		if _, err := db.Collection("messageKeys").InsertOne(ctx, key); err != nil && !mongodb.IsDuplicateKey(err) {
+			return err
+		}
+	}
+
+	return cur.Err()
+}
@@ -0,0 +1,177 @@
+package sms
+
+import (
+	context "context"
+	"time"
+
+	"github.com/golang/protobuf/ptypes"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+// message is a document in the "sms" collection.
+//
+// The document is created by isIdempotent() as QUEUED, and so its id is known
+// before the message is sent. Status is stored by its name, i.e. "DELIVERED".
+type message struct {
+	ID             primitive.ObjectID `bson:"_id"`
+	IdempotencyKey string             `bson:"idempotencyKey"`
+	// RequestHash is the hash of the sms of the request (see idempotency.Hash).
+	// The key itself is held by the message for the idempotency window (see messageKey).
+	RequestHash   string    `bson:"requestHash,omitempty"`
+	From          string    `bson:"from"`
+	To            string    `bson:"to"`
+	Content       string    `bson:"content"`
+	Status        string    `bson:"status"`
+	FailureReason string    `bson:"failureReason,omitempty"`
+	CreatedAt     time.Time `bson:"createdAt"`
+	UpdatedAt     time.Time `bson:"updatedAt"`
+
+	// Encoding is "GSM7" or "UCS2". A content that doesn't fit in one sms is split into
+	// segments, all sharing the same reference number. Content is still the whole text.
+	// If smart encoding replaced any characters, Content is what was sent,
+	// while OriginalContent is what was in the request.
+	Encoding        string    `bson:"encoding,omitempty"`
+	OriginalContent string    `bson:"originalContent,omitempty"`
+	SegmentRef      int       `bson:"segmentRef,omitempty"`
+	Segments        []segment `bson:"segments,omitempty"`
+
+	// FromUserID and ToUserID are the accounts that own the phone numbers, if they are on the platform,
+	// found when the message is sent, and so the webhooks of its events are found without looking them up again.
+	// The messages stored before they were recorded have none.
+	FromUserID int32 `bson:"fromUserId,omitempty"`
+	ToUserID   int32 `bson:"toUserId,omitempty"`
+
+	// Direction is "OUTBOUND" for messages sent by the users on the platform,
+	// and "INBOUND" for messages received by a carrier.
+	Direction string `bson:"direction,omitempty"`
+
+	// Route is "ON_NET" for messages between two phone numbers on the platform,
+	// and "OFF_NET" for messages sent or received by a carrier.
+	Route string `bson:"route,omitempty"`
+
+	// TemplateID is the template the content was rendered from, if any
+	TemplateID string `bson:"templateId,omitempty"`
+
+	// FilterDecisions are the rules of the content filter that matched the content, and what they did.
+	// If any rule rewrote it, Content is what was sent, while OriginalContent is what was in the request.
+	FilterDecisions []filterDecision `bson:"filterDecisions,omitempty"`
+
+	// Carrier is the name of the carrier the message was handed over to,
+	// and CarrierMessageID is the id the carrier knows it by.
+	Carrier          string `bson:"carrier,omitempty"`
+	CarrierMessageID string `bson:"carrierMessageId,omitempty"`
+
+	// SegmentsSent is how many of the segments the carrier accepted, of a message that failed
+	// after some of them were sent (see carrier.PartialError). They could reach the recipient.
+	SegmentsSent int `bson:"segmentsSent,omitempty"`
+
+	// HandedOffAt is when the message was stored to be delivered or handed over to the carrier,
+	// right before it is. A QUEUED message without it was never sent, and so it can be sent again.
+	HandedOffAt time.Time `bson:"handedOffAt,omitempty"`
+
+	// SendAt is when a scheduled message is to be sent, and ClaimedAt is when
+	// a replica claimed it to send it. Once it is sent, CreatedAt is when it was sent.
+	// Held is set if it is held until the quiet hours of the recipient end, rather than scheduled by the sender.
+	SendAt    time.Time `bson:"sendAt,omitempty"`
+	ClaimedAt time.Time `bson:"claimedAt,omitempty"`
+	Held      bool      `bson:"held,omitempty"`
+}
+
+// segment is a part of a concatenated sms
+type segment struct {
+	Seq     int    `bson:"seq"` // starting from 1
+	Content string `bson:"content"`
+}
+
+// status returns the status of the message.
+//
+// Messages stored before the status was recorded have none, and they were all delivered.
+func (m *message) status() Status {
+	if m.Status == "" {
+		return Status_DELIVERED
+	}
+
+	return Status(Status_value[m.Status])
+}
+
+// direction returns the direction of the message.
+//
+// Messages stored before the direction was recorded have none, and they were all sent by the users.
+func (m *message) direction() Direction {
+	if m.Direction == "" {
+		return Direction_OUTBOUND
+	}
+
+	return Direction(Direction_value[m.Direction])
+}
+
+// route returns the route of the message.
+//
+// Messages stored before the route was recorded have none, and they were all between users on the platform.
+func (m *message) route() Route {
+	if m.Route == "" {
+		return Route_ON_NET
+	}
+
+	return Route(Route_value[m.Route])
+}
+
+// GetMessageStatus method gets the status of a message by the id returned by SendOne
+func (s *server) GetMessageStatus(ctx context.Context, req *GetMessageStatusRequest) (*GetMessageStatusResponse, error) {
+	id, err := primitive.ObjectIDFromHex(req.GetMessageId())
+	if err != nil {
+		return nil, status.Error(codes.InvalidArgument, "Invalid message id")
+	}
+
+	var m message
+	err = s.db.FindOne(ctx, bson.M{"_id": id}).Decode(&m)
+	if err == mongo.ErrNoDocuments {
+		return nil, status.Error(codes.NotFound, "Message doesn't exist")
+	}
+
+	if err != nil {
+		return nil, status.Error(codes.Internal, "Internal error "+err.Error())
+	}
+
+	res := &GetMessageStatusResponse{
+		MessageId:     m.ID.Hex(),
+		Status:        m.status(),
+		FailureReason: m.FailureReason,
+	}
+
+	// not set for messages stored before the timestamps were recorded
+	if !m.CreatedAt.IsZero() {
+		res.CreatedAt, _ = ptypes.TimestampProto(m.CreatedAt)
+		res.UpdatedAt, _ = ptypes.TimestampProto(m.UpdatedAt)
+	}
+
+	return res, nil
+}
+
+// toProto converts the message to the one defined in .proto file
+func (m *message) toProto() *Message {
+	res := &Message{
+		MessageId:       m.ID.Hex(),
+		FromPhoneNumber: m.From,
+		ToPhoneNumber:   m.To,
+		Content:         m.Content,
+		Status:          m.status(),
+		Direction:       m.direction(),
+		Route:           m.route(),
+	}
+
+	if !m.CreatedAt.IsZero() {
+		res.CreatedAt, _ = ptypes.TimestampProto(m.CreatedAt)
+	}
+
+	if !m.SendAt.IsZero() {
+		res.SendAt, _ = ptypes.TimestampProto(m.SendAt)
+	}
+
+	return res
+}
@@ -0,0 +1,48 @@
+package sms
+
+import (
+	context "context"
+	"time"
+
+	"go.opencensus.io/stats"
+	"go.opencensus.io/stats/view"
+	"go.opencensus.io/tag"
+)
+
+var (
+	// sentMessages counts the messages that are sent, and sendLatency measures how long sending them takes,
+	// from storing the content until the status is stored
+	sentMessages = stats.Int64("textnow/sms/sent_messages", "The number of sent messages", stats.UnitDimensionless)
+	sendLatency  = stats.Float64("textnow/sms/send_latency", "The time to send a message", stats.UnitMilliseconds)
+
+	// keyRoute is ON_NET or OFF_NET, and keyStatus is the status once it is sent, i.e. DELIVERED
+	keyRoute  = tag.MustNewKey("route")
+	keyStatus = tag.MustNewKey("status")
+)
+
+// Views are the metrics of the sent messages by their route and status.
+// They are registered along with the exporters of the metrics.
+var Views = []*view.View{
+	{
+		Name:        "textnow/sms/sent_messages",
+		Description: "The number of sent messages by route and status",
+		Measure:     sentMessages,
+		TagKeys:     []tag.Key{keyRoute, keyStatus},
+		Aggregation: view.Count(),
+	},
+	{
+		Name:        "textnow/sms/send_latency",
+		Description: "The distribution of the time to send a message by route",
+		Measure:     sendLatency,
+		TagKeys:     []tag.Key{keyRoute},
+		Aggregation: view.Distribution(1, 5, 10, 25, 50, 100, 250, 500, 1000, 2500, 5000, 10000),
+	},
+}
+
+// recordSent records a sent message of the given route and status, which took since start to send
+func recordSent(ctx context.Context, route Route, status Status, start time.Time) {
+	stats.RecordWithTags(ctx,
+		[]tag.Mutator{tag.Upsert(keyRoute, route.String()), tag.Upsert(keyStatus, status.String())},
+		sentMessages.M(1),
+		sendLatency.M(float64(time.Since(start))/float64(time.Millisecond)))
+}
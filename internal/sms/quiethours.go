@@ -0,0 +1,151 @@
+package sms
+
+import (
+	context "context"
+	"fmt"
+	"time"
+
+	"github.com/OmarElGabry/go-textnow/internal/pkg/logger"
+	"github.com/OmarElGabry/go-textnow/internal/pkg/phonenumber"
+	"github.com/OmarElGabry/go-textnow/internal/pkg/quiethours"
+
+	"github.com/golang/protobuf/ptypes"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// quietHours is a document in the "quietHours" collection, one for every account (by user id) that has them.
+// Start and End are "HH:MM" in the local time of the recipient.
+type quietHours struct {
+	UserID          int32     `bson:"_id"`
+	Start           string    `bson:"start"`
+	End             string    `bson:"end"`
+	DefaultTimeZone string    `bson:"defaultTimeZone,omitempty"`
+	UpdatedAt       time.Time `bson:"updatedAt"`
+}
+
+// SetQuietHours method sets (creates or replaces) the quiet hours of an account
+func (s *server) SetQuietHours(ctx context.Context, req *SetQuietHoursRequest) (*SetQuietHoursResponse, error) {
+	if _, err := quiethours.Parse(req.GetStart(), req.GetEnd()); err != nil {
+		return nil, status.Error(codes.InvalidArgument, err.Error())
+	}
+
+	if tz := req.GetDefaultTimeZone(); tz != "" {
+		if _, err := time.LoadLocation(tz); err != nil {
+			return nil, status.Errorf(codes.InvalidArgument, "Invalid time zone %q", tz)
+		}
+	}
+
+	q := &quietHours{
+		UserID:          req.GetUserId(),
+		Start:           req.GetStart(),
+		End:             req.GetEnd(),
+		DefaultTimeZone: req.GetDefaultTimeZone(),
+		UpdatedAt:       time.Now().UTC(),
+	}
+
+	upsert := true // create it if not exists
+	_, err := s.quietHours.ReplaceOne(ctx, bson.M{"_id": q.UserID}, q, &options.ReplaceOptions{Upsert: &upsert})
+	if err != nil {
+		return nil, status.Error(codes.Internal, "Internal error "+err.Error())
+	}
+
+	return &SetQuietHoursResponse{QuietHours: q.toProto()}, nil
+}
+
+// GetQuietHours method gets the quiet hours of an account
+func (s *server) GetQuietHours(ctx context.Context, req *GetQuietHoursRequest) (*GetQuietHoursResponse, error) {
+	var q quietHours
+	err := s.quietHours.FindOne(ctx, bson.M{"_id": req.GetUserId()}).Decode(&q)
+	if err == mongo.ErrNoDocuments {
+		return nil, status.Error(codes.NotFound, "Quiet hours don't exist")
+	}
+
+	if err != nil {
+		return nil, status.Error(codes.Internal, "Internal error "+err.Error())
+	}
+
+	return &GetQuietHoursResponse{QuietHours: q.toProto()}, nil
+}
+
+// DeleteQuietHours method deletes the quiet hours of an account
+func (s *server) DeleteQuietHours(ctx context.Context, req *DeleteQuietHoursRequest) (*DeleteQuietHoursResponse, error) {
+	res, err := s.quietHours.DeleteOne(ctx, bson.M{"_id": req.GetUserId()})
+	if err != nil {
+		return nil, status.Error(codes.Internal, "Internal error "+err.Error())
+	}
+
+	if res.DeletedCount == 0 {
+		return nil, status.Error(codes.NotFound, "Quiet hours don't exist")
+	}
+
+	return &DeleteQuietHoursResponse{Deleted: true}, nil
+}
+
+// holdUntil returns when an sms of the account to the recipient is to be held until,
+// if it would arrive in the quiet hours of the account: the end of them in the local time of the recipient.
+// It returns a zero time if it isn't held, i.e. the account has no quiet hours.
+//
+// The sms arrives at sendAt if it is scheduled, and now otherwise.
+// The local time of the recipient is told by its phone number (see phonenumber.TimeZone),
+// or the default time zone of the account. If neither is known, it isn't held.
+func (s *server) holdUntil(ctx context.Context, userID int32, to string, sendAt time.Time) (time.Time, error) {
+	var q quietHours
+	err := s.quietHours.FindOne(ctx, bson.M{"_id": userID}).Decode(&q)
+	if err == mongo.ErrNoDocuments {
+		return time.Time{}, nil
+	}
+
+	if err != nil {
+		return time.Time{}, err
+	}
+
+	tz := phonenumber.TimeZone(to)
+	if tz == "" {
+		tz = q.DefaultTimeZone
+	}
+
+	if tz == "" {
+		return time.Time{}, nil
+	}
+
+	// the time zone database could be missing (i.e. tzdata isn't installed), rather than held forever, it is sent
+	loc, err := time.LoadLocation(tz)
+	if err != nil {
+		logger.Error(fmt.Sprintf("Failed to load time zone %s of %s error: %v", tz, to, err))
+		return time.Time{}, nil
+	}
+
+	window, err := quiethours.Parse(q.Start, q.End)
+	if err != nil {
+		return time.Time{}, err
+	}
+
+	arrivesAt := time.Now()
+	if sendAt.After(arrivesAt) {
+		arrivesAt = sendAt
+	}
+
+	end, quiet := window.Hold(arrivesAt.In(loc))
+	if !quiet {
+		return time.Time{}, nil
+	}
+
+	return end.UTC(), nil
+}
+
+// toProto converts the quiet hours to the ones defined in .proto file
+func (q *quietHours) toProto() *QuietHours {
+	updatedAt, _ := ptypes.TimestampProto(q.UpdatedAt)
+	return &QuietHours{
+		UserId:          q.UserID,
+		Start:           q.Start,
+		End:             q.End,
+		DefaultTimeZone: q.DefaultTimeZone,
+		UpdatedAt:       updatedAt,
+	}
+}
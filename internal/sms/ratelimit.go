@@ -0,0 +1,126 @@
+package sms
+
+import (
+	context "context"
+	"fmt"
+	"strconv"
+
+	"github.com/OmarElGabry/go-textnow/internal/pkg/logger"
+	"github.com/OmarElGabry/go-textnow/internal/pkg/ratelimit"
+
+	"github.com/golang/protobuf/ptypes"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// RateLimits are the rules of sending SMSs by the sender phone number, and by the account that owns it.
+// An empty list of rules doesn't limit at all.
+type RateLimits struct {
+	PerNumber  []ratelimit.Rule
+	PerAccount []ratelimit.Rule
+}
+
+// tokensTakenKey marks the context of the SMSs whose tokens are taken already, i.e. by SendMany
+type tokensTakenKey struct{}
+
+// takeTokens takes n tokens (messages) from the buckets of the sender phone number and of its account.
+//
+// If they don't have enough tokens, it fails with ResourceExhausted,
+// along with the time to wait before retrying (RetryInfo).
+//
+// The buckets of a phone number and of its account share the account as their hash tag,
+// and so both are taken at once even on Redis Cluster.
+// If Redis fails, the sms isn't limited rather than failed.
+func (s *server) takeTokens(ctx context.Context, phoneNumber string, userID int32, n int64) error {
+	// an auto-reply is required to be sent, whatever the limits are
+	if ctx.Value(tokensTakenKey{}) != nil || ctx.Value(autoReplyKey{}) != nil {
+		return nil
+	}
+
+	wait, err := s.limiter.Take(n, s.buckets(phoneNumber, userID)...)
+	if err == ratelimit.ErrExceedsLimit {
+		return status.Errorf(codes.ResourceExhausted, "%d SMSs from %s are more than its rate limit at once", n, phoneNumber)
+	}
+
+	if err != nil {
+		logger.Error(fmt.Sprintf("Failed to take the rate limit tokens of %s error: %v", phoneNumber, err))
+		return nil
+	}
+
+	if wait == 0 {
+		return nil
+	}
+
+	st := status.Newf(codes.ResourceExhausted, "Rate limit of %s is exceeded, retry after %s", phoneNumber, wait)
+	if detailed, err := st.WithDetails(&errdetails.RetryInfo{RetryDelay: ptypes.DurationProto(wait)}); err == nil {
+		st = detailed
+	}
+
+	return st.Err()
+}
+
+// buckets returns the buckets of the given sender phone number and of its account, if any
+func (s *server) buckets(phoneNumber string, userID int32) []ratelimit.Bucket {
+	tag := "{number:" + phoneNumber + "}"
+	if userID != 0 {
+		tag = "{user:" + strconv.Itoa(int(userID)) + "}"
+	}
+
+	buckets := []ratelimit.Bucket{{Key: tag + "-number:" + phoneNumber, Rules: s.limits.PerNumber}}
+	if userID != 0 {
+		buckets = append(buckets, ratelimit.Bucket{Key: tag + "-account", Rules: s.limits.PerAccount})
+	}
+
+	return buckets
+}
+
+// takeTrackedTokens takes the tokens of all the SMSs of a SendMany request at once,
+// for every sender by the number of its SMSs. They are sent in the background by sendTracked,
+// which tells SendOne not to take them again.
+//
+// The senders are taken from one after another, since they may not share the same hash tag.
+// If one of them is limited, the tokens taken from the ones before it are given back.
+func (s *server) takeTrackedTokens(ctx context.Context, smss []*SMS) error {
+	if len(s.limits.PerNumber) == 0 && len(s.limits.PerAccount) == 0 {
+		return nil
+	}
+
+	senders, counts := []string{}, map[string]int64{}
+	for _, sms := range smss {
+		if counts[sms.GetFromPhoneNumber()] == 0 {
+			senders = append(senders, sms.GetFromPhoneNumber())
+		}
+		counts[sms.GetFromPhoneNumber()]++
+	}
+
+	taken := map[string]int32{}
+	for _, phoneNumber := range senders {
+		userID, err := s.findOwner(ctx, phoneNumber)
+		if status.Code(err) == codes.NotFound {
+			continue // the sms fails when it is sent
+		}
+
+		if err == nil {
+			err = s.takeTokens(ctx, phoneNumber, userID, counts[phoneNumber])
+		}
+
+		if err != nil {
+			s.giveTokens(taken, counts)
+			return err
+		}
+
+		taken[phoneNumber] = userID
+	}
+
+	return nil
+}
+
+// giveTokens gives back the tokens taken from the given senders (and their owners) by their counts
+func (s *server) giveTokens(senders map[string]int32, counts map[string]int64) {
+	for phoneNumber, userID := range senders {
+		if err := s.limiter.Give(counts[phoneNumber], s.buckets(phoneNumber, userID)...); err != nil {
+			logger.Error(fmt.Sprintf("Failed to give back the rate limit tokens of %s error: %v", phoneNumber, err))
+		}
+	}
+}
@@ -0,0 +1,75 @@
+package sms
+
+import (
+	context "context"
+	"fmt"
+	"time"
+
+	"github.com/OmarElGabry/go-textnow/internal/pkg/carrier"
+	"github.com/OmarElGabry/go-textnow/internal/pkg/logger"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+const (
+	// reportAttempts is how many times a report looks for its message. A report could come back
+	// before the carrier message id is stored, right after the carrier has accepted the message.
+	reportAttempts   = 5
+	reportRetryDelay = time.Second
+
+	// reportTimeout bounds the handling of a report, including its attempts
+	reportTimeout = 10 * time.Second
+)
+
+// listenCarriers listens to the carriers that report back
+func (s *server) listenCarriers() {
+	for _, c := range s.carriers.Carriers() {
+		if l, ok := c.(carrier.Listener); ok {
+			l.Listen(s.handleReport, s.handleInbound)
+		}
+	}
+}
+
+// handleReport updates the status of a SENT message by its delivery report, and notifies the sender.
+// The messages sent by the carriers are off-net, and so their recipients aren't on the platform to be notified.
+//
+// A message is updated only if it is still SENT, and so a report that comes twice is applied once.
+func (s *server) handleReport(r *carrier.Report) {
+	ctx, cancel := context.WithTimeout(context.Background(), reportTimeout)
+	defer cancel()
+
+	filter := bson.M{"carrier": r.Carrier, "carrierMessageId": r.CarrierMessageID, "status": Status_SENT.String()}
+
+	update := bson.M{"status": Status_DELIVERED.String(), "updatedAt": time.Now().UTC()}
+	if !r.Delivered {
+		update["status"], update["failureReason"] = Status_FAILED.String(), r.Reason
+	}
+
+	for attempt := 1; ; attempt++ {
+		var m message
+		err := s.db.FindOneAndUpdate(ctx, filter, bson.M{"$set": update},
+			options.FindOneAndUpdate().SetReturnDocument(options.After)).Decode(&m)
+
+		if err == mongo.ErrNoDocuments && attempt < reportAttempts {
+			timer := time.NewTimer(reportRetryDelay)
+			select {
+			case <-ctx.Done():
+				timer.Stop()
+				err = ctx.Err()
+			case <-timer.C:
+				continue
+			}
+		}
+
+		if err != nil {
+			logger.Error(fmt.Sprintf("Failed to apply the report of %s message %s error: %v",
+				r.Carrier, r.CarrierMessageID, err))
+			return
+		}
+
+		s.publishEvent(ctx, m.From, SubscribeResponse_STATUS, &m)
+		return
+	}
+}
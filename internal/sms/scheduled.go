@@ -0,0 +1,290 @@
+package sms
+
+import (
+	context "context"
+	"fmt"
+	"time"
+
+	"github.com/OmarElGabry/go-textnow/internal/pkg/cursor"
+	"github.com/OmarElGabry/go-textnow/internal/pkg/gsm"
+	"github.com/OmarElGabry/go-textnow/internal/pkg/logger"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+const (
+	// schedulerInterval is how often the scheduler checks for due messages
+	schedulerInterval = time.Second
+
+	// scheduledClaimExpiry is how long a due message stays claimed by a replica.
+	// If it isn't sent by then (i.e. the replica has crashed), it is claimed again.
+	scheduledClaimExpiry = time.Minute
+
+	defaultScheduledPageSize = 20
+)
+
+// scheduledPosition is the position of the last message in a page of scheduled messages.
+//
+// Messages are ordered by the time they are to be sent, while the id breaks the ties.
+type scheduledPosition struct {
+	SendAt time.Time `json:"t"`
+	ID     string    `json:"id"`
+}
+
+// ListScheduled method lists the messages from a phone number that are yet to be sent, page by page
+func (s *server) ListScheduled(ctx context.Context, req *ListScheduledRequest) (*ListScheduledResponse, error) {
+	pageSize := int(req.GetPageSize())
+	if pageSize == 0 {
+		pageSize = defaultScheduledPageSize
+	}
+
+	filter := bson.M{"from": req.GetPhoneNumber(), "status": Status_SCHEDULED.String()}
+
+	// start after the position of the cursor (if any)
+	if req.GetCursor() != "" {
+		var position scheduledPosition
+		if err := cursor.Decode(req.GetCursor(), &position); err != nil {
+			return nil, status.Error(codes.InvalidArgument, "Invalid cursor")
+		}
+
+		id, err := primitive.ObjectIDFromHex(position.ID)
+		if err != nil {
+			return nil, status.Error(codes.InvalidArgument, "Invalid cursor")
+		}
+
+		filter["$or"] = []bson.M{
+			{"sendAt": bson.M{"$gt": position.SendAt}},
+			{"sendAt": position.SendAt, "_id": bson.M{"$gt": id}},
+		}
+	}
+
+	// query one more message than the page size to know if there is a next page
+	opts := options.Find().
+		SetSort(bson.D{{Key: "sendAt", Value: 1}, {Key: "_id", Value: 1}}).
+		SetLimit(int64(pageSize + 1))
+
+	cur, err := s.db.Find(ctx, filter, opts)
+	if err != nil {
+		return nil, status.Error(codes.Internal, "Internal error "+err.Error())
+	}
+	defer cur.Close(ctx)
+
+	messages := []*message{}
+	for cur.Next(ctx) {
+		var m message
+		if err := cur.Decode(&m); err != nil {
+			return nil, status.Error(codes.Internal, "Internal error "+err.Error())
+		}
+
+		messages = append(messages, &m)
+	}
+
+	if err := cur.Err(); err != nil {
+		return nil, status.Error(codes.Internal, "Internal error "+err.Error())
+	}
+
+	nextCursor := ""
+	if len(messages) > pageSize {
+		messages = messages[:pageSize]
+		last := messages[pageSize-1]
+		if nextCursor, err = cursor.Encode(scheduledPosition{SendAt: last.SendAt, ID: last.ID.Hex()}); err != nil {
+			return nil, status.Error(codes.Internal, "Internal error "+err.Error())
+		}
+	}
+
+	res := &ListScheduledResponse{Messages: make([]*Message, len(messages)), NextCursor: nextCursor}
+	for i, m := range messages {
+		res.Messages[i] = m.toProto()
+	}
+
+	return res, nil
+}
+
+// CancelScheduled method cancels a scheduled message, provided that it hasn't been sent yet.
+//
+// The message is canceled only if it is still SCHEDULED, in the same (atomic) operation,
+// and so it is either canceled or sent, never both.
+func (s *server) CancelScheduled(ctx context.Context, req *CancelScheduledRequest) (*CancelScheduledResponse, error) {
+	id, err := primitive.ObjectIDFromHex(req.GetMessageId())
+	if err != nil {
+		return nil, status.Error(codes.InvalidArgument, "Invalid message id")
+	}
+
+	var m message
+	err = s.db.FindOneAndUpdate(ctx, bson.M{"_id": id, "status": Status_SCHEDULED.String()},
+		bson.M{"$set": bson.M{"status": Status_CANCELED.String(), "updatedAt": time.Now().UTC()}},
+		options.FindOneAndUpdate().SetReturnDocument(options.After)).Decode(&m)
+
+	if err == mongo.ErrNoDocuments {
+		// either it doesn't exist, or it isn't scheduled (anymore)
+		if err := s.db.FindOne(ctx, bson.M{"_id": id}).Decode(&m); err == mongo.ErrNoDocuments {
+			return nil, status.Error(codes.NotFound, "Message doesn't exist")
+		} else if err != nil {
+			return nil, status.Error(codes.Internal, "Internal error "+err.Error())
+		}
+
+		return nil, status.Errorf(codes.FailedPrecondition, "Message isn't scheduled, it is %s", m.status())
+	}
+
+	if err != nil {
+		return nil, status.Error(codes.Internal, "Internal error "+err.Error())
+	}
+
+	s.publishEvent(ctx, m.From, SubscribeResponse_STATUS, &m)
+	s.trackScheduled(ctx, &m)
+
+	return &CancelScheduledResponse{MessageId: m.ID.Hex(), Status: m.status()}, nil
+}
+
+// schedule stores the message created by isIdempotent() as SCHEDULED to be sent at the given time,
+// and notifies the sender of its status
+func (s *server) schedule(ctx context.Context, filter bson.M, msg *message, sendAt time.Time) error {
+	msg.Status, msg.SendAt, msg.UpdatedAt = Status_SCHEDULED.String(), sendAt, time.Now().UTC()
+
+	_, err := s.db.UpdateOne(ctx, filter, bson.M{"$set": bson.M{
+		"from":            msg.From,
+		"to":              msg.To,
+		"content":         msg.Content,
+		"status":          msg.Status,
+		"encoding":        msg.Encoding,
+		"originalContent": msg.OriginalContent,
+		"templateId":      msg.TemplateID,
+		"filterDecisions": msg.FilterDecisions,
+		"fromUserId":      msg.FromUserID,
+		"sendAt":          msg.SendAt,
+		"held":            msg.Held,
+		"updatedAt":       msg.UpdatedAt,
+	}})
+
+	if err != nil {
+		return err
+	}
+
+	s.publishEvent(ctx, msg.From, SubscribeResponse_STATUS, msg)
+
+	return nil
+}
+
+// runScheduler periodically sends the scheduled messages that are due.
+// It runs for as long as the server does.
+//
+// A message is claimed by setting its "claimedAt" in the same (atomic) operation
+// that finds it, and so only one replica sends it. It is then sent only if it is
+// still SCHEDULED and claimed by the same replica, and so it is sent exactly once,
+// even if it is canceled or claimed again in the meantime.
+func (s *server) runScheduler() {
+	ticker := time.NewTicker(schedulerInterval)
+	defer ticker.Stop()
+
+	for {
+		for {
+			m, err := s.claimDueMessage()
+			if err == mongo.ErrNoDocuments {
+				break
+			}
+
+			if err != nil {
+				logger.Error(fmt.Sprintf("Failed to claim a due message error: %v", err))
+				break
+			}
+
+			go s.sendScheduled(m)
+		}
+
+		<-ticker.C
+	}
+}
+
+// claimDueMessage finds a due message that isn't claimed (or whose claim has expired), and claims it
+func (s *server) claimDueMessage() (*message, error) {
+	now := time.Now().UTC()
+	filter := bson.M{
+		"status": Status_SCHEDULED.String(),
+		"sendAt": bson.M{"$lte": now},
+		"$or": []bson.M{
+			{"claimedAt": bson.M{"$exists": false}},
+			{"claimedAt": bson.M{"$lt": now.Add(-scheduledClaimExpiry)}},
+		},
+	}
+
+	var m message
+	err := s.db.FindOneAndUpdate(context.Background(), filter,
+		bson.M{"$set": bson.M{"claimedAt": now}},
+		options.FindOneAndUpdate().SetReturnDocument(options.After)).Decode(&m)
+
+	if err != nil {
+		return nil, err
+	}
+
+	return &m, nil
+}
+
+// sendScheduled sends a claimed message.
+//
+// The phone numbers are checked again, since they could have been released since it was scheduled:
+// the sender must still be on the platform, while the recipient decides the route.
+// So is the consent, since the recipient could have opted out since then.
+// If they couldn't be checked, the message is left to be claimed again once the claim expires.
+func (s *server) sendScheduled(m *message) {
+	ctx := context.Background()
+	filter := bson.M{"_id": m.ID, "status": Status_SCHEDULED.String(), "claimedAt": m.ClaimedAt}
+
+	route, toUserID, err := s.findRoute(ctx, m.To)
+	if err != nil {
+		logger.Error(fmt.Sprintf("Failed to send scheduled message %s error: %v", m.ID.Hex(), err))
+		return
+	}
+
+	m.Route, m.ToUserID = route.String(), toUserID
+
+	// the sender could have released the phone number, or the recipient opted out, since it was scheduled
+	m.FromUserID, err = s.findOwner(ctx, m.From)
+	if err == nil {
+		err = s.checkConsent(ctx, m.From, m.To)
+	}
+
+	if code := status.Code(err); code == codes.NotFound || code == codes.FailedPrecondition {
+		m.Status, m.FailureReason, m.UpdatedAt = Status_FAILED.String(), status.Convert(err).Message(), time.Now().UTC()
+
+		_, err = s.db.UpdateOne(ctx, filter, bson.M{"$set": bson.M{
+			"status":        m.Status,
+			"failureReason": m.FailureReason,
+			"updatedAt":     m.UpdatedAt,
+		}})
+
+		if err != nil {
+			logger.Error(fmt.Sprintf("Failed to fail scheduled message %s error: %v", m.ID.Hex(), err))
+			return
+		}
+
+		s.publishEvent(ctx, m.From, SubscribeResponse_STATUS, m)
+		s.trackScheduled(ctx, m)
+		return
+	}
+
+	if err != nil {
+		logger.Error(fmt.Sprintf("Failed to send scheduled message %s error: %v", m.ID.Hex(), err))
+		return
+	}
+
+	// the message takes its place in the thread when it is sent, rather than when it was scheduled
+	m.CreatedAt = time.Now().UTC()
+
+	encoding, parts := gsm.Split(m.Content)
+	sent, err := s.deliver(ctx, filter, m, encoding, parts)
+	if err != nil {
+		logger.Error(fmt.Sprintf("Failed to send scheduled message %s error: %v", m.ID.Hex(), err))
+		return
+	}
+
+	// the SMSs of SendMany are tracked until they are sent
+	if sent {
+		s.trackScheduled(ctx, m)
+	}
+}
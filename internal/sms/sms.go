@@ -2,29 +2,120 @@ package sms
 
 import (
 	context "context"
-	fmt "fmt"
+	"fmt"
 	"io"
+	"net/http"
+	"strings"
 	"sync"
+	"time"
 
 	"github.com/OmarElGabry/go-textnow/internal/phonebook"
-
+	"github.com/OmarElGabry/go-textnow/internal/pkg/carrier"
+	"github.com/OmarElGabry/go-textnow/internal/pkg/contentfilter"
+	"github.com/OmarElGabry/go-textnow/internal/pkg/gsm"
+	"github.com/OmarElGabry/go-textnow/internal/pkg/idempotency"
+	"github.com/OmarElGabry/go-textnow/internal/pkg/logger"
+	"github.com/OmarElGabry/go-textnow/internal/pkg/mongodb"
+	"github.com/OmarElGabry/go-textnow/internal/pkg/ratelimit"
+	"github.com/OmarElGabry/go-textnow/internal/pkg/redis"
+
+	"github.com/golang/protobuf/ptypes"
 	"google.golang.org/grpc/codes"
 	"google.golang.org/grpc/status"
 
 	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
 	"go.mongodb.org/mongo-driver/mongo"
 	"go.mongodb.org/mongo-driver/mongo/options"
 )
 
+const (
+	// maxSegments is the max number of segments of a concatenated sms,
+	// since its header counts them in one byte
+	maxSegments = 255
+
+	// statusTimeout bounds storing the status of a message once it is delivered or handed over to the carrier
+	statusTimeout = 10 * time.Second
+)
+
 type server struct {
-	db *mongo.Collection
-	pB phonebook.PhoneBookServiceClient
+	db            *mongo.Collection // sms
+	tracking      *mongo.Collection
+	events        *mongo.Collection
+	counters      *mongo.Collection
+	templates     *mongo.Collection
+	webhooks      *mongo.Collection
+	deliveries    *mongo.Collection
+	messageKeys   *mongo.Collection
+	cache         *redis.Cache
+	pB            phonebook.PhoneBookServiceClient
+	carriers      *carrier.Router
+	limiter       *ratelimit.Limiter
+	limits        RateLimits
+	contentFilter *contentfilter.Watcher
+	consents      *mongo.Collection
+	autoReplies   AutoReplies
+	quietHours    *mongo.Collection
+	// webhookHosts are the hosts the webhooks are allowed to be sent to even if they are private
+	webhookHosts  map[string]bool
+	webhookClient *http.Client
+	// idempotencyTTL is the idempotency window of the keys of the messages
+	idempotencyTTL time.Duration
 	// mu sync.Mutex
 }
 
 // NewSMSServiceServer creates and returns a new SMS service server
-func NewSMSServiceServer(db *mongo.Collection, pB phonebook.PhoneBookServiceClient) SMSServiceServer {
-	return &server{db, pB}
+//
+// It resumes sending the SMSs of SendMany requests that were left unfinished,
+// i.e. when the replica that was sending them crashed or was restarted,
+// runs the scheduler that sends the scheduled SMSs once they are due,
+// and the worker that sends the events to the webhooks.
+//
+// Redis cache is used to announce the new events to the subscribers on all replicas,
+// and to hold the rate limits of the senders across the replicas.
+// The content of every sms goes through the current rules of the content filter, if any,
+// and the recipients that reply with a keyword (i.e. STOP) are sent the auto-replies.
+// The messages are sent by the carriers, picked by the router for every destination phone number,
+// and the carriers that report back are listened to.
+// The webhooks are only sent to public addresses, or to the given hosts (i.e. of the internal network).
+func NewSMSServiceServer(db *mongo.Database, cache *redis.Cache, pB phonebook.PhoneBookServiceClient,
+	carriers *carrier.Router, limits RateLimits, contentFilter *contentfilter.Watcher, autoReplies AutoReplies,
+	idempotencyTTL time.Duration, webhookHosts []string) SMSServiceServer {
+	allowedHosts := map[string]bool{}
+	for _, host := range webhookHosts {
+		allowedHosts[strings.ToLower(host)] = true
+	}
+
+	s := &server{
+		db:             db.Collection("sms"),
+		tracking:       db.Collection("tracking"),
+		events:         db.Collection("events"),
+		counters:       db.Collection("counters"),
+		templates:      db.Collection("templates"),
+		webhooks:       db.Collection("webhooks"),
+		deliveries:     db.Collection("deliveries"),
+		messageKeys:    db.Collection("messageKeys"),
+		cache:          cache,
+		pB:             pB,
+		carriers:       carriers,
+		limiter:        ratelimit.NewLimiter(cache),
+		limits:         limits,
+		contentFilter:  contentFilter,
+		consents:       db.Collection("consents"),
+		autoReplies:    autoReplies,
+		quietHours:     db.Collection("quietHours"),
+		idempotencyTTL: idempotencyTTL,
+		webhookHosts:   allowedHosts,
+		webhookClient:  newWebhookClient(allowedHosts),
+	}
+
+	s.listenCarriers()
+
+	go s.resumeTracking()
+	go s.runScheduler()
+	go s.runDeliveries()
+
+	return s
 }
 
 // SendOne method sends a single sms
@@ -33,45 +124,103 @@ func (s *server) SendOne(ctx context.Context, req *SendOneRequest) (*SendOneResp
 	idempotencyKey := smsReq.GetIdempotencyKey()
 	fromPhoneNumber := smsReq.GetFromPhoneNumber()
 	toPhoneNumber := smsReq.GetToPhoneNumber()
-	content := smsReq.GetContent()
 
-	// 1) Check idempotency
-	filter := bson.M{"idempotencyKey": idempotencyKey}
-	idempotent, err := s.isIdempotent(ctx, filter)
+	// Render the content from the template (if any) before anything is stored,
+	// so that a missing variable is rejected right away.
+	content, err := s.renderContent(ctx, smsReq)
+	if err != nil {
+		return nil, err
+	}
+
+	// Encode and split the content before anything is stored,
+	// so that a content too long to be sent is rejected right away.
+	originalContent := content
+	content, substitutions, encoding, parts, err := encodeContent(originalContent, smsReq.GetSmartEncoding())
+	if err != nil {
+		return nil, err
+	}
+
+	var sendAt time.Time
+	if smsReq.GetSendAt() != nil {
+		t, tErr := ptypes.Timestamp(smsReq.GetSendAt())
+		if tErr != nil {
+			return nil, status.Error(codes.InvalidArgument, "Invalid send_at "+tErr.Error())
+		}
+
+		sendAt = t.UTC()
+	}
+
+	// 1) Check idempotency, along with the hash of the sms to tell a retry from a reused key
+	requestHash, err := idempotency.Hash(smsReq)
+	if err != nil {
+		return nil, status.Error(codes.Internal, "Internal error "+err.Error())
+	}
+
+	msg, idempotent, err := s.isIdempotent(ctx, idempotencyKey, requestHash)
 
 	if err != nil {
 		return nil, status.Error(codes.Internal, "Internal error "+err.Error())
 	}
 
+	// a message of a resumed SendMany that was never handed over before the replica that was sending it
+	// stopped (see resumeTracking) is sent rather than replayed. Only one of them hands it over (see deliver).
+	if !idempotent && ctx.Value(resumedKey{}) != nil && msg.status() == Status_QUEUED && msg.HandedOffAt.IsZero() &&
+		msg.RequestHash == requestHash {
+		idempotent, msg.Segments = true, nil
+	}
+
 	if idempotent == false {
+		// the messages stored before the hash was recorded have none
+		if msg.RequestHash != "" && msg.RequestHash != requestHash {
+			return nil, idempotency.ErrConflict
+		}
+
+		if msg.status() == Status_SCHEDULED {
+			return &SendOneResponse{Message: "Message has been scheduled already",
+				MessageId: msg.ID.Hex(), Status: msg.status()}, nil
+		}
+
 		// If we returned: nil, status.Error(codes.OK, "...")
 		// status.Error() return nil if OK. And response must not be "nil"!.
-		return &SendOneResponse{Sent: true, Message: "Message has been sent already"}, nil
+		return &SendOneResponse{Sent: msg.status() != Status_FAILED, Message: "Message has been sent already",
+			MessageId: msg.ID.Hex(), Status: msg.status()}, nil
 	}
 
-	// make sure to delete the created document (@isIdempotent()) upon failure
+	filter := bson.M{"_id": msg.ID}
+
+	// make sure to delete the created document (@isIdempotent()) upon failure,
+	// unless it is kept along with its key since it is on its way (see deliver)
+	keepKey := false
 	defer func() {
 		// it assumes that when SendOne() returns on error,
 		// it is ONLY when err is != nil. if SendOne() returned on failure
 		// and err was nil (i.e. invalid input), the document won't be deleted!.
-		if err != nil {
-			s.db.DeleteOne(ctx, filter)
+		if err != nil && !keepKey {
+			s.releaseMessage(ctx, msg)
 		}
 	}()
 
 	// 2) Check if phone numbers actually exist in the database
-	// We can send two requests in parallel and validate the result when both are done
+	// We can send two requests in parallel and validate the result when both are done.
+	// The recipient doesn't have to be on the platform, and if it isn't, the sms is sent off-net by a carrier.
 	var wg sync.WaitGroup
 	errChan := make(chan error)
 	wg.Add(2)
 
-	for _, pNumber := range []string{fromPhoneNumber, toPhoneNumber} {
-		go func(pNumber string) {
-			err = s.findPhoneNumber(ctx, pNumber)
-			errChan <- err
-			wg.Done()
-		}(pNumber)
-	}
+	var userID int32 // the account that owns the sender
+	go func() {
+		id, err := s.findOwner(ctx, fromPhoneNumber)
+		userID = id
+		errChan <- err
+		wg.Done()
+	}()
+
+	go func() {
+		route, toUserID, err := s.findRoute(ctx, toPhoneNumber)
+		msg.Route, msg.ToUserID = route.String(), toUserID
+		errChan <- err
+		wg.Done()
+	}()
 
 	// collect the errors if any
 	go func() {
@@ -91,122 +240,484 @@ func (s *server) SendOne(ctx context.Context, req *SendOneRequest) (*SendOneResp
 		return nil, err
 	}
 
-	// 3) Send the sms: Add sms to databsae by updating the the created document (@isIdempotent())
-	// We simulate "sending sms" by inserting it to the database.
-	_, err = s.db.UpdateOne(ctx, filter, bson.M{"$set": bson.M{
-		"from":    fromPhoneNumber,
-		"to":      toPhoneNumber,
-		"content": content,
-	}})
+	msg.FromUserID = userID
+
+	// and the recipient hasn't opted out of the messages of the sender (by replying STOP)
+	if err = s.checkConsent(ctx, fromPhoneNumber, toPhoneNumber); err != nil {
+		return nil, err
+	}
+
+	// and the sender is within its rate limits. A scheduled sms counts when it is requested.
+	if err = s.takeTokens(ctx, fromPhoneNumber, userID, 1); err != nil {
+		return nil, err
+	}
+
+	// and its content passes the content filter of the account. A rewritten content is encoded and split again.
+	decisions, filtered := s.filterContent(originalContent, userID)
+	if filtered.Content != originalContent {
+		content, substitutions, encoding, parts, err = encodeContent(filtered.Content, smsReq.GetSmartEncoding())
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	msg.From, msg.To, msg.Content = fromPhoneNumber, toPhoneNumber, content
+	msg.Encoding = encoding.String()
+	msg.FilterDecisions = decisions
+	if content != originalContent {
+		msg.OriginalContent = originalContent
+	}
+
+	if smsReq.GetTemplateId() != "" {
+		msg.TemplateID = smsReq.GetTemplateId()
+	}
+
+	res := &SendOneResponse{MessageId: msg.ID.Hex(), Route: msg.route(),
+		Encoding: Encoding(Encoding_value[encoding.String()]), SegmentCount: int32(len(parts))}
+
+	for _, sub := range substitutions {
+		res.Substitutions = append(res.Substitutions, &Substitution{From: sub.From, To: sub.To, Count: int32(sub.Count)})
+	}
+
+	for _, d := range decisions {
+		res.FilterDecisions = append(res.FilterDecisions, d.toProto())
+	}
+
+	// a content rejected by the content filter is stored as FAILED, and so its idempotency key is used
+	if filtered.Rejected {
+		if err = s.reject(ctx, filter, msg); err != nil {
+			return nil, status.Error(codes.Internal, "Internal error "+err.Error())
+		}
+
+		res.Status, res.Message = msg.status(), msg.FailureReason
+		return res, nil
+	}
+
+	// 3) Hold the sms if it would arrive in the quiet hours of the recipient, unless it is urgent
+	if smsReq.GetPriority() != Priority_URGENT {
+		var until time.Time
+		if until, err = s.holdUntil(ctx, userID, toPhoneNumber, sendAt); err != nil {
+			return nil, status.Error(codes.Internal, "Internal error "+err.Error())
+		}
+
+		if !until.IsZero() {
+			sendAt, msg.Held = until, true
+		}
+	}
+
+	// and schedule it if it is to be sent later, the scheduler sends it once it is due
+	if sendAt.After(time.Now()) {
+		if err = s.schedule(ctx, filter, msg, sendAt); err != nil {
+			return nil, status.Error(codes.Internal, "Internal error "+err.Error())
+		}
+
+		res.Message, res.Status = "Message has been scheduled", Status_SCHEDULED
+		if msg.Held {
+			res.Message = "Message is held until the quiet hours of the recipient end"
+		}
+
+		res.SendAt, _ = ptypes.TimestampProto(sendAt)
+		return res, nil
+	}
+
+	// 4) Send the sms
+	var sent bool
+	if sent, err = s.deliver(ctx, filter, msg, encoding, parts); err != nil {
+		if _, ok := err.(*handedOffError); ok {
+			// the message is on its way, and so it keeps its idempotency key, a retry doesn't send it again.
+			// It is left QUEUED, and so it is reported as such rather than failed.
+			keepKey = true
+			logger.Error(fmt.Sprintf("Failed to store the status of message %s error: %v", msg.ID.Hex(), err))
+
+			res.Sent, res.Status, res.Message = true, Status_QUEUED, "Message is on its way, its status is yet to be known"
+			return res, nil
+		}
 
-	if err != nil {
 		return nil, status.Error(codes.Internal, "Internal error "+err.Error())
 	}
 
-	return &SendOneResponse{Sent: true}, nil
+	if !sent {
+		return &SendOneResponse{Sent: true, Message: "Message has been sent already",
+			MessageId: msg.ID.Hex(), Status: Status_QUEUED}, nil
+	}
+
+	// a failure of the carrier is final, it is reported by the status rather than an error,
+	// since the message is stored and its idempotency key is used.
+	res.Status = msg.status()
+	res.Sent, res.Message = res.Status != Status_FAILED, msg.FailureReason
+	return res, nil
+}
+
+// encodeContent replaces the characters that force the sms into UCS2 if asked to (smart encoding),
+// and splits the content into the segments of a concatenated sms (if it doesn't fit in one).
+// It fails with InvalidArgument if the content is too long to be sent.
+func encodeContent(content string, smartEncoding bool) (string, []gsm.Substitution, gsm.Encoding, []string, error) {
+	var substitutions []gsm.Substitution
+	if smartEncoding {
+		content, substitutions = gsm.Transliterate(content)
+	}
+
+	encoding, parts := gsm.Split(content)
+	if len(parts) > maxSegments {
+		return "", nil, encoding, nil,
+			status.Errorf(codes.InvalidArgument, "Content is too long, it is more than %d segments", maxSegments)
+	}
+
+	return content, substitutions, encoding, parts, nil
 }
 
 // SendMany method sends many SMSs in one request.
 //
-// This is used when one SMS contains long text (exceeds limit of 1 sms),
-// And so the client will chunck it up, and split it into smaller SMSs
-// And send them in one request.
+// This is used to send many SMSs at once. A long text doesn't have to be split
+// by the client, SendOne splits it into the segments of a concatenated sms.
 //
-// To avoid having the client to wait, we can use the idea of "Tracking ID"
+// To avoid having the client to wait, we use the idea of "Tracking ID"
 // So, the client will make a request, and all SMSs will be sent at the background,
 // and so terminating the reqeust as early as possible.
-// This tracking id can be later used to know about the status of the request, and if any errors.
-// And that requires another RPC method.
+// This tracking id can be later used to know about the status of the request,
+// and if any errors, by GetTracking method.
 func (s *server) SendMany(stream SMSService_SendManyServer) error {
+	smss := []*SMS{}
 
-	var err error
-	errors := []string{}
-
-	var wg sync.WaitGroup
-	errChan := make(chan error)
-
+	// Receive all the SMSs before anything is stored,
+	// so that a broken stream doesn't leave a partial request behind.
 	for {
-		// For each sms, ...
 		req, err := stream.Recv() // blocks!
 		if err == io.EOF {
 			break
 		}
 
 		if err != nil {
-			break // can't return here! must run all goroutines
+			return status.Error(codes.Internal, "Internal error "+err.Error())
 		}
 
-		// Send each using SendOne method. If error returned, append it to errors array
-		// We can spin each request in a goroutine so that we don't block the current loop
-		// and aggregate the results at the end
-		wg.Add(1)
-		go func() {
-			_, err = s.SendOne(stream.Context(), &SendOneRequest{Sms: req.GetSms()})
-			errChan <- err
-			wg.Done()
-		}()
+		smss = append(smss, req.GetSms())
 	}
 
-	// collect the errors if any
-	go func() {
-		wg.Wait()
-		close(errChan)
-	}()
+	if len(smss) == 0 {
+		return status.Error(codes.InvalidArgument, "No sms to send")
+	}
 
-	for ec := range errChan {
-		if ec != nil {
-			errors = append(errors, fmt.Sprintf("Couldn't send sms error %v", ec))
+	// Render the SMSs from templates before anything is stored, so that a missing variable
+	// rejects the whole request rather than failing in the background.
+	// They are rendered again by SendOne when they are sent.
+	for i, sms := range smss {
+		if _, err := s.renderContent(stream.Context(), sms); err != nil {
+			st := status.Convert(err)
+			return status.Errorf(st.Code(), "sms %d: %s", i+1, st.Message())
 		}
 	}
 
-	// must be after we execute all goroutines we spin up and close the channel
+	// Take the rate limit tokens of all the SMSs, so that a limited sender rejects the whole request
+	if err := s.takeTrackedTokens(stream.Context(), smss); err != nil {
+		return err
+	}
+
+	// Store the tracking document, then send the SMSs in the background
+	t, err := s.createTracking(stream.Context(), smss)
 	if err != nil {
 		return status.Error(codes.Internal, "Internal error "+err.Error())
 	}
 
-	return stream.SendAndClose(&SendManyResponse{Errors: errors})
+	go s.sendTracked(context.Background(), t)
+
+	return stream.SendAndClose(&SendManyResponse{TrackingId: t.ID.Hex()})
 }
 
-// isIdempotent is a helper function to check if the SMS is idempotent (has been sent before) or not.
-// Ideally, this should be in a middelware:
-//	- A pre-middleware to check if key is idempotent and create it if not.
-//	- A post-middelware to store the result (response).
+// deliver sends the message by its route: straight to the recipient if it is on the platform (on-net),
+// or through the carrier of its destination phone number otherwise (off-net).
+//  1. Add sms to databsae by updating the the created document (@isIdempotent()) with its content,
+//     provided that it still matches the given filter, and that it hasn't been handed over already.
+//     It is QUEUED until it is delivered or accepted, and marked as handed over (handedOffAt).
+//  2. On-net, it is delivered once it is stored. Off-net, hand it over to the carrier,
+//     which is picked by the routing rules.
+//  3. Store its status: DELIVERED if it is delivered already, SENT if it is on its way,
+//     or FAILED with the reason if the carrier couldn't send it.
+//  4. Notify the subscribers.
 //
-// The client is expected to pass that idempotent key in the request. An example is to use UUID V4.
-func (s *server) isIdempotent(ctx context.Context, idempotencyFilter bson.M) (bool, error) {
-	data := bson.M{"$set": idempotencyFilter}
-	upsert := true // create it if not exists
+// It returns false if the message doesn't match the filter anymore (i.e. a scheduled message that was canceled),
+// or it has been handed over by another request (i.e. a resumed SendMany, see sendTracked).
+// If its status couldn't be stored once it is delivered or handed over, it fails with a handedOffError,
+// and the message is left QUEUED, since it is on its way whatever its status is.
+func (s *server) deliver(ctx context.Context, filter bson.M, msg *message, encoding gsm.Encoding, parts []string) (bool, error) {
+	start := time.Now()
+	msg.Status, msg.UpdatedAt = Status_QUEUED.String(), time.Now().UTC()
+
+	// The segments of a concatenated sms share a reference number, so that the phone puts them together.
+	// It must differ from the other concatenated SMSs in flight between the same phone numbers.
+	if len(parts) > 1 {
+		ref, err := s.nextSeq(ctx, "segment-ref-"+msg.From+"-"+msg.To)
+		if err != nil {
+			return false, err
+		}
 
-	// UpdateOne is used instead of InsertOne because it is easier
-	// to check if sms with the same idempotency key already exists or not.
+		msg.SegmentRef = int(ref % 256)
+		for i, part := range parts {
+			msg.Segments = append(msg.Segments, segment{Seq: i + 1, Content: part})
+		}
+	}
 
-	// s.mu.Lock()
-	res, err := s.db.UpdateOne(ctx, idempotencyFilter, data, &options.UpdateOptions{Upsert: &upsert})
-	// s.mu.Unlock()
+	// 1) Store the content
+	msg.HandedOffAt = msg.UpdatedAt
+	handOff := bson.M{"handedOffAt": bson.M{"$exists": false}}
+	for k, v := range filter {
+		handOff[k] = v
+	}
+
+	res, err := s.db.UpdateOne(ctx, handOff, bson.M{"$set": bson.M{
+		"from":            msg.From,
+		"to":              msg.To,
+		"content":         msg.Content,
+		"status":          msg.Status,
+		"encoding":        msg.Encoding,
+		"originalContent": msg.OriginalContent,
+		"templateId":      msg.TemplateID,
+		"segmentRef":      msg.SegmentRef,
+		"segments":        msg.Segments,
+		"route":           msg.Route,
+		"fromUserId":      msg.FromUserID,
+		"toUserId":        msg.ToUserID,
+		"filterDecisions": msg.FilterDecisions,
+		"handedOffAt":     msg.HandedOffAt,
+		"createdAt":       msg.CreatedAt,
+		"updatedAt":       msg.UpdatedAt,
+	}})
 
 	if err != nil {
 		return false, err
 	}
 
-	// already exists
-	if res.UpsertedCount == 0 {
+	if res.MatchedCount == 0 {
 		return false, nil
 	}
 
+	update := bson.M{}
+	if msg.route() == Route_ON_NET {
+		// 2) Deliver it to the recipient
+		msg.Status = Status_DELIVERED.String()
+	} else {
+		// 2) Hand it over to the carrier
+		receipt, err := s.sendByCarrier(ctx, msg, encoding, parts)
+
+		// 3) Store its status
+		update["carrier"] = msg.Carrier
+		switch {
+		case err != nil:
+			// the failure reason tells how many of the segments were sent, if any
+			msg.Status, msg.FailureReason = Status_FAILED.String(), err.Error()
+			update["failureReason"] = msg.FailureReason
+
+			if partial, ok := err.(*carrier.PartialError); ok {
+				msg.SegmentsSent = partial.Sent
+				update["segmentsSent"] = msg.SegmentsSent
+			}
+		case receipt.Delivered:
+			msg.Status = Status_DELIVERED.String()
+		default:
+			msg.Status = Status_SENT.String()
+		}
+
+		if receipt != nil {
+			msg.CarrierMessageID = receipt.CarrierMessageID
+			update["carrierMessageId"] = msg.CarrierMessageID
+		}
+	}
+
+	msg.UpdatedAt = time.Now().UTC()
+	update["status"], update["updatedAt"] = msg.Status, msg.UpdatedAt
+
+	// the message is on its way, and so its status is stored even if the request is canceled meanwhile
+	statusCtx, cancel := context.WithTimeout(context.Background(), statusTimeout)
+	defer cancel()
+
+	if _, err := s.db.UpdateOne(statusCtx, bson.M{"_id": msg.ID}, bson.M{"$set": update}); err != nil {
+		return false, &handedOffError{err: err}
+	}
+
+	recordSent(ctx, msg.route(), msg.status(), start)
+
+	// 4) Notify the subscribers: the recipient of the new message (if it is on the platform), and the sender of its status
+	if msg.route() == Route_ON_NET {
+		s.publishEvent(ctx, msg.To, SubscribeResponse_MESSAGE, msg)
+	}
+
+	s.publishEvent(ctx, msg.From, SubscribeResponse_STATUS, msg)
+
 	return true, nil
 }
 
+// handedOffError is an error of deliver after the message is delivered or handed over to the carrier,
+// and so it must not be sent again
+type handedOffError struct {
+	err error
+}
+
+func (e *handedOffError) Error() string {
+	return e.err.Error()
+}
+
+// sendByCarrier hands the message over to the carrier of its destination phone number
+func (s *server) sendByCarrier(ctx context.Context, msg *message, encoding gsm.Encoding, parts []string) (*carrier.Receipt, error) {
+	c, err := s.carriers.Route(msg.To)
+	if err != nil {
+		return nil, err
+	}
+
+	msg.Carrier = c.Name()
+
+	return c.Send(ctx, &carrier.Message{
+		ID:         msg.ID.Hex(),
+		From:       msg.From,
+		To:         msg.To,
+		Encoding:   encoding,
+		Segments:   parts,
+		SegmentRef: uint8(msg.SegmentRef),
+	})
+}
+
+// isIdempotent is a helper function to check if the SMS is idempotent (has been sent before) or not.
+// A retry of SendOne gets the response of the first request from the idempotency interceptor
+// (see IdempotentMethods), while this makes sure the message itself is stored and sent once,
+// i.e. the SMSs of SendMany, which don't go through the interceptor.
+//
+// The client is expected to pass that idempotent key in the request. An example is to use UUID V4.
+//
+// A new document is created as a QUEUED message, along with the hash of the request, then the key is
+// inserted for it (see messageKey). It returns the message, whether it is a new one or the one
+// that has been sent before.
+//
+// The key is the id of its document, and so only one of the concurrent requests of the same key inserts it,
+// while the others delete their message and get the one of the key.
+func (s *server) isIdempotent(ctx context.Context, idempotencyKey, requestHash string) (*message, bool, error) {
+	now := time.Now().UTC()
+	msg := &message{
+		ID:             primitive.NewObjectID(),
+		IdempotencyKey: idempotencyKey,
+		RequestHash:    requestHash,
+		Status:         Status_QUEUED.String(),
+		Direction:      Direction_OUTBOUND.String(),
+		CreatedAt:      now,
+		UpdatedAt:      now,
+	}
+
+	doc := bson.M{
+		"_id":            msg.ID,
+		"idempotencyKey": msg.IdempotencyKey,
+		"requestHash":    msg.RequestHash,
+		"status":         msg.Status,
+		"direction":      msg.Direction,
+		"createdAt":      msg.CreatedAt,
+		"updatedAt":      msg.UpdatedAt,
+	}
+
+	if _, err := s.db.InsertOne(ctx, doc); err != nil {
+		return nil, false, err
+	}
+
+	key := messageKey{Key: idempotencyKey, MessageID: msg.ID, ExpiresAt: now.Add(s.idempotencyTTL)}
+	_, err := s.messageKeys.InsertOne(ctx, key)
+	if err == nil {
+		return msg, true, nil
+	}
+
+	// the message isn't of the key, whether it is used already or not
+	s.db.DeleteOne(ctx, bson.M{"_id": msg.ID})
+
+	if !mongodb.IsDuplicateKey(err) {
+		return nil, false, err
+	}
+
+	// MongoDB deletes the expired keys once a minute, and so an expired key that isn't deleted yet
+	// is taken over by the new message.
+	res, err := s.messageKeys.UpdateOne(ctx,
+		bson.M{"_id": idempotencyKey, "expiresAt": bson.M{"$lte": now}},
+		bson.M{"$set": bson.M{"messageId": msg.ID, "expiresAt": key.ExpiresAt}})
+	if err != nil {
+		return nil, false, err
+	}
+
+	if res.ModifiedCount == 1 {
+		if _, err := s.db.InsertOne(ctx, doc); err != nil {
+			return nil, false, err
+		}
+
+		return msg, true, nil
+	}
+
+	if err := s.messageKeys.FindOne(ctx, bson.M{"_id": idempotencyKey}).Decode(&key); err != nil {
+		return nil, false, err
+	}
+
+	var m message
+	if err := s.db.FindOne(ctx, bson.M{"_id": key.MessageID}).Decode(&m); err != nil {
+		return nil, false, err
+	}
+
+	return &m, false, nil
+}
+
+// messageKey is a document in the "messageKeys" collection, the idempotency key of a message.
+//
+// It expires at the end of the idempotency window (IDEMPOTENCY_KEY_TTL) since the key was first used,
+// by the TTL index of the collection, and so the key can be used again for a new message.
+type messageKey struct {
+	Key       string             `bson:"_id"`
+	MessageID primitive.ObjectID `bson:"messageId"`
+	ExpiresAt time.Time          `bson:"expiresAt"`
+}
+
+// releaseMessage deletes a message that wasn't sent, along with its idempotency key,
+// so that a retry of the same key sends it again
+func (s *server) releaseMessage(ctx context.Context, msg *message) {
+	s.messageKeys.DeleteOne(ctx, bson.M{"_id": msg.IdempotencyKey, "messageId": msg.ID})
+	s.db.DeleteOne(ctx, bson.M{"_id": msg.ID})
+}
+
 // findPhoneNumber is a helper function to find
 // if a given phone number exists by calling FindOne of PhoneBook service
 func (s *server) findPhoneNumber(ctx context.Context, phoneNumber string) error {
+	_, err := s.findOwner(ctx, phoneNumber)
+	return err
+}
+
+// findOwner is like findPhoneNumber, but it also returns the user id of the account that owns it
+func (s *server) findOwner(ctx context.Context, phoneNumber string) (int32, error) {
 	res, err := s.pB.FindOne(ctx, &phonebook.FindOneRequest{PhoneNumber: phoneNumber})
 	if err != nil {
-		return status.Error(codes.Internal, "Internal error "+err.Error())
+		return 0, status.Error(codes.Internal, "Internal error "+err.Error())
 	}
 
 	if res.GetExists() == false {
-		return status.Error(codes.NotFound, "Phone number doesn't exist")
+		return 0, status.Error(codes.NotFound, "Phone number doesn't exist")
 	}
 
-	return nil
+	return res.GetUserId(), nil
+}
+
+// findRoute finds the route of a message to the given phone number:
+// ON_NET if it exists on the platform, along with the user id of the account that owns it, and OFF_NET otherwise
+func (s *server) findRoute(ctx context.Context, phoneNumber string) (Route, int32, error) {
+	userID, err := s.findOwner(ctx, phoneNumber)
+	if status.Code(err) == codes.NotFound {
+		return Route_OFF_NET, 0, nil
+	}
+
+	if err != nil {
+		return Route_ON_NET, 0, err
+	}
+
+	return Route_ON_NET, userID, nil
+}
+
+// nextSeq increments and returns the sequence of the given name, starting from 1
+func (s *server) nextSeq(ctx context.Context, name string) (int64, error) {
+	var counter struct {
+		Seq int64 `bson:"seq"`
+	}
+
+	err := s.counters.FindOneAndUpdate(ctx, bson.M{"_id": name},
+		bson.M{"$inc": bson.M{"seq": 1}},
+		options.FindOneAndUpdate().SetUpsert(true).SetReturnDocument(options.After)).Decode(&counter)
+
+	return counter.Seq, err
 }
@@ -3,7 +3,14 @@
 
 // SMS Service
 //
-// SMS Service API consists of 2 services to send a single and multiple SMSs
+// SMS Service API consists of 2 services to send a single and multiple SMSs,
+// services to get the status of a sent SMS, and the progress of SendMany,
+// services to read the conversations of a phone number,
+// and a stream to subscribe to the new messages of a phone number.
+// An SMS can be scheduled to be sent later, and canceled until then.
+// An SMS can be rendered from a template, and there are services to manage the templates.
+// An account can subscribe webhooks to be told about the new messages and status changes.
+// An account can have quiet hours, where its SMSs are held until the recipients' morning.
 // This service is Idempotent:
 //  It is safe to retry sending the same SMS and will be processed only once.
 //  The client has to attach idempotency key with every single sms.
@@ -14,6 +21,7 @@ import (
 	context "context"
 	fmt "fmt"
 	proto "github.com/golang/protobuf/proto"
+	timestamp "github.com/golang/protobuf/ptypes/timestamp"
 	_ "github.com/mwitkow/go-proto-validators"
 	_ "google.golang.org/genproto/googleapis/api/annotations"
 	grpc "google.golang.org/grpc"
@@ -33,11 +41,278 @@ var _ = math.Inf
 // proto package needs to be updated.
 const _ = proto.ProtoPackageIsVersion3 // please upgrade the proto package
 
+// Priority tells if an sms can wait for the quiet hours of the recipient to end.
+type Priority int32
+
+const (
+	Priority_NORMAL Priority = 0
+	Priority_URGENT Priority = 1
+)
+
+var Priority_name = map[int32]string{
+	0: "NORMAL",
+	1: "URGENT",
+}
+
+var Priority_value = map[string]int32{
+	"NORMAL": 0,
+	"URGENT": 1,
+}
+
+func (x Priority) String() string {
+	return proto.EnumName(Priority_name, int32(x))
+}
+
+func (Priority) EnumDescriptor() ([]byte, []int) {
+	return fileDescriptor_c8d8bdc537111860, []int{0}
+}
+
+// Status is the lifecycle of a message.
+// A message is QUEUED, then SENT, then DELIVERED, or FAILED at any step.
+// A scheduled message is SCHEDULED until it is due, or CANCELED.
+type Status int32
+
+const (
+	Status_QUEUED    Status = 0
+	Status_SENT      Status = 1
+	Status_DELIVERED Status = 2
+	Status_FAILED    Status = 3
+	Status_SCHEDULED Status = 4
+	Status_CANCELED  Status = 5
+)
+
+var Status_name = map[int32]string{
+	0: "QUEUED",
+	1: "SENT",
+	2: "DELIVERED",
+	3: "FAILED",
+	4: "SCHEDULED",
+	5: "CANCELED",
+}
+
+var Status_value = map[string]int32{
+	"QUEUED":    0,
+	"SENT":      1,
+	"DELIVERED": 2,
+	"FAILED":    3,
+	"SCHEDULED": 4,
+	"CANCELED":  5,
+}
+
+func (x Status) String() string {
+	return proto.EnumName(Status_name, int32(x))
+}
+
+func (Status) EnumDescriptor() ([]byte, []int) {
+	return fileDescriptor_c8d8bdc537111860, []int{1}
+}
+
+// Encoding is the character encoding of an sms.
+// GSM7 fits 160 characters in one sms (153 per segment when it is split),
+// while UCS2 is used if any character is not in GSM7, and fits 70 (67 per segment).
+type Encoding int32
+
+const (
+	Encoding_GSM7 Encoding = 0
+	Encoding_UCS2 Encoding = 1
+)
+
+var Encoding_name = map[int32]string{
+	0: "GSM7",
+	1: "UCS2",
+}
+
+var Encoding_value = map[string]int32{
+	"GSM7": 0,
+	"UCS2": 1,
+}
+
+func (x Encoding) String() string {
+	return proto.EnumName(Encoding_name, int32(x))
+}
+
+func (Encoding) EnumDescriptor() ([]byte, []int) {
+	return fileDescriptor_c8d8bdc537111860, []int{2}
+}
+
+// Direction tells if a message was sent by a user on the platform (OUTBOUND),
+// or received by a carrier from outside of it (INBOUND).
+type Direction int32
+
+const (
+	Direction_OUTBOUND Direction = 0
+	Direction_INBOUND  Direction = 1
+)
+
+var Direction_name = map[int32]string{
+	0: "OUTBOUND",
+	1: "INBOUND",
+}
+
+var Direction_value = map[string]int32{
+	"OUTBOUND": 0,
+	"INBOUND":  1,
+}
+
+func (x Direction) String() string {
+	return proto.EnumName(Direction_name, int32(x))
+}
+
+func (Direction) EnumDescriptor() ([]byte, []int) {
+	return fileDescriptor_c8d8bdc537111860, []int{3}
+}
+
+// Route tells how a message went: straight to a recipient on the platform (ON_NET),
+// or through a carrier from or to a phone number outside of it (OFF_NET).
+type Route int32
+
+const (
+	Route_ON_NET  Route = 0
+	Route_OFF_NET Route = 1
+)
+
+var Route_name = map[int32]string{
+	0: "ON_NET",
+	1: "OFF_NET",
+}
+
+var Route_value = map[string]int32{
+	"ON_NET":  0,
+	"OFF_NET": 1,
+}
+
+func (x Route) String() string {
+	return proto.EnumName(Route_name, int32(x))
+}
+
+func (Route) EnumDescriptor() ([]byte, []int) {
+	return fileDescriptor_c8d8bdc537111860, []int{4}
+}
+
+type FilterDecision_Action int32
+
+const (
+	FilterDecision_FLAG    FilterDecision_Action = 0
+	FilterDecision_REWRITE FilterDecision_Action = 1
+	FilterDecision_REJECT  FilterDecision_Action = 2
+)
+
+var FilterDecision_Action_name = map[int32]string{
+	0: "FLAG",
+	1: "REWRITE",
+	2: "REJECT",
+}
+
+var FilterDecision_Action_value = map[string]int32{
+	"FLAG":    0,
+	"REWRITE": 1,
+	"REJECT":  2,
+}
+
+func (x FilterDecision_Action) String() string {
+	return proto.EnumName(FilterDecision_Action_name, int32(x))
+}
+
+func (FilterDecision_Action) EnumDescriptor() ([]byte, []int) {
+	return fileDescriptor_c8d8bdc537111860, []int{4, 0}
+}
+
+type GetTrackingResponse_State int32
+
+const (
+	GetTrackingResponse_IN_PROGRESS GetTrackingResponse_State = 0
+	GetTrackingResponse_DONE        GetTrackingResponse_State = 1
+)
+
+var GetTrackingResponse_State_name = map[int32]string{
+	0: "IN_PROGRESS",
+	1: "DONE",
+}
+
+var GetTrackingResponse_State_value = map[string]int32{
+	"IN_PROGRESS": 0,
+	"DONE":        1,
+}
+
+func (x GetTrackingResponse_State) String() string {
+	return proto.EnumName(GetTrackingResponse_State_name, int32(x))
+}
+
+func (GetTrackingResponse_State) EnumDescriptor() ([]byte, []int) {
+	return fileDescriptor_c8d8bdc537111860, []int{11, 0}
+}
+
+type SubscribeResponse_Type int32
+
+const (
+	SubscribeResponse_MESSAGE SubscribeResponse_Type = 0
+	SubscribeResponse_STATUS  SubscribeResponse_Type = 1
+)
+
+var SubscribeResponse_Type_name = map[int32]string{
+	0: "MESSAGE",
+	1: "STATUS",
+}
+
+var SubscribeResponse_Type_value = map[string]int32{
+	"MESSAGE": 0,
+	"STATUS":  1,
+}
+
+func (x SubscribeResponse_Type) String() string {
+	return proto.EnumName(SubscribeResponse_Type_name, int32(x))
+}
+
+func (SubscribeResponse_Type) EnumDescriptor() ([]byte, []int) {
+	return fileDescriptor_c8d8bdc537111860, []int{19, 0}
+}
+
+type Placeholder_Type int32
+
+const (
+	Placeholder_STRING Placeholder_Type = 0
+	Placeholder_NUMBER Placeholder_Type = 1
+	Placeholder_DATE   Placeholder_Type = 2
+)
+
+var Placeholder_Type_name = map[int32]string{
+	0: "STRING",
+	1: "NUMBER",
+	2: "DATE",
+}
+
+var Placeholder_Type_value = map[string]int32{
+	"STRING": 0,
+	"NUMBER": 1,
+	"DATE":   2,
+}
+
+func (x Placeholder_Type) String() string {
+	return proto.EnumName(Placeholder_Type_name, int32(x))
+}
+
+func (Placeholder_Type) EnumDescriptor() ([]byte, []int) {
+	return fileDescriptor_c8d8bdc537111860, []int{24, 0}
+}
+
 type SMS struct {
-	IdempotencyKey       string   `protobuf:"bytes,1,opt,name=idempotency_key,json=idempotencyKey,proto3" json:"idempotency_key,omitempty"`
-	FromPhoneNumber      string   `protobuf:"bytes,2,opt,name=from_phone_number,json=fromPhoneNumber,proto3" json:"from_phone_number,omitempty"`
-	ToPhoneNumber        string   `protobuf:"bytes,3,opt,name=to_phone_number,json=toPhoneNumber,proto3" json:"to_phone_number,omitempty"`
-	Content              string   `protobuf:"bytes,4,opt,name=content,proto3" json:"content,omitempty"`
+	IdempotencyKey  string `protobuf:"bytes,1,opt,name=idempotency_key,json=idempotencyKey,proto3" json:"idempotency_key,omitempty"`
+	FromPhoneNumber string `protobuf:"bytes,2,opt,name=from_phone_number,json=fromPhoneNumber,proto3" json:"from_phone_number,omitempty"`
+	ToPhoneNumber   string `protobuf:"bytes,3,opt,name=to_phone_number,json=toPhoneNumber,proto3" json:"to_phone_number,omitempty"`
+	// Either content, or template_id with the variables, is required.
+	Content string `protobuf:"bytes,4,opt,name=content,proto3" json:"content,omitempty"`
+	// Replace the characters that are not in GSM7 with their GSM7 look-alikes
+	// (i.e. curly quotes with straight ones), so that the sms is sent in GSM7 rather than UCS2.
+	SmartEncoding bool `protobuf:"varint,5,opt,name=smart_encoding,json=smartEncoding,proto3" json:"smart_encoding,omitempty"`
+	// Schedule the sms to be sent at this time. If it is empty or not in the future,
+	// the sms is sent right away.
+	SendAt *timestamp.Timestamp `protobuf:"bytes,6,opt,name=send_at,json=sendAt,proto3" json:"send_at,omitempty"`
+	// Render the content from a template, where variables are the values of its placeholders.
+	TemplateId string            `protobuf:"bytes,7,opt,name=template_id,json=templateId,proto3" json:"template_id,omitempty"`
+	Variables  map[string]string `protobuf:"bytes,8,rep,name=variables,proto3" json:"variables,omitempty" protobuf_key:"bytes,1,opt,name=key,proto3" protobuf_val:"bytes,2,opt,name=value,proto3"`
+	// An URGENT sms (i.e. a verification code) is sent even in the quiet hours of the recipient,
+	// while a NORMAL one is held until they end.
+	Priority             Priority `protobuf:"varint,9,opt,name=priority,proto3,enum=sms.Priority" json:"priority,omitempty"`
 	XXX_NoUnkeyedLiteral struct{} `json:"-"`
 	XXX_unrecognized     []byte   `json:"-"`
 	XXX_sizecache        int32    `json:"-"`
@@ -96,6 +371,42 @@ func (m *SMS) GetContent() string {
 	return ""
 }
 
+func (m *SMS) GetSmartEncoding() bool {
+	if m != nil {
+		return m.SmartEncoding
+	}
+	return false
+}
+
+func (m *SMS) GetSendAt() *timestamp.Timestamp {
+	if m != nil {
+		return m.SendAt
+	}
+	return nil
+}
+
+func (m *SMS) GetTemplateId() string {
+	if m != nil {
+		return m.TemplateId
+	}
+	return ""
+}
+
+func (m *SMS) GetVariables() map[string]string {
+	if m != nil {
+		return m.Variables
+	}
+	return nil
+}
+
+func (m *SMS) GetPriority() Priority {
+	if m != nil {
+		return m.Priority
+	}
+	return Priority_NORMAL
+}
+
+// ---- Send
 type SendOneRequest struct {
 	Sms                  *SMS     `protobuf:"bytes,1,opt,name=sms,proto3" json:"sms,omitempty"`
 	XXX_NoUnkeyedLiteral struct{} `json:"-"`
@@ -136,11 +447,24 @@ func (m *SendOneRequest) GetSms() *SMS {
 }
 
 type SendOneResponse struct {
-	Sent                 bool     `protobuf:"varint,1,opt,name=sent,proto3" json:"sent,omitempty"`
-	Message              string   `protobuf:"bytes,2,opt,name=message,proto3" json:"message,omitempty"`
-	XXX_NoUnkeyedLiteral struct{} `json:"-"`
-	XXX_unrecognized     []byte   `json:"-"`
-	XXX_sizecache        int32    `json:"-"`
+	Sent      bool     `protobuf:"varint,1,opt,name=sent,proto3" json:"sent,omitempty"`
+	Message   string   `protobuf:"bytes,2,opt,name=message,proto3" json:"message,omitempty"`
+	MessageId string   `protobuf:"bytes,3,opt,name=message_id,json=messageId,proto3" json:"message_id,omitempty"`
+	Status    Status   `protobuf:"varint,4,opt,name=status,proto3,enum=sms.Status" json:"status,omitempty"`
+	Encoding  Encoding `protobuf:"varint,5,opt,name=encoding,proto3,enum=sms.Encoding" json:"encoding,omitempty"`
+	// The number of segments of a concatenated sms, 1 if it fits in one.
+	SegmentCount int32 `protobuf:"varint,6,opt,name=segment_count,json=segmentCount,proto3" json:"segment_count,omitempty"`
+	// The characters replaced by smart encoding, if any.
+	Substitutions []*Substitution `protobuf:"bytes,7,rep,name=substitutions,proto3" json:"substitutions,omitempty"`
+	Route         Route           `protobuf:"varint,8,opt,name=route,proto3,enum=sms.Route" json:"route,omitempty"`
+	// The rules of the content filter that matched the content, if any.
+	FilterDecisions []*FilterDecision `protobuf:"bytes,9,rep,name=filter_decisions,json=filterDecisions,proto3" json:"filter_decisions,omitempty"`
+	// When a SCHEDULED sms is to be sent: the send_at of the request,
+	// or the end of the quiet hours of the recipient if it is held until then.
+	SendAt               *timestamp.Timestamp `protobuf:"bytes,10,opt,name=send_at,json=sendAt,proto3" json:"send_at,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}             `json:"-"`
+	XXX_unrecognized     []byte               `json:"-"`
+	XXX_sizecache        int32                `json:"-"`
 }
 
 func (m *SendOneResponse) Reset()         { *m = SendOneResponse{} }
@@ -182,6 +506,174 @@ func (m *SendOneResponse) GetMessage() string {
 	return ""
 }
 
+func (m *SendOneResponse) GetMessageId() string {
+	if m != nil {
+		return m.MessageId
+	}
+	return ""
+}
+
+func (m *SendOneResponse) GetStatus() Status {
+	if m != nil {
+		return m.Status
+	}
+	return Status_QUEUED
+}
+
+func (m *SendOneResponse) GetEncoding() Encoding {
+	if m != nil {
+		return m.Encoding
+	}
+	return Encoding_GSM7
+}
+
+func (m *SendOneResponse) GetSegmentCount() int32 {
+	if m != nil {
+		return m.SegmentCount
+	}
+	return 0
+}
+
+func (m *SendOneResponse) GetSubstitutions() []*Substitution {
+	if m != nil {
+		return m.Substitutions
+	}
+	return nil
+}
+
+func (m *SendOneResponse) GetRoute() Route {
+	if m != nil {
+		return m.Route
+	}
+	return Route_ON_NET
+}
+
+func (m *SendOneResponse) GetFilterDecisions() []*FilterDecision {
+	if m != nil {
+		return m.FilterDecisions
+	}
+	return nil
+}
+
+func (m *SendOneResponse) GetSendAt() *timestamp.Timestamp {
+	if m != nil {
+		return m.SendAt
+	}
+	return nil
+}
+
+type Substitution struct {
+	From                 string   `protobuf:"bytes,1,opt,name=from,proto3" json:"from,omitempty"`
+	To                   string   `protobuf:"bytes,2,opt,name=to,proto3" json:"to,omitempty"`
+	Count                int32    `protobuf:"varint,3,opt,name=count,proto3" json:"count,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *Substitution) Reset()         { *m = Substitution{} }
+func (m *Substitution) String() string { return proto.CompactTextString(m) }
+func (*Substitution) ProtoMessage()    {}
+func (*Substitution) Descriptor() ([]byte, []int) {
+	return fileDescriptor_c8d8bdc537111860, []int{3}
+}
+
+func (m *Substitution) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_Substitution.Unmarshal(m, b)
+}
+func (m *Substitution) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_Substitution.Marshal(b, m, deterministic)
+}
+func (m *Substitution) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_Substitution.Merge(m, src)
+}
+func (m *Substitution) XXX_Size() int {
+	return xxx_messageInfo_Substitution.Size(m)
+}
+func (m *Substitution) XXX_DiscardUnknown() {
+	xxx_messageInfo_Substitution.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_Substitution proto.InternalMessageInfo
+
+func (m *Substitution) GetFrom() string {
+	if m != nil {
+		return m.From
+	}
+	return ""
+}
+
+func (m *Substitution) GetTo() string {
+	if m != nil {
+		return m.To
+	}
+	return ""
+}
+
+func (m *Substitution) GetCount() int32 {
+	if m != nil {
+		return m.Count
+	}
+	return 0
+}
+
+// A rule of the content filter that matched the content of an sms, and what it did.
+type FilterDecision struct {
+	Rule   string                `protobuf:"bytes,1,opt,name=rule,proto3" json:"rule,omitempty"`
+	Action FilterDecision_Action `protobuf:"varint,2,opt,name=action,proto3,enum=sms.FilterDecision_Action" json:"action,omitempty"`
+	// The parts of the content that matched, i.e. the links to a denied domain.
+	Matches              []string `protobuf:"bytes,3,rep,name=matches,proto3" json:"matches,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *FilterDecision) Reset()         { *m = FilterDecision{} }
+func (m *FilterDecision) String() string { return proto.CompactTextString(m) }
+func (*FilterDecision) ProtoMessage()    {}
+func (*FilterDecision) Descriptor() ([]byte, []int) {
+	return fileDescriptor_c8d8bdc537111860, []int{4}
+}
+
+func (m *FilterDecision) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_FilterDecision.Unmarshal(m, b)
+}
+func (m *FilterDecision) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_FilterDecision.Marshal(b, m, deterministic)
+}
+func (m *FilterDecision) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_FilterDecision.Merge(m, src)
+}
+func (m *FilterDecision) XXX_Size() int {
+	return xxx_messageInfo_FilterDecision.Size(m)
+}
+func (m *FilterDecision) XXX_DiscardUnknown() {
+	xxx_messageInfo_FilterDecision.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_FilterDecision proto.InternalMessageInfo
+
+func (m *FilterDecision) GetRule() string {
+	if m != nil {
+		return m.Rule
+	}
+	return ""
+}
+
+func (m *FilterDecision) GetAction() FilterDecision_Action {
+	if m != nil {
+		return m.Action
+	}
+	return FilterDecision_FLAG
+}
+
+func (m *FilterDecision) GetMatches() []string {
+	if m != nil {
+		return m.Matches
+	}
+	return nil
+}
+
 type SendManyRequest struct {
 	Sms                  *SMS     `protobuf:"bytes,1,opt,name=sms,proto3" json:"sms,omitempty"`
 	XXX_NoUnkeyedLiteral struct{} `json:"-"`
@@ -193,7 +685,7 @@ func (m *SendManyRequest) Reset()         { *m = SendManyRequest{} }
 func (m *SendManyRequest) String() string { return proto.CompactTextString(m) }
 func (*SendManyRequest) ProtoMessage()    {}
 func (*SendManyRequest) Descriptor() ([]byte, []int) {
-	return fileDescriptor_c8d8bdc537111860, []int{3}
+	return fileDescriptor_c8d8bdc537111860, []int{5}
 }
 
 func (m *SendManyRequest) XXX_Unmarshal(b []byte) error {
@@ -222,8 +714,8 @@ func (m *SendManyRequest) GetSms() *SMS {
 }
 
 type SendManyResponse struct {
-	// An array of errors occured (if any) for each sms
-	Errors               []string `protobuf:"bytes,1,rep,name=errors,proto3" json:"errors,omitempty"`
+	// Used to get the progress of sending the SMSs by GetTracking
+	TrackingId           string   `protobuf:"bytes,2,opt,name=tracking_id,json=trackingId,proto3" json:"tracking_id,omitempty"`
 	XXX_NoUnkeyedLiteral struct{} `json:"-"`
 	XXX_unrecognized     []byte   `json:"-"`
 	XXX_sizecache        int32    `json:"-"`
@@ -233,7 +725,7 @@ func (m *SendManyResponse) Reset()         { *m = SendManyResponse{} }
 func (m *SendManyResponse) String() string { return proto.CompactTextString(m) }
 func (*SendManyResponse) ProtoMessage()    {}
 func (*SendManyResponse) Descriptor() ([]byte, []int) {
-	return fileDescriptor_c8d8bdc537111860, []int{4}
+	return fileDescriptor_c8d8bdc537111860, []int{6}
 }
 
 func (m *SendManyResponse) XXX_Unmarshal(b []byte) error {
@@ -254,193 +746,3705 @@ func (m *SendManyResponse) XXX_DiscardUnknown() {
 
 var xxx_messageInfo_SendManyResponse proto.InternalMessageInfo
 
-func (m *SendManyResponse) GetErrors() []string {
+func (m *SendManyResponse) GetTrackingId() string {
 	if m != nil {
-		return m.Errors
+		return m.TrackingId
 	}
-	return nil
+	return ""
 }
 
-func init() {
-	proto.RegisterType((*SMS)(nil), "sms.SMS")
-	proto.RegisterType((*SendOneRequest)(nil), "sms.SendOneRequest")
-	proto.RegisterType((*SendOneResponse)(nil), "sms.SendOneResponse")
-	proto.RegisterType((*SendManyRequest)(nil), "sms.SendManyRequest")
-	proto.RegisterType((*SendManyResponse)(nil), "sms.SendManyResponse")
+// ---- Status
+type GetMessageStatusRequest struct {
+	MessageId            string   `protobuf:"bytes,1,opt,name=message_id,json=messageId,proto3" json:"message_id,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
 }
 
-func init() { proto.RegisterFile("sms.proto", fileDescriptor_c8d8bdc537111860) }
+func (m *GetMessageStatusRequest) Reset()         { *m = GetMessageStatusRequest{} }
+func (m *GetMessageStatusRequest) String() string { return proto.CompactTextString(m) }
+func (*GetMessageStatusRequest) ProtoMessage()    {}
+func (*GetMessageStatusRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_c8d8bdc537111860, []int{7}
+}
 
-var fileDescriptor_c8d8bdc537111860 = []byte{
-	// 408 bytes of a gzipped FileDescriptorProto
-	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0x8c, 0x52, 0x4d, 0x8a, 0x13, 0x41,
-	0x14, 0xa6, 0x92, 0x21, 0x3f, 0x4f, 0x26, 0xd1, 0x1a, 0x95, 0xb6, 0x11, 0x1c, 0x7a, 0x15, 0x82,
-	0x49, 0x41, 0x04, 0x17, 0xb3, 0x11, 0xdc, 0x4a, 0x46, 0xe9, 0x72, 0xe1, 0x2e, 0x54, 0x92, 0x67,
-	0x4f, 0x33, 0x53, 0xf5, 0xda, 0xaa, 0xca, 0x0c, 0xbd, 0xf5, 0x0a, 0xde, 0xc4, 0xb5, 0xb7, 0xf0,
-	0x00, 0x82, 0x78, 0x10, 0xa9, 0xee, 0xce, 0x24, 0xed, 0xca, 0x5d, 0xbd, 0xef, 0xef, 0xfd, 0x50,
-	0x30, 0x74, 0xda, 0xcd, 0x0b, 0x4b, 0x9e, 0x78, 0xd7, 0x69, 0x17, 0x3f, 0xcf, 0x88, 0xb2, 0x1b,
-	0x14, 0xaa, 0xc8, 0x85, 0x32, 0x86, 0xbc, 0xf2, 0x39, 0x99, 0x46, 0x12, 0xbf, 0xce, 0x72, 0x7f,
-	0xb5, 0x5b, 0xcf, 0x37, 0xa4, 0x85, 0xbe, 0xcb, 0xfd, 0x35, 0xdd, 0x89, 0x8c, 0x66, 0x15, 0x39,
-	0xbb, 0x55, 0x37, 0xf9, 0x56, 0x79, 0xb2, 0x4e, 0xdc, 0x3f, 0x6b, 0x5f, 0xf2, 0x83, 0x41, 0x57,
-	0x2e, 0x25, 0x17, 0x30, 0xce, 0xb7, 0xa8, 0x0b, 0xf2, 0x68, 0x36, 0xe5, 0xea, 0x1a, 0xcb, 0x88,
-	0x9d, 0xb3, 0xc9, 0xf0, 0x6d, 0xef, 0xf7, 0xaf, 0x17, 0x9d, 0x4f, 0x2c, 0x1d, 0x1d, 0xd1, 0xef,
-	0xb0, 0xe4, 0x0b, 0x78, 0xf4, 0xd9, 0x92, 0x5e, 0x15, 0x57, 0x64, 0x70, 0x65, 0x76, 0x7a, 0x8d,
-	0x36, 0xea, 0xb4, 0x2c, 0xe3, 0x20, 0xf8, 0x10, 0xf8, 0xcb, 0x8a, 0xe6, 0x73, 0x18, 0x7b, 0x6a,
-	0x3b, 0xba, 0x2d, 0xc7, 0xa9, 0xa7, 0x63, 0xfd, 0x39, 0xf4, 0x37, 0x64, 0x3c, 0x1a, 0x1f, 0x9d,
-	0xb4, 0x74, 0x7b, 0x38, 0x79, 0x09, 0x23, 0x89, 0x66, 0xfb, 0xde, 0x60, 0x8a, 0x5f, 0x76, 0xe8,
-	0x3c, 0x8f, 0x21, 0x5c, 0xab, 0x1a, 0xfe, 0xc1, 0x62, 0x30, 0x0f, 0x47, 0x94, 0x4b, 0x99, 0x06,
-	0x30, 0x79, 0x03, 0xe3, 0x7b, 0xb5, 0x2b, 0xc8, 0x38, 0xe4, 0x1c, 0x4e, 0x5c, 0xc8, 0x0f, 0xfa,
-	0x41, 0x5a, 0xbd, 0x79, 0x04, 0x7d, 0x8d, 0xce, 0xa9, 0x0c, 0xeb, 0x85, 0xd2, 0x7d, 0x99, 0xcc,
-	0xea, 0x80, 0xa5, 0x32, 0xe5, 0xff, 0xf4, 0x9b, 0xc2, 0xc3, 0x83, 0xbc, 0x69, 0xf8, 0x14, 0x7a,
-	0x68, 0x2d, 0xd9, 0x60, 0xe9, 0x4e, 0x86, 0x69, 0x53, 0x2d, 0xbe, 0x33, 0x00, 0xb9, 0x94, 0x12,
-	0xed, 0x6d, 0xbe, 0x41, 0x7e, 0x09, 0xfd, 0x66, 0x54, 0x7e, 0x56, 0x87, 0xb6, 0xd6, 0x8c, 0x1f,
-	0xb7, 0xc1, 0x3a, 0x3c, 0x89, 0xbe, 0xfe, 0xfc, 0xf3, 0xad, 0xc3, 0x93, 0x53, 0xe1, 0xb4, 0x13,
-	0x0e, 0xcd, 0x56, 0x90, 0xc1, 0x0b, 0x36, 0xe5, 0x1f, 0x61, 0xb0, 0x1f, 0x85, 0x1f, 0xbc, 0x47,
-	0x8b, 0xc4, 0x4f, 0xfe, 0x41, 0x9b, 0xc8, 0x67, 0x55, 0xe4, 0x59, 0x32, 0x3a, 0x44, 0x6a, 0x65,
-	0xca, 0x0b, 0x36, 0x9d, 0xb0, 0x75, 0xaf, 0xfa, 0x44, 0xaf, 0xfe, 0x06, 0x00, 0x00, 0xff, 0xff,
-	0x37, 0xec, 0x97, 0xf3, 0xac, 0x02, 0x00, 0x00,
+func (m *GetMessageStatusRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_GetMessageStatusRequest.Unmarshal(m, b)
+}
+func (m *GetMessageStatusRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_GetMessageStatusRequest.Marshal(b, m, deterministic)
+}
+func (m *GetMessageStatusRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_GetMessageStatusRequest.Merge(m, src)
+}
+func (m *GetMessageStatusRequest) XXX_Size() int {
+	return xxx_messageInfo_GetMessageStatusRequest.Size(m)
+}
+func (m *GetMessageStatusRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_GetMessageStatusRequest.DiscardUnknown(m)
 }
 
-// Reference imports to suppress errors if they are not otherwise used.
-var _ context.Context
-var _ grpc.ClientConn
+var xxx_messageInfo_GetMessageStatusRequest proto.InternalMessageInfo
 
-// This is a compile-time assertion to ensure that this generated file
-// is compatible with the grpc package it is being compiled against.
-const _ = grpc.SupportPackageIsVersion4
+func (m *GetMessageStatusRequest) GetMessageId() string {
+	if m != nil {
+		return m.MessageId
+	}
+	return ""
+}
+
+type GetMessageStatusResponse struct {
+	MessageId            string               `protobuf:"bytes,1,opt,name=message_id,json=messageId,proto3" json:"message_id,omitempty"`
+	Status               Status               `protobuf:"varint,2,opt,name=status,proto3,enum=sms.Status" json:"status,omitempty"`
+	FailureReason        string               `protobuf:"bytes,3,opt,name=failure_reason,json=failureReason,proto3" json:"failure_reason,omitempty"`
+	CreatedAt            *timestamp.Timestamp `protobuf:"bytes,4,opt,name=created_at,json=createdAt,proto3" json:"created_at,omitempty"`
+	UpdatedAt            *timestamp.Timestamp `protobuf:"bytes,5,opt,name=updated_at,json=updatedAt,proto3" json:"updated_at,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}             `json:"-"`
+	XXX_unrecognized     []byte               `json:"-"`
+	XXX_sizecache        int32                `json:"-"`
+}
+
+func (m *GetMessageStatusResponse) Reset()         { *m = GetMessageStatusResponse{} }
+func (m *GetMessageStatusResponse) String() string { return proto.CompactTextString(m) }
+func (*GetMessageStatusResponse) ProtoMessage()    {}
+func (*GetMessageStatusResponse) Descriptor() ([]byte, []int) {
+	return fileDescriptor_c8d8bdc537111860, []int{8}
+}
+
+func (m *GetMessageStatusResponse) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_GetMessageStatusResponse.Unmarshal(m, b)
+}
+func (m *GetMessageStatusResponse) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_GetMessageStatusResponse.Marshal(b, m, deterministic)
+}
+func (m *GetMessageStatusResponse) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_GetMessageStatusResponse.Merge(m, src)
+}
+func (m *GetMessageStatusResponse) XXX_Size() int {
+	return xxx_messageInfo_GetMessageStatusResponse.Size(m)
+}
+func (m *GetMessageStatusResponse) XXX_DiscardUnknown() {
+	xxx_messageInfo_GetMessageStatusResponse.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_GetMessageStatusResponse proto.InternalMessageInfo
+
+func (m *GetMessageStatusResponse) GetMessageId() string {
+	if m != nil {
+		return m.MessageId
+	}
+	return ""
+}
+
+func (m *GetMessageStatusResponse) GetStatus() Status {
+	if m != nil {
+		return m.Status
+	}
+	return Status_QUEUED
+}
+
+func (m *GetMessageStatusResponse) GetFailureReason() string {
+	if m != nil {
+		return m.FailureReason
+	}
+	return ""
+}
+
+func (m *GetMessageStatusResponse) GetCreatedAt() *timestamp.Timestamp {
+	if m != nil {
+		return m.CreatedAt
+	}
+	return nil
+}
+
+func (m *GetMessageStatusResponse) GetUpdatedAt() *timestamp.Timestamp {
+	if m != nil {
+		return m.UpdatedAt
+	}
+	return nil
+}
+
+// ---- Tracking
+type GetTrackingRequest struct {
+	TrackingId           string   `protobuf:"bytes,1,opt,name=tracking_id,json=trackingId,proto3" json:"tracking_id,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *GetTrackingRequest) Reset()         { *m = GetTrackingRequest{} }
+func (m *GetTrackingRequest) String() string { return proto.CompactTextString(m) }
+func (*GetTrackingRequest) ProtoMessage()    {}
+func (*GetTrackingRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_c8d8bdc537111860, []int{9}
+}
+
+func (m *GetTrackingRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_GetTrackingRequest.Unmarshal(m, b)
+}
+func (m *GetTrackingRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_GetTrackingRequest.Marshal(b, m, deterministic)
+}
+func (m *GetTrackingRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_GetTrackingRequest.Merge(m, src)
+}
+func (m *GetTrackingRequest) XXX_Size() int {
+	return xxx_messageInfo_GetTrackingRequest.Size(m)
+}
+func (m *GetTrackingRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_GetTrackingRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_GetTrackingRequest proto.InternalMessageInfo
+
+func (m *GetTrackingRequest) GetTrackingId() string {
+	if m != nil {
+		return m.TrackingId
+	}
+	return ""
+}
+
+type TrackedMessage struct {
+	IdempotencyKey       string   `protobuf:"bytes,1,opt,name=idempotency_key,json=idempotencyKey,proto3" json:"idempotency_key,omitempty"`
+	MessageId            string   `protobuf:"bytes,2,opt,name=message_id,json=messageId,proto3" json:"message_id,omitempty"`
+	Status               Status   `protobuf:"varint,3,opt,name=status,proto3,enum=sms.Status" json:"status,omitempty"`
+	Error                string   `protobuf:"bytes,4,opt,name=error,proto3" json:"error,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *TrackedMessage) Reset()         { *m = TrackedMessage{} }
+func (m *TrackedMessage) String() string { return proto.CompactTextString(m) }
+func (*TrackedMessage) ProtoMessage()    {}
+func (*TrackedMessage) Descriptor() ([]byte, []int) {
+	return fileDescriptor_c8d8bdc537111860, []int{10}
+}
+
+func (m *TrackedMessage) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_TrackedMessage.Unmarshal(m, b)
+}
+func (m *TrackedMessage) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_TrackedMessage.Marshal(b, m, deterministic)
+}
+func (m *TrackedMessage) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_TrackedMessage.Merge(m, src)
+}
+func (m *TrackedMessage) XXX_Size() int {
+	return xxx_messageInfo_TrackedMessage.Size(m)
+}
+func (m *TrackedMessage) XXX_DiscardUnknown() {
+	xxx_messageInfo_TrackedMessage.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_TrackedMessage proto.InternalMessageInfo
+
+func (m *TrackedMessage) GetIdempotencyKey() string {
+	if m != nil {
+		return m.IdempotencyKey
+	}
+	return ""
+}
+
+func (m *TrackedMessage) GetMessageId() string {
+	if m != nil {
+		return m.MessageId
+	}
+	return ""
+}
+
+func (m *TrackedMessage) GetStatus() Status {
+	if m != nil {
+		return m.Status
+	}
+	return Status_QUEUED
+}
+
+func (m *TrackedMessage) GetError() string {
+	if m != nil {
+		return m.Error
+	}
+	return ""
+}
+
+type GetTrackingResponse struct {
+	TrackingId string                    `protobuf:"bytes,1,opt,name=tracking_id,json=trackingId,proto3" json:"tracking_id,omitempty"`
+	State      GetTrackingResponse_State `protobuf:"varint,2,opt,name=state,proto3,enum=sms.GetTrackingResponse_State" json:"state,omitempty"`
+	Total      int32                     `protobuf:"varint,3,opt,name=total,proto3" json:"total,omitempty"`
+	Sent       int32                     `protobuf:"varint,4,opt,name=sent,proto3" json:"sent,omitempty"`
+	Failed     int32                     `protobuf:"varint,5,opt,name=failed,proto3" json:"failed,omitempty"`
+	// In the same order they were sent in the request.
+	Messages             []*TrackedMessage    `protobuf:"bytes,6,rep,name=messages,proto3" json:"messages,omitempty"`
+	CreatedAt            *timestamp.Timestamp `protobuf:"bytes,7,opt,name=created_at,json=createdAt,proto3" json:"created_at,omitempty"`
+	UpdatedAt            *timestamp.Timestamp `protobuf:"bytes,8,opt,name=updated_at,json=updatedAt,proto3" json:"updated_at,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}             `json:"-"`
+	XXX_unrecognized     []byte               `json:"-"`
+	XXX_sizecache        int32                `json:"-"`
+}
+
+func (m *GetTrackingResponse) Reset()         { *m = GetTrackingResponse{} }
+func (m *GetTrackingResponse) String() string { return proto.CompactTextString(m) }
+func (*GetTrackingResponse) ProtoMessage()    {}
+func (*GetTrackingResponse) Descriptor() ([]byte, []int) {
+	return fileDescriptor_c8d8bdc537111860, []int{11}
+}
+
+func (m *GetTrackingResponse) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_GetTrackingResponse.Unmarshal(m, b)
+}
+func (m *GetTrackingResponse) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_GetTrackingResponse.Marshal(b, m, deterministic)
+}
+func (m *GetTrackingResponse) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_GetTrackingResponse.Merge(m, src)
+}
+func (m *GetTrackingResponse) XXX_Size() int {
+	return xxx_messageInfo_GetTrackingResponse.Size(m)
+}
+func (m *GetTrackingResponse) XXX_DiscardUnknown() {
+	xxx_messageInfo_GetTrackingResponse.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_GetTrackingResponse proto.InternalMessageInfo
+
+func (m *GetTrackingResponse) GetTrackingId() string {
+	if m != nil {
+		return m.TrackingId
+	}
+	return ""
+}
+
+func (m *GetTrackingResponse) GetState() GetTrackingResponse_State {
+	if m != nil {
+		return m.State
+	}
+	return GetTrackingResponse_IN_PROGRESS
+}
+
+func (m *GetTrackingResponse) GetTotal() int32 {
+	if m != nil {
+		return m.Total
+	}
+	return 0
+}
+
+func (m *GetTrackingResponse) GetSent() int32 {
+	if m != nil {
+		return m.Sent
+	}
+	return 0
+}
+
+func (m *GetTrackingResponse) GetFailed() int32 {
+	if m != nil {
+		return m.Failed
+	}
+	return 0
+}
+
+func (m *GetTrackingResponse) GetMessages() []*TrackedMessage {
+	if m != nil {
+		return m.Messages
+	}
+	return nil
+}
+
+func (m *GetTrackingResponse) GetCreatedAt() *timestamp.Timestamp {
+	if m != nil {
+		return m.CreatedAt
+	}
+	return nil
+}
+
+func (m *GetTrackingResponse) GetUpdatedAt() *timestamp.Timestamp {
+	if m != nil {
+		return m.UpdatedAt
+	}
+	return nil
+}
+
+// ---- Conversations
+type Message struct {
+	MessageId            string               `protobuf:"bytes,1,opt,name=message_id,json=messageId,proto3" json:"message_id,omitempty"`
+	FromPhoneNumber      string               `protobuf:"bytes,2,opt,name=from_phone_number,json=fromPhoneNumber,proto3" json:"from_phone_number,omitempty"`
+	ToPhoneNumber        string               `protobuf:"bytes,3,opt,name=to_phone_number,json=toPhoneNumber,proto3" json:"to_phone_number,omitempty"`
+	Content              string               `protobuf:"bytes,4,opt,name=content,proto3" json:"content,omitempty"`
+	Status               Status               `protobuf:"varint,5,opt,name=status,proto3,enum=sms.Status" json:"status,omitempty"`
+	CreatedAt            *timestamp.Timestamp `protobuf:"bytes,6,opt,name=created_at,json=createdAt,proto3" json:"created_at,omitempty"`
+	SendAt               *timestamp.Timestamp `protobuf:"bytes,7,opt,name=send_at,json=sendAt,proto3" json:"send_at,omitempty"`
+	Direction            Direction            `protobuf:"varint,8,opt,name=direction,proto3,enum=sms.Direction" json:"direction,omitempty"`
+	Route                Route                `protobuf:"varint,9,opt,name=route,proto3,enum=sms.Route" json:"route,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}             `json:"-"`
+	XXX_unrecognized     []byte               `json:"-"`
+	XXX_sizecache        int32                `json:"-"`
+}
+
+func (m *Message) Reset()         { *m = Message{} }
+func (m *Message) String() string { return proto.CompactTextString(m) }
+func (*Message) ProtoMessage()    {}
+func (*Message) Descriptor() ([]byte, []int) {
+	return fileDescriptor_c8d8bdc537111860, []int{12}
+}
+
+func (m *Message) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_Message.Unmarshal(m, b)
+}
+func (m *Message) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_Message.Marshal(b, m, deterministic)
+}
+func (m *Message) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_Message.Merge(m, src)
+}
+func (m *Message) XXX_Size() int {
+	return xxx_messageInfo_Message.Size(m)
+}
+func (m *Message) XXX_DiscardUnknown() {
+	xxx_messageInfo_Message.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_Message proto.InternalMessageInfo
+
+func (m *Message) GetMessageId() string {
+	if m != nil {
+		return m.MessageId
+	}
+	return ""
+}
+
+func (m *Message) GetFromPhoneNumber() string {
+	if m != nil {
+		return m.FromPhoneNumber
+	}
+	return ""
+}
+
+func (m *Message) GetToPhoneNumber() string {
+	if m != nil {
+		return m.ToPhoneNumber
+	}
+	return ""
+}
+
+func (m *Message) GetContent() string {
+	if m != nil {
+		return m.Content
+	}
+	return ""
+}
+
+func (m *Message) GetStatus() Status {
+	if m != nil {
+		return m.Status
+	}
+	return Status_QUEUED
+}
+
+func (m *Message) GetCreatedAt() *timestamp.Timestamp {
+	if m != nil {
+		return m.CreatedAt
+	}
+	return nil
+}
+
+func (m *Message) GetSendAt() *timestamp.Timestamp {
+	if m != nil {
+		return m.SendAt
+	}
+	return nil
+}
+
+func (m *Message) GetDirection() Direction {
+	if m != nil {
+		return m.Direction
+	}
+	return Direction_OUTBOUND
+}
+
+func (m *Message) GetRoute() Route {
+	if m != nil {
+		return m.Route
+	}
+	return Route_ON_NET
+}
+
+type ListConversationsRequest struct {
+	PhoneNumber string `protobuf:"bytes,1,opt,name=phone_number,json=phoneNumber,proto3" json:"phone_number,omitempty"`
+	// Defaults to 20, and at most 100.
+	PageSize int32 `protobuf:"varint,2,opt,name=page_size,json=pageSize,proto3" json:"page_size,omitempty"`
+	// The "next_cursor" of the previous page.
+	Cursor               string   `protobuf:"bytes,3,opt,name=cursor,proto3" json:"cursor,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *ListConversationsRequest) Reset()         { *m = ListConversationsRequest{} }
+func (m *ListConversationsRequest) String() string { return proto.CompactTextString(m) }
+func (*ListConversationsRequest) ProtoMessage()    {}
+func (*ListConversationsRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_c8d8bdc537111860, []int{13}
+}
+
+func (m *ListConversationsRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_ListConversationsRequest.Unmarshal(m, b)
+}
+func (m *ListConversationsRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_ListConversationsRequest.Marshal(b, m, deterministic)
+}
+func (m *ListConversationsRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_ListConversationsRequest.Merge(m, src)
+}
+func (m *ListConversationsRequest) XXX_Size() int {
+	return xxx_messageInfo_ListConversationsRequest.Size(m)
+}
+func (m *ListConversationsRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_ListConversationsRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_ListConversationsRequest proto.InternalMessageInfo
+
+func (m *ListConversationsRequest) GetPhoneNumber() string {
+	if m != nil {
+		return m.PhoneNumber
+	}
+	return ""
+}
+
+func (m *ListConversationsRequest) GetPageSize() int32 {
+	if m != nil {
+		return m.PageSize
+	}
+	return 0
+}
+
+func (m *ListConversationsRequest) GetCursor() string {
+	if m != nil {
+		return m.Cursor
+	}
+	return ""
+}
+
+type Conversation struct {
+	// The other phone number in the conversation.
+	PhoneNumber          string   `protobuf:"bytes,1,opt,name=phone_number,json=phoneNumber,proto3" json:"phone_number,omitempty"`
+	LastMessage          *Message `protobuf:"bytes,2,opt,name=last_message,json=lastMessage,proto3" json:"last_message,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *Conversation) Reset()         { *m = Conversation{} }
+func (m *Conversation) String() string { return proto.CompactTextString(m) }
+func (*Conversation) ProtoMessage()    {}
+func (*Conversation) Descriptor() ([]byte, []int) {
+	return fileDescriptor_c8d8bdc537111860, []int{14}
+}
+
+func (m *Conversation) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_Conversation.Unmarshal(m, b)
+}
+func (m *Conversation) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_Conversation.Marshal(b, m, deterministic)
+}
+func (m *Conversation) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_Conversation.Merge(m, src)
+}
+func (m *Conversation) XXX_Size() int {
+	return xxx_messageInfo_Conversation.Size(m)
+}
+func (m *Conversation) XXX_DiscardUnknown() {
+	xxx_messageInfo_Conversation.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_Conversation proto.InternalMessageInfo
+
+func (m *Conversation) GetPhoneNumber() string {
+	if m != nil {
+		return m.PhoneNumber
+	}
+	return ""
+}
+
+func (m *Conversation) GetLastMessage() *Message {
+	if m != nil {
+		return m.LastMessage
+	}
+	return nil
+}
+
+type ListConversationsResponse struct {
+	// The most recent first.
+	Conversations        []*Conversation `protobuf:"bytes,1,rep,name=conversations,proto3" json:"conversations,omitempty"`
+	NextCursor           string          `protobuf:"bytes,2,opt,name=next_cursor,json=nextCursor,proto3" json:"next_cursor,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}        `json:"-"`
+	XXX_unrecognized     []byte          `json:"-"`
+	XXX_sizecache        int32           `json:"-"`
+}
+
+func (m *ListConversationsResponse) Reset()         { *m = ListConversationsResponse{} }
+func (m *ListConversationsResponse) String() string { return proto.CompactTextString(m) }
+func (*ListConversationsResponse) ProtoMessage()    {}
+func (*ListConversationsResponse) Descriptor() ([]byte, []int) {
+	return fileDescriptor_c8d8bdc537111860, []int{15}
+}
+
+func (m *ListConversationsResponse) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_ListConversationsResponse.Unmarshal(m, b)
+}
+func (m *ListConversationsResponse) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_ListConversationsResponse.Marshal(b, m, deterministic)
+}
+func (m *ListConversationsResponse) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_ListConversationsResponse.Merge(m, src)
+}
+func (m *ListConversationsResponse) XXX_Size() int {
+	return xxx_messageInfo_ListConversationsResponse.Size(m)
+}
+func (m *ListConversationsResponse) XXX_DiscardUnknown() {
+	xxx_messageInfo_ListConversationsResponse.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_ListConversationsResponse proto.InternalMessageInfo
+
+func (m *ListConversationsResponse) GetConversations() []*Conversation {
+	if m != nil {
+		return m.Conversations
+	}
+	return nil
+}
+
+func (m *ListConversationsResponse) GetNextCursor() string {
+	if m != nil {
+		return m.NextCursor
+	}
+	return ""
+}
+
+type GetThreadRequest struct {
+	PhoneNumber      string `protobuf:"bytes,1,opt,name=phone_number,json=phoneNumber,proto3" json:"phone_number,omitempty"`
+	OtherPhoneNumber string `protobuf:"bytes,2,opt,name=other_phone_number,json=otherPhoneNumber,proto3" json:"other_phone_number,omitempty"`
+	// Defaults to 50, and at most 100.
+	PageSize int32 `protobuf:"varint,3,opt,name=page_size,json=pageSize,proto3" json:"page_size,omitempty"`
+	// The "next_cursor" of the previous page.
+	Cursor               string   `protobuf:"bytes,4,opt,name=cursor,proto3" json:"cursor,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *GetThreadRequest) Reset()         { *m = GetThreadRequest{} }
+func (m *GetThreadRequest) String() string { return proto.CompactTextString(m) }
+func (*GetThreadRequest) ProtoMessage()    {}
+func (*GetThreadRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_c8d8bdc537111860, []int{16}
+}
+
+func (m *GetThreadRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_GetThreadRequest.Unmarshal(m, b)
+}
+func (m *GetThreadRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_GetThreadRequest.Marshal(b, m, deterministic)
+}
+func (m *GetThreadRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_GetThreadRequest.Merge(m, src)
+}
+func (m *GetThreadRequest) XXX_Size() int {
+	return xxx_messageInfo_GetThreadRequest.Size(m)
+}
+func (m *GetThreadRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_GetThreadRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_GetThreadRequest proto.InternalMessageInfo
+
+func (m *GetThreadRequest) GetPhoneNumber() string {
+	if m != nil {
+		return m.PhoneNumber
+	}
+	return ""
+}
+
+func (m *GetThreadRequest) GetOtherPhoneNumber() string {
+	if m != nil {
+		return m.OtherPhoneNumber
+	}
+	return ""
+}
+
+func (m *GetThreadRequest) GetPageSize() int32 {
+	if m != nil {
+		return m.PageSize
+	}
+	return 0
+}
+
+func (m *GetThreadRequest) GetCursor() string {
+	if m != nil {
+		return m.Cursor
+	}
+	return ""
+}
+
+type GetThreadResponse struct {
+	// The most recent first, in both directions.
+	Messages             []*Message `protobuf:"bytes,1,rep,name=messages,proto3" json:"messages,omitempty"`
+	NextCursor           string     `protobuf:"bytes,2,opt,name=next_cursor,json=nextCursor,proto3" json:"next_cursor,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}   `json:"-"`
+	XXX_unrecognized     []byte     `json:"-"`
+	XXX_sizecache        int32      `json:"-"`
+}
+
+func (m *GetThreadResponse) Reset()         { *m = GetThreadResponse{} }
+func (m *GetThreadResponse) String() string { return proto.CompactTextString(m) }
+func (*GetThreadResponse) ProtoMessage()    {}
+func (*GetThreadResponse) Descriptor() ([]byte, []int) {
+	return fileDescriptor_c8d8bdc537111860, []int{17}
+}
+
+func (m *GetThreadResponse) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_GetThreadResponse.Unmarshal(m, b)
+}
+func (m *GetThreadResponse) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_GetThreadResponse.Marshal(b, m, deterministic)
+}
+func (m *GetThreadResponse) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_GetThreadResponse.Merge(m, src)
+}
+func (m *GetThreadResponse) XXX_Size() int {
+	return xxx_messageInfo_GetThreadResponse.Size(m)
+}
+func (m *GetThreadResponse) XXX_DiscardUnknown() {
+	xxx_messageInfo_GetThreadResponse.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_GetThreadResponse proto.InternalMessageInfo
+
+func (m *GetThreadResponse) GetMessages() []*Message {
+	if m != nil {
+		return m.Messages
+	}
+	return nil
+}
+
+func (m *GetThreadResponse) GetNextCursor() string {
+	if m != nil {
+		return m.NextCursor
+	}
+	return ""
+}
+
+// ---- Subscribe
+type SubscribeRequest struct {
+	PhoneNumber string `protobuf:"bytes,1,opt,name=phone_number,json=phoneNumber,proto3" json:"phone_number,omitempty"`
+	// The "cursor" of the last received event, to resume from after a reconnect.
+	// If empty, only the events from now on are sent.
+	Cursor               string   `protobuf:"bytes,2,opt,name=cursor,proto3" json:"cursor,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *SubscribeRequest) Reset()         { *m = SubscribeRequest{} }
+func (m *SubscribeRequest) String() string { return proto.CompactTextString(m) }
+func (*SubscribeRequest) ProtoMessage()    {}
+func (*SubscribeRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_c8d8bdc537111860, []int{18}
+}
+
+func (m *SubscribeRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_SubscribeRequest.Unmarshal(m, b)
+}
+func (m *SubscribeRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_SubscribeRequest.Marshal(b, m, deterministic)
+}
+func (m *SubscribeRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_SubscribeRequest.Merge(m, src)
+}
+func (m *SubscribeRequest) XXX_Size() int {
+	return xxx_messageInfo_SubscribeRequest.Size(m)
+}
+func (m *SubscribeRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_SubscribeRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_SubscribeRequest proto.InternalMessageInfo
+
+func (m *SubscribeRequest) GetPhoneNumber() string {
+	if m != nil {
+		return m.PhoneNumber
+	}
+	return ""
+}
+
+func (m *SubscribeRequest) GetCursor() string {
+	if m != nil {
+		return m.Cursor
+	}
+	return ""
+}
+
+type SubscribeResponse struct {
+	Type                 SubscribeResponse_Type `protobuf:"varint,1,opt,name=type,proto3,enum=sms.SubscribeResponse_Type" json:"type,omitempty"`
+	Message              *Message               `protobuf:"bytes,2,opt,name=message,proto3" json:"message,omitempty"`
+	Cursor               string                 `protobuf:"bytes,3,opt,name=cursor,proto3" json:"cursor,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}               `json:"-"`
+	XXX_unrecognized     []byte                 `json:"-"`
+	XXX_sizecache        int32                  `json:"-"`
+}
+
+func (m *SubscribeResponse) Reset()         { *m = SubscribeResponse{} }
+func (m *SubscribeResponse) String() string { return proto.CompactTextString(m) }
+func (*SubscribeResponse) ProtoMessage()    {}
+func (*SubscribeResponse) Descriptor() ([]byte, []int) {
+	return fileDescriptor_c8d8bdc537111860, []int{19}
+}
+
+func (m *SubscribeResponse) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_SubscribeResponse.Unmarshal(m, b)
+}
+func (m *SubscribeResponse) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_SubscribeResponse.Marshal(b, m, deterministic)
+}
+func (m *SubscribeResponse) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_SubscribeResponse.Merge(m, src)
+}
+func (m *SubscribeResponse) XXX_Size() int {
+	return xxx_messageInfo_SubscribeResponse.Size(m)
+}
+func (m *SubscribeResponse) XXX_DiscardUnknown() {
+	xxx_messageInfo_SubscribeResponse.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_SubscribeResponse proto.InternalMessageInfo
+
+func (m *SubscribeResponse) GetType() SubscribeResponse_Type {
+	if m != nil {
+		return m.Type
+	}
+	return SubscribeResponse_MESSAGE
+}
+
+func (m *SubscribeResponse) GetMessage() *Message {
+	if m != nil {
+		return m.Message
+	}
+	return nil
+}
+
+func (m *SubscribeResponse) GetCursor() string {
+	if m != nil {
+		return m.Cursor
+	}
+	return ""
+}
+
+// ---- Scheduled
+type ListScheduledRequest struct {
+	// The phone number the messages are from.
+	PhoneNumber string `protobuf:"bytes,1,opt,name=phone_number,json=phoneNumber,proto3" json:"phone_number,omitempty"`
+	// Defaults to 20, and at most 100.
+	PageSize int32 `protobuf:"varint,2,opt,name=page_size,json=pageSize,proto3" json:"page_size,omitempty"`
+	// The "next_cursor" of the previous page.
+	Cursor               string   `protobuf:"bytes,3,opt,name=cursor,proto3" json:"cursor,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *ListScheduledRequest) Reset()         { *m = ListScheduledRequest{} }
+func (m *ListScheduledRequest) String() string { return proto.CompactTextString(m) }
+func (*ListScheduledRequest) ProtoMessage()    {}
+func (*ListScheduledRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_c8d8bdc537111860, []int{20}
+}
+
+func (m *ListScheduledRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_ListScheduledRequest.Unmarshal(m, b)
+}
+func (m *ListScheduledRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_ListScheduledRequest.Marshal(b, m, deterministic)
+}
+func (m *ListScheduledRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_ListScheduledRequest.Merge(m, src)
+}
+func (m *ListScheduledRequest) XXX_Size() int {
+	return xxx_messageInfo_ListScheduledRequest.Size(m)
+}
+func (m *ListScheduledRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_ListScheduledRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_ListScheduledRequest proto.InternalMessageInfo
+
+func (m *ListScheduledRequest) GetPhoneNumber() string {
+	if m != nil {
+		return m.PhoneNumber
+	}
+	return ""
+}
+
+func (m *ListScheduledRequest) GetPageSize() int32 {
+	if m != nil {
+		return m.PageSize
+	}
+	return 0
+}
+
+func (m *ListScheduledRequest) GetCursor() string {
+	if m != nil {
+		return m.Cursor
+	}
+	return ""
+}
+
+type ListScheduledResponse struct {
+	// The soonest to be sent first.
+	Messages             []*Message `protobuf:"bytes,1,rep,name=messages,proto3" json:"messages,omitempty"`
+	NextCursor           string     `protobuf:"bytes,2,opt,name=next_cursor,json=nextCursor,proto3" json:"next_cursor,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}   `json:"-"`
+	XXX_unrecognized     []byte     `json:"-"`
+	XXX_sizecache        int32      `json:"-"`
+}
+
+func (m *ListScheduledResponse) Reset()         { *m = ListScheduledResponse{} }
+func (m *ListScheduledResponse) String() string { return proto.CompactTextString(m) }
+func (*ListScheduledResponse) ProtoMessage()    {}
+func (*ListScheduledResponse) Descriptor() ([]byte, []int) {
+	return fileDescriptor_c8d8bdc537111860, []int{21}
+}
+
+func (m *ListScheduledResponse) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_ListScheduledResponse.Unmarshal(m, b)
+}
+func (m *ListScheduledResponse) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_ListScheduledResponse.Marshal(b, m, deterministic)
+}
+func (m *ListScheduledResponse) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_ListScheduledResponse.Merge(m, src)
+}
+func (m *ListScheduledResponse) XXX_Size() int {
+	return xxx_messageInfo_ListScheduledResponse.Size(m)
+}
+func (m *ListScheduledResponse) XXX_DiscardUnknown() {
+	xxx_messageInfo_ListScheduledResponse.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_ListScheduledResponse proto.InternalMessageInfo
+
+func (m *ListScheduledResponse) GetMessages() []*Message {
+	if m != nil {
+		return m.Messages
+	}
+	return nil
+}
+
+func (m *ListScheduledResponse) GetNextCursor() string {
+	if m != nil {
+		return m.NextCursor
+	}
+	return ""
+}
+
+type CancelScheduledRequest struct {
+	MessageId            string   `protobuf:"bytes,1,opt,name=message_id,json=messageId,proto3" json:"message_id,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *CancelScheduledRequest) Reset()         { *m = CancelScheduledRequest{} }
+func (m *CancelScheduledRequest) String() string { return proto.CompactTextString(m) }
+func (*CancelScheduledRequest) ProtoMessage()    {}
+func (*CancelScheduledRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_c8d8bdc537111860, []int{22}
+}
+
+func (m *CancelScheduledRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_CancelScheduledRequest.Unmarshal(m, b)
+}
+func (m *CancelScheduledRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_CancelScheduledRequest.Marshal(b, m, deterministic)
+}
+func (m *CancelScheduledRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_CancelScheduledRequest.Merge(m, src)
+}
+func (m *CancelScheduledRequest) XXX_Size() int {
+	return xxx_messageInfo_CancelScheduledRequest.Size(m)
+}
+func (m *CancelScheduledRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_CancelScheduledRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_CancelScheduledRequest proto.InternalMessageInfo
+
+func (m *CancelScheduledRequest) GetMessageId() string {
+	if m != nil {
+		return m.MessageId
+	}
+	return ""
+}
+
+type CancelScheduledResponse struct {
+	MessageId            string   `protobuf:"bytes,1,opt,name=message_id,json=messageId,proto3" json:"message_id,omitempty"`
+	Status               Status   `protobuf:"varint,2,opt,name=status,proto3,enum=sms.Status" json:"status,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *CancelScheduledResponse) Reset()         { *m = CancelScheduledResponse{} }
+func (m *CancelScheduledResponse) String() string { return proto.CompactTextString(m) }
+func (*CancelScheduledResponse) ProtoMessage()    {}
+func (*CancelScheduledResponse) Descriptor() ([]byte, []int) {
+	return fileDescriptor_c8d8bdc537111860, []int{23}
+}
+
+func (m *CancelScheduledResponse) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_CancelScheduledResponse.Unmarshal(m, b)
+}
+func (m *CancelScheduledResponse) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_CancelScheduledResponse.Marshal(b, m, deterministic)
+}
+func (m *CancelScheduledResponse) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_CancelScheduledResponse.Merge(m, src)
+}
+func (m *CancelScheduledResponse) XXX_Size() int {
+	return xxx_messageInfo_CancelScheduledResponse.Size(m)
+}
+func (m *CancelScheduledResponse) XXX_DiscardUnknown() {
+	xxx_messageInfo_CancelScheduledResponse.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_CancelScheduledResponse proto.InternalMessageInfo
+
+func (m *CancelScheduledResponse) GetMessageId() string {
+	if m != nil {
+		return m.MessageId
+	}
+	return ""
+}
+
+func (m *CancelScheduledResponse) GetStatus() Status {
+	if m != nil {
+		return m.Status
+	}
+	return Status_QUEUED
+}
+
+// ---- Templates
+type Placeholder struct {
+	Name                 string           `protobuf:"bytes,1,opt,name=name,proto3" json:"name,omitempty"`
+	Type                 Placeholder_Type `protobuf:"varint,2,opt,name=type,proto3,enum=sms.Placeholder_Type" json:"type,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}         `json:"-"`
+	XXX_unrecognized     []byte           `json:"-"`
+	XXX_sizecache        int32            `json:"-"`
+}
+
+func (m *Placeholder) Reset()         { *m = Placeholder{} }
+func (m *Placeholder) String() string { return proto.CompactTextString(m) }
+func (*Placeholder) ProtoMessage()    {}
+func (*Placeholder) Descriptor() ([]byte, []int) {
+	return fileDescriptor_c8d8bdc537111860, []int{24}
+}
+
+func (m *Placeholder) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_Placeholder.Unmarshal(m, b)
+}
+func (m *Placeholder) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_Placeholder.Marshal(b, m, deterministic)
+}
+func (m *Placeholder) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_Placeholder.Merge(m, src)
+}
+func (m *Placeholder) XXX_Size() int {
+	return xxx_messageInfo_Placeholder.Size(m)
+}
+func (m *Placeholder) XXX_DiscardUnknown() {
+	xxx_messageInfo_Placeholder.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_Placeholder proto.InternalMessageInfo
+
+func (m *Placeholder) GetName() string {
+	if m != nil {
+		return m.Name
+	}
+	return ""
+}
+
+func (m *Placeholder) GetType() Placeholder_Type {
+	if m != nil {
+		return m.Type
+	}
+	return Placeholder_STRING
+}
+
+type Template struct {
+	TemplateId string `protobuf:"bytes,1,opt,name=template_id,json=templateId,proto3" json:"template_id,omitempty"`
+	Name       string `protobuf:"bytes,2,opt,name=name,proto3" json:"name,omitempty"`
+	// The content with the placeholders in double braces, i.e. "Hi {{name}}, your order ships on {{date}}".
+	Content      string               `protobuf:"bytes,3,opt,name=content,proto3" json:"content,omitempty"`
+	Placeholders []*Placeholder       `protobuf:"bytes,4,rep,name=placeholders,proto3" json:"placeholders,omitempty"`
+	CreatedAt    *timestamp.Timestamp `protobuf:"bytes,5,opt,name=created_at,json=createdAt,proto3" json:"created_at,omitempty"`
+	UpdatedAt    *timestamp.Timestamp `protobuf:"bytes,6,opt,name=updated_at,json=updatedAt,proto3" json:"updated_at,omitempty"`
+	// The account (user) the template belongs to.
+	UserId               int32    `protobuf:"varint,7,opt,name=user_id,json=userId,proto3" json:"user_id,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *Template) Reset()         { *m = Template{} }
+func (m *Template) String() string { return proto.CompactTextString(m) }
+func (*Template) ProtoMessage()    {}
+func (*Template) Descriptor() ([]byte, []int) {
+	return fileDescriptor_c8d8bdc537111860, []int{25}
+}
+
+func (m *Template) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_Template.Unmarshal(m, b)
+}
+func (m *Template) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_Template.Marshal(b, m, deterministic)
+}
+func (m *Template) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_Template.Merge(m, src)
+}
+func (m *Template) XXX_Size() int {
+	return xxx_messageInfo_Template.Size(m)
+}
+func (m *Template) XXX_DiscardUnknown() {
+	xxx_messageInfo_Template.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_Template proto.InternalMessageInfo
+
+func (m *Template) GetTemplateId() string {
+	if m != nil {
+		return m.TemplateId
+	}
+	return ""
+}
+
+func (m *Template) GetName() string {
+	if m != nil {
+		return m.Name
+	}
+	return ""
+}
+
+func (m *Template) GetContent() string {
+	if m != nil {
+		return m.Content
+	}
+	return ""
+}
+
+func (m *Template) GetPlaceholders() []*Placeholder {
+	if m != nil {
+		return m.Placeholders
+	}
+	return nil
+}
+
+func (m *Template) GetCreatedAt() *timestamp.Timestamp {
+	if m != nil {
+		return m.CreatedAt
+	}
+	return nil
+}
+
+func (m *Template) GetUpdatedAt() *timestamp.Timestamp {
+	if m != nil {
+		return m.UpdatedAt
+	}
+	return nil
+}
+
+func (m *Template) GetUserId() int32 {
+	if m != nil {
+		return m.UserId
+	}
+	return 0
+}
+
+type CreateTemplateRequest struct {
+	// Must be unique among the templates of the account.
+	Name    string `protobuf:"bytes,1,opt,name=name,proto3" json:"name,omitempty"`
+	Content string `protobuf:"bytes,2,opt,name=content,proto3" json:"content,omitempty"`
+	// Every placeholder in the content must be declared, and every declared one must be used.
+	Placeholders         []*Placeholder `protobuf:"bytes,3,rep,name=placeholders,proto3" json:"placeholders,omitempty"`
+	UserId               int32          `protobuf:"varint,4,opt,name=user_id,json=userId,proto3" json:"user_id,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}       `json:"-"`
+	XXX_unrecognized     []byte         `json:"-"`
+	XXX_sizecache        int32          `json:"-"`
+}
+
+func (m *CreateTemplateRequest) Reset()         { *m = CreateTemplateRequest{} }
+func (m *CreateTemplateRequest) String() string { return proto.CompactTextString(m) }
+func (*CreateTemplateRequest) ProtoMessage()    {}
+func (*CreateTemplateRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_c8d8bdc537111860, []int{26}
+}
+
+func (m *CreateTemplateRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_CreateTemplateRequest.Unmarshal(m, b)
+}
+func (m *CreateTemplateRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_CreateTemplateRequest.Marshal(b, m, deterministic)
+}
+func (m *CreateTemplateRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_CreateTemplateRequest.Merge(m, src)
+}
+func (m *CreateTemplateRequest) XXX_Size() int {
+	return xxx_messageInfo_CreateTemplateRequest.Size(m)
+}
+func (m *CreateTemplateRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_CreateTemplateRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_CreateTemplateRequest proto.InternalMessageInfo
+
+func (m *CreateTemplateRequest) GetName() string {
+	if m != nil {
+		return m.Name
+	}
+	return ""
+}
+
+func (m *CreateTemplateRequest) GetContent() string {
+	if m != nil {
+		return m.Content
+	}
+	return ""
+}
+
+func (m *CreateTemplateRequest) GetPlaceholders() []*Placeholder {
+	if m != nil {
+		return m.Placeholders
+	}
+	return nil
+}
+
+func (m *CreateTemplateRequest) GetUserId() int32 {
+	if m != nil {
+		return m.UserId
+	}
+	return 0
+}
+
+type CreateTemplateResponse struct {
+	Template             *Template `protobuf:"bytes,1,opt,name=template,proto3" json:"template,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}  `json:"-"`
+	XXX_unrecognized     []byte    `json:"-"`
+	XXX_sizecache        int32     `json:"-"`
+}
+
+func (m *CreateTemplateResponse) Reset()         { *m = CreateTemplateResponse{} }
+func (m *CreateTemplateResponse) String() string { return proto.CompactTextString(m) }
+func (*CreateTemplateResponse) ProtoMessage()    {}
+func (*CreateTemplateResponse) Descriptor() ([]byte, []int) {
+	return fileDescriptor_c8d8bdc537111860, []int{27}
+}
+
+func (m *CreateTemplateResponse) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_CreateTemplateResponse.Unmarshal(m, b)
+}
+func (m *CreateTemplateResponse) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_CreateTemplateResponse.Marshal(b, m, deterministic)
+}
+func (m *CreateTemplateResponse) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_CreateTemplateResponse.Merge(m, src)
+}
+func (m *CreateTemplateResponse) XXX_Size() int {
+	return xxx_messageInfo_CreateTemplateResponse.Size(m)
+}
+func (m *CreateTemplateResponse) XXX_DiscardUnknown() {
+	xxx_messageInfo_CreateTemplateResponse.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_CreateTemplateResponse proto.InternalMessageInfo
+
+func (m *CreateTemplateResponse) GetTemplate() *Template {
+	if m != nil {
+		return m.Template
+	}
+	return nil
+}
+
+type GetTemplateRequest struct {
+	TemplateId           string   `protobuf:"bytes,1,opt,name=template_id,json=templateId,proto3" json:"template_id,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *GetTemplateRequest) Reset()         { *m = GetTemplateRequest{} }
+func (m *GetTemplateRequest) String() string { return proto.CompactTextString(m) }
+func (*GetTemplateRequest) ProtoMessage()    {}
+func (*GetTemplateRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_c8d8bdc537111860, []int{28}
+}
+
+func (m *GetTemplateRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_GetTemplateRequest.Unmarshal(m, b)
+}
+func (m *GetTemplateRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_GetTemplateRequest.Marshal(b, m, deterministic)
+}
+func (m *GetTemplateRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_GetTemplateRequest.Merge(m, src)
+}
+func (m *GetTemplateRequest) XXX_Size() int {
+	return xxx_messageInfo_GetTemplateRequest.Size(m)
+}
+func (m *GetTemplateRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_GetTemplateRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_GetTemplateRequest proto.InternalMessageInfo
+
+func (m *GetTemplateRequest) GetTemplateId() string {
+	if m != nil {
+		return m.TemplateId
+	}
+	return ""
+}
+
+type GetTemplateResponse struct {
+	Template             *Template `protobuf:"bytes,1,opt,name=template,proto3" json:"template,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}  `json:"-"`
+	XXX_unrecognized     []byte    `json:"-"`
+	XXX_sizecache        int32     `json:"-"`
+}
+
+func (m *GetTemplateResponse) Reset()         { *m = GetTemplateResponse{} }
+func (m *GetTemplateResponse) String() string { return proto.CompactTextString(m) }
+func (*GetTemplateResponse) ProtoMessage()    {}
+func (*GetTemplateResponse) Descriptor() ([]byte, []int) {
+	return fileDescriptor_c8d8bdc537111860, []int{29}
+}
+
+func (m *GetTemplateResponse) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_GetTemplateResponse.Unmarshal(m, b)
+}
+func (m *GetTemplateResponse) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_GetTemplateResponse.Marshal(b, m, deterministic)
+}
+func (m *GetTemplateResponse) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_GetTemplateResponse.Merge(m, src)
+}
+func (m *GetTemplateResponse) XXX_Size() int {
+	return xxx_messageInfo_GetTemplateResponse.Size(m)
+}
+func (m *GetTemplateResponse) XXX_DiscardUnknown() {
+	xxx_messageInfo_GetTemplateResponse.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_GetTemplateResponse proto.InternalMessageInfo
+
+func (m *GetTemplateResponse) GetTemplate() *Template {
+	if m != nil {
+		return m.Template
+	}
+	return nil
+}
+
+type ListTemplatesRequest struct {
+	// Defaults to 20, and at most 100.
+	PageSize int32 `protobuf:"varint,1,opt,name=page_size,json=pageSize,proto3" json:"page_size,omitempty"`
+	// The "next_cursor" of the previous page.
+	Cursor               string   `protobuf:"bytes,2,opt,name=cursor,proto3" json:"cursor,omitempty"`
+	UserId               int32    `protobuf:"varint,3,opt,name=user_id,json=userId,proto3" json:"user_id,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *ListTemplatesRequest) Reset()         { *m = ListTemplatesRequest{} }
+func (m *ListTemplatesRequest) String() string { return proto.CompactTextString(m) }
+func (*ListTemplatesRequest) ProtoMessage()    {}
+func (*ListTemplatesRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_c8d8bdc537111860, []int{30}
+}
+
+func (m *ListTemplatesRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_ListTemplatesRequest.Unmarshal(m, b)
+}
+func (m *ListTemplatesRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_ListTemplatesRequest.Marshal(b, m, deterministic)
+}
+func (m *ListTemplatesRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_ListTemplatesRequest.Merge(m, src)
+}
+func (m *ListTemplatesRequest) XXX_Size() int {
+	return xxx_messageInfo_ListTemplatesRequest.Size(m)
+}
+func (m *ListTemplatesRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_ListTemplatesRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_ListTemplatesRequest proto.InternalMessageInfo
+
+func (m *ListTemplatesRequest) GetPageSize() int32 {
+	if m != nil {
+		return m.PageSize
+	}
+	return 0
+}
+
+func (m *ListTemplatesRequest) GetCursor() string {
+	if m != nil {
+		return m.Cursor
+	}
+	return ""
+}
+
+func (m *ListTemplatesRequest) GetUserId() int32 {
+	if m != nil {
+		return m.UserId
+	}
+	return 0
+}
+
+type ListTemplatesResponse struct {
+	// Ordered by name.
+	Templates            []*Template `protobuf:"bytes,1,rep,name=templates,proto3" json:"templates,omitempty"`
+	NextCursor           string      `protobuf:"bytes,2,opt,name=next_cursor,json=nextCursor,proto3" json:"next_cursor,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}    `json:"-"`
+	XXX_unrecognized     []byte      `json:"-"`
+	XXX_sizecache        int32       `json:"-"`
+}
+
+func (m *ListTemplatesResponse) Reset()         { *m = ListTemplatesResponse{} }
+func (m *ListTemplatesResponse) String() string { return proto.CompactTextString(m) }
+func (*ListTemplatesResponse) ProtoMessage()    {}
+func (*ListTemplatesResponse) Descriptor() ([]byte, []int) {
+	return fileDescriptor_c8d8bdc537111860, []int{31}
+}
+
+func (m *ListTemplatesResponse) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_ListTemplatesResponse.Unmarshal(m, b)
+}
+func (m *ListTemplatesResponse) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_ListTemplatesResponse.Marshal(b, m, deterministic)
+}
+func (m *ListTemplatesResponse) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_ListTemplatesResponse.Merge(m, src)
+}
+func (m *ListTemplatesResponse) XXX_Size() int {
+	return xxx_messageInfo_ListTemplatesResponse.Size(m)
+}
+func (m *ListTemplatesResponse) XXX_DiscardUnknown() {
+	xxx_messageInfo_ListTemplatesResponse.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_ListTemplatesResponse proto.InternalMessageInfo
+
+func (m *ListTemplatesResponse) GetTemplates() []*Template {
+	if m != nil {
+		return m.Templates
+	}
+	return nil
+}
+
+func (m *ListTemplatesResponse) GetNextCursor() string {
+	if m != nil {
+		return m.NextCursor
+	}
+	return ""
+}
+
+type UpdateTemplateRequest struct {
+	TemplateId string `protobuf:"bytes,1,opt,name=template_id,json=templateId,proto3" json:"template_id,omitempty"`
+	// Replace the name, content and placeholders, same as CreateTemplateRequest.
+	Name                 string         `protobuf:"bytes,2,opt,name=name,proto3" json:"name,omitempty"`
+	Content              string         `protobuf:"bytes,3,opt,name=content,proto3" json:"content,omitempty"`
+	Placeholders         []*Placeholder `protobuf:"bytes,4,rep,name=placeholders,proto3" json:"placeholders,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}       `json:"-"`
+	XXX_unrecognized     []byte         `json:"-"`
+	XXX_sizecache        int32          `json:"-"`
+}
+
+func (m *UpdateTemplateRequest) Reset()         { *m = UpdateTemplateRequest{} }
+func (m *UpdateTemplateRequest) String() string { return proto.CompactTextString(m) }
+func (*UpdateTemplateRequest) ProtoMessage()    {}
+func (*UpdateTemplateRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_c8d8bdc537111860, []int{32}
+}
+
+func (m *UpdateTemplateRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_UpdateTemplateRequest.Unmarshal(m, b)
+}
+func (m *UpdateTemplateRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_UpdateTemplateRequest.Marshal(b, m, deterministic)
+}
+func (m *UpdateTemplateRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_UpdateTemplateRequest.Merge(m, src)
+}
+func (m *UpdateTemplateRequest) XXX_Size() int {
+	return xxx_messageInfo_UpdateTemplateRequest.Size(m)
+}
+func (m *UpdateTemplateRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_UpdateTemplateRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_UpdateTemplateRequest proto.InternalMessageInfo
+
+func (m *UpdateTemplateRequest) GetTemplateId() string {
+	if m != nil {
+		return m.TemplateId
+	}
+	return ""
+}
+
+func (m *UpdateTemplateRequest) GetName() string {
+	if m != nil {
+		return m.Name
+	}
+	return ""
+}
+
+func (m *UpdateTemplateRequest) GetContent() string {
+	if m != nil {
+		return m.Content
+	}
+	return ""
+}
+
+func (m *UpdateTemplateRequest) GetPlaceholders() []*Placeholder {
+	if m != nil {
+		return m.Placeholders
+	}
+	return nil
+}
+
+type UpdateTemplateResponse struct {
+	Template             *Template `protobuf:"bytes,1,opt,name=template,proto3" json:"template,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}  `json:"-"`
+	XXX_unrecognized     []byte    `json:"-"`
+	XXX_sizecache        int32     `json:"-"`
+}
+
+func (m *UpdateTemplateResponse) Reset()         { *m = UpdateTemplateResponse{} }
+func (m *UpdateTemplateResponse) String() string { return proto.CompactTextString(m) }
+func (*UpdateTemplateResponse) ProtoMessage()    {}
+func (*UpdateTemplateResponse) Descriptor() ([]byte, []int) {
+	return fileDescriptor_c8d8bdc537111860, []int{33}
+}
+
+func (m *UpdateTemplateResponse) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_UpdateTemplateResponse.Unmarshal(m, b)
+}
+func (m *UpdateTemplateResponse) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_UpdateTemplateResponse.Marshal(b, m, deterministic)
+}
+func (m *UpdateTemplateResponse) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_UpdateTemplateResponse.Merge(m, src)
+}
+func (m *UpdateTemplateResponse) XXX_Size() int {
+	return xxx_messageInfo_UpdateTemplateResponse.Size(m)
+}
+func (m *UpdateTemplateResponse) XXX_DiscardUnknown() {
+	xxx_messageInfo_UpdateTemplateResponse.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_UpdateTemplateResponse proto.InternalMessageInfo
+
+func (m *UpdateTemplateResponse) GetTemplate() *Template {
+	if m != nil {
+		return m.Template
+	}
+	return nil
+}
+
+type DeleteTemplateRequest struct {
+	TemplateId           string   `protobuf:"bytes,1,opt,name=template_id,json=templateId,proto3" json:"template_id,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *DeleteTemplateRequest) Reset()         { *m = DeleteTemplateRequest{} }
+func (m *DeleteTemplateRequest) String() string { return proto.CompactTextString(m) }
+func (*DeleteTemplateRequest) ProtoMessage()    {}
+func (*DeleteTemplateRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_c8d8bdc537111860, []int{34}
+}
+
+func (m *DeleteTemplateRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_DeleteTemplateRequest.Unmarshal(m, b)
+}
+func (m *DeleteTemplateRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_DeleteTemplateRequest.Marshal(b, m, deterministic)
+}
+func (m *DeleteTemplateRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_DeleteTemplateRequest.Merge(m, src)
+}
+func (m *DeleteTemplateRequest) XXX_Size() int {
+	return xxx_messageInfo_DeleteTemplateRequest.Size(m)
+}
+func (m *DeleteTemplateRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_DeleteTemplateRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_DeleteTemplateRequest proto.InternalMessageInfo
+
+func (m *DeleteTemplateRequest) GetTemplateId() string {
+	if m != nil {
+		return m.TemplateId
+	}
+	return ""
+}
+
+type DeleteTemplateResponse struct {
+	Deleted              bool     `protobuf:"varint,1,opt,name=deleted,proto3" json:"deleted,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *DeleteTemplateResponse) Reset()         { *m = DeleteTemplateResponse{} }
+func (m *DeleteTemplateResponse) String() string { return proto.CompactTextString(m) }
+func (*DeleteTemplateResponse) ProtoMessage()    {}
+func (*DeleteTemplateResponse) Descriptor() ([]byte, []int) {
+	return fileDescriptor_c8d8bdc537111860, []int{35}
+}
+
+func (m *DeleteTemplateResponse) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_DeleteTemplateResponse.Unmarshal(m, b)
+}
+func (m *DeleteTemplateResponse) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_DeleteTemplateResponse.Marshal(b, m, deterministic)
+}
+func (m *DeleteTemplateResponse) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_DeleteTemplateResponse.Merge(m, src)
+}
+func (m *DeleteTemplateResponse) XXX_Size() int {
+	return xxx_messageInfo_DeleteTemplateResponse.Size(m)
+}
+func (m *DeleteTemplateResponse) XXX_DiscardUnknown() {
+	xxx_messageInfo_DeleteTemplateResponse.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_DeleteTemplateResponse proto.InternalMessageInfo
+
+func (m *DeleteTemplateResponse) GetDeleted() bool {
+	if m != nil {
+		return m.Deleted
+	}
+	return false
+}
+
+// ---- Webhooks
+type Webhook struct {
+	WebhookId string `protobuf:"bytes,1,opt,name=webhook_id,json=webhookId,proto3" json:"webhook_id,omitempty"`
+	// The account (user) the webhook belongs to.
+	UserId int32  `protobuf:"varint,2,opt,name=user_id,json=userId,proto3" json:"user_id,omitempty"`
+	Url    string `protobuf:"bytes,3,opt,name=url,proto3" json:"url,omitempty"`
+	// The events to send: new messages to, and status changes of messages from, the phone number of the account.
+	EventTypes []SubscribeResponse_Type `protobuf:"varint,4,rep,packed,name=event_types,json=eventTypes,proto3,enum=sms.SubscribeResponse_Type" json:"event_types,omitempty"`
+	// The secret the payloads are signed with. Only returned when the webhook is created.
+	Secret               string               `protobuf:"bytes,5,opt,name=secret,proto3" json:"secret,omitempty"`
+	CreatedAt            *timestamp.Timestamp `protobuf:"bytes,6,opt,name=created_at,json=createdAt,proto3" json:"created_at,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}             `json:"-"`
+	XXX_unrecognized     []byte               `json:"-"`
+	XXX_sizecache        int32                `json:"-"`
+}
+
+func (m *Webhook) Reset()         { *m = Webhook{} }
+func (m *Webhook) String() string { return proto.CompactTextString(m) }
+func (*Webhook) ProtoMessage()    {}
+func (*Webhook) Descriptor() ([]byte, []int) {
+	return fileDescriptor_c8d8bdc537111860, []int{36}
+}
+
+func (m *Webhook) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_Webhook.Unmarshal(m, b)
+}
+func (m *Webhook) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_Webhook.Marshal(b, m, deterministic)
+}
+func (m *Webhook) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_Webhook.Merge(m, src)
+}
+func (m *Webhook) XXX_Size() int {
+	return xxx_messageInfo_Webhook.Size(m)
+}
+func (m *Webhook) XXX_DiscardUnknown() {
+	xxx_messageInfo_Webhook.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_Webhook proto.InternalMessageInfo
+
+func (m *Webhook) GetWebhookId() string {
+	if m != nil {
+		return m.WebhookId
+	}
+	return ""
+}
+
+func (m *Webhook) GetUserId() int32 {
+	if m != nil {
+		return m.UserId
+	}
+	return 0
+}
+
+func (m *Webhook) GetUrl() string {
+	if m != nil {
+		return m.Url
+	}
+	return ""
+}
+
+func (m *Webhook) GetEventTypes() []SubscribeResponse_Type {
+	if m != nil {
+		return m.EventTypes
+	}
+	return nil
+}
+
+func (m *Webhook) GetSecret() string {
+	if m != nil {
+		return m.Secret
+	}
+	return ""
+}
+
+func (m *Webhook) GetCreatedAt() *timestamp.Timestamp {
+	if m != nil {
+		return m.CreatedAt
+	}
+	return nil
+}
+
+// WebhookPayload is the body of the POST request to the url of a webhook.
+// It is signed by HMAC-SHA256 with the secret of the webhook, see "X-Textnow-Signature" header.
+type WebhookPayload struct {
+	// Unique for every event, the same on every retry of it.
+	DeliveryId           string                 `protobuf:"bytes,1,opt,name=delivery_id,json=deliveryId,proto3" json:"delivery_id,omitempty"`
+	Type                 SubscribeResponse_Type `protobuf:"varint,2,opt,name=type,proto3,enum=sms.SubscribeResponse_Type" json:"type,omitempty"`
+	PhoneNumber          string                 `protobuf:"bytes,3,opt,name=phone_number,json=phoneNumber,proto3" json:"phone_number,omitempty"`
+	Message              *Message               `protobuf:"bytes,4,opt,name=message,proto3" json:"message,omitempty"`
+	CreatedAt            *timestamp.Timestamp   `protobuf:"bytes,5,opt,name=created_at,json=createdAt,proto3" json:"created_at,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}               `json:"-"`
+	XXX_unrecognized     []byte                 `json:"-"`
+	XXX_sizecache        int32                  `json:"-"`
+}
+
+func (m *WebhookPayload) Reset()         { *m = WebhookPayload{} }
+func (m *WebhookPayload) String() string { return proto.CompactTextString(m) }
+func (*WebhookPayload) ProtoMessage()    {}
+func (*WebhookPayload) Descriptor() ([]byte, []int) {
+	return fileDescriptor_c8d8bdc537111860, []int{37}
+}
+
+func (m *WebhookPayload) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_WebhookPayload.Unmarshal(m, b)
+}
+func (m *WebhookPayload) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_WebhookPayload.Marshal(b, m, deterministic)
+}
+func (m *WebhookPayload) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_WebhookPayload.Merge(m, src)
+}
+func (m *WebhookPayload) XXX_Size() int {
+	return xxx_messageInfo_WebhookPayload.Size(m)
+}
+func (m *WebhookPayload) XXX_DiscardUnknown() {
+	xxx_messageInfo_WebhookPayload.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_WebhookPayload proto.InternalMessageInfo
+
+func (m *WebhookPayload) GetDeliveryId() string {
+	if m != nil {
+		return m.DeliveryId
+	}
+	return ""
+}
+
+func (m *WebhookPayload) GetType() SubscribeResponse_Type {
+	if m != nil {
+		return m.Type
+	}
+	return SubscribeResponse_MESSAGE
+}
+
+func (m *WebhookPayload) GetPhoneNumber() string {
+	if m != nil {
+		return m.PhoneNumber
+	}
+	return ""
+}
+
+func (m *WebhookPayload) GetMessage() *Message {
+	if m != nil {
+		return m.Message
+	}
+	return nil
+}
+
+func (m *WebhookPayload) GetCreatedAt() *timestamp.Timestamp {
+	if m != nil {
+		return m.CreatedAt
+	}
+	return nil
+}
+
+type CreateWebhookRequest struct {
+	UserId               int32                    `protobuf:"varint,1,opt,name=user_id,json=userId,proto3" json:"user_id,omitempty"`
+	Url                  string                   `protobuf:"bytes,2,opt,name=url,proto3" json:"url,omitempty"`
+	EventTypes           []SubscribeResponse_Type `protobuf:"varint,3,rep,packed,name=event_types,json=eventTypes,proto3,enum=sms.SubscribeResponse_Type" json:"event_types,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}                 `json:"-"`
+	XXX_unrecognized     []byte                   `json:"-"`
+	XXX_sizecache        int32                    `json:"-"`
+}
+
+func (m *CreateWebhookRequest) Reset()         { *m = CreateWebhookRequest{} }
+func (m *CreateWebhookRequest) String() string { return proto.CompactTextString(m) }
+func (*CreateWebhookRequest) ProtoMessage()    {}
+func (*CreateWebhookRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_c8d8bdc537111860, []int{38}
+}
+
+func (m *CreateWebhookRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_CreateWebhookRequest.Unmarshal(m, b)
+}
+func (m *CreateWebhookRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_CreateWebhookRequest.Marshal(b, m, deterministic)
+}
+func (m *CreateWebhookRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_CreateWebhookRequest.Merge(m, src)
+}
+func (m *CreateWebhookRequest) XXX_Size() int {
+	return xxx_messageInfo_CreateWebhookRequest.Size(m)
+}
+func (m *CreateWebhookRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_CreateWebhookRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_CreateWebhookRequest proto.InternalMessageInfo
+
+func (m *CreateWebhookRequest) GetUserId() int32 {
+	if m != nil {
+		return m.UserId
+	}
+	return 0
+}
+
+func (m *CreateWebhookRequest) GetUrl() string {
+	if m != nil {
+		return m.Url
+	}
+	return ""
+}
+
+func (m *CreateWebhookRequest) GetEventTypes() []SubscribeResponse_Type {
+	if m != nil {
+		return m.EventTypes
+	}
+	return nil
+}
+
+type CreateWebhookResponse struct {
+	Webhook              *Webhook `protobuf:"bytes,1,opt,name=webhook,proto3" json:"webhook,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *CreateWebhookResponse) Reset()         { *m = CreateWebhookResponse{} }
+func (m *CreateWebhookResponse) String() string { return proto.CompactTextString(m) }
+func (*CreateWebhookResponse) ProtoMessage()    {}
+func (*CreateWebhookResponse) Descriptor() ([]byte, []int) {
+	return fileDescriptor_c8d8bdc537111860, []int{39}
+}
+
+func (m *CreateWebhookResponse) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_CreateWebhookResponse.Unmarshal(m, b)
+}
+func (m *CreateWebhookResponse) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_CreateWebhookResponse.Marshal(b, m, deterministic)
+}
+func (m *CreateWebhookResponse) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_CreateWebhookResponse.Merge(m, src)
+}
+func (m *CreateWebhookResponse) XXX_Size() int {
+	return xxx_messageInfo_CreateWebhookResponse.Size(m)
+}
+func (m *CreateWebhookResponse) XXX_DiscardUnknown() {
+	xxx_messageInfo_CreateWebhookResponse.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_CreateWebhookResponse proto.InternalMessageInfo
+
+func (m *CreateWebhookResponse) GetWebhook() *Webhook {
+	if m != nil {
+		return m.Webhook
+	}
+	return nil
+}
+
+type ListWebhooksRequest struct {
+	UserId               int32    `protobuf:"varint,1,opt,name=user_id,json=userId,proto3" json:"user_id,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *ListWebhooksRequest) Reset()         { *m = ListWebhooksRequest{} }
+func (m *ListWebhooksRequest) String() string { return proto.CompactTextString(m) }
+func (*ListWebhooksRequest) ProtoMessage()    {}
+func (*ListWebhooksRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_c8d8bdc537111860, []int{40}
+}
+
+func (m *ListWebhooksRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_ListWebhooksRequest.Unmarshal(m, b)
+}
+func (m *ListWebhooksRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_ListWebhooksRequest.Marshal(b, m, deterministic)
+}
+func (m *ListWebhooksRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_ListWebhooksRequest.Merge(m, src)
+}
+func (m *ListWebhooksRequest) XXX_Size() int {
+	return xxx_messageInfo_ListWebhooksRequest.Size(m)
+}
+func (m *ListWebhooksRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_ListWebhooksRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_ListWebhooksRequest proto.InternalMessageInfo
+
+func (m *ListWebhooksRequest) GetUserId() int32 {
+	if m != nil {
+		return m.UserId
+	}
+	return 0
+}
+
+type ListWebhooksResponse struct {
+	Webhooks             []*Webhook `protobuf:"bytes,1,rep,name=webhooks,proto3" json:"webhooks,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}   `json:"-"`
+	XXX_unrecognized     []byte     `json:"-"`
+	XXX_sizecache        int32      `json:"-"`
+}
+
+func (m *ListWebhooksResponse) Reset()         { *m = ListWebhooksResponse{} }
+func (m *ListWebhooksResponse) String() string { return proto.CompactTextString(m) }
+func (*ListWebhooksResponse) ProtoMessage()    {}
+func (*ListWebhooksResponse) Descriptor() ([]byte, []int) {
+	return fileDescriptor_c8d8bdc537111860, []int{41}
+}
+
+func (m *ListWebhooksResponse) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_ListWebhooksResponse.Unmarshal(m, b)
+}
+func (m *ListWebhooksResponse) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_ListWebhooksResponse.Marshal(b, m, deterministic)
+}
+func (m *ListWebhooksResponse) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_ListWebhooksResponse.Merge(m, src)
+}
+func (m *ListWebhooksResponse) XXX_Size() int {
+	return xxx_messageInfo_ListWebhooksResponse.Size(m)
+}
+func (m *ListWebhooksResponse) XXX_DiscardUnknown() {
+	xxx_messageInfo_ListWebhooksResponse.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_ListWebhooksResponse proto.InternalMessageInfo
+
+func (m *ListWebhooksResponse) GetWebhooks() []*Webhook {
+	if m != nil {
+		return m.Webhooks
+	}
+	return nil
+}
+
+type DeleteWebhookRequest struct {
+	WebhookId            string   `protobuf:"bytes,1,opt,name=webhook_id,json=webhookId,proto3" json:"webhook_id,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *DeleteWebhookRequest) Reset()         { *m = DeleteWebhookRequest{} }
+func (m *DeleteWebhookRequest) String() string { return proto.CompactTextString(m) }
+func (*DeleteWebhookRequest) ProtoMessage()    {}
+func (*DeleteWebhookRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_c8d8bdc537111860, []int{42}
+}
+
+func (m *DeleteWebhookRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_DeleteWebhookRequest.Unmarshal(m, b)
+}
+func (m *DeleteWebhookRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_DeleteWebhookRequest.Marshal(b, m, deterministic)
+}
+func (m *DeleteWebhookRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_DeleteWebhookRequest.Merge(m, src)
+}
+func (m *DeleteWebhookRequest) XXX_Size() int {
+	return xxx_messageInfo_DeleteWebhookRequest.Size(m)
+}
+func (m *DeleteWebhookRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_DeleteWebhookRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_DeleteWebhookRequest proto.InternalMessageInfo
+
+func (m *DeleteWebhookRequest) GetWebhookId() string {
+	if m != nil {
+		return m.WebhookId
+	}
+	return ""
+}
+
+type DeleteWebhookResponse struct {
+	Deleted              bool     `protobuf:"varint,1,opt,name=deleted,proto3" json:"deleted,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *DeleteWebhookResponse) Reset()         { *m = DeleteWebhookResponse{} }
+func (m *DeleteWebhookResponse) String() string { return proto.CompactTextString(m) }
+func (*DeleteWebhookResponse) ProtoMessage()    {}
+func (*DeleteWebhookResponse) Descriptor() ([]byte, []int) {
+	return fileDescriptor_c8d8bdc537111860, []int{43}
+}
+
+func (m *DeleteWebhookResponse) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_DeleteWebhookResponse.Unmarshal(m, b)
+}
+func (m *DeleteWebhookResponse) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_DeleteWebhookResponse.Marshal(b, m, deterministic)
+}
+func (m *DeleteWebhookResponse) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_DeleteWebhookResponse.Merge(m, src)
+}
+func (m *DeleteWebhookResponse) XXX_Size() int {
+	return xxx_messageInfo_DeleteWebhookResponse.Size(m)
+}
+func (m *DeleteWebhookResponse) XXX_DiscardUnknown() {
+	xxx_messageInfo_DeleteWebhookResponse.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_DeleteWebhookResponse proto.InternalMessageInfo
+
+func (m *DeleteWebhookResponse) GetDeleted() bool {
+	if m != nil {
+		return m.Deleted
+	}
+	return false
+}
+
+type ReplayDeliveriesRequest struct {
+	WebhookId string `protobuf:"bytes,1,opt,name=webhook_id,json=webhookId,proto3" json:"webhook_id,omitempty"`
+	// Replay only this delivery. If empty, all the failed deliveries of the webhook are replayed.
+	DeliveryId           string   `protobuf:"bytes,2,opt,name=delivery_id,json=deliveryId,proto3" json:"delivery_id,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *ReplayDeliveriesRequest) Reset()         { *m = ReplayDeliveriesRequest{} }
+func (m *ReplayDeliveriesRequest) String() string { return proto.CompactTextString(m) }
+func (*ReplayDeliveriesRequest) ProtoMessage()    {}
+func (*ReplayDeliveriesRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_c8d8bdc537111860, []int{44}
+}
+
+func (m *ReplayDeliveriesRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_ReplayDeliveriesRequest.Unmarshal(m, b)
+}
+func (m *ReplayDeliveriesRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_ReplayDeliveriesRequest.Marshal(b, m, deterministic)
+}
+func (m *ReplayDeliveriesRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_ReplayDeliveriesRequest.Merge(m, src)
+}
+func (m *ReplayDeliveriesRequest) XXX_Size() int {
+	return xxx_messageInfo_ReplayDeliveriesRequest.Size(m)
+}
+func (m *ReplayDeliveriesRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_ReplayDeliveriesRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_ReplayDeliveriesRequest proto.InternalMessageInfo
+
+func (m *ReplayDeliveriesRequest) GetWebhookId() string {
+	if m != nil {
+		return m.WebhookId
+	}
+	return ""
+}
+
+func (m *ReplayDeliveriesRequest) GetDeliveryId() string {
+	if m != nil {
+		return m.DeliveryId
+	}
+	return ""
+}
+
+type ReplayDeliveriesResponse struct {
+	// The number of failed deliveries that are queued to be sent again.
+	Replayed             int32    `protobuf:"varint,1,opt,name=replayed,proto3" json:"replayed,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *ReplayDeliveriesResponse) Reset()         { *m = ReplayDeliveriesResponse{} }
+func (m *ReplayDeliveriesResponse) String() string { return proto.CompactTextString(m) }
+func (*ReplayDeliveriesResponse) ProtoMessage()    {}
+func (*ReplayDeliveriesResponse) Descriptor() ([]byte, []int) {
+	return fileDescriptor_c8d8bdc537111860, []int{45}
+}
+
+func (m *ReplayDeliveriesResponse) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_ReplayDeliveriesResponse.Unmarshal(m, b)
+}
+func (m *ReplayDeliveriesResponse) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_ReplayDeliveriesResponse.Marshal(b, m, deterministic)
+}
+func (m *ReplayDeliveriesResponse) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_ReplayDeliveriesResponse.Merge(m, src)
+}
+func (m *ReplayDeliveriesResponse) XXX_Size() int {
+	return xxx_messageInfo_ReplayDeliveriesResponse.Size(m)
+}
+func (m *ReplayDeliveriesResponse) XXX_DiscardUnknown() {
+	xxx_messageInfo_ReplayDeliveriesResponse.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_ReplayDeliveriesResponse proto.InternalMessageInfo
+
+func (m *ReplayDeliveriesResponse) GetReplayed() int32 {
+	if m != nil {
+		return m.Replayed
+	}
+	return 0
+}
+
+// ---- Quiet hours
+type QuietHours struct {
+	// The account (user) the quiet hours belong to.
+	UserId int32 `protobuf:"varint,1,opt,name=user_id,json=userId,proto3" json:"user_id,omitempty"`
+	// The start and end of the quiet hours, "HH:MM" in the local time of the recipient.
+	// Quiet hours that end before they start (i.e. "21:00" to "08:00") span midnight.
+	Start string `protobuf:"bytes,2,opt,name=start,proto3" json:"start,omitempty"`
+	End   string `protobuf:"bytes,3,opt,name=end,proto3" json:"end,omitempty"`
+	// The time zone of the recipients whose time zone can't be told by their phone number, i.e. "America/Toronto".
+	// If empty, the SMSs to them are not held.
+	DefaultTimeZone      string               `protobuf:"bytes,4,opt,name=default_time_zone,json=defaultTimeZone,proto3" json:"default_time_zone,omitempty"`
+	UpdatedAt            *timestamp.Timestamp `protobuf:"bytes,5,opt,name=updated_at,json=updatedAt,proto3" json:"updated_at,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}             `json:"-"`
+	XXX_unrecognized     []byte               `json:"-"`
+	XXX_sizecache        int32                `json:"-"`
+}
+
+func (m *QuietHours) Reset()         { *m = QuietHours{} }
+func (m *QuietHours) String() string { return proto.CompactTextString(m) }
+func (*QuietHours) ProtoMessage()    {}
+func (*QuietHours) Descriptor() ([]byte, []int) {
+	return fileDescriptor_c8d8bdc537111860, []int{46}
+}
+
+func (m *QuietHours) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_QuietHours.Unmarshal(m, b)
+}
+func (m *QuietHours) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_QuietHours.Marshal(b, m, deterministic)
+}
+func (m *QuietHours) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_QuietHours.Merge(m, src)
+}
+func (m *QuietHours) XXX_Size() int {
+	return xxx_messageInfo_QuietHours.Size(m)
+}
+func (m *QuietHours) XXX_DiscardUnknown() {
+	xxx_messageInfo_QuietHours.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_QuietHours proto.InternalMessageInfo
+
+func (m *QuietHours) GetUserId() int32 {
+	if m != nil {
+		return m.UserId
+	}
+	return 0
+}
+
+func (m *QuietHours) GetStart() string {
+	if m != nil {
+		return m.Start
+	}
+	return ""
+}
+
+func (m *QuietHours) GetEnd() string {
+	if m != nil {
+		return m.End
+	}
+	return ""
+}
+
+func (m *QuietHours) GetDefaultTimeZone() string {
+	if m != nil {
+		return m.DefaultTimeZone
+	}
+	return ""
+}
+
+func (m *QuietHours) GetUpdatedAt() *timestamp.Timestamp {
+	if m != nil {
+		return m.UpdatedAt
+	}
+	return nil
+}
+
+type SetQuietHoursRequest struct {
+	UserId               int32    `protobuf:"varint,1,opt,name=user_id,json=userId,proto3" json:"user_id,omitempty"`
+	Start                string   `protobuf:"bytes,2,opt,name=start,proto3" json:"start,omitempty"`
+	End                  string   `protobuf:"bytes,3,opt,name=end,proto3" json:"end,omitempty"`
+	DefaultTimeZone      string   `protobuf:"bytes,4,opt,name=default_time_zone,json=defaultTimeZone,proto3" json:"default_time_zone,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *SetQuietHoursRequest) Reset()         { *m = SetQuietHoursRequest{} }
+func (m *SetQuietHoursRequest) String() string { return proto.CompactTextString(m) }
+func (*SetQuietHoursRequest) ProtoMessage()    {}
+func (*SetQuietHoursRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_c8d8bdc537111860, []int{47}
+}
+
+func (m *SetQuietHoursRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_SetQuietHoursRequest.Unmarshal(m, b)
+}
+func (m *SetQuietHoursRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_SetQuietHoursRequest.Marshal(b, m, deterministic)
+}
+func (m *SetQuietHoursRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_SetQuietHoursRequest.Merge(m, src)
+}
+func (m *SetQuietHoursRequest) XXX_Size() int {
+	return xxx_messageInfo_SetQuietHoursRequest.Size(m)
+}
+func (m *SetQuietHoursRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_SetQuietHoursRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_SetQuietHoursRequest proto.InternalMessageInfo
+
+func (m *SetQuietHoursRequest) GetUserId() int32 {
+	if m != nil {
+		return m.UserId
+	}
+	return 0
+}
+
+func (m *SetQuietHoursRequest) GetStart() string {
+	if m != nil {
+		return m.Start
+	}
+	return ""
+}
+
+func (m *SetQuietHoursRequest) GetEnd() string {
+	if m != nil {
+		return m.End
+	}
+	return ""
+}
+
+func (m *SetQuietHoursRequest) GetDefaultTimeZone() string {
+	if m != nil {
+		return m.DefaultTimeZone
+	}
+	return ""
+}
+
+type SetQuietHoursResponse struct {
+	QuietHours           *QuietHours `protobuf:"bytes,1,opt,name=quiet_hours,json=quietHours,proto3" json:"quiet_hours,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}    `json:"-"`
+	XXX_unrecognized     []byte      `json:"-"`
+	XXX_sizecache        int32       `json:"-"`
+}
+
+func (m *SetQuietHoursResponse) Reset()         { *m = SetQuietHoursResponse{} }
+func (m *SetQuietHoursResponse) String() string { return proto.CompactTextString(m) }
+func (*SetQuietHoursResponse) ProtoMessage()    {}
+func (*SetQuietHoursResponse) Descriptor() ([]byte, []int) {
+	return fileDescriptor_c8d8bdc537111860, []int{48}
+}
+
+func (m *SetQuietHoursResponse) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_SetQuietHoursResponse.Unmarshal(m, b)
+}
+func (m *SetQuietHoursResponse) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_SetQuietHoursResponse.Marshal(b, m, deterministic)
+}
+func (m *SetQuietHoursResponse) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_SetQuietHoursResponse.Merge(m, src)
+}
+func (m *SetQuietHoursResponse) XXX_Size() int {
+	return xxx_messageInfo_SetQuietHoursResponse.Size(m)
+}
+func (m *SetQuietHoursResponse) XXX_DiscardUnknown() {
+	xxx_messageInfo_SetQuietHoursResponse.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_SetQuietHoursResponse proto.InternalMessageInfo
+
+func (m *SetQuietHoursResponse) GetQuietHours() *QuietHours {
+	if m != nil {
+		return m.QuietHours
+	}
+	return nil
+}
+
+type GetQuietHoursRequest struct {
+	UserId               int32    `protobuf:"varint,1,opt,name=user_id,json=userId,proto3" json:"user_id,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *GetQuietHoursRequest) Reset()         { *m = GetQuietHoursRequest{} }
+func (m *GetQuietHoursRequest) String() string { return proto.CompactTextString(m) }
+func (*GetQuietHoursRequest) ProtoMessage()    {}
+func (*GetQuietHoursRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_c8d8bdc537111860, []int{49}
+}
+
+func (m *GetQuietHoursRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_GetQuietHoursRequest.Unmarshal(m, b)
+}
+func (m *GetQuietHoursRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_GetQuietHoursRequest.Marshal(b, m, deterministic)
+}
+func (m *GetQuietHoursRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_GetQuietHoursRequest.Merge(m, src)
+}
+func (m *GetQuietHoursRequest) XXX_Size() int {
+	return xxx_messageInfo_GetQuietHoursRequest.Size(m)
+}
+func (m *GetQuietHoursRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_GetQuietHoursRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_GetQuietHoursRequest proto.InternalMessageInfo
+
+func (m *GetQuietHoursRequest) GetUserId() int32 {
+	if m != nil {
+		return m.UserId
+	}
+	return 0
+}
+
+type GetQuietHoursResponse struct {
+	QuietHours           *QuietHours `protobuf:"bytes,1,opt,name=quiet_hours,json=quietHours,proto3" json:"quiet_hours,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}    `json:"-"`
+	XXX_unrecognized     []byte      `json:"-"`
+	XXX_sizecache        int32       `json:"-"`
+}
+
+func (m *GetQuietHoursResponse) Reset()         { *m = GetQuietHoursResponse{} }
+func (m *GetQuietHoursResponse) String() string { return proto.CompactTextString(m) }
+func (*GetQuietHoursResponse) ProtoMessage()    {}
+func (*GetQuietHoursResponse) Descriptor() ([]byte, []int) {
+	return fileDescriptor_c8d8bdc537111860, []int{50}
+}
+
+func (m *GetQuietHoursResponse) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_GetQuietHoursResponse.Unmarshal(m, b)
+}
+func (m *GetQuietHoursResponse) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_GetQuietHoursResponse.Marshal(b, m, deterministic)
+}
+func (m *GetQuietHoursResponse) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_GetQuietHoursResponse.Merge(m, src)
+}
+func (m *GetQuietHoursResponse) XXX_Size() int {
+	return xxx_messageInfo_GetQuietHoursResponse.Size(m)
+}
+func (m *GetQuietHoursResponse) XXX_DiscardUnknown() {
+	xxx_messageInfo_GetQuietHoursResponse.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_GetQuietHoursResponse proto.InternalMessageInfo
+
+func (m *GetQuietHoursResponse) GetQuietHours() *QuietHours {
+	if m != nil {
+		return m.QuietHours
+	}
+	return nil
+}
+
+type DeleteQuietHoursRequest struct {
+	UserId               int32    `protobuf:"varint,1,opt,name=user_id,json=userId,proto3" json:"user_id,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *DeleteQuietHoursRequest) Reset()         { *m = DeleteQuietHoursRequest{} }
+func (m *DeleteQuietHoursRequest) String() string { return proto.CompactTextString(m) }
+func (*DeleteQuietHoursRequest) ProtoMessage()    {}
+func (*DeleteQuietHoursRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_c8d8bdc537111860, []int{51}
+}
+
+func (m *DeleteQuietHoursRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_DeleteQuietHoursRequest.Unmarshal(m, b)
+}
+func (m *DeleteQuietHoursRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_DeleteQuietHoursRequest.Marshal(b, m, deterministic)
+}
+func (m *DeleteQuietHoursRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_DeleteQuietHoursRequest.Merge(m, src)
+}
+func (m *DeleteQuietHoursRequest) XXX_Size() int {
+	return xxx_messageInfo_DeleteQuietHoursRequest.Size(m)
+}
+func (m *DeleteQuietHoursRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_DeleteQuietHoursRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_DeleteQuietHoursRequest proto.InternalMessageInfo
+
+func (m *DeleteQuietHoursRequest) GetUserId() int32 {
+	if m != nil {
+		return m.UserId
+	}
+	return 0
+}
+
+type DeleteQuietHoursResponse struct {
+	Deleted              bool     `protobuf:"varint,1,opt,name=deleted,proto3" json:"deleted,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *DeleteQuietHoursResponse) Reset()         { *m = DeleteQuietHoursResponse{} }
+func (m *DeleteQuietHoursResponse) String() string { return proto.CompactTextString(m) }
+func (*DeleteQuietHoursResponse) ProtoMessage()    {}
+func (*DeleteQuietHoursResponse) Descriptor() ([]byte, []int) {
+	return fileDescriptor_c8d8bdc537111860, []int{52}
+}
+
+func (m *DeleteQuietHoursResponse) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_DeleteQuietHoursResponse.Unmarshal(m, b)
+}
+func (m *DeleteQuietHoursResponse) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_DeleteQuietHoursResponse.Marshal(b, m, deterministic)
+}
+func (m *DeleteQuietHoursResponse) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_DeleteQuietHoursResponse.Merge(m, src)
+}
+func (m *DeleteQuietHoursResponse) XXX_Size() int {
+	return xxx_messageInfo_DeleteQuietHoursResponse.Size(m)
+}
+func (m *DeleteQuietHoursResponse) XXX_DiscardUnknown() {
+	xxx_messageInfo_DeleteQuietHoursResponse.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_DeleteQuietHoursResponse proto.InternalMessageInfo
+
+func (m *DeleteQuietHoursResponse) GetDeleted() bool {
+	if m != nil {
+		return m.Deleted
+	}
+	return false
+}
+
+// ---- Inbound
+type ReceiveRequest struct {
+	// The phone numbers are normalized to E.164, i.e. "(613) 555-0172" to "+16135550172".
+	FromPhoneNumber string `protobuf:"bytes,1,opt,name=from_phone_number,json=fromPhoneNumber,proto3" json:"from_phone_number,omitempty"`
+	ToPhoneNumber   string `protobuf:"bytes,2,opt,name=to_phone_number,json=toPhoneNumber,proto3" json:"to_phone_number,omitempty"`
+	Content         string `protobuf:"bytes,3,opt,name=content,proto3" json:"content,omitempty"`
+	// The carrier the message was received by, and the id it knows the message by, if any.
+	// A message of the same carrier and carrier message id is only stored once.
+	Carrier              string   `protobuf:"bytes,4,opt,name=carrier,proto3" json:"carrier,omitempty"`
+	CarrierMessageId     string   `protobuf:"bytes,5,opt,name=carrier_message_id,json=carrierMessageId,proto3" json:"carrier_message_id,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *ReceiveRequest) Reset()         { *m = ReceiveRequest{} }
+func (m *ReceiveRequest) String() string { return proto.CompactTextString(m) }
+func (*ReceiveRequest) ProtoMessage()    {}
+func (*ReceiveRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_c8d8bdc537111860, []int{53}
+}
+
+func (m *ReceiveRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_ReceiveRequest.Unmarshal(m, b)
+}
+func (m *ReceiveRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_ReceiveRequest.Marshal(b, m, deterministic)
+}
+func (m *ReceiveRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_ReceiveRequest.Merge(m, src)
+}
+func (m *ReceiveRequest) XXX_Size() int {
+	return xxx_messageInfo_ReceiveRequest.Size(m)
+}
+func (m *ReceiveRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_ReceiveRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_ReceiveRequest proto.InternalMessageInfo
+
+func (m *ReceiveRequest) GetFromPhoneNumber() string {
+	if m != nil {
+		return m.FromPhoneNumber
+	}
+	return ""
+}
+
+func (m *ReceiveRequest) GetToPhoneNumber() string {
+	if m != nil {
+		return m.ToPhoneNumber
+	}
+	return ""
+}
+
+func (m *ReceiveRequest) GetContent() string {
+	if m != nil {
+		return m.Content
+	}
+	return ""
+}
+
+func (m *ReceiveRequest) GetCarrier() string {
+	if m != nil {
+		return m.Carrier
+	}
+	return ""
+}
+
+func (m *ReceiveRequest) GetCarrierMessageId() string {
+	if m != nil {
+		return m.CarrierMessageId
+	}
+	return ""
+}
+
+type ReceiveResponse struct {
+	MessageId string `protobuf:"bytes,1,opt,name=message_id,json=messageId,proto3" json:"message_id,omitempty"`
+	// The message has been received already, and so it is the id of the one stored before.
+	Duplicate            bool     `protobuf:"varint,2,opt,name=duplicate,proto3" json:"duplicate,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *ReceiveResponse) Reset()         { *m = ReceiveResponse{} }
+func (m *ReceiveResponse) String() string { return proto.CompactTextString(m) }
+func (*ReceiveResponse) ProtoMessage()    {}
+func (*ReceiveResponse) Descriptor() ([]byte, []int) {
+	return fileDescriptor_c8d8bdc537111860, []int{54}
+}
+
+func (m *ReceiveResponse) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_ReceiveResponse.Unmarshal(m, b)
+}
+func (m *ReceiveResponse) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_ReceiveResponse.Marshal(b, m, deterministic)
+}
+func (m *ReceiveResponse) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_ReceiveResponse.Merge(m, src)
+}
+func (m *ReceiveResponse) XXX_Size() int {
+	return xxx_messageInfo_ReceiveResponse.Size(m)
+}
+func (m *ReceiveResponse) XXX_DiscardUnknown() {
+	xxx_messageInfo_ReceiveResponse.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_ReceiveResponse proto.InternalMessageInfo
+
+func (m *ReceiveResponse) GetMessageId() string {
+	if m != nil {
+		return m.MessageId
+	}
+	return ""
+}
+
+func (m *ReceiveResponse) GetDuplicate() bool {
+	if m != nil {
+		return m.Duplicate
+	}
+	return false
+}
+
+func init() {
+	proto.RegisterEnum("sms.Priority", Priority_name, Priority_value)
+	proto.RegisterEnum("sms.Status", Status_name, Status_value)
+	proto.RegisterEnum("sms.Encoding", Encoding_name, Encoding_value)
+	proto.RegisterEnum("sms.Direction", Direction_name, Direction_value)
+	proto.RegisterEnum("sms.Route", Route_name, Route_value)
+	proto.RegisterEnum("sms.FilterDecision_Action", FilterDecision_Action_name, FilterDecision_Action_value)
+	proto.RegisterEnum("sms.GetTrackingResponse_State", GetTrackingResponse_State_name, GetTrackingResponse_State_value)
+	proto.RegisterEnum("sms.SubscribeResponse_Type", SubscribeResponse_Type_name, SubscribeResponse_Type_value)
+	proto.RegisterEnum("sms.Placeholder_Type", Placeholder_Type_name, Placeholder_Type_value)
+	proto.RegisterType((*SMS)(nil), "sms.SMS")
+	proto.RegisterMapType((map[string]string)(nil), "sms.SMS.VariablesEntry")
+	proto.RegisterType((*SendOneRequest)(nil), "sms.SendOneRequest")
+	proto.RegisterType((*SendOneResponse)(nil), "sms.SendOneResponse")
+	proto.RegisterType((*Substitution)(nil), "sms.Substitution")
+	proto.RegisterType((*FilterDecision)(nil), "sms.FilterDecision")
+	proto.RegisterType((*SendManyRequest)(nil), "sms.SendManyRequest")
+	proto.RegisterType((*SendManyResponse)(nil), "sms.SendManyResponse")
+	proto.RegisterType((*GetMessageStatusRequest)(nil), "sms.GetMessageStatusRequest")
+	proto.RegisterType((*GetMessageStatusResponse)(nil), "sms.GetMessageStatusResponse")
+	proto.RegisterType((*GetTrackingRequest)(nil), "sms.GetTrackingRequest")
+	proto.RegisterType((*TrackedMessage)(nil), "sms.TrackedMessage")
+	proto.RegisterType((*GetTrackingResponse)(nil), "sms.GetTrackingResponse")
+	proto.RegisterType((*Message)(nil), "sms.Message")
+	proto.RegisterType((*ListConversationsRequest)(nil), "sms.ListConversationsRequest")
+	proto.RegisterType((*Conversation)(nil), "sms.Conversation")
+	proto.RegisterType((*ListConversationsResponse)(nil), "sms.ListConversationsResponse")
+	proto.RegisterType((*GetThreadRequest)(nil), "sms.GetThreadRequest")
+	proto.RegisterType((*GetThreadResponse)(nil), "sms.GetThreadResponse")
+	proto.RegisterType((*SubscribeRequest)(nil), "sms.SubscribeRequest")
+	proto.RegisterType((*SubscribeResponse)(nil), "sms.SubscribeResponse")
+	proto.RegisterType((*ListScheduledRequest)(nil), "sms.ListScheduledRequest")
+	proto.RegisterType((*ListScheduledResponse)(nil), "sms.ListScheduledResponse")
+	proto.RegisterType((*CancelScheduledRequest)(nil), "sms.CancelScheduledRequest")
+	proto.RegisterType((*CancelScheduledResponse)(nil), "sms.CancelScheduledResponse")
+	proto.RegisterType((*Placeholder)(nil), "sms.Placeholder")
+	proto.RegisterType((*Template)(nil), "sms.Template")
+	proto.RegisterType((*CreateTemplateRequest)(nil), "sms.CreateTemplateRequest")
+	proto.RegisterType((*CreateTemplateResponse)(nil), "sms.CreateTemplateResponse")
+	proto.RegisterType((*GetTemplateRequest)(nil), "sms.GetTemplateRequest")
+	proto.RegisterType((*GetTemplateResponse)(nil), "sms.GetTemplateResponse")
+	proto.RegisterType((*ListTemplatesRequest)(nil), "sms.ListTemplatesRequest")
+	proto.RegisterType((*ListTemplatesResponse)(nil), "sms.ListTemplatesResponse")
+	proto.RegisterType((*UpdateTemplateRequest)(nil), "sms.UpdateTemplateRequest")
+	proto.RegisterType((*UpdateTemplateResponse)(nil), "sms.UpdateTemplateResponse")
+	proto.RegisterType((*DeleteTemplateRequest)(nil), "sms.DeleteTemplateRequest")
+	proto.RegisterType((*DeleteTemplateResponse)(nil), "sms.DeleteTemplateResponse")
+	proto.RegisterType((*Webhook)(nil), "sms.Webhook")
+	proto.RegisterType((*WebhookPayload)(nil), "sms.WebhookPayload")
+	proto.RegisterType((*CreateWebhookRequest)(nil), "sms.CreateWebhookRequest")
+	proto.RegisterType((*CreateWebhookResponse)(nil), "sms.CreateWebhookResponse")
+	proto.RegisterType((*ListWebhooksRequest)(nil), "sms.ListWebhooksRequest")
+	proto.RegisterType((*ListWebhooksResponse)(nil), "sms.ListWebhooksResponse")
+	proto.RegisterType((*DeleteWebhookRequest)(nil), "sms.DeleteWebhookRequest")
+	proto.RegisterType((*DeleteWebhookResponse)(nil), "sms.DeleteWebhookResponse")
+	proto.RegisterType((*ReplayDeliveriesRequest)(nil), "sms.ReplayDeliveriesRequest")
+	proto.RegisterType((*ReplayDeliveriesResponse)(nil), "sms.ReplayDeliveriesResponse")
+	proto.RegisterType((*QuietHours)(nil), "sms.QuietHours")
+	proto.RegisterType((*SetQuietHoursRequest)(nil), "sms.SetQuietHoursRequest")
+	proto.RegisterType((*SetQuietHoursResponse)(nil), "sms.SetQuietHoursResponse")
+	proto.RegisterType((*GetQuietHoursRequest)(nil), "sms.GetQuietHoursRequest")
+	proto.RegisterType((*GetQuietHoursResponse)(nil), "sms.GetQuietHoursResponse")
+	proto.RegisterType((*DeleteQuietHoursRequest)(nil), "sms.DeleteQuietHoursRequest")
+	proto.RegisterType((*DeleteQuietHoursResponse)(nil), "sms.DeleteQuietHoursResponse")
+	proto.RegisterType((*ReceiveRequest)(nil), "sms.ReceiveRequest")
+	proto.RegisterType((*ReceiveResponse)(nil), "sms.ReceiveResponse")
+}
+
+func init() { proto.RegisterFile("sms.proto", fileDescriptor_c8d8bdc537111860) }
+
+var fileDescriptor_c8d8bdc537111860 = []byte{
+	// 3034 bytes of a gzipped FileDescriptorProto
+	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0xac, 0x39, 0xcd, 0x73, 0xdb, 0xd6,
+	0xf1, 0x06, 0x29, 0x7e, 0x2d, 0x45, 0x0a, 0x7e, 0x96, 0x2c, 0x1a, 0xb6, 0x2c, 0x19, 0xfe, 0x92,
+	0xe5, 0x48, 0x54, 0x18, 0xc7, 0x4e, 0xf4, 0xcb, 0x87, 0x65, 0x89, 0x52, 0x94, 0x9f, 0x25, 0x39,
+	0x20, 0x95, 0x74, 0xe2, 0xb1, 0x59, 0x88, 0x78, 0x92, 0x50, 0x93, 0x00, 0x0d, 0x80, 0x4a, 0x65,
+	0x57, 0xd3, 0x99, 0xde, 0x7a, 0xe8, 0x29, 0xf7, 0xf6, 0x90, 0xe9, 0xad, 0xc7, 0xde, 0xfa, 0x57,
+	0x74, 0x7a, 0xea, 0xc9, 0x33, 0x9e, 0x4c, 0xa7, 0xff, 0x42, 0x27, 0x87, 0x76, 0xde, 0x07, 0x40,
+	0x3c, 0x10, 0xd4, 0x57, 0xcc, 0x13, 0xdf, 0xdb, 0x7d, 0xbb, 0xfb, 0xf6, 0xeb, 0xed, 0x2e, 0x20,
+	0xe7, 0xb6, 0xdd, 0xb9, 0x8e, 0x63, 0x7b, 0x36, 0x4a, 0xba, 0x6d, 0x57, 0xb9, 0xb2, 0x6b, 0xdb,
+	0xbb, 0x2d, 0x5c, 0xd6, 0x3b, 0x66, 0x59, 0xb7, 0x2c, 0xdb, 0xd3, 0x3d, 0xd3, 0xb6, 0x38, 0x8a,
+	0x32, 0xc9, 0xa1, 0x74, 0xb5, 0xdd, 0xdd, 0x29, 0x7b, 0x66, 0x1b, 0xbb, 0x9e, 0xde, 0xee, 0x70,
+	0x84, 0xfb, 0xbb, 0xa6, 0xb7, 0xd7, 0xdd, 0x9e, 0x6b, 0xda, 0xed, 0x72, 0xfb, 0x3b, 0xd3, 0x7b,
+	0x61, 0x7f, 0x57, 0xde, 0xb5, 0x67, 0x29, 0x70, 0x76, 0x5f, 0x6f, 0x99, 0x86, 0xee, 0xd9, 0x8e,
+	0x5b, 0x0e, 0xfe, 0xb2, 0x73, 0xea, 0xdf, 0x93, 0x90, 0xac, 0xad, 0xd7, 0x50, 0x19, 0x46, 0x4c,
+	0x03, 0xb7, 0x3b, 0xb6, 0x87, 0xad, 0xe6, 0x41, 0xe3, 0x05, 0x3e, 0x28, 0x49, 0x53, 0xd2, 0x74,
+	0xee, 0x51, 0xfa, 0xed, 0x9b, 0xc9, 0xc4, 0x2f, 0x24, 0xad, 0x18, 0x02, 0xff, 0x3f, 0x3e, 0x40,
+	0x15, 0x38, 0xbf, 0xe3, 0xd8, 0xed, 0x46, 0x67, 0xcf, 0xb6, 0x70, 0xc3, 0xea, 0xb6, 0xb7, 0xb1,
+	0x53, 0x4a, 0x08, 0x47, 0x46, 0x08, 0xc2, 0x13, 0x02, 0xdf, 0xa0, 0x60, 0x34, 0x07, 0x23, 0x9e,
+	0x2d, 0x9e, 0x48, 0x0a, 0x27, 0x0a, 0x9e, 0x1d, 0xc6, 0x2f, 0x41, 0xa6, 0x69, 0x5b, 0x1e, 0xb6,
+	0xbc, 0xd2, 0x10, 0xc1, 0xd3, 0xfc, 0x25, 0xba, 0x09, 0x45, 0xb7, 0xad, 0x3b, 0x5e, 0x03, 0x5b,
+	0x4d, 0xdb, 0x30, 0xad, 0xdd, 0x52, 0x6a, 0x4a, 0x9a, 0xce, 0x6a, 0x05, 0xba, 0x5b, 0xe5, 0x9b,
+	0xe8, 0x03, 0xc8, 0xb8, 0xd8, 0x32, 0x1a, 0xba, 0x57, 0x4a, 0x4f, 0x49, 0xd3, 0xf9, 0x8a, 0x32,
+	0xc7, 0x14, 0x39, 0xe7, 0x2b, 0x72, 0xae, 0xee, 0x2b, 0x52, 0x4b, 0x13, 0xd4, 0x45, 0x0f, 0x4d,
+	0x42, 0xde, 0xc3, 0xed, 0x4e, 0x4b, 0xf7, 0x70, 0xc3, 0x34, 0x4a, 0x19, 0xca, 0x19, 0xfc, 0xad,
+	0x35, 0x03, 0x7d, 0x08, 0xb9, 0x7d, 0xdd, 0x31, 0xf5, 0xed, 0x16, 0x76, 0x4b, 0xd9, 0xa9, 0xe4,
+	0x74, 0xbe, 0x32, 0x3e, 0x47, 0xcc, 0x59, 0x5b, 0xaf, 0xcd, 0x7d, 0xed, 0x43, 0xaa, 0x96, 0xe7,
+	0x1c, 0x68, 0x3d, 0x4c, 0x74, 0x07, 0xb2, 0x1d, 0xc7, 0xb4, 0x1d, 0xd3, 0x3b, 0x28, 0xe5, 0xa6,
+	0xa4, 0xe9, 0x62, 0xa5, 0x40, 0x4f, 0x3d, 0xe1, 0x9b, 0x5a, 0x00, 0x56, 0x3e, 0x81, 0xa2, 0x48,
+	0x07, 0xc9, 0x90, 0x0c, 0x6c, 0xa2, 0x91, 0xbf, 0x68, 0x14, 0x52, 0xfb, 0x7a, 0xab, 0x8b, 0x99,
+	0xd2, 0x35, 0xb6, 0x58, 0x48, 0x7c, 0x24, 0xa9, 0xef, 0x41, 0xb1, 0x86, 0x2d, 0x63, 0xd3, 0xc2,
+	0x1a, 0x7e, 0xd9, 0xc5, 0xae, 0x87, 0x14, 0x20, 0x3e, 0x46, 0x4f, 0xe7, 0x2b, 0x59, 0x5f, 0x56,
+	0x8d, 0x6c, 0xaa, 0x3f, 0x24, 0x61, 0x24, 0x40, 0x77, 0x3b, 0xb6, 0xe5, 0x62, 0x84, 0x60, 0xc8,
+	0x25, 0x5a, 0x97, 0xa8, 0x52, 0xe9, 0x7f, 0x62, 0x8c, 0x36, 0x76, 0x5d, 0x7d, 0xd7, 0xe7, 0xe8,
+	0x2f, 0xd1, 0x04, 0x00, 0xff, 0x4b, 0xf4, 0x45, 0x2d, 0xaa, 0xe5, 0xf8, 0xce, 0x9a, 0x81, 0xae,
+	0x43, 0xda, 0xf5, 0x74, 0xaf, 0xeb, 0x52, 0x23, 0x16, 0x2b, 0x79, 0xc6, 0x9f, 0x6e, 0x69, 0x1c,
+	0x44, 0x94, 0x23, 0x98, 0xd2, 0x57, 0x8e, 0x6f, 0x4a, 0x2d, 0x00, 0xa3, 0xeb, 0x50, 0x70, 0xf1,
+	0x6e, 0x1b, 0x5b, 0x5e, 0xa3, 0x69, 0x77, 0x2d, 0x66, 0xda, 0x94, 0x36, 0xcc, 0x37, 0x97, 0xc8,
+	0x1e, 0x7a, 0x00, 0x05, 0xb7, 0xbb, 0xed, 0x7a, 0xa6, 0xd7, 0xa5, 0x71, 0x54, 0xca, 0x50, 0x3b,
+	0x9d, 0x67, 0xbc, 0x43, 0x10, 0x4d, 0xc4, 0x43, 0x53, 0x90, 0x72, 0xec, 0xae, 0x87, 0x4b, 0x59,
+	0x2a, 0x05, 0xd0, 0x03, 0x1a, 0xd9, 0xd1, 0x18, 0x00, 0x7d, 0x06, 0xf2, 0x8e, 0xd9, 0xf2, 0xb0,
+	0xd3, 0x30, 0x70, 0xd3, 0x74, 0x29, 0xf5, 0x1c, 0xa5, 0x7e, 0x81, 0x22, 0xaf, 0x50, 0xe0, 0x32,
+	0x87, 0x69, 0x23, 0x3b, 0xc2, 0xda, 0x0d, 0x3b, 0x25, 0x9c, 0xd4, 0x29, 0xd5, 0x2f, 0x60, 0x38,
+	0x2c, 0x35, 0xb1, 0x10, 0x89, 0x2e, 0xee, 0x10, 0xf4, 0x3f, 0x2a, 0x42, 0xc2, 0xb3, 0xb9, 0x71,
+	0x12, 0x9e, 0x4d, 0x3c, 0x84, 0x29, 0x28, 0x49, 0x15, 0xc4, 0x16, 0xea, 0x9f, 0x24, 0x28, 0x8a,
+	0x22, 0x12, 0x62, 0x4e, 0xb7, 0x85, 0x7d, 0x62, 0xe4, 0x3f, 0xaa, 0x40, 0x5a, 0x6f, 0x12, 0x56,
+	0x94, 0x60, 0xb1, 0xa2, 0xc4, 0xdc, 0x6d, 0x6e, 0x91, 0x62, 0x68, 0x1c, 0x93, 0xba, 0x88, 0xee,
+	0x35, 0xf7, 0xb0, 0x5b, 0x4a, 0x4e, 0x25, 0xa9, 0x8b, 0xb0, 0xa5, 0x7a, 0x17, 0xd2, 0x0c, 0x17,
+	0x65, 0x61, 0x68, 0xe5, 0xf1, 0xe2, 0xaa, 0x7c, 0x0e, 0xe5, 0x21, 0xa3, 0x55, 0xbf, 0xd1, 0xd6,
+	0xea, 0x55, 0x59, 0x42, 0x00, 0x69, 0xad, 0xfa, 0x65, 0x75, 0xa9, 0x2e, 0x27, 0xd4, 0x59, 0xe6,
+	0x90, 0xeb, 0xba, 0x75, 0x70, 0x12, 0x07, 0x5e, 0x04, 0xb9, 0x87, 0xce, 0x1d, 0x98, 0xc4, 0xb0,
+	0xa3, 0x37, 0x5f, 0x98, 0xd6, 0x2e, 0xf1, 0xc9, 0x04, 0x8f, 0x61, 0xbe, 0xb5, 0x66, 0x7c, 0x39,
+	0x94, 0x95, 0xe4, 0x84, 0x96, 0xc6, 0x8e, 0x63, 0x3b, 0xae, 0xfa, 0x10, 0xc6, 0x57, 0xb1, 0xb7,
+	0xce, 0x5c, 0x96, 0x7b, 0x26, 0xe7, 0x7c, 0x53, 0x70, 0x6e, 0x31, 0x27, 0xf6, 0x9c, 0x5c, 0xfd,
+	0x49, 0x82, 0x52, 0x3f, 0x09, 0x2e, 0xcd, 0x44, 0x3f, 0x8d, 0xf8, 0x00, 0x49, 0x0c, 0x0e, 0x90,
+	0x9b, 0x50, 0xdc, 0xd1, 0xcd, 0x56, 0xd7, 0xc1, 0x0d, 0x07, 0xeb, 0xae, 0x6d, 0xf1, 0x40, 0x2b,
+	0xf0, 0x5d, 0x8d, 0x6e, 0xa2, 0x8f, 0x01, 0x9a, 0x0e, 0xd6, 0x3d, 0x4c, 0xfd, 0x6b, 0xe8, 0x58,
+	0xff, 0xca, 0x71, 0xec, 0x45, 0x8f, 0x1c, 0xed, 0x76, 0x0c, 0xff, 0x68, 0xea, 0xf8, 0xa3, 0x1c,
+	0x7b, 0xd1, 0x53, 0x3f, 0x05, 0xb4, 0x8a, 0xbd, 0x3a, 0x57, 0xaf, 0xaf, 0xba, 0xdb, 0xa2, 0x11,
+	0x44, 0xdd, 0x85, 0x8c, 0xa1, 0x7e, 0x2f, 0x41, 0x91, 0x1e, 0xc6, 0x06, 0x57, 0x20, 0xba, 0x3d,
+	0xe0, 0x3d, 0xea, 0x7b, 0x87, 0x44, 0xdd, 0x26, 0x06, 0xeb, 0x36, 0x39, 0x58, 0xb7, 0xa3, 0x90,
+	0xa2, 0x8e, 0xc0, 0x5f, 0x19, 0xb6, 0x50, 0xff, 0x93, 0x80, 0x0b, 0xc2, 0xad, 0xe2, 0x7d, 0x4b,
+	0x8a, 0xfa, 0x16, 0xba, 0x07, 0x29, 0x42, 0x18, 0x73, 0x73, 0x5e, 0xa5, 0x2c, 0x63, 0x28, 0x51,
+	0x31, 0xb0, 0xc6, 0x90, 0x89, 0x10, 0x9e, 0xed, 0xe9, 0x2d, 0x3f, 0x5a, 0xe9, 0x22, 0xc8, 0xc4,
+	0x43, 0x74, 0x93, 0xfe, 0x47, 0x17, 0x21, 0x4d, 0x8c, 0x8e, 0x0d, 0x6a, 0xa4, 0x94, 0xc6, 0x57,
+	0xa8, 0x0c, 0x59, 0x7e, 0x71, 0xb7, 0x94, 0x0e, 0x25, 0x24, 0x51, 0xb5, 0x5a, 0x80, 0x14, 0x71,
+	0x96, 0xcc, 0xd9, 0x9d, 0x25, 0x7b, 0x1a, 0x67, 0x51, 0x21, 0x45, 0x2f, 0x8e, 0x46, 0x20, 0xbf,
+	0xb6, 0xd1, 0x78, 0xa2, 0x6d, 0xae, 0x6a, 0xd5, 0x5a, 0x4d, 0x3e, 0x47, 0x72, 0xc3, 0xf2, 0xe6,
+	0x46, 0x55, 0x96, 0xd4, 0x9f, 0x12, 0x90, 0x59, 0x8f, 0x7d, 0x5e, 0xfa, 0xa2, 0x67, 0x66, 0x60,
+	0x21, 0xd2, 0x5f, 0x80, 0xdc, 0x1a, 0x50, 0x80, 0x9c, 0xbc, 0xf0, 0xe8, 0xf9, 0x53, 0x6a, 0xb0,
+	0x3f, 0x89, 0x7a, 0x4d, 0x9f, 0x46, 0xaf, 0xa1, 0xc7, 0x21, 0x73, 0xe2, 0x8a, 0xe5, 0x3d, 0xc8,
+	0x19, 0xa6, 0x83, 0x59, 0xba, 0x66, 0xef, 0x56, 0x91, 0xca, 0xb5, 0xec, 0xef, 0x6a, 0x3d, 0x84,
+	0xde, 0x0b, 0x97, 0x1b, 0xf0, 0xc2, 0xa9, 0x7f, 0x90, 0xa0, 0xf4, 0xd8, 0x74, 0xbd, 0x25, 0xdb,
+	0xda, 0xc7, 0x8e, 0xcb, 0x2a, 0x51, 0x3f, 0xaa, 0xef, 0xc0, 0xb0, 0xa0, 0x40, 0x31, 0xac, 0xf3,
+	0x1d, 0xa1, 0xde, 0xcb, 0x75, 0x88, 0xd9, 0x5c, 0xf3, 0x15, 0x0b, 0x86, 0xd4, 0xa3, 0xf3, 0x6f,
+	0xdf, 0x4c, 0x16, 0x4a, 0x58, 0xfe, 0xaf, 0xff, 0x93, 0xb4, 0x2c, 0xc1, 0xa9, 0x99, 0xaf, 0x30,
+	0x71, 0xec, 0x66, 0xd7, 0x71, 0x6d, 0xdf, 0x2a, 0x7c, 0xa5, 0x6e, 0xc3, 0x70, 0x58, 0x14, 0x74,
+	0x2d, 0x4e, 0x04, 0x91, 0x75, 0x19, 0x86, 0x5b, 0xba, 0xeb, 0x35, 0xc2, 0x25, 0x4b, 0xbe, 0x32,
+	0x4c, 0xef, 0xea, 0x07, 0x42, 0x9e, 0x60, 0xf0, 0x85, 0xda, 0x85, 0x4b, 0x31, 0x57, 0xe6, 0x21,
+	0xff, 0x00, 0x0a, 0xcd, 0x30, 0xa0, 0x24, 0x85, 0xaa, 0x89, 0xf0, 0x11, 0x4d, 0xc4, 0x23, 0xb9,
+	0xc2, 0xc2, 0xbf, 0xf6, 0x1a, 0xfc, 0x5a, 0xfc, 0x1d, 0x22, 0x5b, 0x4b, 0xec, 0x6a, 0x7f, 0x93,
+	0x40, 0x26, 0xa9, 0x61, 0xcf, 0xc1, 0xba, 0x71, 0x06, 0x15, 0xdf, 0x03, 0x64, 0x7b, 0x7b, 0xd8,
+	0x39, 0xaa, 0x0e, 0x97, 0x29, 0xc6, 0x93, 0x41, 0x86, 0x49, 0xf6, 0x0c, 0x13, 0x32, 0x4b, 0x09,
+	0xc7, 0x1a, 0x66, 0x48, 0x30, 0xcc, 0x73, 0x38, 0x1f, 0x12, 0x9e, 0x2b, 0x6b, 0x3a, 0x94, 0x86,
+	0x98, 0x9e, 0x44, 0xb5, 0x07, 0xd0, 0xe3, 0xb5, 0xb3, 0x05, 0x32, 0xa9, 0x7a, 0x9a, 0x8e, 0xb9,
+	0x8d, 0xcf, 0xa0, 0x9c, 0x9e, 0xd8, 0x09, 0x41, 0xec, 0x3f, 0x4b, 0x70, 0x3e, 0x44, 0x97, 0xcb,
+	0x5d, 0x86, 0x21, 0xef, 0xa0, 0xc3, 0xaa, 0xa0, 0x62, 0xe5, 0x72, 0x50, 0x29, 0x0a, 0x58, 0x73,
+	0xf5, 0x83, 0x0e, 0xd6, 0x28, 0x22, 0xba, 0x25, 0x56, 0xc4, 0xd1, 0x7b, 0xfa, 0xc0, 0x81, 0x6e,
+	0x3d, 0x09, 0x43, 0x84, 0x1a, 0x29, 0x84, 0xd6, 0xab, 0xb5, 0xda, 0xe2, 0x6a, 0x55, 0x3e, 0x47,
+	0x0a, 0xa1, 0x5a, 0x7d, 0xb1, 0xbe, 0x55, 0x93, 0x25, 0xf5, 0xf7, 0x12, 0x8c, 0x12, 0xa7, 0xac,
+	0x35, 0xf7, 0xb0, 0xd1, 0x6d, 0x61, 0xe3, 0x1d, 0xc6, 0xe0, 0xf1, 0xa6, 0x8e, 0xc6, 0xe0, 0x58,
+	0x44, 0x94, 0x77, 0x6f, 0xee, 0xcf, 0xe1, 0xe2, 0x92, 0x6e, 0x35, 0x71, 0xab, 0xef, 0xc2, 0x27,
+	0xac, 0xc2, 0x9e, 0xc1, 0x78, 0x1f, 0x81, 0x77, 0x57, 0x83, 0x91, 0xbc, 0x98, 0x7f, 0xd2, 0xd2,
+	0x9b, 0x78, 0xcf, 0x6e, 0x19, 0xd8, 0x41, 0x77, 0x60, 0xc8, 0xd2, 0xdb, 0xbc, 0x6e, 0x7e, 0x34,
+	0xf6, 0xf6, 0xcd, 0xe4, 0x79, 0x18, 0x79, 0xfe, 0x74, 0x71, 0xf6, 0x5b, 0x7d, 0xf6, 0xd5, 0xfc,
+	0xec, 0xc7, 0x8d, 0x67, 0x77, 0x6f, 0x68, 0x14, 0x85, 0xa0, 0x52, 0xe7, 0x62, 0xd4, 0xc7, 0x58,
+	0xe3, 0xd7, 0x23, 0x15, 0x72, 0x2b, 0x75, 0x9a, 0xbb, 0x05, 0xf5, 0x04, 0x6d, 0x6d, 0x63, 0x95,
+	0x79, 0xc5, 0xc6, 0xd6, 0xfa, 0xa3, 0xaa, 0x26, 0x4b, 0xf4, 0x95, 0x5c, 0xac, 0x57, 0xe5, 0x84,
+	0xfa, 0xc7, 0x04, 0x64, 0xeb, 0xbc, 0x2f, 0x8d, 0xb6, 0xad, 0x52, 0x5f, 0xdb, 0x8a, 0xb8, 0xb4,
+	0x4c, 0xef, 0x4c, 0xac, 0xd0, 0x43, 0x97, 0x14, 0x1f, 0xba, 0x7b, 0x30, 0xdc, 0xe9, 0xc9, 0x47,
+	0x7a, 0x37, 0x62, 0x5a, 0x39, 0x2a, 0xb8, 0x26, 0x60, 0x45, 0x5e, 0xbe, 0xd4, 0xd9, 0x2b, 0x8a,
+	0xf4, 0x29, 0x2a, 0x0a, 0x34, 0x0e, 0x99, 0xae, 0x8b, 0x1d, 0xbf, 0x5b, 0x4f, 0x69, 0x69, 0xb2,
+	0x5c, 0x33, 0xd4, 0xbf, 0x48, 0x30, 0xb6, 0x44, 0x39, 0xf8, 0x6a, 0xea, 0x35, 0x14, 0x61, 0xd3,
+	0xf9, 0xae, 0xc4, 0x94, 0x32, 0xd5, 0x53, 0x8a, 0x98, 0x48, 0x07, 0x2a, 0x27, 0x79, 0x22, 0xe5,
+	0x4c, 0xf6, 0xc4, 0xa4, 0xe5, 0x1c, 0xa3, 0x2b, 0x9f, 0x0b, 0xc4, 0x5d, 0x82, 0x8b, 0x51, 0x69,
+	0xb9, 0xf7, 0xde, 0x81, 0xac, 0x6f, 0x49, 0xde, 0x04, 0xb1, 0xf6, 0x38, 0x40, 0x0c, 0xc0, 0x7e,
+	0x2d, 0x1e, 0xb9, 0xef, 0xed, 0x18, 0xef, 0x08, 0xd5, 0xe2, 0x81, 0x97, 0xa8, 0x0f, 0x59, 0xd1,
+	0xfb, 0x33, 0x04, 0xf8, 0x2d, 0x4b, 0x5a, 0x3e, 0x24, 0x28, 0x1c, 0x84, 0x4c, 0x24, 0x9d, 0xa6,
+	0x1a, 0x10, 0xb2, 0x77, 0x58, 0x8d, 0xc9, 0x58, 0x35, 0x62, 0x18, 0x8b, 0x08, 0xc0, 0x2f, 0x71,
+	0x17, 0x72, 0xbe, 0x94, 0x7e, 0xae, 0x8a, 0xdc, 0xa2, 0x07, 0x3f, 0x3e, 0x5b, 0xfd, 0x55, 0x82,
+	0xb1, 0x2d, 0xea, 0x83, 0x67, 0x55, 0x76, 0xe0, 0x85, 0x89, 0xa3, 0xbd, 0x30, 0x79, 0x32, 0x2f,
+	0x3c, 0x51, 0x88, 0x12, 0x27, 0x8b, 0x4a, 0x7d, 0x7a, 0x1b, 0x3f, 0x84, 0xb1, 0x65, 0xdc, 0xc2,
+	0x67, 0xbf, 0xba, 0x5a, 0x81, 0x8b, 0x51, 0x0a, 0x5c, 0x8c, 0x12, 0x64, 0x0c, 0x0a, 0x31, 0xf8,
+	0xfc, 0xc9, 0x5f, 0xaa, 0x3f, 0x4a, 0x90, 0xf9, 0x06, 0x6f, 0xef, 0xd9, 0xf6, 0x0b, 0x92, 0xcf,
+	0xbf, 0x63, 0x7f, 0x43, 0xf9, 0x9c, 0xef, 0xac, 0x19, 0xe1, 0x94, 0x90, 0x08, 0xa7, 0x04, 0x32,
+	0x48, 0xeb, 0x3a, 0x2d, 0x9e, 0xed, 0xc8, 0x5f, 0xf4, 0x09, 0xe4, 0xf1, 0x3e, 0x99, 0x26, 0x91,
+	0xec, 0xcb, 0xb4, 0x78, 0xcc, 0xf3, 0x0f, 0x14, 0x9f, 0xfc, 0x75, 0x89, 0x97, 0xba, 0xb8, 0xe9,
+	0x60, 0x96, 0xed, 0x72, 0x1a, 0x5f, 0xfd, 0x8c, 0x1e, 0x40, 0xfd, 0xb7, 0x04, 0x45, 0x7e, 0xcd,
+	0x27, 0xfa, 0x41, 0xcb, 0xd6, 0x0d, 0xe2, 0x8c, 0x06, 0x6e, 0x99, 0xfb, 0xd8, 0x39, 0x08, 0x25,
+	0x77, 0x7f, 0x6b, 0xcd, 0x08, 0x8a, 0x97, 0xc4, 0x49, 0x8b, 0x97, 0x68, 0x0d, 0x9d, 0xec, 0xaf,
+	0xa1, 0x43, 0xf5, 0xcd, 0xd0, 0x51, 0xf5, 0xcd, 0xd9, 0x93, 0xbe, 0xfa, 0x83, 0x04, 0xa3, 0x2c,
+	0xe5, 0xf1, 0x0b, 0xfb, 0x7e, 0x14, 0x0a, 0x72, 0x29, 0x2e, 0xc8, 0xd1, 0x0d, 0x66, 0x47, 0x16,
+	0x39, 0xe8, 0xed, 0x9b, 0xc9, 0x22, 0x0c, 0x3f, 0xdf, 0xf3, 0xbc, 0x8e, 0xfb, 0xf9, 0x42, 0xb9,
+	0x3c, 0x77, 0x97, 0xd9, 0x76, 0x59, 0xb4, 0x6d, 0xf2, 0x58, 0xdb, 0x32, 0x3e, 0xbf, 0x94, 0xc2,
+	0x36, 0x56, 0x3f, 0x87, 0xb1, 0x88, 0x90, 0xdc, 0x55, 0x6f, 0x41, 0x86, 0xbb, 0x5c, 0x49, 0x0a,
+	0x69, 0xc8, 0x47, 0xf3, 0x81, 0xea, 0x7d, 0xb8, 0x40, 0x32, 0x12, 0xdf, 0x77, 0x4f, 0x7a, 0x49,
+	0xf5, 0x21, 0x8c, 0x8a, 0xe7, 0x7a, 0x35, 0x17, 0x27, 0x2d, 0xd6, 0x5c, 0x3e, 0xe3, 0x00, 0xaa,
+	0x7e, 0x0a, 0xa3, 0x2c, 0xcc, 0x22, 0xfa, 0xbd, 0xd9, 0x1f, 0x3e, 0xbd, 0x82, 0x2a, 0x08, 0x23,
+	0xf5, 0x7d, 0x18, 0x8b, 0x1c, 0x3f, 0x36, 0x48, 0x75, 0x18, 0xd7, 0x70, 0xa7, 0xa5, 0x1f, 0x2c,
+	0x33, 0xef, 0x34, 0xb1, 0x7b, 0x3a, 0xa6, 0x51, 0x67, 0x4f, 0x44, 0x9d, 0x5d, 0xbd, 0x0f, 0xa5,
+	0x7e, 0x16, 0x5c, 0x30, 0x05, 0xb2, 0x0e, 0x85, 0x71, 0xc9, 0x52, 0x5a, 0xb0, 0x26, 0x19, 0x1b,
+	0xbe, 0xea, 0x9a, 0xd8, 0xfb, 0xc2, 0xee, 0x3a, 0x6e, 0x38, 0x47, 0x48, 0x42, 0x8e, 0x18, 0xa5,
+	0x03, 0x1c, 0xc7, 0xf3, 0x47, 0xeb, 0x74, 0x41, 0x32, 0x07, 0xb6, 0xfc, 0xf9, 0x36, 0xf9, 0x4b,
+	0x46, 0x0f, 0x06, 0xde, 0xd1, 0xbb, 0x2d, 0xaf, 0xe1, 0x99, 0x6d, 0xdc, 0x78, 0x65, 0x5b, 0x98,
+	0x77, 0x48, 0x23, 0x1c, 0x40, 0xbc, 0xfe, 0x5b, 0xdb, 0xc2, 0x3f, 0x67, 0xba, 0xf6, 0x4f, 0x09,
+	0x46, 0x6b, 0xd8, 0xeb, 0x49, 0x7e, 0xe2, 0x20, 0xf9, 0x3f, 0xe1, 0x22, 0x8f, 0x6e, 0xbe, 0x7d,
+	0x33, 0x79, 0x0d, 0x26, 0x9f, 0x4f, 0x3f, 0x9d, 0x7f, 0xff, 0xd9, 0xd3, 0xf9, 0xd9, 0x8f, 0x9f,
+	0xfd, 0xa6, 0xf2, 0x74, 0x7e, 0xf6, 0x83, 0x67, 0x77, 0x16, 0x9e, 0xce, 0xcf, 0x7e, 0xc8, 0xb6,
+	0x6e, 0xf8, 0xf7, 0x7d, 0x10, 0xba, 0xef, 0x49, 0x8f, 0x9e, 0x56, 0x2d, 0xea, 0x1a, 0x8c, 0x45,
+	0xae, 0xc6, 0xed, 0x38, 0x0f, 0xf9, 0x97, 0x64, 0xb7, 0xb1, 0x47, 0xb6, 0x79, 0x78, 0x8d, 0x50,
+	0x2f, 0x0f, 0x61, 0xc3, 0xcb, 0xe0, 0xbf, 0xfa, 0x00, 0x46, 0x57, 0xcf, 0xa2, 0x25, 0x22, 0xc3,
+	0xea, 0x3b, 0x92, 0x61, 0x01, 0xc6, 0x59, 0xbc, 0x9c, 0x41, 0x8c, 0x7b, 0x50, 0xea, 0x3f, 0x7b,
+	0x6c, 0xb8, 0xfd, 0x4b, 0x82, 0xa2, 0x86, 0x9b, 0xd8, 0xdc, 0x0f, 0xde, 0xe0, 0xd8, 0x4f, 0x73,
+	0xd2, 0xa9, 0x3f, 0xcd, 0x25, 0x8e, 0xfa, 0x34, 0x77, 0x7c, 0x75, 0x42, 0x30, 0x74, 0xc7, 0x31,
+	0x31, 0x1f, 0x1a, 0x84, 0x30, 0xd8, 0x36, 0x7a, 0x0f, 0x10, 0xff, 0xdb, 0x08, 0xb5, 0x66, 0xec,
+	0x19, 0x95, 0x39, 0x64, 0x3d, 0xe8, 0xed, 0x36, 0x60, 0x24, 0xb8, 0xe7, 0xc9, 0x7a, 0xba, 0x2b,
+	0x90, 0x33, 0xba, 0x9d, 0x96, 0xd9, 0xf4, 0x67, 0xb1, 0x59, 0xad, 0xb7, 0x31, 0xa3, 0x42, 0xd6,
+	0xff, 0xf2, 0x46, 0xdb, 0xab, 0x4d, 0x6d, 0x7d, 0xf1, 0x31, 0x6b, 0xb5, 0xb6, 0xb4, 0xd5, 0xea,
+	0x46, 0x5d, 0x96, 0x66, 0xbe, 0x86, 0x34, 0x6b, 0x01, 0xc9, 0xee, 0x57, 0x5b, 0xd5, 0xad, 0xea,
+	0x32, 0x1b, 0x53, 0xd6, 0x28, 0x1c, 0x15, 0x20, 0xb7, 0x5c, 0x7d, 0xbc, 0xf6, 0x75, 0x55, 0xab,
+	0x2e, 0xcb, 0x09, 0x82, 0xb4, 0xb2, 0xb8, 0xf6, 0xb8, 0xba, 0x2c, 0x27, 0x09, 0xa8, 0xb6, 0xf4,
+	0x45, 0x75, 0x79, 0x8b, 0x2c, 0x87, 0xd0, 0x30, 0x64, 0x97, 0x16, 0x37, 0x96, 0xaa, 0x64, 0x95,
+	0x9a, 0xb9, 0x0a, 0xd9, 0xe0, 0x1b, 0x65, 0x16, 0x86, 0x56, 0x6b, 0xeb, 0x0f, 0x18, 0xdd, 0xad,
+	0xa5, 0x5a, 0x45, 0x96, 0x66, 0x6e, 0x41, 0x2e, 0x18, 0xdd, 0x91, 0xa3, 0x9b, 0x5b, 0xf5, 0x47,
+	0x9b, 0x5b, 0x1b, 0xcb, 0xec, 0xab, 0xc9, 0xda, 0x06, 0x5b, 0x48, 0x33, 0x53, 0x90, 0xa2, 0x83,
+	0x3b, 0xc2, 0x79, 0x73, 0xa3, 0xb1, 0x51, 0xad, 0x33, 0x8c, 0xcd, 0x95, 0x15, 0xba, 0x90, 0x2a,
+	0x3f, 0xca, 0x00, 0xb5, 0xf5, 0x5a, 0x0d, 0x3b, 0xfb, 0x66, 0x13, 0xa3, 0x0d, 0xc8, 0xf0, 0x6f,
+	0x7d, 0x88, 0xcd, 0x86, 0xc5, 0x0f, 0x85, 0xca, 0xa8, 0xb8, 0xc9, 0xf4, 0xac, 0x96, 0x7e, 0xf7,
+	0x8f, 0x1f, 0xbf, 0x4f, 0x20, 0xb5, 0x50, 0x76, 0xdb, 0x6e, 0xd9, 0xc5, 0x96, 0x51, 0xb6, 0x2d,
+	0xbc, 0x20, 0xcd, 0xa0, 0x3a, 0x64, 0xfd, 0x6f, 0x2f, 0xa8, 0x77, 0x36, 0xf4, 0xe5, 0x46, 0x19,
+	0x8b, 0xec, 0x72, 0x92, 0x97, 0x28, 0xc9, 0x0b, 0x6a, 0xb1, 0x47, 0xb2, 0xad, 0x5b, 0x07, 0x0b,
+	0xd2, 0xcc, 0xb4, 0x84, 0x5e, 0xd2, 0x99, 0x98, 0xf0, 0x2d, 0x05, 0x5d, 0xf1, 0xa7, 0xe8, 0x71,
+	0x5f, 0x69, 0x94, 0x89, 0x01, 0x50, 0xce, 0x6d, 0x8a, 0x72, 0x53, 0x50, 0x89, 0x71, 0xa3, 0xc0,
+	0xf2, 0xeb, 0x9e, 0xef, 0x1c, 0x22, 0x0c, 0xf9, 0xd0, 0x84, 0x1e, 0x8d, 0xf7, 0xcf, 0xec, 0x19,
+	0xa3, 0xd2, 0xa0, 0x61, 0xbe, 0x7a, 0x9d, 0xf2, 0x98, 0x40, 0x97, 0x29, 0x0f, 0xff, 0x73, 0x40,
+	0xf9, 0x75, 0xe8, 0x5b, 0xc1, 0x21, 0x3a, 0x84, 0xf3, 0x7d, 0x53, 0x46, 0xc4, 0x84, 0x1f, 0x34,
+	0x70, 0x55, 0xae, 0x0e, 0x02, 0x73, 0xc6, 0x77, 0x28, 0xe3, 0xeb, 0xe8, 0x1a, 0x65, 0x2c, 0xcc,
+	0x1f, 0xcb, 0xaf, 0xc3, 0x11, 0x7d, 0x88, 0x3c, 0xc8, 0x05, 0xf3, 0x3a, 0x34, 0x16, 0x5c, 0x25,
+	0x3c, 0x7c, 0x54, 0x2e, 0x46, 0xb7, 0x39, 0x9b, 0x8f, 0x28, 0x9b, 0x0a, 0x9a, 0x67, 0xf7, 0xa3,
+	0xc0, 0x28, 0x83, 0xf2, 0xeb, 0xfe, 0x61, 0xe4, 0x21, 0xfa, 0x0c, 0x72, 0x41, 0xb1, 0xc5, 0xb9,
+	0x46, 0xa7, 0x7a, 0xca, 0xc5, 0xe8, 0x36, 0xe7, 0x7a, 0x6e, 0x5e, 0x42, 0x6d, 0x28, 0x08, 0xa3,
+	0x27, 0x74, 0x29, 0xd0, 0x48, 0x74, 0x50, 0xa4, 0x28, 0x71, 0x20, 0x4e, 0xeb, 0x26, 0xbd, 0xc1,
+	0x24, 0x9a, 0x60, 0x5e, 0xe0, 0xc3, 0xa3, 0x4a, 0x7a, 0x09, 0x23, 0x91, 0x21, 0x12, 0x62, 0x15,
+	0x63, 0xfc, 0x6c, 0x4a, 0xb9, 0x12, 0x0f, 0x14, 0xdd, 0x62, 0xe6, 0x72, 0x94, 0x69, 0xd8, 0xfb,
+	0x0c, 0x28, 0x8a, 0x8d, 0x3f, 0x62, 0xf7, 0x88, 0x9d, 0x5d, 0x28, 0x97, 0x63, 0x61, 0x62, 0x60,
+	0x2d, 0x48, 0x33, 0x3c, 0xb6, 0x7a, 0x1d, 0xed, 0x0e, 0xf3, 0x71, 0x9f, 0x45, 0xcf, 0xc7, 0x23,
+	0xf4, 0x4b, 0xfd, 0x00, 0x4e, 0xfc, 0x06, 0x25, 0x7e, 0x15, 0x5d, 0x11, 0x29, 0x97, 0x5f, 0x87,
+	0x7a, 0xbe, 0x43, 0xf4, 0x9c, 0xd9, 0xab, 0x1e, 0x30, 0xee, 0xd9, 0x2b, 0x3a, 0x14, 0x50, 0x94,
+	0x38, 0x10, 0xe7, 0x76, 0x91, 0x72, 0x93, 0x51, 0xf4, 0x1e, 0x0e, 0x14, 0xc5, 0x0e, 0x96, 0x6b,
+	0x2b, 0xb6, 0x19, 0x57, 0x2e, 0xc7, 0xc2, 0x38, 0x8b, 0xdb, 0x94, 0xc5, 0xb5, 0x05, 0x69, 0x46,
+	0x39, 0xfa, 0x4e, 0x36, 0x14, 0xc5, 0x76, 0x95, 0xf3, 0x8c, 0xed, 0x82, 0x95, 0xcb, 0xb1, 0x30,
+	0x51, 0x89, 0x33, 0x47, 0x33, 0x5c, 0x81, 0x82, 0xd0, 0x73, 0x70, 0x25, 0xc6, 0x35, 0x4b, 0x8a,
+	0x12, 0x07, 0xe2, 0x6f, 0xe4, 0x12, 0x0c, 0x87, 0x5b, 0x08, 0x54, 0x0a, 0x14, 0x1e, 0xe9, 0x46,
+	0x94, 0x4b, 0x31, 0x10, 0x4e, 0x64, 0x05, 0x0a, 0x42, 0x1b, 0xc0, 0x85, 0x89, 0xeb, 0x2c, 0x14,
+	0x25, 0x0e, 0xc4, 0xe9, 0x6c, 0x82, 0x1c, 0x2d, 0xdc, 0x79, 0x62, 0x1f, 0xd0, 0x32, 0x28, 0x13,
+	0x03, 0xa0, 0x9c, 0x60, 0x1b, 0x0a, 0x42, 0xf9, 0xc8, 0x05, 0x8b, 0xab, 0x96, 0x15, 0x25, 0x0e,
+	0x24, 0xa6, 0x06, 0x45, 0xa1, 0x36, 0xa1, 0x05, 0xdd, 0x2c, 0x2d, 0xfa, 0xca, 0xaf, 0x79, 0xd1,
+	0x76, 0x48, 0x9e, 0xbb, 0x5f, 0x41, 0x61, 0x35, 0x86, 0xdd, 0xea, 0x60, 0x76, 0xb1, 0x85, 0xa5,
+	0xaa, 0x52, 0x76, 0x57, 0xd0, 0x11, 0xec, 0x90, 0x0b, 0x72, 0xb4, 0x1c, 0xe4, 0xba, 0x1a, 0x50,
+	0x61, 0x2a, 0x13, 0x03, 0xa0, 0x22, 0xd3, 0x99, 0xa3, 0x98, 0xde, 0x87, 0x0c, 0x2f, 0xb2, 0x78,
+	0x7d, 0x20, 0x96, 0x96, 0xca, 0xa8, 0xb8, 0xe9, 0x27, 0xe9, 0xed, 0x34, 0xed, 0x60, 0x3e, 0xf8,
+	0xdf, 0x00, 0x8b, 0x30, 0xff, 0x68, 0xd6, 0x26, 0x00, 0x00,
+}
+
+// Reference imports to suppress errors if they are not otherwise used.
+var _ context.Context
+var _ grpc.ClientConn
+
+// This is a compile-time assertion to ensure that this generated file
+// is compatible with the grpc package it is being compiled against.
+const _ = grpc.SupportPackageIsVersion4
 
 // SMSServiceClient is the client API for SMSService service.
 //
 // For semantics around ctx use and closing/ending streaming RPCs, please refer to https://godoc.org/google.golang.org/grpc#ClientConn.NewStream.
 type SMSServiceClient interface {
 	// SendOne method sends a single sms
-	SendOne(ctx context.Context, in *SendOneRequest, opts ...grpc.CallOption) (*SendOneResponse, error)
+	SendOne(ctx context.Context, in *SendOneRequest, opts ...grpc.CallOption) (*SendOneResponse, error)
+	// SendMany method sends many SMSs in one request.
+	// It relies on calling SendOne method for each sms.
+	//
+	// This is used to send many SMSs at once. A long text doesn't have to be split
+	// by the client, SendOne splits it into the segments of a concatenated sms.
+	//
+	// It returns a tracking id right away, while the SMSs are sent in the background.
+	//
+	// For HTTP API, newline-delimited JSON is used for streaming.
+	SendMany(ctx context.Context, opts ...grpc.CallOption) (SMSService_SendManyClient, error)
+	// GetMessageStatus method gets the status of a message by the id returned by SendOne.
+	GetMessageStatus(ctx context.Context, in *GetMessageStatusRequest, opts ...grpc.CallOption) (*GetMessageStatusResponse, error)
+	// GetTracking method gets the progress of sending the SMSs of SendMany,
+	// and the status (or error) of every sms, by the tracking id returned by SendMany.
+	GetTracking(ctx context.Context, in *GetTrackingRequest, opts ...grpc.CallOption) (*GetTrackingResponse, error)
+	// ListConversations method lists the conversations of a phone number, page by page.
+	// Every conversation is the latest message with another phone number.
+	ListConversations(ctx context.Context, in *ListConversationsRequest, opts ...grpc.CallOption) (*ListConversationsResponse, error)
+	// GetThread method gets the messages between two phone numbers, page by page.
+	GetThread(ctx context.Context, in *GetThreadRequest, opts ...grpc.CallOption) (*GetThreadResponse, error)
+	// Subscribe method pushes the new messages to a phone number,
+	// and the status changes of the messages from it, as they happen.
+	//
+	// Every event has a cursor. After a reconnect, the client passes the cursor
+	// of the last received event, and the missed events are sent first.
+	//
+	// It isn't published by the gateway, since it has no authentication of its own.
+	// The clients of the gateway use its live endpoints (SSE and WebSocket) instead.
+	Subscribe(ctx context.Context, in *SubscribeRequest, opts ...grpc.CallOption) (SMSService_SubscribeClient, error)
+	// ListScheduled method lists the messages from a phone number that are yet to be sent, page by page.
+	ListScheduled(ctx context.Context, in *ListScheduledRequest, opts ...grpc.CallOption) (*ListScheduledResponse, error)
+	// CancelScheduled method cancels a scheduled message, provided that it hasn't been sent yet.
+	CancelScheduled(ctx context.Context, in *CancelScheduledRequest, opts ...grpc.CallOption) (*CancelScheduledResponse, error)
+	// CreateTemplate method creates a template to send SMSs from.
+	CreateTemplate(ctx context.Context, in *CreateTemplateRequest, opts ...grpc.CallOption) (*CreateTemplateResponse, error)
+	// GetTemplate method gets a template by its id.
+	GetTemplate(ctx context.Context, in *GetTemplateRequest, opts ...grpc.CallOption) (*GetTemplateResponse, error)
+	// ListTemplates method lists the templates by their name, page by page.
+	ListTemplates(ctx context.Context, in *ListTemplatesRequest, opts ...grpc.CallOption) (*ListTemplatesResponse, error)
+	// UpdateTemplate method replaces the name, content and placeholders of a template.
+	// The SMSs that have been sent or scheduled already are not affected.
+	UpdateTemplate(ctx context.Context, in *UpdateTemplateRequest, opts ...grpc.CallOption) (*UpdateTemplateResponse, error)
+	// DeleteTemplate method deletes a template.
+	DeleteTemplate(ctx context.Context, in *DeleteTemplateRequest, opts ...grpc.CallOption) (*DeleteTemplateResponse, error)
+	// CreateWebhook method subscribes a webhook of an account to the given event types.
+	//
+	// The webhook methods aren't published by the gateway, since the account is taken from the request
+	// rather than authenticated. They are called by the services that authenticate the account.
+	CreateWebhook(ctx context.Context, in *CreateWebhookRequest, opts ...grpc.CallOption) (*CreateWebhookResponse, error)
+	// ListWebhooks method lists the webhooks of an account.
+	ListWebhooks(ctx context.Context, in *ListWebhooksRequest, opts ...grpc.CallOption) (*ListWebhooksResponse, error)
+	// DeleteWebhook method deletes a webhook, its pending deliveries are dropped.
+	DeleteWebhook(ctx context.Context, in *DeleteWebhookRequest, opts ...grpc.CallOption) (*DeleteWebhookResponse, error)
+	// ReplayDeliveries method sends the failed deliveries of a webhook again,
+	// the ones that were given up on after all the retries.
+	ReplayDeliveries(ctx context.Context, in *ReplayDeliveriesRequest, opts ...grpc.CallOption) (*ReplayDeliveriesResponse, error)
+	// SetQuietHours method sets the quiet hours of an account, where its SMSs are held
+	// until they end in the local time of the recipient, unless they are URGENT.
+	// The SMSs that have been held or scheduled already are not affected.
+	SetQuietHours(ctx context.Context, in *SetQuietHoursRequest, opts ...grpc.CallOption) (*SetQuietHoursResponse, error)
+	// GetQuietHours method gets the quiet hours of an account.
+	GetQuietHours(ctx context.Context, in *GetQuietHoursRequest, opts ...grpc.CallOption) (*GetQuietHoursResponse, error)
+	// DeleteQuietHours method deletes the quiet hours of an account, and so its SMSs are sent at any time.
+	DeleteQuietHours(ctx context.Context, in *DeleteQuietHoursRequest, opts ...grpc.CallOption) (*DeleteQuietHoursResponse, error)
+	// Receive method stores a message received by a carrier, sent to a phone number on the platform.
+	// It has no REST API, since only the carriers send them: through the inbound webhook of the gateway,
+	// which verifies the carrier, or through SMPP.
+	Receive(ctx context.Context, in *ReceiveRequest, opts ...grpc.CallOption) (*ReceiveResponse, error)
+}
+
+type sMSServiceClient struct {
+	cc *grpc.ClientConn
+}
+
+func NewSMSServiceClient(cc *grpc.ClientConn) SMSServiceClient {
+	return &sMSServiceClient{cc}
+}
+
+func (c *sMSServiceClient) SendOne(ctx context.Context, in *SendOneRequest, opts ...grpc.CallOption) (*SendOneResponse, error) {
+	out := new(SendOneResponse)
+	err := c.cc.Invoke(ctx, "/sms.SMSService/SendOne", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *sMSServiceClient) SendMany(ctx context.Context, opts ...grpc.CallOption) (SMSService_SendManyClient, error) {
+	stream, err := c.cc.NewStream(ctx, &_SMSService_serviceDesc.Streams[0], "/sms.SMSService/SendMany", opts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &sMSServiceSendManyClient{stream}
+	return x, nil
+}
+
+type SMSService_SendManyClient interface {
+	Send(*SendManyRequest) error
+	CloseAndRecv() (*SendManyResponse, error)
+	grpc.ClientStream
+}
+
+type sMSServiceSendManyClient struct {
+	grpc.ClientStream
+}
+
+func (x *sMSServiceSendManyClient) Send(m *SendManyRequest) error {
+	return x.ClientStream.SendMsg(m)
+}
+
+func (x *sMSServiceSendManyClient) CloseAndRecv() (*SendManyResponse, error) {
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	m := new(SendManyResponse)
+	if err := x.ClientStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+func (c *sMSServiceClient) GetMessageStatus(ctx context.Context, in *GetMessageStatusRequest, opts ...grpc.CallOption) (*GetMessageStatusResponse, error) {
+	out := new(GetMessageStatusResponse)
+	err := c.cc.Invoke(ctx, "/sms.SMSService/GetMessageStatus", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *sMSServiceClient) GetTracking(ctx context.Context, in *GetTrackingRequest, opts ...grpc.CallOption) (*GetTrackingResponse, error) {
+	out := new(GetTrackingResponse)
+	err := c.cc.Invoke(ctx, "/sms.SMSService/GetTracking", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *sMSServiceClient) ListConversations(ctx context.Context, in *ListConversationsRequest, opts ...grpc.CallOption) (*ListConversationsResponse, error) {
+	out := new(ListConversationsResponse)
+	err := c.cc.Invoke(ctx, "/sms.SMSService/ListConversations", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *sMSServiceClient) GetThread(ctx context.Context, in *GetThreadRequest, opts ...grpc.CallOption) (*GetThreadResponse, error) {
+	out := new(GetThreadResponse)
+	err := c.cc.Invoke(ctx, "/sms.SMSService/GetThread", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *sMSServiceClient) Subscribe(ctx context.Context, in *SubscribeRequest, opts ...grpc.CallOption) (SMSService_SubscribeClient, error) {
+	stream, err := c.cc.NewStream(ctx, &_SMSService_serviceDesc.Streams[1], "/sms.SMSService/Subscribe", opts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &sMSServiceSubscribeClient{stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+type SMSService_SubscribeClient interface {
+	Recv() (*SubscribeResponse, error)
+	grpc.ClientStream
+}
+
+type sMSServiceSubscribeClient struct {
+	grpc.ClientStream
+}
+
+func (x *sMSServiceSubscribeClient) Recv() (*SubscribeResponse, error) {
+	m := new(SubscribeResponse)
+	if err := x.ClientStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+func (c *sMSServiceClient) ListScheduled(ctx context.Context, in *ListScheduledRequest, opts ...grpc.CallOption) (*ListScheduledResponse, error) {
+	out := new(ListScheduledResponse)
+	err := c.cc.Invoke(ctx, "/sms.SMSService/ListScheduled", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *sMSServiceClient) CancelScheduled(ctx context.Context, in *CancelScheduledRequest, opts ...grpc.CallOption) (*CancelScheduledResponse, error) {
+	out := new(CancelScheduledResponse)
+	err := c.cc.Invoke(ctx, "/sms.SMSService/CancelScheduled", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *sMSServiceClient) CreateTemplate(ctx context.Context, in *CreateTemplateRequest, opts ...grpc.CallOption) (*CreateTemplateResponse, error) {
+	out := new(CreateTemplateResponse)
+	err := c.cc.Invoke(ctx, "/sms.SMSService/CreateTemplate", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *sMSServiceClient) GetTemplate(ctx context.Context, in *GetTemplateRequest, opts ...grpc.CallOption) (*GetTemplateResponse, error) {
+	out := new(GetTemplateResponse)
+	err := c.cc.Invoke(ctx, "/sms.SMSService/GetTemplate", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *sMSServiceClient) ListTemplates(ctx context.Context, in *ListTemplatesRequest, opts ...grpc.CallOption) (*ListTemplatesResponse, error) {
+	out := new(ListTemplatesResponse)
+	err := c.cc.Invoke(ctx, "/sms.SMSService/ListTemplates", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *sMSServiceClient) UpdateTemplate(ctx context.Context, in *UpdateTemplateRequest, opts ...grpc.CallOption) (*UpdateTemplateResponse, error) {
+	out := new(UpdateTemplateResponse)
+	err := c.cc.Invoke(ctx, "/sms.SMSService/UpdateTemplate", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *sMSServiceClient) DeleteTemplate(ctx context.Context, in *DeleteTemplateRequest, opts ...grpc.CallOption) (*DeleteTemplateResponse, error) {
+	out := new(DeleteTemplateResponse)
+	err := c.cc.Invoke(ctx, "/sms.SMSService/DeleteTemplate", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *sMSServiceClient) CreateWebhook(ctx context.Context, in *CreateWebhookRequest, opts ...grpc.CallOption) (*CreateWebhookResponse, error) {
+	out := new(CreateWebhookResponse)
+	err := c.cc.Invoke(ctx, "/sms.SMSService/CreateWebhook", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *sMSServiceClient) ListWebhooks(ctx context.Context, in *ListWebhooksRequest, opts ...grpc.CallOption) (*ListWebhooksResponse, error) {
+	out := new(ListWebhooksResponse)
+	err := c.cc.Invoke(ctx, "/sms.SMSService/ListWebhooks", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *sMSServiceClient) DeleteWebhook(ctx context.Context, in *DeleteWebhookRequest, opts ...grpc.CallOption) (*DeleteWebhookResponse, error) {
+	out := new(DeleteWebhookResponse)
+	err := c.cc.Invoke(ctx, "/sms.SMSService/DeleteWebhook", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *sMSServiceClient) ReplayDeliveries(ctx context.Context, in *ReplayDeliveriesRequest, opts ...grpc.CallOption) (*ReplayDeliveriesResponse, error) {
+	out := new(ReplayDeliveriesResponse)
+	err := c.cc.Invoke(ctx, "/sms.SMSService/ReplayDeliveries", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *sMSServiceClient) SetQuietHours(ctx context.Context, in *SetQuietHoursRequest, opts ...grpc.CallOption) (*SetQuietHoursResponse, error) {
+	out := new(SetQuietHoursResponse)
+	err := c.cc.Invoke(ctx, "/sms.SMSService/SetQuietHours", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *sMSServiceClient) GetQuietHours(ctx context.Context, in *GetQuietHoursRequest, opts ...grpc.CallOption) (*GetQuietHoursResponse, error) {
+	out := new(GetQuietHoursResponse)
+	err := c.cc.Invoke(ctx, "/sms.SMSService/GetQuietHours", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *sMSServiceClient) DeleteQuietHours(ctx context.Context, in *DeleteQuietHoursRequest, opts ...grpc.CallOption) (*DeleteQuietHoursResponse, error) {
+	out := new(DeleteQuietHoursResponse)
+	err := c.cc.Invoke(ctx, "/sms.SMSService/DeleteQuietHours", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *sMSServiceClient) Receive(ctx context.Context, in *ReceiveRequest, opts ...grpc.CallOption) (*ReceiveResponse, error) {
+	out := new(ReceiveResponse)
+	err := c.cc.Invoke(ctx, "/sms.SMSService/Receive", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// SMSServiceServer is the server API for SMSService service.
+type SMSServiceServer interface {
+	// SendOne method sends a single sms
+	SendOne(context.Context, *SendOneRequest) (*SendOneResponse, error)
 	// SendMany method sends many SMSs in one request.
 	// It relies on calling SendOne method for each sms.
 	//
+	// This is used to send many SMSs at once. A long text doesn't have to be split
+	// by the client, SendOne splits it into the segments of a concatenated sms.
+	//
+	// It returns a tracking id right away, while the SMSs are sent in the background.
+	//
 	// For HTTP API, newline-delimited JSON is used for streaming.
-	SendMany(ctx context.Context, opts ...grpc.CallOption) (SMSService_SendManyClient, error)
+	SendMany(SMSService_SendManyServer) error
+	// GetMessageStatus method gets the status of a message by the id returned by SendOne.
+	GetMessageStatus(context.Context, *GetMessageStatusRequest) (*GetMessageStatusResponse, error)
+	// GetTracking method gets the progress of sending the SMSs of SendMany,
+	// and the status (or error) of every sms, by the tracking id returned by SendMany.
+	GetTracking(context.Context, *GetTrackingRequest) (*GetTrackingResponse, error)
+	// ListConversations method lists the conversations of a phone number, page by page.
+	// Every conversation is the latest message with another phone number.
+	ListConversations(context.Context, *ListConversationsRequest) (*ListConversationsResponse, error)
+	// GetThread method gets the messages between two phone numbers, page by page.
+	GetThread(context.Context, *GetThreadRequest) (*GetThreadResponse, error)
+	// Subscribe method pushes the new messages to a phone number,
+	// and the status changes of the messages from it, as they happen.
+	//
+	// Every event has a cursor. After a reconnect, the client passes the cursor
+	// of the last received event, and the missed events are sent first.
+	//
+	// It isn't published by the gateway, since it has no authentication of its own.
+	// The clients of the gateway use its live endpoints (SSE and WebSocket) instead.
+	Subscribe(*SubscribeRequest, SMSService_SubscribeServer) error
+	// ListScheduled method lists the messages from a phone number that are yet to be sent, page by page.
+	ListScheduled(context.Context, *ListScheduledRequest) (*ListScheduledResponse, error)
+	// CancelScheduled method cancels a scheduled message, provided that it hasn't been sent yet.
+	CancelScheduled(context.Context, *CancelScheduledRequest) (*CancelScheduledResponse, error)
+	// CreateTemplate method creates a template to send SMSs from.
+	CreateTemplate(context.Context, *CreateTemplateRequest) (*CreateTemplateResponse, error)
+	// GetTemplate method gets a template by its id.
+	GetTemplate(context.Context, *GetTemplateRequest) (*GetTemplateResponse, error)
+	// ListTemplates method lists the templates by their name, page by page.
+	ListTemplates(context.Context, *ListTemplatesRequest) (*ListTemplatesResponse, error)
+	// UpdateTemplate method replaces the name, content and placeholders of a template.
+	// The SMSs that have been sent or scheduled already are not affected.
+	UpdateTemplate(context.Context, *UpdateTemplateRequest) (*UpdateTemplateResponse, error)
+	// DeleteTemplate method deletes a template.
+	DeleteTemplate(context.Context, *DeleteTemplateRequest) (*DeleteTemplateResponse, error)
+	// CreateWebhook method subscribes a webhook of an account to the given event types.
+	//
+	// The webhook methods aren't published by the gateway, since the account is taken from the request
+	// rather than authenticated. They are called by the services that authenticate the account.
+	CreateWebhook(context.Context, *CreateWebhookRequest) (*CreateWebhookResponse, error)
+	// ListWebhooks method lists the webhooks of an account.
+	ListWebhooks(context.Context, *ListWebhooksRequest) (*ListWebhooksResponse, error)
+	// DeleteWebhook method deletes a webhook, its pending deliveries are dropped.
+	DeleteWebhook(context.Context, *DeleteWebhookRequest) (*DeleteWebhookResponse, error)
+	// ReplayDeliveries method sends the failed deliveries of a webhook again,
+	// the ones that were given up on after all the retries.
+	ReplayDeliveries(context.Context, *ReplayDeliveriesRequest) (*ReplayDeliveriesResponse, error)
+	// SetQuietHours method sets the quiet hours of an account, where its SMSs are held
+	// until they end in the local time of the recipient, unless they are URGENT.
+	// The SMSs that have been held or scheduled already are not affected.
+	SetQuietHours(context.Context, *SetQuietHoursRequest) (*SetQuietHoursResponse, error)
+	// GetQuietHours method gets the quiet hours of an account.
+	GetQuietHours(context.Context, *GetQuietHoursRequest) (*GetQuietHoursResponse, error)
+	// DeleteQuietHours method deletes the quiet hours of an account, and so its SMSs are sent at any time.
+	DeleteQuietHours(context.Context, *DeleteQuietHoursRequest) (*DeleteQuietHoursResponse, error)
+	// Receive method stores a message received by a carrier, sent to a phone number on the platform.
+	// It has no REST API, since only the carriers send them: through the inbound webhook of the gateway,
+	// which verifies the carrier, or through SMPP.
+	Receive(context.Context, *ReceiveRequest) (*ReceiveResponse, error)
+}
+
+// UnimplementedSMSServiceServer can be embedded to have forward compatible implementations.
+type UnimplementedSMSServiceServer struct {
+}
+
+func (*UnimplementedSMSServiceServer) SendOne(ctx context.Context, req *SendOneRequest) (*SendOneResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method SendOne not implemented")
+}
+func (*UnimplementedSMSServiceServer) SendMany(srv SMSService_SendManyServer) error {
+	return status.Errorf(codes.Unimplemented, "method SendMany not implemented")
+}
+func (*UnimplementedSMSServiceServer) GetMessageStatus(ctx context.Context, req *GetMessageStatusRequest) (*GetMessageStatusResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetMessageStatus not implemented")
+}
+func (*UnimplementedSMSServiceServer) GetTracking(ctx context.Context, req *GetTrackingRequest) (*GetTrackingResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetTracking not implemented")
+}
+func (*UnimplementedSMSServiceServer) ListConversations(ctx context.Context, req *ListConversationsRequest) (*ListConversationsResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ListConversations not implemented")
+}
+func (*UnimplementedSMSServiceServer) GetThread(ctx context.Context, req *GetThreadRequest) (*GetThreadResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetThread not implemented")
+}
+func (*UnimplementedSMSServiceServer) Subscribe(req *SubscribeRequest, srv SMSService_SubscribeServer) error {
+	return status.Errorf(codes.Unimplemented, "method Subscribe not implemented")
+}
+func (*UnimplementedSMSServiceServer) ListScheduled(ctx context.Context, req *ListScheduledRequest) (*ListScheduledResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ListScheduled not implemented")
+}
+func (*UnimplementedSMSServiceServer) CancelScheduled(ctx context.Context, req *CancelScheduledRequest) (*CancelScheduledResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method CancelScheduled not implemented")
+}
+func (*UnimplementedSMSServiceServer) CreateTemplate(ctx context.Context, req *CreateTemplateRequest) (*CreateTemplateResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method CreateTemplate not implemented")
+}
+func (*UnimplementedSMSServiceServer) GetTemplate(ctx context.Context, req *GetTemplateRequest) (*GetTemplateResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetTemplate not implemented")
+}
+func (*UnimplementedSMSServiceServer) ListTemplates(ctx context.Context, req *ListTemplatesRequest) (*ListTemplatesResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ListTemplates not implemented")
+}
+func (*UnimplementedSMSServiceServer) UpdateTemplate(ctx context.Context, req *UpdateTemplateRequest) (*UpdateTemplateResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method UpdateTemplate not implemented")
+}
+func (*UnimplementedSMSServiceServer) DeleteTemplate(ctx context.Context, req *DeleteTemplateRequest) (*DeleteTemplateResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method DeleteTemplate not implemented")
+}
+func (*UnimplementedSMSServiceServer) CreateWebhook(ctx context.Context, req *CreateWebhookRequest) (*CreateWebhookResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method CreateWebhook not implemented")
+}
+func (*UnimplementedSMSServiceServer) ListWebhooks(ctx context.Context, req *ListWebhooksRequest) (*ListWebhooksResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ListWebhooks not implemented")
+}
+func (*UnimplementedSMSServiceServer) DeleteWebhook(ctx context.Context, req *DeleteWebhookRequest) (*DeleteWebhookResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method DeleteWebhook not implemented")
+}
+func (*UnimplementedSMSServiceServer) ReplayDeliveries(ctx context.Context, req *ReplayDeliveriesRequest) (*ReplayDeliveriesResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ReplayDeliveries not implemented")
+}
+func (*UnimplementedSMSServiceServer) SetQuietHours(ctx context.Context, req *SetQuietHoursRequest) (*SetQuietHoursResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method SetQuietHours not implemented")
+}
+func (*UnimplementedSMSServiceServer) GetQuietHours(ctx context.Context, req *GetQuietHoursRequest) (*GetQuietHoursResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetQuietHours not implemented")
+}
+func (*UnimplementedSMSServiceServer) DeleteQuietHours(ctx context.Context, req *DeleteQuietHoursRequest) (*DeleteQuietHoursResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method DeleteQuietHours not implemented")
+}
+func (*UnimplementedSMSServiceServer) Receive(ctx context.Context, req *ReceiveRequest) (*ReceiveResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method Receive not implemented")
+}
+
+func RegisterSMSServiceServer(s *grpc.Server, srv SMSServiceServer) {
+	s.RegisterService(&_SMSService_serviceDesc, srv)
+}
+
+func _SMSService_SendOne_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(SendOneRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(SMSServiceServer).SendOne(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/sms.SMSService/SendOne",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(SMSServiceServer).SendOne(ctx, req.(*SendOneRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _SMSService_SendMany_Handler(srv interface{}, stream grpc.ServerStream) error {
+	return srv.(SMSServiceServer).SendMany(&sMSServiceSendManyServer{stream})
+}
+
+type SMSService_SendManyServer interface {
+	SendAndClose(*SendManyResponse) error
+	Recv() (*SendManyRequest, error)
+	grpc.ServerStream
+}
+
+type sMSServiceSendManyServer struct {
+	grpc.ServerStream
+}
+
+func (x *sMSServiceSendManyServer) SendAndClose(m *SendManyResponse) error {
+	return x.ServerStream.SendMsg(m)
+}
+
+func (x *sMSServiceSendManyServer) Recv() (*SendManyRequest, error) {
+	m := new(SendManyRequest)
+	if err := x.ServerStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+func _SMSService_GetMessageStatus_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetMessageStatusRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(SMSServiceServer).GetMessageStatus(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/sms.SMSService/GetMessageStatus",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(SMSServiceServer).GetMessageStatus(ctx, req.(*GetMessageStatusRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _SMSService_GetTracking_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetTrackingRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(SMSServiceServer).GetTracking(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/sms.SMSService/GetTracking",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(SMSServiceServer).GetTracking(ctx, req.(*GetTrackingRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _SMSService_ListConversations_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ListConversationsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(SMSServiceServer).ListConversations(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/sms.SMSService/ListConversations",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(SMSServiceServer).ListConversations(ctx, req.(*ListConversationsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _SMSService_GetThread_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetThreadRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(SMSServiceServer).GetThread(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/sms.SMSService/GetThread",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(SMSServiceServer).GetThread(ctx, req.(*GetThreadRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _SMSService_Subscribe_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(SubscribeRequest)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(SMSServiceServer).Subscribe(m, &sMSServiceSubscribeServer{stream})
+}
+
+type SMSService_SubscribeServer interface {
+	Send(*SubscribeResponse) error
+	grpc.ServerStream
 }
 
-type sMSServiceClient struct {
-	cc *grpc.ClientConn
+type sMSServiceSubscribeServer struct {
+	grpc.ServerStream
 }
 
-func NewSMSServiceClient(cc *grpc.ClientConn) SMSServiceClient {
-	return &sMSServiceClient{cc}
+func (x *sMSServiceSubscribeServer) Send(m *SubscribeResponse) error {
+	return x.ServerStream.SendMsg(m)
 }
 
-func (c *sMSServiceClient) SendOne(ctx context.Context, in *SendOneRequest, opts ...grpc.CallOption) (*SendOneResponse, error) {
-	out := new(SendOneResponse)
-	err := c.cc.Invoke(ctx, "/sms.SMSService/SendOne", in, out, opts...)
-	if err != nil {
+func _SMSService_ListScheduled_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ListScheduledRequest)
+	if err := dec(in); err != nil {
 		return nil, err
 	}
-	return out, nil
+	if interceptor == nil {
+		return srv.(SMSServiceServer).ListScheduled(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/sms.SMSService/ListScheduled",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(SMSServiceServer).ListScheduled(ctx, req.(*ListScheduledRequest))
+	}
+	return interceptor(ctx, in, info, handler)
 }
 
-func (c *sMSServiceClient) SendMany(ctx context.Context, opts ...grpc.CallOption) (SMSService_SendManyClient, error) {
-	stream, err := c.cc.NewStream(ctx, &_SMSService_serviceDesc.Streams[0], "/sms.SMSService/SendMany", opts...)
-	if err != nil {
+func _SMSService_CancelScheduled_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(CancelScheduledRequest)
+	if err := dec(in); err != nil {
 		return nil, err
 	}
-	x := &sMSServiceSendManyClient{stream}
-	return x, nil
-}
-
-type SMSService_SendManyClient interface {
-	Send(*SendManyRequest) error
-	CloseAndRecv() (*SendManyResponse, error)
-	grpc.ClientStream
+	if interceptor == nil {
+		return srv.(SMSServiceServer).CancelScheduled(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/sms.SMSService/CancelScheduled",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(SMSServiceServer).CancelScheduled(ctx, req.(*CancelScheduledRequest))
+	}
+	return interceptor(ctx, in, info, handler)
 }
 
-type sMSServiceSendManyClient struct {
-	grpc.ClientStream
+func _SMSService_CreateTemplate_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(CreateTemplateRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(SMSServiceServer).CreateTemplate(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/sms.SMSService/CreateTemplate",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(SMSServiceServer).CreateTemplate(ctx, req.(*CreateTemplateRequest))
+	}
+	return interceptor(ctx, in, info, handler)
 }
 
-func (x *sMSServiceSendManyClient) Send(m *SendManyRequest) error {
-	return x.ClientStream.SendMsg(m)
+func _SMSService_GetTemplate_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetTemplateRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(SMSServiceServer).GetTemplate(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/sms.SMSService/GetTemplate",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(SMSServiceServer).GetTemplate(ctx, req.(*GetTemplateRequest))
+	}
+	return interceptor(ctx, in, info, handler)
 }
 
-func (x *sMSServiceSendManyClient) CloseAndRecv() (*SendManyResponse, error) {
-	if err := x.ClientStream.CloseSend(); err != nil {
+func _SMSService_ListTemplates_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ListTemplatesRequest)
+	if err := dec(in); err != nil {
 		return nil, err
 	}
-	m := new(SendManyResponse)
-	if err := x.ClientStream.RecvMsg(m); err != nil {
-		return nil, err
+	if interceptor == nil {
+		return srv.(SMSServiceServer).ListTemplates(ctx, in)
 	}
-	return m, nil
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/sms.SMSService/ListTemplates",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(SMSServiceServer).ListTemplates(ctx, req.(*ListTemplatesRequest))
+	}
+	return interceptor(ctx, in, info, handler)
 }
 
-// SMSServiceServer is the server API for SMSService service.
-type SMSServiceServer interface {
-	// SendOne method sends a single sms
-	SendOne(context.Context, *SendOneRequest) (*SendOneResponse, error)
-	// SendMany method sends many SMSs in one request.
-	// It relies on calling SendOne method for each sms.
-	//
-	// For HTTP API, newline-delimited JSON is used for streaming.
-	SendMany(SMSService_SendManyServer) error
+func _SMSService_UpdateTemplate_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(UpdateTemplateRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(SMSServiceServer).UpdateTemplate(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/sms.SMSService/UpdateTemplate",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(SMSServiceServer).UpdateTemplate(ctx, req.(*UpdateTemplateRequest))
+	}
+	return interceptor(ctx, in, info, handler)
 }
 
-// UnimplementedSMSServiceServer can be embedded to have forward compatible implementations.
-type UnimplementedSMSServiceServer struct {
+func _SMSService_DeleteTemplate_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(DeleteTemplateRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(SMSServiceServer).DeleteTemplate(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/sms.SMSService/DeleteTemplate",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(SMSServiceServer).DeleteTemplate(ctx, req.(*DeleteTemplateRequest))
+	}
+	return interceptor(ctx, in, info, handler)
 }
 
-func (*UnimplementedSMSServiceServer) SendOne(ctx context.Context, req *SendOneRequest) (*SendOneResponse, error) {
-	return nil, status.Errorf(codes.Unimplemented, "method SendOne not implemented")
-}
-func (*UnimplementedSMSServiceServer) SendMany(srv SMSService_SendManyServer) error {
-	return status.Errorf(codes.Unimplemented, "method SendMany not implemented")
+func _SMSService_CreateWebhook_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(CreateWebhookRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(SMSServiceServer).CreateWebhook(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/sms.SMSService/CreateWebhook",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(SMSServiceServer).CreateWebhook(ctx, req.(*CreateWebhookRequest))
+	}
+	return interceptor(ctx, in, info, handler)
 }
 
-func RegisterSMSServiceServer(s *grpc.Server, srv SMSServiceServer) {
-	s.RegisterService(&_SMSService_serviceDesc, srv)
+func _SMSService_ListWebhooks_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ListWebhooksRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(SMSServiceServer).ListWebhooks(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/sms.SMSService/ListWebhooks",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(SMSServiceServer).ListWebhooks(ctx, req.(*ListWebhooksRequest))
+	}
+	return interceptor(ctx, in, info, handler)
 }
 
-func _SMSService_SendOne_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
-	in := new(SendOneRequest)
+func _SMSService_DeleteWebhook_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(DeleteWebhookRequest)
 	if err := dec(in); err != nil {
 		return nil, err
 	}
 	if interceptor == nil {
-		return srv.(SMSServiceServer).SendOne(ctx, in)
+		return srv.(SMSServiceServer).DeleteWebhook(ctx, in)
 	}
 	info := &grpc.UnaryServerInfo{
 		Server:     srv,
-		FullMethod: "/sms.SMSService/SendOne",
+		FullMethod: "/sms.SMSService/DeleteWebhook",
 	}
 	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
-		return srv.(SMSServiceServer).SendOne(ctx, req.(*SendOneRequest))
+		return srv.(SMSServiceServer).DeleteWebhook(ctx, req.(*DeleteWebhookRequest))
 	}
 	return interceptor(ctx, in, info, handler)
 }
 
-func _SMSService_SendMany_Handler(srv interface{}, stream grpc.ServerStream) error {
-	return srv.(SMSServiceServer).SendMany(&sMSServiceSendManyServer{stream})
+func _SMSService_ReplayDeliveries_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ReplayDeliveriesRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(SMSServiceServer).ReplayDeliveries(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/sms.SMSService/ReplayDeliveries",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(SMSServiceServer).ReplayDeliveries(ctx, req.(*ReplayDeliveriesRequest))
+	}
+	return interceptor(ctx, in, info, handler)
 }
 
-type SMSService_SendManyServer interface {
-	SendAndClose(*SendManyResponse) error
-	Recv() (*SendManyRequest, error)
-	grpc.ServerStream
+func _SMSService_SetQuietHours_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(SetQuietHoursRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(SMSServiceServer).SetQuietHours(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/sms.SMSService/SetQuietHours",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(SMSServiceServer).SetQuietHours(ctx, req.(*SetQuietHoursRequest))
+	}
+	return interceptor(ctx, in, info, handler)
 }
 
-type sMSServiceSendManyServer struct {
-	grpc.ServerStream
+func _SMSService_GetQuietHours_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetQuietHoursRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(SMSServiceServer).GetQuietHours(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/sms.SMSService/GetQuietHours",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(SMSServiceServer).GetQuietHours(ctx, req.(*GetQuietHoursRequest))
+	}
+	return interceptor(ctx, in, info, handler)
 }
 
-func (x *sMSServiceSendManyServer) SendAndClose(m *SendManyResponse) error {
-	return x.ServerStream.SendMsg(m)
+func _SMSService_DeleteQuietHours_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(DeleteQuietHoursRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(SMSServiceServer).DeleteQuietHours(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/sms.SMSService/DeleteQuietHours",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(SMSServiceServer).DeleteQuietHours(ctx, req.(*DeleteQuietHoursRequest))
+	}
+	return interceptor(ctx, in, info, handler)
 }
 
-func (x *sMSServiceSendManyServer) Recv() (*SendManyRequest, error) {
-	m := new(SendManyRequest)
-	if err := x.ServerStream.RecvMsg(m); err != nil {
+func _SMSService_Receive_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ReceiveRequest)
+	if err := dec(in); err != nil {
 		return nil, err
 	}
-	return m, nil
+	if interceptor == nil {
+		return srv.(SMSServiceServer).Receive(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/sms.SMSService/Receive",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(SMSServiceServer).Receive(ctx, req.(*ReceiveRequest))
+	}
+	return interceptor(ctx, in, info, handler)
 }
 
 var _SMSService_serviceDesc = grpc.ServiceDesc{
@@ -451,6 +4455,82 @@ var _SMSService_serviceDesc = grpc.ServiceDesc{
 			MethodName: "SendOne",
 			Handler:    _SMSService_SendOne_Handler,
 		},
+		{
+			MethodName: "GetMessageStatus",
+			Handler:    _SMSService_GetMessageStatus_Handler,
+		},
+		{
+			MethodName: "GetTracking",
+			Handler:    _SMSService_GetTracking_Handler,
+		},
+		{
+			MethodName: "ListConversations",
+			Handler:    _SMSService_ListConversations_Handler,
+		},
+		{
+			MethodName: "GetThread",
+			Handler:    _SMSService_GetThread_Handler,
+		},
+		{
+			MethodName: "ListScheduled",
+			Handler:    _SMSService_ListScheduled_Handler,
+		},
+		{
+			MethodName: "CancelScheduled",
+			Handler:    _SMSService_CancelScheduled_Handler,
+		},
+		{
+			MethodName: "CreateTemplate",
+			Handler:    _SMSService_CreateTemplate_Handler,
+		},
+		{
+			MethodName: "GetTemplate",
+			Handler:    _SMSService_GetTemplate_Handler,
+		},
+		{
+			MethodName: "ListTemplates",
+			Handler:    _SMSService_ListTemplates_Handler,
+		},
+		{
+			MethodName: "UpdateTemplate",
+			Handler:    _SMSService_UpdateTemplate_Handler,
+		},
+		{
+			MethodName: "DeleteTemplate",
+			Handler:    _SMSService_DeleteTemplate_Handler,
+		},
+		{
+			MethodName: "CreateWebhook",
+			Handler:    _SMSService_CreateWebhook_Handler,
+		},
+		{
+			MethodName: "ListWebhooks",
+			Handler:    _SMSService_ListWebhooks_Handler,
+		},
+		{
+			MethodName: "DeleteWebhook",
+			Handler:    _SMSService_DeleteWebhook_Handler,
+		},
+		{
+			MethodName: "ReplayDeliveries",
+			Handler:    _SMSService_ReplayDeliveries_Handler,
+		},
+		{
+			MethodName: "SetQuietHours",
+			Handler:    _SMSService_SetQuietHours_Handler,
+		},
+		{
+			MethodName: "GetQuietHours",
+			Handler:    _SMSService_GetQuietHours_Handler,
+		},
+		{
+			MethodName: "DeleteQuietHours",
+			Handler:    _SMSService_DeleteQuietHours_Handler,
+		},
+		{
+			MethodName: "Receive",
+			Handler:    _SMSService_Receive_Handler,
+		},
 	},
 	Streams: []grpc.StreamDesc{
 		{
@@ -458,6 +4538,11 @@ var _SMSService_serviceDesc = grpc.ServiceDesc{
 			Handler:       _SMSService_SendMany_Handler,
 			ClientStreams: true,
 		},
+		{
+			StreamName:    "Subscribe",
+			Handler:       _SMSService_Subscribe_Handler,
+			ServerStreams: true,
+		},
 	},
 	Metadata: "sms.proto",
 }
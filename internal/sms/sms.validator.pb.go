@@ -3,7 +3,14 @@
 
 // SMS Service
 //
-// SMS Service API consists of 2 services to send a single and multiple SMSs
+// SMS Service API consists of 2 services to send a single and multiple SMSs,
+// services to get the status of a sent SMS, and the progress of SendMany,
+// services to read the conversations of a phone number,
+// and a stream to subscribe to the new messages of a phone number.
+// An SMS can be scheduled to be sent later, and canceled until then.
+// An SMS can be rendered from a template, and there are services to manage the templates.
+// An account can subscribe webhooks to be told about the new messages and status changes.
+// An account can have quiet hours, where its SMSs are held until the recipients' morning.
 // This service is Idempotent:
 //  It is safe to retry sending the same SMS and will be processed only once.
 //  The client has to attach idempotency key with every single sms.
@@ -15,7 +22,9 @@ import (
 	math "math"
 	proto "github.com/golang/protobuf/proto"
 	_ "google.golang.org/genproto/googleapis/api/annotations"
+	_ "github.com/golang/protobuf/ptypes/timestamp"
 	_ "github.com/mwitkow/go-proto-validators"
+	regexp "regexp"
 	github_com_mwitkow_go_proto_validators "github.com/mwitkow/go-proto-validators"
 )
 
@@ -34,9 +43,12 @@ func (this *SMS) Validate() error {
 	if this.ToPhoneNumber == "" {
 		return github_com_mwitkow_go_proto_validators.FieldError("ToPhoneNumber", fmt.Errorf(`value '%v' must not be an empty string`, this.ToPhoneNumber))
 	}
-	if this.Content == "" {
-		return github_com_mwitkow_go_proto_validators.FieldError("Content", fmt.Errorf(`value '%v' must not be an empty string`, this.Content))
+	if this.SendAt != nil {
+		if err := github_com_mwitkow_go_proto_validators.CallValidatorIfExists(this.SendAt); err != nil {
+			return github_com_mwitkow_go_proto_validators.FieldError("SendAt", err)
+		}
 	}
+	// Validation of proto3 map<> fields is unsupported.
 	return nil
 }
 func (this *SendOneRequest) Validate() error {
@@ -48,6 +60,31 @@ func (this *SendOneRequest) Validate() error {
 	return nil
 }
 func (this *SendOneResponse) Validate() error {
+	for _, item := range this.Substitutions {
+		if item != nil {
+			if err := github_com_mwitkow_go_proto_validators.CallValidatorIfExists(item); err != nil {
+				return github_com_mwitkow_go_proto_validators.FieldError("Substitutions", err)
+			}
+		}
+	}
+	for _, item := range this.FilterDecisions {
+		if item != nil {
+			if err := github_com_mwitkow_go_proto_validators.CallValidatorIfExists(item); err != nil {
+				return github_com_mwitkow_go_proto_validators.FieldError("FilterDecisions", err)
+			}
+		}
+	}
+	if this.SendAt != nil {
+		if err := github_com_mwitkow_go_proto_validators.CallValidatorIfExists(this.SendAt); err != nil {
+			return github_com_mwitkow_go_proto_validators.FieldError("SendAt", err)
+		}
+	}
+	return nil
+}
+func (this *Substitution) Validate() error {
+	return nil
+}
+func (this *FilterDecision) Validate() error {
 	return nil
 }
 func (this *SendManyRequest) Validate() error {
@@ -61,3 +98,443 @@ func (this *SendManyRequest) Validate() error {
 func (this *SendManyResponse) Validate() error {
 	return nil
 }
+func (this *GetMessageStatusRequest) Validate() error {
+	if this.MessageId == "" {
+		return github_com_mwitkow_go_proto_validators.FieldError("MessageId", fmt.Errorf(`value '%v' must not be an empty string`, this.MessageId))
+	}
+	return nil
+}
+func (this *GetMessageStatusResponse) Validate() error {
+	if this.CreatedAt != nil {
+		if err := github_com_mwitkow_go_proto_validators.CallValidatorIfExists(this.CreatedAt); err != nil {
+			return github_com_mwitkow_go_proto_validators.FieldError("CreatedAt", err)
+		}
+	}
+	if this.UpdatedAt != nil {
+		if err := github_com_mwitkow_go_proto_validators.CallValidatorIfExists(this.UpdatedAt); err != nil {
+			return github_com_mwitkow_go_proto_validators.FieldError("UpdatedAt", err)
+		}
+	}
+	return nil
+}
+func (this *GetTrackingRequest) Validate() error {
+	if this.TrackingId == "" {
+		return github_com_mwitkow_go_proto_validators.FieldError("TrackingId", fmt.Errorf(`value '%v' must not be an empty string`, this.TrackingId))
+	}
+	return nil
+}
+func (this *TrackedMessage) Validate() error {
+	return nil
+}
+func (this *GetTrackingResponse) Validate() error {
+	for _, item := range this.Messages {
+		if item != nil {
+			if err := github_com_mwitkow_go_proto_validators.CallValidatorIfExists(item); err != nil {
+				return github_com_mwitkow_go_proto_validators.FieldError("Messages", err)
+			}
+		}
+	}
+	if this.CreatedAt != nil {
+		if err := github_com_mwitkow_go_proto_validators.CallValidatorIfExists(this.CreatedAt); err != nil {
+			return github_com_mwitkow_go_proto_validators.FieldError("CreatedAt", err)
+		}
+	}
+	if this.UpdatedAt != nil {
+		if err := github_com_mwitkow_go_proto_validators.CallValidatorIfExists(this.UpdatedAt); err != nil {
+			return github_com_mwitkow_go_proto_validators.FieldError("UpdatedAt", err)
+		}
+	}
+	return nil
+}
+func (this *Message) Validate() error {
+	if this.CreatedAt != nil {
+		if err := github_com_mwitkow_go_proto_validators.CallValidatorIfExists(this.CreatedAt); err != nil {
+			return github_com_mwitkow_go_proto_validators.FieldError("CreatedAt", err)
+		}
+	}
+	if this.SendAt != nil {
+		if err := github_com_mwitkow_go_proto_validators.CallValidatorIfExists(this.SendAt); err != nil {
+			return github_com_mwitkow_go_proto_validators.FieldError("SendAt", err)
+		}
+	}
+	return nil
+}
+func (this *ListConversationsRequest) Validate() error {
+	if this.PhoneNumber == "" {
+		return github_com_mwitkow_go_proto_validators.FieldError("PhoneNumber", fmt.Errorf(`value '%v' must not be an empty string`, this.PhoneNumber))
+	}
+	if !(this.PageSize > -1) {
+		return github_com_mwitkow_go_proto_validators.FieldError("PageSize", fmt.Errorf(`value '%v' must be greater than '-1'`, this.PageSize))
+	}
+	if !(this.PageSize < 101) {
+		return github_com_mwitkow_go_proto_validators.FieldError("PageSize", fmt.Errorf(`value '%v' must be less than '101'`, this.PageSize))
+	}
+	return nil
+}
+func (this *Conversation) Validate() error {
+	if this.LastMessage != nil {
+		if err := github_com_mwitkow_go_proto_validators.CallValidatorIfExists(this.LastMessage); err != nil {
+			return github_com_mwitkow_go_proto_validators.FieldError("LastMessage", err)
+		}
+	}
+	return nil
+}
+func (this *ListConversationsResponse) Validate() error {
+	for _, item := range this.Conversations {
+		if item != nil {
+			if err := github_com_mwitkow_go_proto_validators.CallValidatorIfExists(item); err != nil {
+				return github_com_mwitkow_go_proto_validators.FieldError("Conversations", err)
+			}
+		}
+	}
+	return nil
+}
+func (this *GetThreadRequest) Validate() error {
+	if this.PhoneNumber == "" {
+		return github_com_mwitkow_go_proto_validators.FieldError("PhoneNumber", fmt.Errorf(`value '%v' must not be an empty string`, this.PhoneNumber))
+	}
+	if this.OtherPhoneNumber == "" {
+		return github_com_mwitkow_go_proto_validators.FieldError("OtherPhoneNumber", fmt.Errorf(`value '%v' must not be an empty string`, this.OtherPhoneNumber))
+	}
+	if !(this.PageSize > -1) {
+		return github_com_mwitkow_go_proto_validators.FieldError("PageSize", fmt.Errorf(`value '%v' must be greater than '-1'`, this.PageSize))
+	}
+	if !(this.PageSize < 101) {
+		return github_com_mwitkow_go_proto_validators.FieldError("PageSize", fmt.Errorf(`value '%v' must be less than '101'`, this.PageSize))
+	}
+	return nil
+}
+func (this *GetThreadResponse) Validate() error {
+	for _, item := range this.Messages {
+		if item != nil {
+			if err := github_com_mwitkow_go_proto_validators.CallValidatorIfExists(item); err != nil {
+				return github_com_mwitkow_go_proto_validators.FieldError("Messages", err)
+			}
+		}
+	}
+	return nil
+}
+func (this *SubscribeRequest) Validate() error {
+	if this.PhoneNumber == "" {
+		return github_com_mwitkow_go_proto_validators.FieldError("PhoneNumber", fmt.Errorf(`value '%v' must not be an empty string`, this.PhoneNumber))
+	}
+	return nil
+}
+func (this *SubscribeResponse) Validate() error {
+	if this.Message != nil {
+		if err := github_com_mwitkow_go_proto_validators.CallValidatorIfExists(this.Message); err != nil {
+			return github_com_mwitkow_go_proto_validators.FieldError("Message", err)
+		}
+	}
+	return nil
+}
+func (this *ListScheduledRequest) Validate() error {
+	if this.PhoneNumber == "" {
+		return github_com_mwitkow_go_proto_validators.FieldError("PhoneNumber", fmt.Errorf(`value '%v' must not be an empty string`, this.PhoneNumber))
+	}
+	if !(this.PageSize > -1) {
+		return github_com_mwitkow_go_proto_validators.FieldError("PageSize", fmt.Errorf(`value '%v' must be greater than '-1'`, this.PageSize))
+	}
+	if !(this.PageSize < 101) {
+		return github_com_mwitkow_go_proto_validators.FieldError("PageSize", fmt.Errorf(`value '%v' must be less than '101'`, this.PageSize))
+	}
+	return nil
+}
+func (this *ListScheduledResponse) Validate() error {
+	for _, item := range this.Messages {
+		if item != nil {
+			if err := github_com_mwitkow_go_proto_validators.CallValidatorIfExists(item); err != nil {
+				return github_com_mwitkow_go_proto_validators.FieldError("Messages", err)
+			}
+		}
+	}
+	return nil
+}
+func (this *CancelScheduledRequest) Validate() error {
+	if this.MessageId == "" {
+		return github_com_mwitkow_go_proto_validators.FieldError("MessageId", fmt.Errorf(`value '%v' must not be an empty string`, this.MessageId))
+	}
+	return nil
+}
+func (this *CancelScheduledResponse) Validate() error {
+	return nil
+}
+
+var _regex_Placeholder_Name = regexp.MustCompile(`^[A-Za-z0-9_]+$`)
+
+func (this *Placeholder) Validate() error {
+	if !_regex_Placeholder_Name.MatchString(this.Name) {
+		return github_com_mwitkow_go_proto_validators.FieldError("Name", fmt.Errorf(`value '%v' must be a string conforming to regex "^[A-Za-z0-9_]+$"`, this.Name))
+	}
+	return nil
+}
+func (this *Template) Validate() error {
+	for _, item := range this.Placeholders {
+		if item != nil {
+			if err := github_com_mwitkow_go_proto_validators.CallValidatorIfExists(item); err != nil {
+				return github_com_mwitkow_go_proto_validators.FieldError("Placeholders", err)
+			}
+		}
+	}
+	if this.CreatedAt != nil {
+		if err := github_com_mwitkow_go_proto_validators.CallValidatorIfExists(this.CreatedAt); err != nil {
+			return github_com_mwitkow_go_proto_validators.FieldError("CreatedAt", err)
+		}
+	}
+	if this.UpdatedAt != nil {
+		if err := github_com_mwitkow_go_proto_validators.CallValidatorIfExists(this.UpdatedAt); err != nil {
+			return github_com_mwitkow_go_proto_validators.FieldError("UpdatedAt", err)
+		}
+	}
+	return nil
+}
+func (this *CreateTemplateRequest) Validate() error {
+	if this.Name == "" {
+		return github_com_mwitkow_go_proto_validators.FieldError("Name", fmt.Errorf(`value '%v' must not be an empty string`, this.Name))
+	}
+	if this.Content == "" {
+		return github_com_mwitkow_go_proto_validators.FieldError("Content", fmt.Errorf(`value '%v' must not be an empty string`, this.Content))
+	}
+	for _, item := range this.Placeholders {
+		if item != nil {
+			if err := github_com_mwitkow_go_proto_validators.CallValidatorIfExists(item); err != nil {
+				return github_com_mwitkow_go_proto_validators.FieldError("Placeholders", err)
+			}
+		}
+	}
+	if !(this.UserId > 0) {
+		return github_com_mwitkow_go_proto_validators.FieldError("UserId", fmt.Errorf(`value '%v' must be greater than '0'`, this.UserId))
+	}
+	return nil
+}
+func (this *CreateTemplateResponse) Validate() error {
+	if this.Template != nil {
+		if err := github_com_mwitkow_go_proto_validators.CallValidatorIfExists(this.Template); err != nil {
+			return github_com_mwitkow_go_proto_validators.FieldError("Template", err)
+		}
+	}
+	return nil
+}
+func (this *GetTemplateRequest) Validate() error {
+	if this.TemplateId == "" {
+		return github_com_mwitkow_go_proto_validators.FieldError("TemplateId", fmt.Errorf(`value '%v' must not be an empty string`, this.TemplateId))
+	}
+	return nil
+}
+func (this *GetTemplateResponse) Validate() error {
+	if this.Template != nil {
+		if err := github_com_mwitkow_go_proto_validators.CallValidatorIfExists(this.Template); err != nil {
+			return github_com_mwitkow_go_proto_validators.FieldError("Template", err)
+		}
+	}
+	return nil
+}
+func (this *ListTemplatesRequest) Validate() error {
+	if !(this.PageSize > -1) {
+		return github_com_mwitkow_go_proto_validators.FieldError("PageSize", fmt.Errorf(`value '%v' must be greater than '-1'`, this.PageSize))
+	}
+	if !(this.PageSize < 101) {
+		return github_com_mwitkow_go_proto_validators.FieldError("PageSize", fmt.Errorf(`value '%v' must be less than '101'`, this.PageSize))
+	}
+	if !(this.UserId > 0) {
+		return github_com_mwitkow_go_proto_validators.FieldError("UserId", fmt.Errorf(`value '%v' must be greater than '0'`, this.UserId))
+	}
+	return nil
+}
+func (this *ListTemplatesResponse) Validate() error {
+	for _, item := range this.Templates {
+		if item != nil {
+			if err := github_com_mwitkow_go_proto_validators.CallValidatorIfExists(item); err != nil {
+				return github_com_mwitkow_go_proto_validators.FieldError("Templates", err)
+			}
+		}
+	}
+	return nil
+}
+func (this *UpdateTemplateRequest) Validate() error {
+	if this.TemplateId == "" {
+		return github_com_mwitkow_go_proto_validators.FieldError("TemplateId", fmt.Errorf(`value '%v' must not be an empty string`, this.TemplateId))
+	}
+	if this.Name == "" {
+		return github_com_mwitkow_go_proto_validators.FieldError("Name", fmt.Errorf(`value '%v' must not be an empty string`, this.Name))
+	}
+	if this.Content == "" {
+		return github_com_mwitkow_go_proto_validators.FieldError("Content", fmt.Errorf(`value '%v' must not be an empty string`, this.Content))
+	}
+	for _, item := range this.Placeholders {
+		if item != nil {
+			if err := github_com_mwitkow_go_proto_validators.CallValidatorIfExists(item); err != nil {
+				return github_com_mwitkow_go_proto_validators.FieldError("Placeholders", err)
+			}
+		}
+	}
+	return nil
+}
+func (this *UpdateTemplateResponse) Validate() error {
+	if this.Template != nil {
+		if err := github_com_mwitkow_go_proto_validators.CallValidatorIfExists(this.Template); err != nil {
+			return github_com_mwitkow_go_proto_validators.FieldError("Template", err)
+		}
+	}
+	return nil
+}
+func (this *DeleteTemplateRequest) Validate() error {
+	if this.TemplateId == "" {
+		return github_com_mwitkow_go_proto_validators.FieldError("TemplateId", fmt.Errorf(`value '%v' must not be an empty string`, this.TemplateId))
+	}
+	return nil
+}
+func (this *DeleteTemplateResponse) Validate() error {
+	return nil
+}
+func (this *Webhook) Validate() error {
+	if this.CreatedAt != nil {
+		if err := github_com_mwitkow_go_proto_validators.CallValidatorIfExists(this.CreatedAt); err != nil {
+			return github_com_mwitkow_go_proto_validators.FieldError("CreatedAt", err)
+		}
+	}
+	return nil
+}
+func (this *WebhookPayload) Validate() error {
+	if this.Message != nil {
+		if err := github_com_mwitkow_go_proto_validators.CallValidatorIfExists(this.Message); err != nil {
+			return github_com_mwitkow_go_proto_validators.FieldError("Message", err)
+		}
+	}
+	if this.CreatedAt != nil {
+		if err := github_com_mwitkow_go_proto_validators.CallValidatorIfExists(this.CreatedAt); err != nil {
+			return github_com_mwitkow_go_proto_validators.FieldError("CreatedAt", err)
+		}
+	}
+	return nil
+}
+
+var _regex_CreateWebhookRequest_Url = regexp.MustCompile(`^https?://.+`)
+
+func (this *CreateWebhookRequest) Validate() error {
+	if !(this.UserId > 0) {
+		return github_com_mwitkow_go_proto_validators.FieldError("UserId", fmt.Errorf(`value '%v' must be greater than '0'`, this.UserId))
+	}
+	if !_regex_CreateWebhookRequest_Url.MatchString(this.Url) {
+		return github_com_mwitkow_go_proto_validators.FieldError("Url", fmt.Errorf(`value '%v' must be a string conforming to regex "^https?://.+"`, this.Url))
+	}
+	if len(this.EventTypes) < 1 {
+		return github_com_mwitkow_go_proto_validators.FieldError("EventTypes", fmt.Errorf(`value '%v' must contain at least 1 elements`, this.EventTypes))
+	}
+	return nil
+}
+func (this *CreateWebhookResponse) Validate() error {
+	if this.Webhook != nil {
+		if err := github_com_mwitkow_go_proto_validators.CallValidatorIfExists(this.Webhook); err != nil {
+			return github_com_mwitkow_go_proto_validators.FieldError("Webhook", err)
+		}
+	}
+	return nil
+}
+func (this *ListWebhooksRequest) Validate() error {
+	if !(this.UserId > 0) {
+		return github_com_mwitkow_go_proto_validators.FieldError("UserId", fmt.Errorf(`value '%v' must be greater than '0'`, this.UserId))
+	}
+	return nil
+}
+func (this *ListWebhooksResponse) Validate() error {
+	for _, item := range this.Webhooks {
+		if item != nil {
+			if err := github_com_mwitkow_go_proto_validators.CallValidatorIfExists(item); err != nil {
+				return github_com_mwitkow_go_proto_validators.FieldError("Webhooks", err)
+			}
+		}
+	}
+	return nil
+}
+func (this *DeleteWebhookRequest) Validate() error {
+	if this.WebhookId == "" {
+		return github_com_mwitkow_go_proto_validators.FieldError("WebhookId", fmt.Errorf(`value '%v' must not be an empty string`, this.WebhookId))
+	}
+	return nil
+}
+func (this *DeleteWebhookResponse) Validate() error {
+	return nil
+}
+func (this *ReplayDeliveriesRequest) Validate() error {
+	if this.WebhookId == "" {
+		return github_com_mwitkow_go_proto_validators.FieldError("WebhookId", fmt.Errorf(`value '%v' must not be an empty string`, this.WebhookId))
+	}
+	return nil
+}
+func (this *ReplayDeliveriesResponse) Validate() error {
+	return nil
+}
+func (this *QuietHours) Validate() error {
+	if this.UpdatedAt != nil {
+		if err := github_com_mwitkow_go_proto_validators.CallValidatorIfExists(this.UpdatedAt); err != nil {
+			return github_com_mwitkow_go_proto_validators.FieldError("UpdatedAt", err)
+		}
+	}
+	return nil
+}
+
+var _regex_SetQuietHoursRequest_Start = regexp.MustCompile(`^([01][0-9]|2[0-3]):[0-5][0-9]$`)
+var _regex_SetQuietHoursRequest_End = regexp.MustCompile(`^([01][0-9]|2[0-3]):[0-5][0-9]$`)
+
+func (this *SetQuietHoursRequest) Validate() error {
+	if !(this.UserId > 0) {
+		return github_com_mwitkow_go_proto_validators.FieldError("UserId", fmt.Errorf(`value '%v' must be greater than '0'`, this.UserId))
+	}
+	if !_regex_SetQuietHoursRequest_Start.MatchString(this.Start) {
+		return github_com_mwitkow_go_proto_validators.FieldError("Start", fmt.Errorf(`value '%v' must be a string conforming to regex "^([01][0-9]|2[0-3]):[0-5][0-9]$"`, this.Start))
+	}
+	if !_regex_SetQuietHoursRequest_End.MatchString(this.End) {
+		return github_com_mwitkow_go_proto_validators.FieldError("End", fmt.Errorf(`value '%v' must be a string conforming to regex "^([01][0-9]|2[0-3]):[0-5][0-9]$"`, this.End))
+	}
+	return nil
+}
+func (this *SetQuietHoursResponse) Validate() error {
+	if this.QuietHours != nil {
+		if err := github_com_mwitkow_go_proto_validators.CallValidatorIfExists(this.QuietHours); err != nil {
+			return github_com_mwitkow_go_proto_validators.FieldError("QuietHours", err)
+		}
+	}
+	return nil
+}
+func (this *GetQuietHoursRequest) Validate() error {
+	if !(this.UserId > 0) {
+		return github_com_mwitkow_go_proto_validators.FieldError("UserId", fmt.Errorf(`value '%v' must be greater than '0'`, this.UserId))
+	}
+	return nil
+}
+func (this *GetQuietHoursResponse) Validate() error {
+	if this.QuietHours != nil {
+		if err := github_com_mwitkow_go_proto_validators.CallValidatorIfExists(this.QuietHours); err != nil {
+			return github_com_mwitkow_go_proto_validators.FieldError("QuietHours", err)
+		}
+	}
+	return nil
+}
+func (this *DeleteQuietHoursRequest) Validate() error {
+	if !(this.UserId > 0) {
+		return github_com_mwitkow_go_proto_validators.FieldError("UserId", fmt.Errorf(`value '%v' must be greater than '0'`, this.UserId))
+	}
+	return nil
+}
+func (this *DeleteQuietHoursResponse) Validate() error {
+	return nil
+}
+func (this *ReceiveRequest) Validate() error {
+	if this.FromPhoneNumber == "" {
+		return github_com_mwitkow_go_proto_validators.FieldError("FromPhoneNumber", fmt.Errorf(`value '%v' must not be an empty string`, this.FromPhoneNumber))
+	}
+	if this.ToPhoneNumber == "" {
+		return github_com_mwitkow_go_proto_validators.FieldError("ToPhoneNumber", fmt.Errorf(`value '%v' must not be an empty string`, this.ToPhoneNumber))
+	}
+	if this.Content == "" {
+		return github_com_mwitkow_go_proto_validators.FieldError("Content", fmt.Errorf(`value '%v' must not be an empty string`, this.Content))
+	}
+	if this.Carrier == "" {
+		return github_com_mwitkow_go_proto_validators.FieldError("Carrier", fmt.Errorf(`value '%v' must not be an empty string`, this.Carrier))
+	}
+	return nil
+}
+func (this *ReceiveResponse) Validate() error {
+	return nil
+}
@@ -0,0 +1,338 @@
+package sms
+
+import (
+	context "context"
+	"math"
+	"regexp"
+	"strconv"
+	"time"
+
+	"github.com/OmarElGabry/go-textnow/internal/pkg/cursor"
+	"github.com/OmarElGabry/go-textnow/internal/pkg/mongodb"
+
+	"github.com/golang/protobuf/ptypes"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+const defaultTemplatesPageSize = 20
+
+// placeholderPattern matches a placeholder in the content of a template, i.e. "{{name}}"
+var placeholderPattern = regexp.MustCompile(`{{\s*([A-Za-z0-9_]+)\s*}}`)
+
+// template is a document in the "templates" collection, of an account (user).
+// The type of a placeholder is stored by its name, i.e. "NUMBER".
+type template struct {
+	ID           primitive.ObjectID `bson:"_id"`
+	UserID       int32              `bson:"userId"`
+	Name         string             `bson:"name"`
+	Content      string             `bson:"content"`
+	Placeholders []placeholder      `bson:"placeholders"`
+	CreatedAt    time.Time          `bson:"createdAt"`
+	UpdatedAt    time.Time          `bson:"updatedAt"`
+}
+
+type placeholder struct {
+	Name string `bson:"name"`
+	Type string `bson:"type"`
+}
+
+// templatesPosition is the position of the last template in a page.
+// Templates are ordered by their name, which is unique within the account.
+type templatesPosition struct {
+	Name string `json:"name"`
+}
+
+// CreateTemplate method creates a template to send SMSs from
+func (s *server) CreateTemplate(ctx context.Context, req *CreateTemplateRequest) (*CreateTemplateResponse, error) {
+	placeholders, err := validateTemplate(req.GetContent(), req.GetPlaceholders())
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now().UTC()
+	t := &template{
+		ID:           primitive.NewObjectID(),
+		UserID:       req.GetUserId(),
+		Name:         req.GetName(),
+		Content:      req.GetContent(),
+		Placeholders: placeholders,
+		CreatedAt:    now,
+		UpdatedAt:    now,
+	}
+
+	if _, err := s.templates.InsertOne(ctx, t); mongodb.IsDuplicateKey(err) {
+		return nil, status.Error(codes.AlreadyExists, "Template name is taken")
+	} else if err != nil {
+		return nil, status.Error(codes.Internal, "Internal error "+err.Error())
+	}
+
+	return &CreateTemplateResponse{Template: t.toProto()}, nil
+}
+
+// GetTemplate method gets a template by its id
+func (s *server) GetTemplate(ctx context.Context, req *GetTemplateRequest) (*GetTemplateResponse, error) {
+	t, err := s.findTemplate(ctx, req.GetTemplateId())
+	if err != nil {
+		return nil, err
+	}
+
+	return &GetTemplateResponse{Template: t.toProto()}, nil
+}
+
+// ListTemplates method lists the templates of an account by their name, page by page
+func (s *server) ListTemplates(ctx context.Context, req *ListTemplatesRequest) (*ListTemplatesResponse, error) {
+	pageSize := int(req.GetPageSize())
+	if pageSize == 0 {
+		pageSize = defaultTemplatesPageSize
+	}
+
+	// start after the position of the cursor (if any)
+	filter := bson.M{"userId": req.GetUserId()}
+	if req.GetCursor() != "" {
+		var position templatesPosition
+		if err := cursor.Decode(req.GetCursor(), &position); err != nil {
+			return nil, status.Error(codes.InvalidArgument, "Invalid cursor")
+		}
+
+		filter["name"] = bson.M{"$gt": position.Name}
+	}
+
+	// query one more template than the page size to know if there is a next page
+	opts := options.Find().SetSort(bson.M{"name": 1}).SetLimit(int64(pageSize + 1))
+
+	cur, err := s.templates.Find(ctx, filter, opts)
+	if err != nil {
+		return nil, status.Error(codes.Internal, "Internal error "+err.Error())
+	}
+	defer cur.Close(ctx)
+
+	templates := []*Template{}
+	for cur.Next(ctx) {
+		var t template
+		if err := cur.Decode(&t); err != nil {
+			return nil, status.Error(codes.Internal, "Internal error "+err.Error())
+		}
+
+		templates = append(templates, t.toProto())
+	}
+
+	if err := cur.Err(); err != nil {
+		return nil, status.Error(codes.Internal, "Internal error "+err.Error())
+	}
+
+	nextCursor := ""
+	if len(templates) > pageSize {
+		templates = templates[:pageSize]
+		if nextCursor, err = cursor.Encode(templatesPosition{Name: templates[pageSize-1].Name}); err != nil {
+			return nil, status.Error(codes.Internal, "Internal error "+err.Error())
+		}
+	}
+
+	return &ListTemplatesResponse{Templates: templates, NextCursor: nextCursor}, nil
+}
+
+// UpdateTemplate method replaces the name, content and placeholders of a template
+func (s *server) UpdateTemplate(ctx context.Context, req *UpdateTemplateRequest) (*UpdateTemplateResponse, error) {
+	id, err := primitive.ObjectIDFromHex(req.GetTemplateId())
+	if err != nil {
+		return nil, status.Error(codes.InvalidArgument, "Invalid template id")
+	}
+
+	placeholders, err := validateTemplate(req.GetContent(), req.GetPlaceholders())
+	if err != nil {
+		return nil, err
+	}
+
+	var t template
+	err = s.templates.FindOneAndUpdate(ctx, bson.M{"_id": id}, bson.M{"$set": bson.M{
+		"name":         req.GetName(),
+		"content":      req.GetContent(),
+		"placeholders": placeholders,
+		"updatedAt":    time.Now().UTC(),
+	}}, options.FindOneAndUpdate().SetReturnDocument(options.After)).Decode(&t)
+
+	if err == mongo.ErrNoDocuments {
+		return nil, status.Error(codes.NotFound, "Template doesn't exist")
+	}
+
+	if mongodb.IsDuplicateKey(err) {
+		return nil, status.Error(codes.AlreadyExists, "Template name is taken")
+	}
+
+	if err != nil {
+		return nil, status.Error(codes.Internal, "Internal error "+err.Error())
+	}
+
+	return &UpdateTemplateResponse{Template: t.toProto()}, nil
+}
+
+// DeleteTemplate method deletes a template
+func (s *server) DeleteTemplate(ctx context.Context, req *DeleteTemplateRequest) (*DeleteTemplateResponse, error) {
+	id, err := primitive.ObjectIDFromHex(req.GetTemplateId())
+	if err != nil {
+		return nil, status.Error(codes.InvalidArgument, "Invalid template id")
+	}
+
+	res, err := s.templates.DeleteOne(ctx, bson.M{"_id": id})
+	if err != nil {
+		return nil, status.Error(codes.Internal, "Internal error "+err.Error())
+	}
+
+	if res.DeletedCount == 0 {
+		return nil, status.Error(codes.NotFound, "Template doesn't exist")
+	}
+
+	return &DeleteTemplateResponse{Deleted: true}, nil
+}
+
+// renderContent returns the content of an sms: either its content,
+// or the content rendered from its template and variables.
+//
+// Every placeholder must have a variable of its type, and every variable must have a placeholder.
+// Otherwise, it is an InvalidArgument error.
+func (s *server) renderContent(ctx context.Context, sms *SMS) (string, error) {
+	if sms.GetTemplateId() == "" {
+		if sms.GetContent() == "" {
+			return "", status.Error(codes.InvalidArgument, "Either content or template_id is required")
+		}
+
+		return sms.GetContent(), nil
+	}
+
+	if sms.GetContent() != "" {
+		return "", status.Error(codes.InvalidArgument, "Content can't be set along with template_id")
+	}
+
+	t, err := s.findTemplate(ctx, sms.GetTemplateId())
+	if err != nil {
+		return "", err
+	}
+
+	variables := sms.GetVariables()
+	for _, p := range t.Placeholders {
+		value, ok := variables[p.Name]
+		if !ok {
+			return "", status.Errorf(codes.InvalidArgument, "Missing variable %q", p.Name)
+		}
+
+		if !validValue(p.Type, value) {
+			return "", status.Errorf(codes.InvalidArgument, "Variable %q must be a %s", p.Name, p.Type)
+		}
+	}
+
+	if len(variables) > len(t.Placeholders) {
+		for name := range variables {
+			if !t.hasPlaceholder(name) {
+				return "", status.Errorf(codes.InvalidArgument, "Unknown variable %q", name)
+			}
+		}
+	}
+
+	return placeholderPattern.ReplaceAllStringFunc(t.Content, func(match string) string {
+		return variables[placeholderPattern.FindStringSubmatch(match)[1]]
+	}), nil
+}
+
+// findTemplate finds a template by its id
+func (s *server) findTemplate(ctx context.Context, templateID string) (*template, error) {
+	id, err := primitive.ObjectIDFromHex(templateID)
+	if err != nil {
+		return nil, status.Error(codes.InvalidArgument, "Invalid template id")
+	}
+
+	var t template
+	err = s.templates.FindOne(ctx, bson.M{"_id": id}).Decode(&t)
+	if err == mongo.ErrNoDocuments {
+		return nil, status.Error(codes.NotFound, "Template doesn't exist")
+	}
+
+	if err != nil {
+		return nil, status.Error(codes.Internal, "Internal error "+err.Error())
+	}
+
+	return &t, nil
+}
+
+// validateTemplate checks that the placeholders in the content are the declared ones,
+// and returns them as they are stored
+func validateTemplate(content string, declared []*Placeholder) ([]placeholder, error) {
+	placeholders := make([]placeholder, len(declared))
+	used := map[string]bool{}
+
+	for _, match := range placeholderPattern.FindAllStringSubmatch(content, -1) {
+		used[match[1]] = true
+	}
+
+	for i, p := range declared {
+		for _, other := range placeholders[:i] {
+			if other.Name == p.GetName() {
+				return nil, status.Errorf(codes.InvalidArgument, "Placeholder %q is declared twice", p.GetName())
+			}
+		}
+
+		if !used[p.GetName()] {
+			return nil, status.Errorf(codes.InvalidArgument, "Placeholder %q isn't used in the content", p.GetName())
+		}
+
+		placeholders[i] = placeholder{Name: p.GetName(), Type: p.GetType().String()}
+		delete(used, p.GetName())
+	}
+
+	for name := range used {
+		return nil, status.Errorf(codes.InvalidArgument, "Placeholder %q isn't declared", name)
+	}
+
+	return placeholders, nil
+}
+
+// validValue checks if the value of a variable is of the given placeholder type
+func validValue(placeholderType string, value string) bool {
+	switch placeholderType {
+	case Placeholder_NUMBER.String():
+		// NaN and Inf are parsed too, but aren't numbers to be sent
+		f, err := strconv.ParseFloat(value, 64)
+		return err == nil && !math.IsNaN(f) && !math.IsInf(f, 0)
+	case Placeholder_DATE.String():
+		_, err := time.Parse("2006-01-02", value)
+		return err == nil
+	default:
+		return true
+	}
+}
+
+// hasPlaceholder checks if the template has a placeholder of the given name
+func (t *template) hasPlaceholder(name string) bool {
+	for _, p := range t.Placeholders {
+		if p.Name == name {
+			return true
+		}
+	}
+
+	return false
+}
+
+// toProto converts the template to the one defined in .proto file
+func (t *template) toProto() *Template {
+	res := &Template{
+		TemplateId:   t.ID.Hex(),
+		UserId:       t.UserID,
+		Name:         t.Name,
+		Content:      t.Content,
+		Placeholders: make([]*Placeholder, len(t.Placeholders)),
+	}
+
+	for i, p := range t.Placeholders {
+		res.Placeholders[i] = &Placeholder{Name: p.Name, Type: Placeholder_Type(Placeholder_Type_value[p.Type])}
+	}
+
+	res.CreatedAt, _ = ptypes.TimestampProto(t.CreatedAt)
+	res.UpdatedAt, _ = ptypes.TimestampProto(t.UpdatedAt)
+
+	return res
+}
@@ -0,0 +1,296 @@
+package sms
+
+import (
+	context "context"
+	"fmt"
+	"time"
+
+	"github.com/OmarElGabry/go-textnow/internal/pkg/logger"
+
+	"github.com/golang/protobuf/proto"
+	"github.com/golang/protobuf/ptypes"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// trackingStaleAfter is how long a tracking can go without progress before it is
+// considered abandoned (i.e. the replica sending its SMSs has crashed), and so resumed.
+// Its claim is renewed before every sms, and so only an sms that takes longer than that gets it resumed.
+const trackingStaleAfter = time.Minute
+
+// resumedKey marks the context of the SMSs of a tracking that is resumed (see resumeTracking)
+type resumedKey struct{}
+
+// tracking is a document in the "tracking" collection, one for every SendMany request.
+//
+// Its "updatedAt" is bumped before and after every sms, and so it tells if it is still being sent.
+// It is the claim of the replica sending it too, which stops once another replica has claimed it.
+type tracking struct {
+	ID        primitive.ObjectID `bson:"_id"`
+	State     string             `bson:"state"`
+	Messages  []trackedMessage   `bson:"messages"`
+	CreatedAt time.Time          `bson:"createdAt"`
+	UpdatedAt time.Time          `bson:"updatedAt"`
+}
+
+// trackedMessage is an sms of a tracking document.
+//
+// The sms is stored in its wire format, so that it holds every field of the request.
+type trackedMessage struct {
+	IdempotencyKey string `bson:"idempotencyKey"`
+	SMS            []byte `bson:"sms"`
+	MessageID      string `bson:"messageId,omitempty"`
+	Status         string `bson:"status"`
+	Error          string `bson:"error,omitempty"`
+}
+
+// GetTracking method gets the progress of sending the SMSs of SendMany
+func (s *server) GetTracking(ctx context.Context, req *GetTrackingRequest) (*GetTrackingResponse, error) {
+	id, err := primitive.ObjectIDFromHex(req.GetTrackingId())
+	if err != nil {
+		return nil, status.Error(codes.InvalidArgument, "Invalid tracking id")
+	}
+
+	var t tracking
+	err = s.tracking.FindOne(ctx, bson.M{"_id": id}).Decode(&t)
+	if err == mongo.ErrNoDocuments {
+		return nil, status.Error(codes.NotFound, "Tracking doesn't exist")
+	}
+
+	if err != nil {
+		return nil, status.Error(codes.Internal, "Internal error "+err.Error())
+	}
+
+	res := &GetTrackingResponse{
+		TrackingId: t.ID.Hex(),
+		State:      GetTrackingResponse_State(GetTrackingResponse_State_value[t.State]),
+		Total:      int32(len(t.Messages)),
+		Messages:   make([]*TrackedMessage, len(t.Messages)),
+	}
+
+	res.CreatedAt, _ = ptypes.TimestampProto(t.CreatedAt)
+	res.UpdatedAt, _ = ptypes.TimestampProto(t.UpdatedAt)
+
+	for i, m := range t.Messages {
+		res.Messages[i] = &TrackedMessage{
+			IdempotencyKey: m.IdempotencyKey,
+			MessageId:      m.MessageID,
+			Status:         Status(Status_value[m.Status]),
+			Error:          m.Error,
+		}
+
+		switch m.Status {
+		case Status_QUEUED.String(), Status_SCHEDULED.String(), Status_CANCELED.String():
+		case Status_FAILED.String():
+			res.Failed++
+		default:
+			res.Sent++
+		}
+	}
+
+	return res, nil
+}
+
+// createTracking stores a tracking document for the given SMSs, all are QUEUED
+func (s *server) createTracking(ctx context.Context, smss []*SMS) (*tracking, error) {
+	now := trackingNow()
+	t := &tracking{
+		ID:        primitive.NewObjectID(),
+		State:     GetTrackingResponse_IN_PROGRESS.String(),
+		Messages:  make([]trackedMessage, len(smss)),
+		CreatedAt: now,
+		UpdatedAt: now,
+	}
+
+	for i, sms := range smss {
+		buf, err := proto.Marshal(sms)
+		if err != nil {
+			return nil, err
+		}
+
+		t.Messages[i] = trackedMessage{
+			IdempotencyKey: sms.GetIdempotencyKey(),
+			SMS:            buf,
+			Status:         Status_QUEUED.String(),
+		}
+	}
+
+	if _, err := s.tracking.InsertOne(ctx, t); err != nil {
+		return nil, err
+	}
+
+	return t, nil
+}
+
+// sendTracked sends the QUEUED SMSs of a tracking document one by one, in the order
+// of the request, since they are likely to be chunks of the same long text.
+// The result of every sms is stored right after it is sent.
+//
+// If it is interrupted, the tracking is resumed later by resumeTracking.
+// An sms could then be sent twice, but SendOne is idempotent, and so it is sent only once.
+// Only a message that is still QUEUED and was never handed over is sent again (see deliver).
+// A scheduled sms is stored as SCHEDULED, and its status is stored once it is due (see trackScheduled).
+//
+// The tracking is claimed by its "updatedAt", which is renewed before every sms.
+// If another replica has claimed it meanwhile, it is left to that replica.
+//
+// The rate limit tokens of the SMSs were taken by SendMany, and so SendOne doesn't take them again.
+func (s *server) sendTracked(ctx context.Context, t *tracking) {
+	ctx = context.WithValue(ctx, tokensTakenKey{}, true)
+	claimedAt := t.UpdatedAt
+
+	for i, m := range t.Messages {
+		if m.Status != Status_QUEUED.String() {
+			continue
+		}
+
+		if !s.renewTracking(ctx, t.ID, &claimedAt, bson.M{}) {
+			return
+		}
+
+		update := bson.M{}
+		scheduledID := ""
+		sms := &SMS{}
+
+		if err := proto.Unmarshal(m.SMS, sms); err != nil {
+			update[fmt.Sprintf("messages.%d.status", i)] = Status_FAILED.String()
+			update[fmt.Sprintf("messages.%d.error", i)] = "Invalid sms: " + err.Error()
+		} else if res, err := s.SendOne(ctx, &SendOneRequest{Sms: sms}); err != nil {
+			update[fmt.Sprintf("messages.%d.status", i)] = Status_FAILED.String()
+			update[fmt.Sprintf("messages.%d.error", i)] = status.Convert(err).Message()
+		} else {
+			update[fmt.Sprintf("messages.%d.status", i)] = res.GetStatus().String()
+			update[fmt.Sprintf("messages.%d.messageId", i)] = res.GetMessageId()
+
+			if res.GetStatus() == Status_SCHEDULED {
+				scheduledID = res.GetMessageId()
+			}
+		}
+
+		if !s.renewTracking(ctx, t.ID, &claimedAt, update) {
+			return
+		}
+
+		// a message that is due soon could have been sent before it was stored as SCHEDULED
+		if scheduledID != "" {
+			s.refreshScheduled(ctx, scheduledID)
+		}
+	}
+
+	s.renewTracking(ctx, t.ID, &claimedAt, bson.M{"state": GetTrackingResponse_DONE.String()})
+}
+
+// trackScheduled stores the status of a scheduled message in the tracking of the SendMany request
+// it was sent by (if any), once it is sent, failed or canceled.
+//
+// The claim of the tracking ("updatedAt") isn't renewed, since it is of the replica sending its SMSs,
+// if it is still IN_PROGRESS. A tracking can be DONE while some of its SMSs are still SCHEDULED.
+func (s *server) trackScheduled(ctx context.Context, m *message) {
+	_, err := s.tracking.UpdateOne(ctx,
+		bson.M{"messages": bson.M{"$elemMatch": bson.M{"messageId": m.ID.Hex(), "status": Status_SCHEDULED.String()}}},
+		bson.M{"$set": bson.M{"messages.$.status": m.Status, "messages.$.error": m.FailureReason}})
+
+	if err != nil {
+		logger.Error(fmt.Sprintf("Failed to track scheduled message %s error: %v", m.ID.Hex(), err))
+	}
+}
+
+// refreshScheduled tracks a message stored as SCHEDULED in a tracking again,
+// in case it was sent, failed or canceled before it was stored
+func (s *server) refreshScheduled(ctx context.Context, messageID string) {
+	id, err := primitive.ObjectIDFromHex(messageID)
+	if err != nil {
+		return
+	}
+
+	var m message
+	if err := s.db.FindOne(ctx, bson.M{"_id": id}).Decode(&m); err != nil {
+		logger.Error(fmt.Sprintf("Failed to find scheduled message %s error: %v", messageID, err))
+		return
+	}
+
+	if m.status() != Status_SCHEDULED {
+		s.trackScheduled(ctx, &m)
+	}
+}
+
+// renewTracking stores the given update of a tracking along with a new claim ("updatedAt"),
+// provided that it is still claimed by this replica. It returns false if it isn't, or if it fails,
+// and so resumeTracking picks it up from where it stopped.
+func (s *server) renewTracking(ctx context.Context, id primitive.ObjectID, claimedAt *time.Time, update bson.M) bool {
+	now := trackingNow()
+	update["updatedAt"] = now
+
+	res, err := s.tracking.UpdateOne(ctx, bson.M{"_id": id, "updatedAt": *claimedAt}, bson.M{"$set": update})
+	if err != nil {
+		logger.Error(fmt.Sprintf("Failed to update tracking %s error: %v", id.Hex(), err))
+		return false
+	}
+
+	if res.MatchedCount == 0 {
+		logger.Error(fmt.Sprintf("Tracking %s has been claimed by another replica", id.Hex()))
+		return false
+	}
+
+	*claimedAt = now
+	return true
+}
+
+// trackingNow returns the current time as it is stored (in milliseconds),
+// so that it matches the "updatedAt" of a tracking it is compared to
+func trackingNow() time.Time {
+	return time.Now().UTC().Truncate(time.Millisecond)
+}
+
+// resumeTracking periodically resumes the trackings that are still IN_PROGRESS,
+// but haven't made any progress for a while. It runs for as long as the server does.
+//
+// A tracking is claimed by bumping its "updatedAt" in the same (atomic) operation
+// that finds it, and so only one replica resumes it.
+func (s *server) resumeTracking() {
+	ticker := time.NewTicker(trackingStaleAfter / 2)
+	defer ticker.Stop()
+
+	for {
+		for {
+			t, err := s.claimStaleTracking()
+			if err == mongo.ErrNoDocuments {
+				break
+			}
+
+			if err != nil {
+				logger.Error(fmt.Sprintf("Failed to claim a stale tracking error: %v", err))
+				break
+			}
+
+			go s.sendTracked(context.WithValue(context.Background(), resumedKey{}, true), t)
+		}
+
+		<-ticker.C
+	}
+}
+
+// claimStaleTracking finds a stale tracking and claims it
+func (s *server) claimStaleTracking() (*tracking, error) {
+	now := trackingNow()
+	filter := bson.M{
+		"state":     GetTrackingResponse_IN_PROGRESS.String(),
+		"updatedAt": bson.M{"$lt": now.Add(-trackingStaleAfter)},
+	}
+
+	var t tracking
+	err := s.tracking.FindOneAndUpdate(context.Background(), filter,
+		bson.M{"$set": bson.M{"updatedAt": now}},
+		options.FindOneAndUpdate().SetReturnDocument(options.After)).Decode(&t)
+
+	if err != nil {
+		return nil, err
+	}
+
+	return &t, nil
+}
@@ -0,0 +1,513 @@
+package sms
+
+import (
+	"bytes"
+	context "context"
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"net/url"
+	"strings"
+	"syscall"
+	"time"
+
+	"github.com/OmarElGabry/go-textnow/internal/phonebook"
+	"github.com/OmarElGabry/go-textnow/internal/pkg/logger"
+	"github.com/OmarElGabry/go-textnow/internal/pkg/signature"
+
+	"github.com/golang/protobuf/jsonpb"
+	"github.com/golang/protobuf/ptypes"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+const (
+	// deliveriesInterval is how often the delivery worker checks for due deliveries
+	deliveriesInterval = time.Second
+
+	// deliveryClaimExpiry is how long a due delivery stays claimed by a replica.
+	// If it isn't done by then (i.e. the replica has crashed), it is claimed again.
+	deliveryClaimExpiry = time.Minute
+
+	// deliveryTimeout is how long the url of a webhook has to respond
+	deliveryTimeout = 10 * time.Second
+
+	// deliveryBackoff is the wait before the first retry, it doubles on every retry after.
+	// With deliveryMaxAttempts, a delivery is given up on after ~17 minutes.
+	deliveryBackoff     = 2 * time.Second
+	deliveryMaxAttempts = 10
+)
+
+// Delivery states. A FAILED delivery has been given up on, and it is in the dead-letter queue
+// until it is replayed.
+const (
+	deliveryPending   = "PENDING"
+	deliveryDelivered = "DELIVERED"
+	deliveryFailed    = "FAILED"
+)
+
+// privateNetworks are the networks the webhooks aren't sent to, unless their host is allowed:
+// loopback, private, shared (carrier-grade NAT), link-local (i.e. the metadata of a cloud instance) and unspecified.
+var privateNetworks = func() []*net.IPNet {
+	var networks []*net.IPNet
+	for _, cidr := range []string{
+		"0.0.0.0/8", "10.0.0.0/8", "100.64.0.0/10", "127.0.0.0/8", "169.254.0.0/16", "172.16.0.0/12", "192.168.0.0/16",
+		"::/128", "::1/128", "fc00::/7", "fe80::/10",
+	} {
+		_, network, _ := net.ParseCIDR(cidr)
+		networks = append(networks, network)
+	}
+
+	return networks
+}()
+
+// errPrivateAddress is returned for a webhook url that resolves to an address in one of the private networks
+var errPrivateAddress = errors.New("address is loopback, private or link-local")
+
+// webhook is a document in the "webhooks" collection.
+// The event types are stored by their names, i.e. "STATUS".
+type webhook struct {
+	ID         primitive.ObjectID `bson:"_id"`
+	UserID     int32              `bson:"userId"`
+	URL        string             `bson:"url"`
+	EventTypes []string           `bson:"eventTypes"`
+	Secret     string             `bson:"secret"`
+	CreatedAt  time.Time          `bson:"createdAt"`
+}
+
+// delivery is a document in the "deliveries" collection, an event to be sent to a webhook.
+//
+// The payload is rendered once when the event happens, and so every retry sends the same one.
+type delivery struct {
+	ID            primitive.ObjectID `bson:"_id"`
+	WebhookID     primitive.ObjectID `bson:"webhookId"`
+	Payload       []byte             `bson:"payload"`
+	State         string             `bson:"state"`
+	Attempts      int                `bson:"attempts"`
+	NextAttemptAt time.Time          `bson:"nextAttemptAt"`
+	LastError     string             `bson:"lastError,omitempty"`
+	CreatedAt     time.Time          `bson:"createdAt"`
+	UpdatedAt     time.Time          `bson:"updatedAt"`
+}
+
+// CreateWebhook method subscribes a webhook of an account to the given event types
+func (s *server) CreateWebhook(ctx context.Context, req *CreateWebhookRequest) (*CreateWebhookResponse, error) {
+	if err := s.checkWebhookURL(ctx, req.GetUrl()); err != nil {
+		return nil, status.Error(codes.InvalidArgument, "Invalid url: "+err.Error())
+	}
+
+	secret := make([]byte, 32)
+	if _, err := rand.Read(secret); err != nil {
+		return nil, status.Error(codes.Internal, "Internal error "+err.Error())
+	}
+
+	w := &webhook{
+		ID:        primitive.NewObjectID(),
+		UserID:    req.GetUserId(),
+		URL:       req.GetUrl(),
+		Secret:    hex.EncodeToString(secret),
+		CreatedAt: time.Now().UTC(),
+	}
+
+	for _, t := range req.GetEventTypes() {
+		w.EventTypes = append(w.EventTypes, t.String())
+	}
+
+	if _, err := s.webhooks.InsertOne(ctx, w); err != nil {
+		return nil, status.Error(codes.Internal, "Internal error "+err.Error())
+	}
+
+	res := w.toProto()
+	res.Secret = w.Secret
+
+	return &CreateWebhookResponse{Webhook: res}, nil
+}
+
+// ListWebhooks method lists the webhooks of an account, without their secrets
+func (s *server) ListWebhooks(ctx context.Context, req *ListWebhooksRequest) (*ListWebhooksResponse, error) {
+	cur, err := s.webhooks.Find(ctx, bson.M{"userId": req.GetUserId()}, options.Find().SetSort(bson.M{"_id": 1}))
+	if err != nil {
+		return nil, status.Error(codes.Internal, "Internal error "+err.Error())
+	}
+	defer cur.Close(ctx)
+
+	webhooks := []*Webhook{}
+	for cur.Next(ctx) {
+		var w webhook
+		if err := cur.Decode(&w); err != nil {
+			return nil, status.Error(codes.Internal, "Internal error "+err.Error())
+		}
+
+		webhooks = append(webhooks, w.toProto())
+	}
+
+	if err := cur.Err(); err != nil {
+		return nil, status.Error(codes.Internal, "Internal error "+err.Error())
+	}
+
+	return &ListWebhooksResponse{Webhooks: webhooks}, nil
+}
+
+// DeleteWebhook method deletes a webhook, and drops its deliveries
+func (s *server) DeleteWebhook(ctx context.Context, req *DeleteWebhookRequest) (*DeleteWebhookResponse, error) {
+	id, err := primitive.ObjectIDFromHex(req.GetWebhookId())
+	if err != nil {
+		return nil, status.Error(codes.InvalidArgument, "Invalid webhook id")
+	}
+
+	res, err := s.webhooks.DeleteOne(ctx, bson.M{"_id": id})
+	if err != nil {
+		return nil, status.Error(codes.Internal, "Internal error "+err.Error())
+	}
+
+	if res.DeletedCount == 0 {
+		return nil, status.Error(codes.NotFound, "Webhook doesn't exist")
+	}
+
+	if _, err := s.deliveries.DeleteMany(ctx, bson.M{"webhookId": id}); err != nil {
+		return nil, status.Error(codes.Internal, "Internal error "+err.Error())
+	}
+
+	return &DeleteWebhookResponse{Deleted: true}, nil
+}
+
+// ReplayDeliveries method queues the failed deliveries of a webhook (the dead-letter queue)
+// to be sent again, as if they were new
+func (s *server) ReplayDeliveries(ctx context.Context, req *ReplayDeliveriesRequest) (*ReplayDeliveriesResponse, error) {
+	id, err := primitive.ObjectIDFromHex(req.GetWebhookId())
+	if err != nil {
+		return nil, status.Error(codes.InvalidArgument, "Invalid webhook id")
+	}
+
+	if err := s.webhooks.FindOne(ctx, bson.M{"_id": id}).Err(); err == mongo.ErrNoDocuments {
+		return nil, status.Error(codes.NotFound, "Webhook doesn't exist")
+	} else if err != nil {
+		return nil, status.Error(codes.Internal, "Internal error "+err.Error())
+	}
+
+	filter := bson.M{"webhookId": id, "state": deliveryFailed}
+	if req.GetDeliveryId() != "" {
+		deliveryID, err := primitive.ObjectIDFromHex(req.GetDeliveryId())
+		if err != nil {
+			return nil, status.Error(codes.InvalidArgument, "Invalid delivery id")
+		}
+
+		filter["_id"] = deliveryID
+	}
+
+	now := time.Now().UTC()
+	res, err := s.deliveries.UpdateMany(ctx, filter, bson.M{"$set": bson.M{
+		"state":         deliveryPending,
+		"attempts":      0,
+		"nextAttemptAt": now,
+		"updatedAt":     now,
+	}})
+
+	if err != nil {
+		return nil, status.Error(codes.Internal, "Internal error "+err.Error())
+	}
+
+	if req.GetDeliveryId() != "" && res.ModifiedCount == 0 {
+		return nil, status.Error(codes.NotFound, "Failed delivery doesn't exist")
+	}
+
+	return &ReplayDeliveriesResponse{Replayed: int32(res.ModifiedCount)}, nil
+}
+
+// enqueueDeliveries queues a delivery of an event to every webhook of the account (userID) that owns
+// the phone number, and is subscribed to the event type.
+//
+// The account is found when the message is sent (see message.FromUserID), it is only looked up
+// for the messages stored before it was recorded.
+//
+// The message has been sent already, and so a failure is logged rather than returned.
+func (s *server) enqueueDeliveries(ctx context.Context, phoneNumber string, userID int32, eventType SubscribeResponse_Type, m *message) {
+	if userID == 0 {
+		owner, err := s.pB.FindOne(ctx, &phonebook.FindOneRequest{PhoneNumber: phoneNumber})
+		if err != nil {
+			logger.Error(fmt.Sprintf("Failed to find the account of %s error: %v", phoneNumber, err))
+			return
+		}
+
+		userID = owner.GetUserId()
+	}
+
+	if userID == 0 {
+		return
+	}
+
+	cur, err := s.webhooks.Find(ctx, bson.M{"userId": userID, "eventTypes": eventType.String()})
+	if err != nil {
+		logger.Error(fmt.Sprintf("Failed to find the webhooks of %s error: %v", phoneNumber, err))
+		return
+	}
+	defer cur.Close(ctx)
+
+	now := time.Now().UTC()
+	for cur.Next(ctx) {
+		var w webhook
+		if err := cur.Decode(&w); err != nil {
+			logger.Error(fmt.Sprintf("Failed to decode a webhook of %s error: %v", phoneNumber, err))
+			continue
+		}
+
+		d := &delivery{
+			ID:            primitive.NewObjectID(),
+			WebhookID:     w.ID,
+			State:         deliveryPending,
+			NextAttemptAt: now,
+			CreatedAt:     now,
+			UpdatedAt:     now,
+		}
+
+		payload := &WebhookPayload{DeliveryId: d.ID.Hex(), Type: eventType, PhoneNumber: phoneNumber, Message: m.toProto()}
+		payload.CreatedAt, _ = ptypes.TimestampProto(now)
+
+		buf := &bytes.Buffer{}
+		if err := (&jsonpb.Marshaler{OrigName: true}).Marshal(buf, payload); err != nil {
+			logger.Error(fmt.Sprintf("Failed to render a delivery to webhook %s error: %v", w.ID.Hex(), err))
+			continue
+		}
+
+		d.Payload = buf.Bytes()
+		if _, err := s.deliveries.InsertOne(ctx, d); err != nil {
+			logger.Error(fmt.Sprintf("Failed to queue a delivery to webhook %s error: %v", w.ID.Hex(), err))
+		}
+	}
+}
+
+// runDeliveries periodically sends the deliveries that are due, the new ones and the ones to retry.
+// It runs for as long as the server does.
+//
+// A delivery is claimed by pushing its "nextAttemptAt" forward in the same (atomic) operation
+// that finds it, and so only one replica sends it.
+func (s *server) runDeliveries() {
+	ticker := time.NewTicker(deliveriesInterval)
+	defer ticker.Stop()
+
+	for {
+		for {
+			d, err := s.claimDueDelivery()
+			if err == mongo.ErrNoDocuments {
+				break
+			}
+
+			if err != nil {
+				logger.Error(fmt.Sprintf("Failed to claim a due delivery error: %v", err))
+				break
+			}
+
+			go s.sendDelivery(d)
+		}
+
+		<-ticker.C
+	}
+}
+
+// claimDueDelivery finds a due delivery and claims it
+func (s *server) claimDueDelivery() (*delivery, error) {
+	now := time.Now().UTC()
+	filter := bson.M{"state": deliveryPending, "nextAttemptAt": bson.M{"$lte": now}}
+
+	var d delivery
+	err := s.deliveries.FindOneAndUpdate(context.Background(), filter,
+		bson.M{"$set": bson.M{"nextAttemptAt": now.Add(deliveryClaimExpiry)}},
+		options.FindOneAndUpdate().SetReturnDocument(options.After)).Decode(&d)
+
+	if err != nil {
+		return nil, err
+	}
+
+	return &d, nil
+}
+
+// sendDelivery posts a claimed delivery to the url of its webhook, signed by its secret.
+//
+// A response other than 2xx is a failure. It is retried with exponential backoff,
+// unless it is a client error (4xx) other than 408 and 429, since sending it again won't help.
+// Once it is given up on, it is FAILED (in the dead-letter queue) until it is replayed.
+func (s *server) sendDelivery(d *delivery) {
+	ctx := context.Background()
+
+	var w webhook
+	err := s.webhooks.FindOne(ctx, bson.M{"_id": d.WebhookID}).Decode(&w)
+	if err == mongo.ErrNoDocuments {
+		// the webhook was deleted after the delivery was claimed
+		s.deliveries.DeleteOne(ctx, bson.M{"_id": d.ID})
+		return
+	}
+
+	if err != nil {
+		logger.Error(fmt.Sprintf("Failed to find webhook %s error: %v", d.WebhookID.Hex(), err))
+		return
+	}
+
+	retry, err := s.postDelivery(&w, d)
+
+	now := time.Now().UTC()
+	update := bson.M{"attempts": d.Attempts + 1, "updatedAt": now}
+
+	switch {
+	case err == nil:
+		update["state"] = deliveryDelivered
+	case retry && d.Attempts+1 < deliveryMaxAttempts:
+		update["lastError"] = err.Error()
+		update["nextAttemptAt"] = now.Add(deliveryBackoff << uint(d.Attempts))
+	default:
+		update["lastError"] = err.Error()
+		update["state"] = deliveryFailed
+	}
+
+	// only if it is still claimed by this replica
+	_, err = s.deliveries.UpdateOne(ctx, bson.M{"_id": d.ID, "nextAttemptAt": d.NextAttemptAt}, bson.M{"$set": update})
+	if err != nil {
+		logger.Error(fmt.Sprintf("Failed to update delivery %s error: %v", d.ID.Hex(), err))
+	}
+}
+
+// postDelivery posts the payload of a delivery to the url of the webhook.
+// It returns whether it is worth retrying if it fails.
+func (s *server) postDelivery(w *webhook, d *delivery) (bool, error) {
+	req, err := http.NewRequest(http.MethodPost, w.URL, bytes.NewReader(d.Payload))
+	if err != nil {
+		return false, err
+	}
+
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("X-Textnow-Delivery", d.ID.Hex())
+	req.Header.Set(signature.Header, signature.Sign([]byte(w.Secret), time.Now(), d.Payload))
+
+	res, err := s.webhookClient.Do(req)
+	if err != nil {
+		return true, err
+	}
+	defer res.Body.Close()
+
+	if res.StatusCode >= 200 && res.StatusCode < 300 {
+		return false, nil
+	}
+
+	retry := res.StatusCode >= 500 || res.StatusCode == http.StatusRequestTimeout ||
+		res.StatusCode == http.StatusTooManyRequests
+
+	return retry, fmt.Errorf("Webhook responded with %s", res.Status)
+}
+
+// checkWebhookURL checks the url of a webhook is http(s), and that its host is either allowed
+// or only resolves to public addresses, so a webhook can't reach the internal services.
+func (s *server) checkWebhookURL(ctx context.Context, rawURL string) error {
+	u, err := url.Parse(rawURL)
+	if err != nil {
+		return err
+	}
+
+	if u.Scheme != "http" && u.Scheme != "https" {
+		return errors.New("scheme must be http or https")
+	}
+
+	host := strings.ToLower(u.Hostname())
+	if host == "" {
+		return errors.New("host is missing")
+	}
+
+	if s.webhookHosts[host] {
+		return nil
+	}
+
+	addrs, err := net.DefaultResolver.LookupIPAddr(ctx, host)
+	if err != nil {
+		return fmt.Errorf("couldn't resolve %s", host)
+	}
+
+	for _, addr := range addrs {
+		if isPrivateIP(addr.IP) {
+			return fmt.Errorf("%s %s", host, errPrivateAddress)
+		}
+	}
+
+	return nil
+}
+
+// newWebhookClient returns the client that sends the deliveries.
+//
+// The address of a host that isn't allowed is checked again when it is connected to,
+// since it may resolve to another one by then (DNS rebinding), or the webhook may redirect to another host.
+func newWebhookClient(allowedHosts map[string]bool) *http.Client {
+	dialer := &net.Dialer{Timeout: deliveryTimeout}
+	public := &net.Dialer{
+		Timeout: deliveryTimeout,
+		Control: func(network, address string, c syscall.RawConn) error {
+			host, _, err := net.SplitHostPort(address)
+			if err != nil {
+				return err
+			}
+
+			if isPrivateIP(net.ParseIP(host)) {
+				return fmt.Errorf("%s %s", host, errPrivateAddress)
+			}
+
+			return nil
+		},
+	}
+
+	transport := &http.Transport{
+		DialContext: func(ctx context.Context, network, address string) (net.Conn, error) {
+			host, _, err := net.SplitHostPort(address)
+			if err != nil {
+				return nil, err
+			}
+
+			if allowedHosts[strings.ToLower(host)] {
+				return dialer.DialContext(ctx, network, address)
+			}
+
+			return public.DialContext(ctx, network, address)
+		},
+		MaxIdleConns:          100,
+		IdleConnTimeout:       90 * time.Second,
+		TLSHandshakeTimeout:   10 * time.Second,
+		ExpectContinueTimeout: time.Second,
+	}
+
+	return &http.Client{Timeout: deliveryTimeout, Transport: transport}
+}
+
+// isPrivateIP returns whether the ip is in one of the private networks, an invalid one is as well
+func isPrivateIP(ip net.IP) bool {
+	if ip == nil {
+		return true
+	}
+
+	for _, network := range privateNetworks {
+		if network.Contains(ip) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// toProto converts the webhook to the one defined in .proto file, without its secret
+func (w *webhook) toProto() *Webhook {
+	res := &Webhook{
+		WebhookId: w.ID.Hex(),
+		UserId:    w.UserID,
+		Url:       w.URL,
+	}
+
+	for _, t := range w.EventTypes {
+		res.EventTypes = append(res.EventTypes, SubscribeResponse_Type(SubscribeResponse_Type_value[t]))
+	}
+
+	res.CreatedAt, _ = ptypes.TimestampProto(w.CreatedAt)
+
+	return res
+}
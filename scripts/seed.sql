@@ -0,0 +1,4 @@
+-- Seed of a development database, run it after the migrations (see "Database migrations" in README).
+-- Reserve and Assign assume the user exists already, these are users without phone numbers.
+INSERT INTO `phonebook` (`phone_number`) 
+VALUES (NULL), (NULL), (NULL), (NULL);
@@ -0,0 +1,88 @@
+package tests
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/OmarElGabry/go-textnow/internal/pkg/carrier"
+)
+
+func TestCarrier(t *testing.T) {
+	msg := &carrier.Message{ID: "1", From: "+16135550172", To: "+16135550173", Segments: []string{"hi"}}
+
+	t.Run("TestRouter", func(t *testing.T) {
+		local, national, international := carrier.NewFake("local", 0, 0),
+			carrier.NewFake("national", 0, 0), carrier.NewFake("international", 0, 0)
+
+		router, err := carrier.NewRouter("+1=national, +1613=local, *=international", local, national, international)
+		if err != nil {
+			t.Errorf("NewRouter failed with %v", err)
+			return
+		}
+
+		tests := []struct {
+			to   string
+			want string
+		}{
+			{"+16135550173", "local"},    // the longest prefix wins
+			{"+14165550173", "national"}, // regardless of the order of the rules
+			{"+442079460173", "international"},
+		}
+
+		for _, test := range tests {
+			c, err := router.Route(test.to)
+			if err != nil {
+				t.Errorf("Route(%s) failed with %v", test.to, err)
+			} else if got := c.Name(); got != test.want {
+				t.Errorf("Route(%s) = %s; want %s", test.to, got, test.want)
+			}
+		}
+
+		// without a wildcard, a phone number may have no route
+		router, _ = carrier.NewRouter("+1=national", national)
+		if _, err := router.Route("+442079460173"); err != carrier.ErrNoRoute {
+			t.Errorf("Route error = %v; want %v", err, carrier.ErrNoRoute)
+		}
+
+		for _, rules := range []string{"+1=unknown", "+1"} {
+			if _, err := carrier.NewRouter(rules, national); err == nil {
+				t.Errorf("NewRouter(%q) succeeded; want an error", rules)
+			}
+		}
+	})
+
+	t.Run("TestFake", func(t *testing.T) {
+		// 1) test with latency
+		start := time.Now()
+		receipt, err := carrier.NewFake("fake", 50*time.Millisecond, 0).Send(context.Background(), msg)
+		if err != nil {
+			t.Errorf("Send failed with %v", err)
+		} else if !receipt.Delivered || receipt.CarrierMessageID == "" {
+			t.Errorf("receipt = %+v; want delivered with an id", receipt)
+		}
+
+		if elapsed := time.Since(start); elapsed < 50*time.Millisecond {
+			t.Errorf("Send took %v; want at least the latency", elapsed)
+		}
+
+		// the ids are unique across the carriers (i.e. the replicas), since the reports are matched by them
+		other, err := carrier.NewFake("fake", 0, 0).Send(context.Background(), msg)
+		if err == nil && receipt != nil && other.CarrierMessageID == receipt.CarrierMessageID {
+			t.Errorf("CarrierMessageID = %s of both; want unique ids", other.CarrierMessageID)
+		}
+
+		// 2) test with failures
+		if _, err := carrier.NewFake("fake", 0, 1).Send(context.Background(), msg); err != carrier.ErrFakeFailure {
+			t.Errorf("Send error = %v; want %v", err, carrier.ErrFakeFailure)
+		}
+
+		// 3) test canceling while waiting for the latency
+		ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+		defer cancel()
+
+		if _, err := carrier.NewFake("fake", time.Second, 0).Send(ctx, msg); err != context.DeadlineExceeded {
+			t.Errorf("Send error = %v; want %v", err, context.DeadlineExceeded)
+		}
+	})
+}
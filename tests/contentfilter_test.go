@@ -0,0 +1,140 @@
+package tests
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/OmarElGabry/go-textnow/internal/pkg/contentfilter"
+)
+
+func TestContentFilter(t *testing.T) {
+	filter, err := contentfilter.Load("../deployment/content-filter.example.json")
+	if err != nil {
+		t.Fatalf("Load failed with %v", err)
+	}
+
+	t.Run("TestApply", func(t *testing.T) {
+		tests := []struct {
+			name      string
+			content   string
+			account   int32
+			want      string
+			decisions []contentfilter.Decision
+			rejected  bool
+		}{
+			{"clean", "see you at 5", 1, "see you at 5", nil, false},
+			{"denied domain", "verify at https://login-verify.example/account now", 1, "", []contentfilter.Decision{
+				{Rule: "phishing", Action: contentfilter.Reject, Matches: []string{"https://login-verify.example/account"}},
+			}, true},
+			{"subdomain", "go to secure.Phishing.example", 1, "", []contentfilter.Decision{
+				{Rule: "phishing", Action: contentfilter.Reject, Matches: []string{"secure.Phishing.example"}},
+			}, true},
+			{"banned word", "Free CASINO chips", 1, "", []contentfilter.Decision{
+				{Rule: "banned-words", Action: contentfilter.Reject, Matches: []string{"CASINO"}},
+			}, true},
+			{"rewrite", "card 4111 1111 1111 1234 at bit.ly/x", 1, "card **** 1234 at bit.ly/x", []contentfilter.Decision{
+				{Rule: "card-numbers", Action: contentfilter.Rewrite, Matches: []string{"4111 1111 1111 1234"}},
+				{Rule: "shorteners", Action: contentfilter.Flag, Matches: []string{"bit.ly/x"}},
+			}, false},
+			{"allowed domain", "see bit.ly/x", 42, "see bit.ly/x", nil, false},
+			{"not allowed domain", "see tinyurl.com/x", 42, "see tinyurl.com/x", []contentfilter.Decision{
+				{Rule: "shorteners", Action: contentfilter.Flag, Matches: []string{"tinyurl.com/x"}},
+			}, false},
+		}
+
+		for _, test := range tests {
+			res := filter.Apply(test.content, test.account)
+
+			if res.Rejected != test.rejected || !reflect.DeepEqual(res.Decisions, test.decisions) {
+				t.Errorf("%s: Apply() = %+v; want decisions %+v, rejected %t", test.name, res, test.decisions, test.rejected)
+			}
+
+			if !test.rejected && res.Content != test.want {
+				t.Errorf("%s: Content = %q; want %q", test.name, res.Content, test.want)
+			}
+		}
+
+		// no filter lets every message through
+		var none *contentfilter.Filter
+		if res := none.Apply("Free casino chips", 1); res.Rejected || res.Content != "Free casino chips" {
+			t.Errorf("Apply() of no filter = %+v; want the content as is", res)
+		}
+	})
+
+	t.Run("TestParse", func(t *testing.T) {
+		for _, config := range []string{
+			`{"rules": [{"type": "regex", "action": "FLAG", "pattern": "a"}]}`,
+			`{"rules": [{"name": "a", "type": "regex", "action": "DROP", "pattern": "a"}]}`,
+			`{"rules": [{"name": "a", "type": "regex", "action": "FLAG", "pattern": "("}]}`,
+			`{"rules": [{"name": "a", "type": "domains", "action": "FLAG"}]}`,
+			`{"rules": [{"name": "a", "type": "words", "action": "FLAG"}]}`,
+			`{"rules": [{"name": "a", "type": "regex", "action": "FLAG", "pattern": "a"},
+				{"name": "a", "type": "regex", "action": "FLAG", "pattern": "b"}]}`,
+			`{"allow": {"user": ["bit.ly"]}}`,
+			`{"rules": `,
+		} {
+			if _, err := contentfilter.Parse([]byte(config)); err == nil {
+				t.Errorf("Parse(%s) succeeded; want an error", config)
+			}
+		}
+	})
+
+	t.Run("TestWatch", func(t *testing.T) {
+		dir, err := ioutil.TempDir("", "contentfilter")
+		if err != nil {
+			t.Fatalf("TempDir failed with %v", err)
+		}
+		defer os.RemoveAll(dir)
+
+		path := filepath.Join(dir, "rules.json")
+		write := func(config string, modTime time.Time) {
+			if err := ioutil.WriteFile(path, []byte(config), 0644); err != nil {
+				t.Fatalf("WriteFile failed with %v", err)
+			}
+
+			// the modification time could be the same as the previous write's
+			if err := os.Chtimes(path, modTime, modTime); err != nil {
+				t.Fatalf("Chtimes failed with %v", err)
+			}
+		}
+
+		now := time.Now()
+		write(`{"rules": [{"name": "a", "type": "regex", "action": "REJECT", "pattern": "apple"}]}`, now)
+
+		errs := make(chan error, 10)
+		w, err := contentfilter.Watch(path, 10*time.Millisecond, func(err error) { errs <- err })
+		if err != nil {
+			t.Fatalf("Watch failed with %v", err)
+		}
+		defer w.Close()
+
+		if !w.Filter().Apply("an apple", 1).Rejected {
+			t.Errorf("apple is not rejected; want rejected")
+		}
+
+		// 1) test the rules are reloaded once the file changes
+		write(`{"rules": [{"name": "b", "type": "regex", "action": "REJECT", "pattern": "banana"}]}`, now.Add(time.Second))
+
+		reloaded := waitFor(func() bool { return w.Filter().Apply("a banana", 1).Rejected })
+		if !reloaded || w.Filter().Apply("an apple", 1).Rejected {
+			t.Errorf("the rules are not reloaded; want banana rejected only")
+		}
+
+		// 2) test an invalid file keeps the previous rules
+		write(`{"rules": [`, now.Add(2*time.Second))
+
+		select {
+		case <-errs:
+		case <-time.After(time.Second):
+			t.Errorf("no error is reported; want the invalid file reported")
+		}
+
+		if !w.Filter().Apply("a banana", 1).Rejected {
+			t.Errorf("the previous rules are not kept; want banana rejected")
+		}
+	})
+}
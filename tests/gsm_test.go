@@ -0,0 +1,121 @@
+package tests
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/OmarElGabry/go-textnow/internal/pkg/gsm"
+)
+
+func TestGSM(t *testing.T) {
+	t.Run("TestDetect", func(t *testing.T) {
+		tests := []struct {
+			text string
+			want gsm.Encoding
+		}{
+			{"hi, how are you?", gsm.GSM7},
+			{"€10 [or] {so}", gsm.GSM7}, // extension table
+			{"Ça coûte 5€", gsm.UCS2},   // "û" isn't in GSM7
+			{"it’s", gsm.UCS2},          // curly quote
+			{"see you 😀", gsm.UCS2},
+		}
+
+		for _, test := range tests {
+			if got := gsm.Detect(test.text); got != test.want {
+				t.Errorf("Detect(%q) = %s; want %s", test.text, got, test.want)
+			}
+		}
+	})
+
+	t.Run("TestSplit", func(t *testing.T) {
+		tests := []struct {
+			name     string
+			text     string
+			encoding gsm.Encoding
+			lengths  []int // of every segment in its encoding
+		}{
+			{"GSM7 single", strings.Repeat("a", 160), gsm.GSM7, []int{160}},
+			{"GSM7 concatenated", strings.Repeat("a", 161), gsm.GSM7, []int{153, 8}},
+			// an escaped character takes two septets, and isn't split across segments
+			{"GSM7 escaped", strings.Repeat("a", 152) + "€" + strings.Repeat("a", 10), gsm.GSM7, []int{152, 12}},
+			{"UCS2 single", strings.Repeat("ж", 70), gsm.UCS2, []int{70}},
+			{"UCS2 concatenated", strings.Repeat("ж", 71), gsm.UCS2, []int{67, 4}},
+			// a surrogate pair isn't split across segments
+			{"UCS2 surrogate pair", strings.Repeat("ж", 66) + "😀" + strings.Repeat("ж", 10), gsm.UCS2, []int{66, 12}},
+		}
+
+		for _, test := range tests {
+			encoding, segments := gsm.Split(test.text)
+			if encoding != test.encoding {
+				t.Errorf("%s: encoding = %s; want %s", test.name, encoding, test.encoding)
+			}
+
+			if got, want := len(segments), len(test.lengths); got != want {
+				t.Errorf("%s: number of segments = %d; want %d", test.name, got, want)
+				continue
+			}
+
+			for i, segment := range segments {
+				if got, want := gsm.Length(segment, encoding), test.lengths[i]; got != want {
+					t.Errorf("%s: length of segment %d = %d; want %d", test.name, i+1, got, want)
+				}
+			}
+
+			if got := strings.Join(segments, ""); got != test.text {
+				t.Errorf("%s: joined segments = %q; want %q", test.name, got, test.text)
+			}
+		}
+	})
+
+	t.Run("TestEncode", func(t *testing.T) {
+		tests := []struct {
+			text     string
+			encoding gsm.Encoding
+			want     []byte
+		}{
+			{"Hi@", gsm.GSM7, []byte{0x48, 0x69, 0x00}},
+			{"5€", gsm.GSM7, []byte{0x35, 0x1b, 0x65}}, // escaped
+			{"ж😀", gsm.UCS2, []byte{0x04, 0x36, 0xd8, 0x3d, 0xde, 0x00}},
+		}
+
+		for _, test := range tests {
+			got := gsm.Encode(test.text, test.encoding)
+			if !reflect.DeepEqual(got, test.want) {
+				t.Errorf("Encode(%q) = % x; want % x", test.text, got, test.want)
+			}
+
+			if decoded := gsm.Decode(got, test.encoding); decoded != test.text {
+				t.Errorf("Decode(% x) = %q; want %q", got, decoded, test.text)
+			}
+		}
+	})
+}
+
+func TestTransliterate(t *testing.T) {
+	tests := []struct {
+		name          string
+		text          string
+		want          string
+		substitutions []gsm.Substitution
+	}{
+		{"GSM7", "It's 5 o'clock", "It's 5 o'clock", nil},
+		{"Look-alikes", "It’s “5” o’clock…", "It's \"5\" o'clock...", []gsm.Substitution{
+			{From: "’", To: "'", Count: 2}, {From: "“", To: "\"", Count: 1},
+			{From: "”", To: "\"", Count: 1}, {From: "…", To: "...", Count: 1},
+		}},
+		// an emoji has no look-alike, and so nothing is replaced
+		{"No look-alike", "It’s 😀", "It’s 😀", nil},
+	}
+
+	for _, test := range tests {
+		got, substitutions := gsm.Transliterate(test.text)
+		if got != test.want {
+			t.Errorf("%s: text = %q; want %q", test.name, got, test.want)
+		}
+
+		if !reflect.DeepEqual(substitutions, test.substitutions) {
+			t.Errorf("%s: substitutions = %v; want %v", test.name, substitutions, test.substitutions)
+		}
+	}
+}
@@ -0,0 +1,172 @@
+package tests
+
+import (
+	"context"
+	"sync"
+	"testing"
+
+	"github.com/OmarElGabry/go-textnow/internal/pkg/idempotency"
+	"github.com/OmarElGabry/go-textnow/internal/sms"
+
+	"github.com/golang/protobuf/proto"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+)
+
+// memStore is an in-memory idempotency store
+type memStore struct {
+	mu      sync.Mutex
+	records map[string]*idempotency.Record
+}
+
+func (s *memStore) Begin(ctx context.Context, key, requestHash string) (*idempotency.Record, bool, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if rec, ok := s.records[key]; ok {
+		return rec, false, nil
+	}
+
+	s.records[key] = &idempotency.Record{Key: key, RequestHash: requestHash, State: idempotency.InProgress}
+	return nil, true, nil
+}
+
+func (s *memStore) Complete(ctx context.Context, rec *idempotency.Record) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.records[rec.Key] = rec
+	return nil
+}
+
+func (s *memStore) Release(ctx context.Context, key string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	delete(s.records, key)
+	return nil
+}
+
+func TestIdempotency(t *testing.T) {
+	store := &memStore{records: map[string]*idempotency.Record{}}
+	interceptor := idempotency.New(store, sms.IdempotentMethods).Unary()
+
+	sendOne := &grpc.UnaryServerInfo{FullMethod: "/sms.SMSService/SendOne"}
+	request := func(key string) *sms.SendOneRequest {
+		return &sms.SendOneRequest{Sms: &sms.SMS{IdempotencyKey: key, Content: "hi"}}
+	}
+
+	// handler counts the calls, and returns the given response or error
+	calls := 0
+	handler := func(resp interface{}, err error) grpc.UnaryHandler {
+		return func(ctx context.Context, req interface{}) (interface{}, error) {
+			calls++
+			return resp, err
+		}
+	}
+
+	t.Run("TestReplayResponse", func(t *testing.T) {
+		calls = 0
+		first := &sms.SendOneResponse{Sent: true, MessageId: "1", Status: sms.Status_DELIVERED, SegmentCount: 1}
+
+		for i := 0; i < 2; i++ {
+			resp, err := interceptor(context.Background(), request("replay"), sendOne, handler(first, nil))
+			if err != nil || !proto.Equal(resp.(proto.Message), first) {
+				t.Errorf("attempt %d = %v, %v; want %v", i+1, resp, err, first)
+			}
+		}
+
+		if calls != 1 {
+			t.Errorf("handler is called %d times; want once", calls)
+		}
+	})
+
+	t.Run("TestReplayError", func(t *testing.T) {
+		// 1) test an error of the request itself is returned again
+		calls = 0
+		outOfRange := status.Error(codes.OutOfRange, "Content is too long")
+
+		for i := 0; i < 2; i++ {
+			_, err := interceptor(context.Background(), request("out-of-range"), sendOne, handler(nil, outOfRange))
+			if status.Code(err) != codes.OutOfRange || status.Convert(err).Message() != "Content is too long" {
+				t.Errorf("attempt %d error = %v; want %v", i+1, err, outOfRange)
+			}
+		}
+
+		if calls != 1 {
+			t.Errorf("handler is called %d times; want once", calls)
+		}
+
+		// 2) test any other error is handled again, InvalidArgument too
+		for _, err := range []error{
+			status.Error(codes.NotFound, "Phone number doesn't exist"),
+			status.Error(codes.InvalidArgument, "Phone number isn't reserved"),
+		} {
+			calls = 0
+			for i := 0; i < 2; i++ {
+				interceptor(context.Background(), request(status.Code(err).String()), sendOne, handler(nil, err))
+			}
+
+			if calls != 2 {
+				t.Errorf("%v: handler is called %d times; want twice", status.Code(err), calls)
+			}
+		}
+	})
+
+	t.Run("TestInProgress", func(t *testing.T) {
+		hash, _ := idempotency.Hash(request("in-progress"))
+		store.Begin(context.Background(), "/sms.SMSService/SendOne:in-progress", hash)
+
+		_, err := interceptor(context.Background(), request("in-progress"), sendOne, handler(&sms.SendOneResponse{}, nil))
+		if err != idempotency.ErrInProgress {
+			t.Errorf("error = %v; want %v", err, idempotency.ErrInProgress)
+		}
+	})
+
+	t.Run("TestConflict", func(t *testing.T) {
+		calls = 0
+		interceptor(context.Background(), request("conflict"), sendOne, handler(&sms.SendOneResponse{Sent: true}, nil))
+
+		// the same key with a different content
+		reused := request("conflict")
+		reused.Sms.Content = "bye"
+
+		_, err := interceptor(context.Background(), reused, sendOne, handler(&sms.SendOneResponse{Sent: true}, nil))
+		if err != idempotency.ErrConflict {
+			t.Errorf("error = %v; want %v", err, idempotency.ErrConflict)
+		}
+
+		if calls != 1 {
+			t.Errorf("handler is called %d times; want once", calls)
+		}
+	})
+
+	t.Run("TestKeys", func(t *testing.T) {
+		// 1) test the key of the metadata is used rather than the one of the request
+		calls = 0
+		md := metadata.Pairs(idempotency.MetadataKey, "metadata")
+		ctx := metadata.NewIncomingContext(context.Background(), md)
+
+		interceptor(ctx, request("request-1"), sendOne, handler(&sms.SendOneResponse{}, nil))
+		interceptor(ctx, request("request-2"), sendOne, handler(&sms.SendOneResponse{}, nil))
+
+		if calls != 1 {
+			t.Errorf("handler is called %d times; want once", calls)
+		}
+
+		// 2) test a request without a key, or of a method that hasn't opted in, is handled every time
+		calls = 0
+		getStatus := &grpc.UnaryServerInfo{FullMethod: "/sms.SMSService/GetMessageStatus"}
+
+		for i := 0; i < 2; i++ {
+			interceptor(context.Background(), request(""), sendOne, handler(&sms.SendOneResponse{}, nil))
+			interceptor(ctx, &sms.GetMessageStatusRequest{}, getStatus, handler(&sms.GetMessageStatusResponse{}, nil))
+		}
+
+		if calls != 4 {
+			t.Errorf("handler is called %d times; want 4", calls)
+		}
+	})
+}
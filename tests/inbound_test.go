@@ -0,0 +1,277 @@
+package tests
+
+import (
+	"fmt"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/OmarElGabry/go-textnow/internal/pkg/config"
+	"github.com/OmarElGabry/go-textnow/internal/pkg/phonenumber"
+	"github.com/OmarElGabry/go-textnow/internal/pkg/signature"
+	"github.com/OmarElGabry/go-textnow/internal/sms"
+	"github.com/OmarElGabry/go-textnow/tests/stubs"
+)
+
+func TestInbound(t *testing.T) {
+	uri := "http://gateway-service:8080/sms/"
+
+	config, err := config.Load()
+	if err != nil {
+		t.Fatalf("Couldn't load env variables: %v", err)
+	}
+
+	secret := []byte(config("INBOUND_WEBHOOK_SECRET"))
+
+	// post sends an inbound message signed with the given secret
+	post := func(secret []byte, body string) (*http.Response, error) {
+		req, err := http.NewRequest(http.MethodPost, uri+"inbound", strings.NewReader(body))
+		if err != nil {
+			return nil, err
+		}
+
+		req.Header.Set("Content-Type", "application/json")
+		if secret != nil {
+			req.Header.Set(signature.Header, signature.Sign(secret, time.Now(), []byte(body)))
+		}
+
+		return http.DefaultClient.Do(req)
+	}
+
+	t.Run("TestNormalize", func(t *testing.T) {
+		tests := []struct {
+			number string
+			want   string
+		}{
+			{"+16135550172", "+16135550172"},
+			{"16135550172", "+16135550172"},
+			{"(613) 555-0172", "+16135550172"},
+			{"613.555.0172", "+16135550172"},
+			{"0044 20 7946 0173", "+442079460173"},
+			{"447911123456", "+447911123456"},
+			{"555-0172", ""},
+			{"+1613555017a", ""},
+			{"+1234567890123456", ""}, // more than 15 digits
+		}
+
+		for _, test := range tests {
+			got, err := phonenumber.Normalize(test.number)
+			if test.want == "" {
+				if err != phonenumber.ErrInvalid {
+					t.Errorf("Normalize(%q) error = %v; want %v", test.number, err, phonenumber.ErrInvalid)
+				}
+			} else if err != nil || got != test.want {
+				t.Errorf("Normalize(%q) = %q, %v; want %q", test.number, got, err, test.want)
+			}
+		}
+	})
+
+	// insert the recipient
+	toPhoneNumber := stubs.GetPhoneNumber()
+	_, err = dbMySQL.Exec("INSERT INTO phonebook (user_id, phone_number) VALUES (?, ?)",
+		stubs.GetUserID(), toPhoneNumber)
+	if err != nil {
+		t.Fatalf("couldn't insert phone number: %v", err)
+	}
+
+	fromPhoneNumber := "+16135550199"
+	carrierMessageID := "SM" + stubs.GetPhoneNumber()[1:]
+
+	// the numbers are formatted the way a carrier could send them
+	body := `{"from_phone_number": "(613) 555-0199", "to_phone_number": "+1 ` + toPhoneNumber[2:] + `",
+		"content": "hi from outside", "carrier": "test", "carrier_message_id": "` + carrierMessageID + `"}`
+
+	t.Run("TestSignature", func(t *testing.T) {
+		for _, s := range [][]byte{nil, []byte("other secret")} {
+			res, err := post(s, body)
+			if err != nil {
+				t.Errorf("http.Post failed with %v", err)
+				return
+			}
+			res.Body.Close()
+
+			if got, want := res.StatusCode, http.StatusUnauthorized; got != want {
+				t.Errorf("StatusCode = %d; want %d", got, want)
+			}
+		}
+	})
+
+	t.Run("TestReceive", func(t *testing.T) {
+		// 1) test receiving a message
+		res, err := post(secret, body)
+		if err != nil {
+			t.Errorf("http.Post failed with %v", err)
+			return
+		}
+
+		var received sms.ReceiveResponse
+		if err := ReadRespone(res.Body, &received); err != nil {
+			t.Errorf("ReadRespone failed with %v", err)
+			return
+		}
+
+		if res.StatusCode != http.StatusOK || received.MessageId == "" || received.Duplicate {
+			t.Errorf("response = %d %+v; want a new message", res.StatusCode, received)
+			return
+		}
+
+		// 2) test it is in the thread of the recipient, with the numbers normalized
+		res, err = http.Get(uri + "threads/" + toPhoneNumber + "/" + fromPhoneNumber)
+		if err != nil {
+			t.Errorf("http.Get failed with %v", err)
+			return
+		}
+
+		var thread sms.GetThreadResponse
+		if err := ReadRespone(res.Body, &thread); err != nil {
+			t.Errorf("ReadRespone failed with %v", err)
+			return
+		}
+
+		if len(thread.Messages) != 1 {
+			t.Errorf("number of messages = %d; want 1", len(thread.Messages))
+			return
+		}
+
+		m := thread.Messages[0]
+		if m.MessageId != received.MessageId || m.FromPhoneNumber != fromPhoneNumber || m.ToPhoneNumber != toPhoneNumber ||
+			m.Direction != sms.Direction_INBOUND || m.Status != sms.Status_DELIVERED {
+			t.Errorf("message = %+v; want the inbound message", m)
+		}
+
+		// 3) test a retry of the carrier is acknowledged, and stored once
+		res, err = post(secret, body)
+		if err != nil {
+			t.Errorf("http.Post failed with %v", err)
+			return
+		}
+
+		var duplicate sms.ReceiveResponse
+		if err := ReadRespone(res.Body, &duplicate); err != nil {
+			t.Errorf("ReadRespone failed with %v", err)
+			return
+		}
+
+		if !duplicate.Duplicate || duplicate.MessageId != received.MessageId {
+			t.Errorf("response = %+v; want a duplicate of %s", duplicate, received.MessageId)
+		}
+	})
+
+	t.Run("TestOptOut", func(t *testing.T) {
+		// send sends a message back to the sender of the inbound messages, and returns the status code
+		send := func() (int, error) {
+			postData, err := CreateRequest(&sms.SendOneRequest{
+				Sms: &sms.SMS{
+					IdempotencyKey:  stubs.GetIdempotencyKey(),
+					FromPhoneNumber: toPhoneNumber,
+					ToPhoneNumber:   fromPhoneNumber,
+					Content:         "hi back",
+				},
+			})
+			if err != nil {
+				return 0, err
+			}
+
+			res, err := http.Post(uri+"send/one", "application/json", postData)
+			if err != nil {
+				return 0, err
+			}
+			res.Body.Close()
+
+			return res.StatusCode, nil
+		}
+
+		// keyword sends an inbound keyword from the sender
+		keyword := func(content string) error {
+			res, err := post(secret, `{"from_phone_number": "`+fromPhoneNumber+`", "to_phone_number": "`+toPhoneNumber+`",
+				"content": "`+content+`", "carrier": "test", "carrier_message_id": "SM`+stubs.GetPhoneNumber()[1:]+`"}`)
+			if err != nil {
+				return err
+			}
+			res.Body.Close()
+
+			if res.StatusCode != http.StatusOK {
+				return fmt.Errorf("StatusCode = %d; want %d", res.StatusCode, http.StatusOK)
+			}
+
+			return nil
+		}
+
+		// 1) test a recipient that replies STOP is not sent any more messages
+		if err := keyword(" Stop. "); err != nil {
+			t.Errorf("keyword failed with %v", err)
+			return
+		}
+
+		if code, err := send(); err != nil || code != http.StatusBadRequest {
+			t.Errorf("send() = %d, %v; want %d", code, err, http.StatusBadRequest)
+		}
+
+		// 2) test the opt-out is confirmed by the auto-reply, which is sent even though it has opted out
+		if reply := config("OPT_OUT_REPLY"); reply != "" {
+			replied := waitFor(func() bool {
+				res, err := http.Get(uri + "threads/" + toPhoneNumber + "/" + fromPhoneNumber)
+				if err != nil {
+					return false
+				}
+
+				var thread sms.GetThreadResponse
+				if err := ReadRespone(res.Body, &thread); err != nil {
+					return false
+				}
+
+				for _, m := range thread.Messages {
+					if m.Direction == sms.Direction_OUTBOUND && m.Content == reply {
+						return true
+					}
+				}
+
+				return false
+			})
+
+			if !replied {
+				t.Errorf("no auto-reply in the thread; want %q", reply)
+			}
+		}
+
+		// 3) test a recipient that replies START is sent messages again
+		if err := keyword("start"); err != nil {
+			t.Errorf("keyword failed with %v", err)
+			return
+		}
+
+		if code, err := send(); err != nil || code != http.StatusOK {
+			t.Errorf("send() = %d, %v; want %d", code, err, http.StatusOK)
+		}
+	})
+
+	t.Run("TestUnknownRecipient", func(t *testing.T) {
+		body := `{"from_phone_number": "+16135550199", "to_phone_number": "` + stubs.GetPhoneNumber() + `",
+			"content": "hi", "carrier": "test"}`
+
+		res, err := post(secret, body)
+		if err != nil {
+			t.Errorf("http.Post failed with %v", err)
+			return
+		}
+		res.Body.Close()
+
+		if got, want := res.StatusCode, http.StatusNotFound; got != want {
+			t.Errorf("StatusCode = %d; want %d", got, want)
+		}
+
+		// an invalid phone number
+		body = `{"from_phone_number": "555-0199", "to_phone_number": "` + toPhoneNumber + `", "content": "hi", "carrier": "test"}`
+		res, err = post(secret, body)
+		if err != nil {
+			t.Errorf("http.Post failed with %v", err)
+			return
+		}
+		res.Body.Close()
+
+		if got, want := res.StatusCode, http.StatusBadRequest; got != want {
+			t.Errorf("StatusCode = %d; want %d", got, want)
+		}
+	})
+}
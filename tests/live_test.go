@@ -0,0 +1,172 @@
+package tests
+
+import (
+	"bufio"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+
+	"golang.org/x/net/websocket"
+
+	"github.com/OmarElGabry/go-textnow/internal/pkg/config"
+	"github.com/OmarElGabry/go-textnow/internal/pkg/token"
+	"github.com/OmarElGabry/go-textnow/internal/sms"
+	"github.com/OmarElGabry/go-textnow/tests/stubs"
+	"github.com/golang/protobuf/jsonpb"
+)
+
+func TestLive(t *testing.T) {
+	uri := "http://gateway-service:8080/sms/"
+
+	config, err := config.Load()
+	if err != nil {
+		t.Fatalf("Couldn't load env variables: %v", err)
+	}
+
+	secret := []byte(config("LIVE_TOKEN_SECRET"))
+
+	t.Run("TestToken", func(t *testing.T) {
+		claims := token.Claims{UserID: 1, PhoneNumber: "+16135550172", ExpiresAt: time.Now().Add(time.Minute).Unix()}
+
+		tk, err := token.Sign([]byte("secret"), claims)
+		if err != nil {
+			t.Fatalf("token.Sign failed with %v", err)
+		}
+
+		got, err := token.Verify([]byte("secret"), tk)
+		if err != nil {
+			t.Errorf("token.Verify failed with %v", err)
+		} else if *got != claims {
+			t.Errorf("claims = %+v; want %+v", *got, claims)
+		}
+
+		if _, err := token.Verify([]byte("other secret"), tk); err != token.ErrInvalid {
+			t.Errorf("token.Verify with another secret = %v; want %v", err, token.ErrInvalid)
+		}
+
+		claims.ExpiresAt = time.Now().Add(-time.Minute).Unix()
+		tk, _ = token.Sign([]byte("secret"), claims)
+		if _, err := token.Verify([]byte("secret"), tk); err != token.ErrExpired {
+			t.Errorf("token.Verify of an expired token = %v; want %v", err, token.ErrExpired)
+		}
+	})
+
+	// insert the phone numbers, and issue a token for the recipient
+	fromPhoneNumber := stubs.GetPhoneNumber()
+	toPhoneNumber := stubs.GetPhoneNumber()
+
+	for _, pNumber := range []string{fromPhoneNumber, toPhoneNumber} {
+		_, err := dbMySQL.Exec("INSERT INTO phonebook (user_id, phone_number) VALUES (?, ?)",
+			stubs.GetUserID(), pNumber)
+		if err != nil {
+			t.Fatalf("couldn't insert phone number: %v", err)
+		}
+	}
+
+	tk, err := token.Sign(secret, token.Claims{
+		UserID:      int32(stubs.GetUserID()),
+		PhoneNumber: toPhoneNumber,
+		ExpiresAt:   time.Now().Add(time.Hour).Unix(),
+	})
+
+	if err != nil {
+		t.Fatalf("token.Sign failed with %v", err)
+	}
+
+	t.Run("TestSSE", func(t *testing.T) {
+		// 1) test without a token
+		res, err := http.Get(uri + "live/sse?phone_number=" + toPhoneNumber)
+		if err != nil {
+			t.Errorf("http.Get failed with %v", err)
+			return
+		}
+		res.Body.Close()
+
+		if got, want := res.StatusCode, http.StatusUnauthorized; got != want {
+			t.Errorf("StatusCode = %d; want %d", got, want)
+		}
+
+		// 2) test with a token, and wait for the message
+		client := http.Client{Timeout: 10 * time.Second}
+		res, err = client.Get(uri + "live/sse?phone_number=" + toPhoneNumber + "&token=" + tk)
+		if err != nil {
+			t.Errorf("http.Get failed with %v", err)
+			return
+		}
+		defer res.Body.Close()
+
+		if got, want := res.Header.Get("Content-Type"), "text/event-stream"; got != want {
+			t.Errorf("Content-Type = %s; want %s", got, want)
+		}
+
+		// give the stream some time to subscribe, then send a message
+		time.Sleep(500 * time.Millisecond)
+
+		if _, err := SendSMS(uri, fromPhoneNumber, toPhoneNumber, "over sse"); err != nil {
+			t.Errorf("sending sms failed with %v", err)
+			return
+		}
+
+		// an event is "id: <cursor>" and "data: <json>" lines, followed by an empty line
+		reader := bufio.NewReader(res.Body)
+		var data string
+		for data == "" {
+			line, err := reader.ReadString('\n')
+			if err != nil {
+				t.Errorf("reading the stream failed with %v", err)
+				return
+			}
+
+			if strings.HasPrefix(line, "data: ") {
+				data = strings.TrimPrefix(line, "data: ")
+			}
+		}
+
+		var event sms.SubscribeResponse
+		if err := jsonpb.UnmarshalString(data, &event); err != nil {
+			t.Errorf("parsing the event failed with %v", err)
+			return
+		}
+
+		if got, want := event.Message.GetContent(), "over sse"; got != want {
+			t.Errorf("Content = %s; want %s", got, want)
+		}
+	})
+
+	t.Run("TestWebSocket", func(t *testing.T) {
+		ws, err := websocket.Dial("ws://gateway-service:8080/sms/live/ws?phone_number="+toPhoneNumber+"&token="+tk,
+			"", "http://gateway-service:8080")
+		if err != nil {
+			t.Errorf("websocket.Dial failed with %v", err)
+			return
+		}
+		defer ws.Close()
+
+		// give the stream some time to subscribe, then send a message
+		time.Sleep(500 * time.Millisecond)
+
+		if _, err := SendSMS(uri, fromPhoneNumber, toPhoneNumber, "over websocket"); err != nil {
+			t.Errorf("sending sms failed with %v", err)
+			return
+		}
+
+		ws.SetReadDeadline(time.Now().Add(10 * time.Second))
+
+		var data string
+		if err := websocket.Message.Receive(ws, &data); err != nil {
+			t.Errorf("reading the websocket failed with %v", err)
+			return
+		}
+
+		var event sms.SubscribeResponse
+		if err := jsonpb.UnmarshalString(data, &event); err != nil {
+			t.Errorf("parsing the event failed with %v", err)
+			return
+		}
+
+		if got, want := event.Message.GetContent(), "over websocket"; got != want {
+			t.Errorf("Content = %s; want %s", got, want)
+		}
+	})
+}
@@ -0,0 +1,95 @@
+package tests
+
+import (
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/OmarElGabry/go-textnow/internal/pkg/lru"
+)
+
+func TestLRU(t *testing.T) {
+	t.Run("TestEvictsLeastRecentlyUsed", func(t *testing.T) {
+		cache := lru.New(2, time.Minute)
+		cache.Add("a", 1)
+		cache.Add("b", 2)
+
+		// "a" becomes the most recently used, and so "b" is evicted
+		cache.Get("a")
+		cache.Add("c", 3)
+
+		if _, ok := cache.Get("b"); ok {
+			t.Errorf("b exists; want evicted")
+		}
+
+		if _, ok := cache.Get("a"); !ok {
+			t.Errorf("a is evicted; want exists")
+		}
+
+		if got, want := cache.Len(), 2; got != want {
+			t.Errorf("Len = %d; want %d", got, want)
+		}
+	})
+
+	t.Run("TestExpires", func(t *testing.T) {
+		cache := lru.New(2, 50*time.Millisecond)
+		cache.Add("a", 1)
+
+		time.Sleep(100 * time.Millisecond)
+
+		if _, ok := cache.Get("a"); ok {
+			t.Errorf("a exists; want expired")
+		}
+	})
+
+	t.Run("TestCoalescesConcurrentLoads", func(t *testing.T) {
+		cache := lru.New(10, time.Minute)
+		var loads int32
+
+		var wg sync.WaitGroup
+		for i := 0; i < 10; i++ {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+
+				value, err := cache.GetOrLoad("a", func() (interface{}, error) {
+					atomic.AddInt32(&loads, 1)
+					time.Sleep(100 * time.Millisecond)
+					return 1, nil
+				})
+
+				if err != nil || value != 1 {
+					t.Errorf("GetOrLoad = %v, %v; want 1, nil", value, err)
+				}
+			}()
+		}
+		wg.Wait()
+
+		if got, want := atomic.LoadInt32(&loads), int32(1); got != want {
+			t.Errorf("number of loads = %d; want %d", got, want)
+		}
+	})
+
+	t.Run("TestRemoveWhileLoading", func(t *testing.T) {
+		cache := lru.New(10, time.Minute)
+		loading := make(chan bool)
+
+		go func() {
+			<-loading
+			cache.Remove("a")
+			loading <- true
+		}()
+
+		cache.GetOrLoad("a", func() (interface{}, error) {
+			loading <- true
+			<-loading
+			return 1, nil
+		})
+
+		// the loaded value might be stale, and so it isn't cached
+		if _, ok := cache.Get("a"); ok {
+			t.Errorf("a exists; want removed")
+		}
+	})
+}
@@ -71,6 +71,25 @@ func ReadRespone(source io.ReadCloser, dest proto.Message) error {
 	return nil
 }
 
+// ReadStreamChunk parses a single line of a streamed (newline-delimited JSON) HTTP response
+// to a response struct defined in .proto file. Every line has the response wrapped in "result".
+func ReadStreamChunk(line string, dest proto.Message) error {
+	var chunk struct {
+		Result json.RawMessage `json:"result"`
+		Error  *ErrorBody      `json:"error"`
+	}
+
+	if err := json.Unmarshal([]byte(line), &chunk); err != nil {
+		return err
+	}
+
+	if chunk.Error != nil {
+		return fmt.Errorf("stream error: %s", chunk.Error.Message)
+	}
+
+	return jsonpb.UnmarshalString(string(chunk.Result), dest)
+}
+
 // ReadError reads and parses the incoming JSON error response
 func ReadError(source io.ReadCloser, dest *ErrorBody) error {
 	buf, err := ioutil.ReadAll(source)
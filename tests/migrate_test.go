@@ -0,0 +1,185 @@
+package tests
+
+import (
+	"context"
+	"testing"
+
+	"github.com/OmarElGabry/go-textnow/internal/phonebook"
+	"github.com/OmarElGabry/go-textnow/internal/pkg/migrate"
+)
+
+func TestMigrate(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("TestPhoneBookSchemaIsUpToDate", func(t *testing.T) {
+		migrator, err := migrate.New(dbMySQL.DB, phonebook.Migrations)
+		if err != nil {
+			t.Errorf("migrate.New failed with %v", err)
+			return
+		}
+
+		// phonebook service doesn't start unless the schema is up to date
+		if err := migrator.Check(ctx); err != nil {
+			t.Errorf("Check failed with %v; want success", err)
+		}
+	})
+
+	t.Run("TestUpAndDown", func(t *testing.T) {
+		// use separate tables, not to touch the phonebook schema
+		migrations := []migrate.Migration{
+			{
+				Version: 1,
+				Name:    "create_test_migrate",
+				Up:      []string{"CREATE TABLE test_migrate (id int(11) NOT NULL, PRIMARY KEY (id))"},
+				Down:    []string{"DROP TABLE test_migrate"},
+			},
+			{
+				Version: 2,
+				Name:    "add_test_migrate_name",
+				Up:      []string{"ALTER TABLE test_migrate ADD COLUMN name varchar(48) DEFAULT NULL"},
+				Down:    []string{"ALTER TABLE test_migrate DROP COLUMN name"},
+			},
+		}
+
+		migrator, err := migrate.New(dbMySQL.DB, migrations)
+		if err != nil {
+			t.Errorf("migrate.New failed with %v", err)
+			return
+		}
+		migrator.Table = "test_schema_migrations"
+
+		// clean up from previous runs
+		dbMySQL.Exec("DROP TABLE IF EXISTS test_migrate")
+		dbMySQL.Exec("DROP TABLE IF EXISTS test_schema_migrations")
+
+		// 1) empty database is too old, and checking it doesn't create the table of the applied migrations
+		if err := migrator.Check(ctx); err == nil {
+			t.Errorf("Check succeeded; want %v", migrate.ErrSchemaTooOld)
+			return
+		}
+
+		var count int
+		err = dbMySQL.QueryRow("SELECT COUNT(*) FROM information_schema.tables " +
+			"WHERE table_schema = DATABASE() AND table_name = 'test_schema_migrations'").Scan(&count)
+		if err != nil {
+			t.Errorf("couldn't look up the table: %v", err)
+		} else if count != 0 {
+			t.Errorf("Check created the table of the applied migrations; want it read-only")
+		}
+
+		// 2) apply all migrations
+		applied, err := migrator.Up(ctx)
+		if err != nil {
+			t.Errorf("Up failed with %v", err)
+			return
+		}
+
+		if got, want := applied, 2; got != want {
+			t.Errorf("applied migrations = %d; want %d", got, want)
+		}
+
+		_, err = dbMySQL.Exec("INSERT INTO test_migrate (id, name) VALUES (1, 'name')")
+		if err != nil {
+			t.Errorf("couldn't insert into migrated table: %v", err)
+			return
+		}
+
+		// running it again is a no-op
+		applied, err = migrator.Up(ctx)
+		if err != nil {
+			t.Errorf("Up failed with %v", err)
+			return
+		}
+
+		if got, want := applied, 0; got != want {
+			t.Errorf("applied migrations = %d; want %d", got, want)
+		}
+
+		if err := migrator.Check(ctx); err != nil {
+			t.Errorf("Check failed with %v; want success", err)
+		}
+
+		// 3) revert the last one
+		reverted, err := migrator.Down(ctx, 1)
+		if err != nil {
+			t.Errorf("Down failed with %v", err)
+			return
+		}
+
+		if got, want := reverted, 1; got != want {
+			t.Errorf("reverted migrations = %d; want %d", got, want)
+		}
+
+		version, err := migrator.Version(ctx)
+		if err != nil {
+			t.Errorf("Version failed with %v", err)
+			return
+		}
+
+		if got, want := version, 1; got != want {
+			t.Errorf("version = %d; want %d", got, want)
+		}
+
+		_, err = dbMySQL.Exec("INSERT INTO test_migrate (id, name) VALUES (2, 'name')")
+		if err == nil {
+			t.Errorf("inserted into a reverted column; want error")
+		}
+
+		// 4) revert all
+		_, err = migrator.Down(ctx, 10)
+		if err != nil {
+			t.Errorf("Down failed with %v", err)
+			return
+		}
+
+		dbMySQL.Exec("DROP TABLE IF EXISTS test_schema_migrations")
+	})
+
+	t.Run("TestExists", func(t *testing.T) {
+		// the column is there already, as if the table was created before the migration
+		migrations := []migrate.Migration{
+			{
+				Version: 1,
+				Name:    "create_test_migrate",
+				Up:      []string{"CREATE TABLE test_migrate (id int(11) NOT NULL, name varchar(48) DEFAULT NULL, PRIMARY KEY (id))"},
+				Down:    []string{"DROP TABLE test_migrate"},
+			},
+			{
+				Version: 2,
+				Name:    "add_test_migrate_name",
+				Exists: "SELECT COUNT(*) FROM information_schema.COLUMNS " +
+					"WHERE TABLE_SCHEMA = DATABASE() AND TABLE_NAME = 'test_migrate' AND COLUMN_NAME = 'name'",
+				Up:   []string{"ALTER TABLE test_migrate ADD COLUMN name varchar(48) DEFAULT NULL"},
+				Down: []string{"ALTER TABLE test_migrate DROP COLUMN name"},
+			},
+		}
+
+		migrator, err := migrate.New(dbMySQL.DB, migrations)
+		if err != nil {
+			t.Errorf("migrate.New failed with %v", err)
+			return
+		}
+		migrator.Table = "test_schema_migrations"
+
+		dbMySQL.Exec("DROP TABLE IF EXISTS test_migrate")
+		dbMySQL.Exec("DROP TABLE IF EXISTS test_schema_migrations")
+
+		// the second one is skipped, rather than failing on the duplicate column
+		applied, err := migrator.Up(ctx)
+		if err != nil {
+			t.Errorf("Up failed with %v", err)
+			return
+		}
+
+		if got, want := applied, 2; got != want {
+			t.Errorf("applied migrations = %d; want %d", got, want)
+		}
+
+		if err := migrator.Check(ctx); err != nil {
+			t.Errorf("Check failed with %v; want success", err)
+		}
+
+		migrator.Down(ctx, 10)
+		dbMySQL.Exec("DROP TABLE IF EXISTS test_schema_migrations")
+	})
+}
@@ -1,9 +1,12 @@
 package tests
 
 import (
+	"bufio"
+	"encoding/csv"
 	"net/http"
 	"strconv"
 	"testing"
+	"time"
 
 	"google.golang.org/grpc/codes"
 
@@ -80,6 +83,10 @@ func TestPhoneBook(t *testing.T) {
 			t.Errorf("exists = %t; want = %t", got, want)
 			return
 		}
+
+		if got, want := int(resData.UserId), userID; got != want {
+			t.Errorf("user id = %d; want = %d", got, want)
+		}
 	})
 
 	t.Run("TestReserveAndAssign", func(t *testing.T) {
@@ -88,7 +95,7 @@ func TestPhoneBook(t *testing.T) {
 		areaCode := 613
 		numOfPhoneNumbers := 5
 		phoneNumbers := []string{}
-		areaCodeKey := "areacode-" + strconv.Itoa(int(areaCode))
+		areaCodeKey := "areacode-{" + strconv.Itoa(int(areaCode)) + "}"
 
 		for i := 0; i < numOfPhoneNumbers; i++ {
 			phoneNumbers = append(phoneNumbers, stubs.GetPhoneNumberWithAreaCode(areaCode))
@@ -242,4 +249,236 @@ func TestPhoneBook(t *testing.T) {
 			return
 		}
 	})
+
+	t.Run("TestAssignLegacyReservation", func(t *testing.T) {
+		// a reservation made before the area code was part of the refID
+		areaCode := 905
+		refID := stubs.GetRefID()
+		phoneNumbers := []string{stubs.GetPhoneNumber(), stubs.GetPhoneNumber(), stubs.GetPhoneNumber()}
+
+		_, err := cacheRedis.SAdd("refid-"+refID, phoneNumbers[0], phoneNumbers[1], phoneNumbers[2], "areacode-905").Result()
+		if err != nil {
+			t.Errorf("couldn't insert the reservation: %v", err)
+			return
+		}
+
+		userID := int32(stubs.GetUserID())
+		_, err = dbMySQL.Exec("INSERT INTO phonebook (user_id, phone_number) VALUES (?, NULL)", userID)
+		if err != nil {
+			t.Errorf("couldn't insert new user: %v", err)
+			return
+		}
+
+		postData, err := CreateRequest(&pb.AssignRequest{PhoneNumber: phoneNumbers[0], RefId: refID, UserId: userID})
+		if err != nil {
+			t.Fatalf("failed to write request body %v; want success", err)
+			return
+		}
+
+		res, err := http.Post(uri+"assign", "application/json", postData)
+		if err != nil {
+			t.Errorf("http.Post failed with %v", err)
+			return
+		}
+		defer res.Body.Close()
+
+		var resData pb.AssignResponse
+		if err := ReadRespone(res.Body, &resData); err != nil || !resData.Assigned {
+			t.Errorf("Assigned = %t, %v; want true", resData.Assigned, err)
+			return
+		}
+
+		// the skipped numbers are back in the area code Set of the new format, without the legacy member
+		members, err := cacheRedis.SMembers("areacode-{" + strconv.Itoa(areaCode) + "}").Result()
+		if err != nil {
+			t.Errorf("couldn't get the available phone numbers: %v", err)
+			return
+		}
+
+		if got, want := len(members), 2; got != want {
+			t.Errorf("available phone numbers = %v; want %d of them", members, want)
+		}
+	})
+
+	t.Run("TestWatchAvailability", func(t *testing.T) {
+		areaCode := 416
+		threshold := 2
+		areaCodeKey := "areacode-{" + strconv.Itoa(int(areaCode)) + "}"
+
+		// start with no available numbers, whatever the earlier subtests or runs left behind
+		if _, err := cacheRedis.Del(areaCodeKey).Result(); err != nil {
+			t.Errorf("couldn't clear the area code: %v", err)
+			return
+		}
+
+		// open the stream and wait for the first notification
+		type result struct {
+			res *pb.WatchAvailabilityResponse
+			err error
+		}
+		resChan := make(chan result, 1)
+
+		go func() {
+			client := http.Client{Timeout: 10 * time.Second}
+			res, err := client.Get(uri + "watch/" + strconv.Itoa(areaCode) + "?threshold=" + strconv.Itoa(threshold))
+			if err != nil {
+				resChan <- result{nil, err}
+				return
+			}
+			defer res.Body.Close()
+
+			// every line is a JSON object wrapped in "result"
+			line, err := bufio.NewReader(res.Body).ReadString('\n')
+			if err != nil {
+				resChan <- result{nil, err}
+				return
+			}
+
+			var resData pb.WatchAvailabilityResponse
+			err = ReadStreamChunk(line, &resData)
+			resChan <- result{&resData, err}
+		}()
+
+		// give the stream some time to subscribe, then make numbers available
+		time.Sleep(500 * time.Millisecond)
+
+		phoneNumbers := []string{}
+		for i := 0; i < threshold; i++ {
+			phoneNumbers = append(phoneNumbers, stubs.GetPhoneNumberWithAreaCode(areaCode))
+		}
+
+		_, err := cacheRedis.SAdd(areaCodeKey, phoneNumbers).Result()
+		if err != nil {
+			t.Errorf("couldn't insert phone number: %v", err)
+			return
+		}
+
+		r := <-resChan
+		if r.err != nil {
+			t.Errorf("reading the stream failed with %v", r.err)
+			return
+		}
+
+		if got, want := r.res.AreaCode, int32(areaCode); got != want {
+			t.Errorf("AreaCode = %d; want %d", got, want)
+		}
+
+		if got, want := r.res.Available, int64(threshold); got != want {
+			t.Errorf("Available = %d; want %d", got, want)
+		}
+	})
+	t.Run("TestListAssignments", func(t *testing.T) {
+		// assign 3 phone numbers in the same area code, a minute apart
+		areaCode := 905
+		phoneNumbers := []string{}
+		assignedAt := time.Now().UTC().Add(-time.Hour)
+
+		for i := 0; i < 3; i++ {
+			phoneNumber := stubs.GetPhoneNumberWithAreaCode(areaCode)
+			_, err := dbMySQL.Exec("INSERT INTO phonebook (phone_number, assigned_at) VALUES (?, ?)",
+				phoneNumber, assignedAt.Add(time.Duration(i)*time.Minute).Format("2006-01-02 15:04:05"))
+			if err != nil {
+				t.Errorf("couldn't insert phone number: %v", err)
+				return
+			}
+
+			phoneNumbers = append(phoneNumbers, phoneNumber)
+		}
+
+		// 1) list them in two pages, newest first
+		query := "assignments?area_code=" + strconv.Itoa(areaCode) + "&sort_by=ASSIGNED_AT&descending=true&page_size=2"
+		res, err := http.Get(uri + query)
+		if err != nil {
+			t.Errorf("http.Get failed with %v", err)
+			return
+		}
+		defer res.Body.Close()
+
+		var resData pb.ListAssignmentsResponse
+		err = ReadRespone(res.Body, &resData)
+		if err != nil {
+			t.Errorf("reading res.Body failed with %v", err)
+			return
+		}
+
+		if got, want := len(resData.Assignments), 2; got != want {
+			t.Errorf("length of assignments = %d; want %d", got, want)
+			return
+		}
+
+		if got, want := resData.Assignments[0].PhoneNumber, phoneNumbers[2]; got != want {
+			t.Errorf("first phone number = %s; want %s", got, want)
+		}
+
+		if got, want := len(resData.NextCursor) > 0, true; got != want {
+			t.Errorf("next cursor is missing")
+			return
+		}
+
+		res, err = http.Get(uri + query + "&cursor=" + resData.NextCursor)
+		if err != nil {
+			t.Errorf("http.Get failed with %v", err)
+			return
+		}
+		defer res.Body.Close()
+
+		resData = pb.ListAssignmentsResponse{}
+		err = ReadRespone(res.Body, &resData)
+		if err != nil {
+			t.Errorf("reading res.Body failed with %v", err)
+			return
+		}
+
+		if got, want := len(resData.Assignments), 1; got != want {
+			t.Errorf("length of assignments = %d; want %d", got, want)
+			return
+		}
+
+		if got, want := resData.Assignments[0].PhoneNumber, phoneNumbers[0]; got != want {
+			t.Errorf("last phone number = %s; want %s", got, want)
+		}
+
+		if got, want := resData.NextCursor, ""; got != want {
+			t.Errorf("next cursor = %s; want %s", got, want)
+		}
+
+		// 2) export them as CSV
+		res, err = http.Get(uri + "assignments.csv?area_code=" + strconv.Itoa(areaCode))
+		if err != nil {
+			t.Errorf("http.Get failed with %v", err)
+			return
+		}
+		defer res.Body.Close()
+
+		if got, want := res.StatusCode, http.StatusOK; got != want {
+			t.Errorf("resp.StatusCode = %d; want %d", got, want)
+			return
+		}
+
+		records, err := csv.NewReader(res.Body).ReadAll()
+		if err != nil {
+			t.Errorf("reading CSV failed with %v", err)
+			return
+		}
+
+		// header + 3 phone numbers
+		if got, want := len(records), 4; got != want {
+			t.Errorf("number of CSV records = %d; want %d", got, want)
+		}
+
+		// 3) test an area code that isn't 3 digits
+		res, err = http.Get(uri + "assignments?area_code=90")
+		if err != nil {
+			t.Errorf("http.Get failed with %v", err)
+			return
+		}
+		defer res.Body.Close()
+
+		var errorMsg ErrorBody
+		if err := ReadError(res.Body, &errorMsg); err != nil {
+			t.Errorf("failed to read error body %v; want success", err)
+		} else if got, want := errorMsg.Code, int(codes.InvalidArgument); got != want {
+			t.Errorf("msg.Code = %d; want %d", got, want)
+		}
+	})
 }
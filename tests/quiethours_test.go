@@ -0,0 +1,197 @@
+package tests
+
+import (
+	"fmt"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/OmarElGabry/go-textnow/internal/pkg/phonenumber"
+	"github.com/OmarElGabry/go-textnow/internal/pkg/quiethours"
+	"github.com/OmarElGabry/go-textnow/internal/sms"
+	"github.com/OmarElGabry/go-textnow/tests/stubs"
+
+	"github.com/golang/protobuf/ptypes"
+)
+
+func TestQuietHours(t *testing.T) {
+	uri := "http://gateway-service:8080/sms/"
+
+	t.Run("TestTimeZone", func(t *testing.T) {
+		tests := []struct {
+			number string
+			want   string
+		}{
+			{"+16135550172", "America/Toronto"},
+			{"+16045550172", "America/Vancouver"},
+			{"+12125550172", "America/New_York"},
+			{"+16025550172", "America/Phoenix"},
+			{"+18675550172", ""}, // spans many time zones
+			{"+442079460173", "Europe/London"},
+			{"+35312345678", "Europe/Dublin"},
+			{"+79161234567", ""}, // spans many time zones
+			{"16135550172", ""},
+		}
+
+		for _, test := range tests {
+			if got := phonenumber.TimeZone(test.number); got != test.want {
+				t.Errorf("TimeZone(%q) = %q; want %q", test.number, got, test.want)
+			}
+		}
+	})
+
+	t.Run("TestHold", func(t *testing.T) {
+		toronto, err := time.LoadLocation("America/Toronto")
+		if err != nil {
+			t.Fatalf("LoadLocation failed with %v", err)
+		}
+
+		night, err := quiethours.Parse("21:00", "08:00")
+		if err != nil {
+			t.Fatalf("Parse failed with %v", err)
+		}
+
+		lunch, err := quiethours.Parse("12:00", "13:30")
+		if err != nil {
+			t.Fatalf("Parse failed with %v", err)
+		}
+
+		at := func(month time.Month, day, hour, min int) time.Time {
+			return time.Date(2019, month, day, hour, min, 0, 0, toronto)
+		}
+
+		tests := []struct {
+			window quiethours.Window
+			t      time.Time
+			want   time.Time // zero if it isn't held
+		}{
+			{night, at(10, 1, 20, 59), time.Time{}},
+			{night, at(10, 1, 21, 0), at(10, 2, 8, 0)},
+			{night, at(10, 1, 3, 0), at(10, 1, 8, 0)},
+			{night, at(10, 1, 8, 0), time.Time{}},
+			{night, at(11, 2, 23, 0), at(11, 3, 8, 0)}, // daylight saving time ends at night
+			{lunch, at(10, 1, 12, 15), at(10, 1, 13, 30)},
+			{lunch, at(10, 1, 13, 30), time.Time{}},
+		}
+
+		for _, test := range tests {
+			end, quiet := test.window.Hold(test.t)
+			if quiet != !test.want.IsZero() || !end.Equal(test.want) {
+				t.Errorf("%s Hold(%s) = %s, %t; want %s", test.window, test.t, end, quiet, test.want)
+			}
+		}
+
+		for _, window := range [][2]string{{"8:00", "09:00"}, {"08:00", "24:00"}, {"08:00", "08:00"}} {
+			if _, err := quiethours.Parse(window[0], window[1]); err == nil {
+				t.Errorf("Parse(%q, %q) succeeded; want an error", window[0], window[1])
+			}
+		}
+	})
+
+	t.Run("TestHoldMessage", func(t *testing.T) {
+		userID := stubs.GetUserID()
+		fromPhoneNumber := stubs.GetPhoneNumber()
+		toPhoneNumber := stubs.GetPhoneNumberWithAreaCode(613) // America/Toronto
+
+		_, err := dbMySQL.Exec("INSERT INTO phonebook (user_id, phone_number) VALUES (?, ?)", userID, fromPhoneNumber)
+		if err != nil {
+			t.Fatalf("couldn't insert phone number: %v", err)
+		}
+
+		toronto, err := time.LoadLocation("America/Toronto")
+		if err != nil {
+			t.Fatalf("LoadLocation failed with %v", err)
+		}
+
+		// 1) set the quiet hours to the hour around now in the time zone of the recipient
+		now := time.Now().In(toronto)
+		start, end := now.Add(-time.Hour).Format("15:04"), now.Add(time.Hour).Format("15:04")
+
+		postData, err := CreateRequest(&sms.SetQuietHoursRequest{Start: start, End: end})
+		if err != nil {
+			t.Fatalf("failed to write request body %v; want success", err)
+		}
+
+		req, err := http.NewRequest(http.MethodPut, fmt.Sprintf("%squiet-hours/%d", uri, userID), postData)
+		if err != nil {
+			t.Fatalf("http.NewRequest failed with %v", err)
+		}
+		req.Header.Set("Content-Type", "application/json")
+
+		res, err := http.DefaultClient.Do(req)
+		if err != nil {
+			t.Fatalf("http.Do failed with %v", err)
+		}
+
+		var set sms.SetQuietHoursResponse
+		if err := ReadRespone(res.Body, &set); err != nil {
+			t.Fatalf("ReadRespone failed with %v", err)
+		}
+
+		if q := set.QuietHours; q.GetUserId() != int32(userID) || q.GetStart() != start || q.GetEnd() != end {
+			t.Errorf("quiet hours = %+v; want %s to %s of user %d", q, start, end, userID)
+		}
+
+		defer func() {
+			req, _ := http.NewRequest(http.MethodDelete, fmt.Sprintf("%squiet-hours/%d", uri, userID), nil)
+			if res, err := http.DefaultClient.Do(req); err == nil {
+				res.Body.Close()
+			}
+		}()
+
+		send := func(priority sms.Priority) (*sms.SendOneResponse, error) {
+			postData, err := CreateRequest(&sms.SendOneRequest{
+				Sms: &sms.SMS{
+					IdempotencyKey:  stubs.GetIdempotencyKey(),
+					FromPhoneNumber: fromPhoneNumber,
+					ToPhoneNumber:   toPhoneNumber,
+					Content:         "50% off everything today",
+					Priority:        priority,
+				},
+			})
+			if err != nil {
+				return nil, err
+			}
+
+			res, err := http.Post(uri+"send/one", "application/json", postData)
+			if err != nil {
+				return nil, err
+			}
+
+			var sent sms.SendOneResponse
+			if err := ReadRespone(res.Body, &sent); err != nil {
+				return nil, err
+			}
+
+			return &sent, nil
+		}
+
+		// 2) test a normal sms is held until the quiet hours end
+		held, err := send(sms.Priority_NORMAL)
+		if err != nil {
+			t.Errorf("send failed with %v", err)
+			return
+		}
+
+		sendAt, err := ptypes.Timestamp(held.GetSendAt())
+		if err != nil {
+			t.Errorf("send_at = %v; want a timestamp", held.GetSendAt())
+			return
+		}
+
+		if got := sendAt.In(toronto).Format("15:04"); held.Status != sms.Status_SCHEDULED || got != end {
+			t.Errorf("response = %s at %s; want %s at %s", held.Status, got, sms.Status_SCHEDULED, end)
+		}
+
+		// 3) test an urgent sms is sent right away
+		urgent, err := send(sms.Priority_URGENT)
+		if err != nil {
+			t.Errorf("send failed with %v", err)
+			return
+		}
+
+		if urgent.Status == sms.Status_SCHEDULED || urgent.GetSendAt() != nil {
+			t.Errorf("response = %+v; want it sent right away", urgent)
+		}
+	})
+}
@@ -0,0 +1,159 @@
+package tests
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/golang/protobuf/ptypes"
+	"github.com/grpc-ecosystem/grpc-gateway/runtime"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"github.com/OmarElGabry/go-textnow/internal/gateway"
+	"github.com/OmarElGabry/go-textnow/internal/pkg/ratelimit"
+	"github.com/OmarElGabry/go-textnow/tests/stubs"
+)
+
+func TestRateLimit(t *testing.T) {
+	limiter := ratelimit.NewLimiter(cacheRedis)
+
+	t.Run("TestParseRules", func(t *testing.T) {
+		rules, err := ratelimit.ParseRules("10/s, 300/m,10000/d")
+		if err != nil {
+			t.Errorf("ParseRules failed with %v", err)
+			return
+		}
+
+		want := []ratelimit.Rule{{Count: 10, Period: time.Second}, {Count: 300, Period: time.Minute}, {Count: 10000, Period: 24 * time.Hour}}
+		if len(rules) != len(want) {
+			t.Errorf("rules = %v; want %v", rules, want)
+			return
+		}
+
+		for i := range want {
+			if rules[i] != want[i] {
+				t.Errorf("rules[%d] = %v; want %v", i, rules[i], want[i])
+			}
+		}
+
+		if rules, err := ratelimit.ParseRules(""); err != nil || len(rules) != 0 {
+			t.Errorf("ParseRules(\"\") = %v, %v; want no rules", rules, err)
+		}
+
+		for _, s := range []string{"10", "10/w", "0/s", "-1/m", "a/s", "10/s,"} {
+			if _, err := ratelimit.ParseRules(s); err == nil {
+				t.Errorf("ParseRules(%q) succeeded; want an error", s)
+			}
+		}
+	})
+
+	t.Run("TestTake", func(t *testing.T) {
+		bucket := ratelimit.Bucket{Key: "{test:" + stubs.GetPhoneNumber() + "}", Rules: []ratelimit.Rule{{Count: 3, Period: time.Second}}}
+
+		// 1) test taking up to the limit
+		for i := 0; i < 3; i++ {
+			if wait, err := limiter.Take(1, bucket); err != nil || wait != 0 {
+				t.Errorf("Take() = %v, %v; want no wait", wait, err)
+				return
+			}
+		}
+
+		// 2) test it is limited, until a token is refilled (1 every 333ms)
+		wait, err := limiter.Take(1, bucket)
+		if err != nil || wait <= 0 || wait > 334*time.Millisecond {
+			t.Errorf("Take() = %v, %v; want to wait at most 334ms", wait, err)
+			return
+		}
+
+		time.Sleep(wait)
+
+		if wait, err := limiter.Take(1, bucket); err != nil || wait != 0 {
+			t.Errorf("Take() after waiting = %v, %v; want no wait", wait, err)
+		}
+
+		// 3) test taking more than the limit at once
+		if _, err := limiter.Take(4, bucket); err != ratelimit.ErrExceedsLimit {
+			t.Errorf("Take(4) error = %v; want %v", err, ratelimit.ErrExceedsLimit)
+		}
+	})
+
+	t.Run("TestTakeAll", func(t *testing.T) {
+		tag := "{test:" + stubs.GetPhoneNumber() + "}"
+		number := ratelimit.Bucket{Key: tag + "-number", Rules: []ratelimit.Rule{{Count: 2, Period: time.Minute}}}
+		account := ratelimit.Bucket{Key: tag + "-account", Rules: []ratelimit.Rule{{Count: 1, Period: time.Minute}}}
+
+		if wait, err := limiter.Take(1, number, account); err != nil || wait != 0 {
+			t.Errorf("Take() = %v, %v; want no wait", wait, err)
+			return
+		}
+
+		// the account is limited, and so nothing is taken from the number
+		if wait, err := limiter.Take(1, number, account); err != nil || wait == 0 {
+			t.Errorf("Take() = %v, %v; want to wait", wait, err)
+			return
+		}
+
+		if wait, err := limiter.Take(1, number); err != nil || wait != 0 {
+			t.Errorf("Take() of the number = %v, %v; want no wait", wait, err)
+		}
+
+		if wait, err := limiter.Take(1, number); err != nil || wait == 0 {
+			t.Errorf("Take() of the number = %v, %v; want to wait", wait, err)
+		}
+	})
+
+	t.Run("TestGive", func(t *testing.T) {
+		bucket := ratelimit.Bucket{Key: "{test:" + stubs.GetPhoneNumber() + "}", Rules: []ratelimit.Rule{{Count: 2, Period: time.Minute}}}
+
+		if wait, err := limiter.Take(2, bucket); err != nil || wait != 0 {
+			t.Errorf("Take() = %v, %v; want no wait", wait, err)
+			return
+		}
+
+		// give back more than was taken, and so the bucket is only full
+		if err := limiter.Give(3, bucket); err != nil {
+			t.Errorf("Give() failed with %v; want success", err)
+			return
+		}
+
+		if wait, err := limiter.Take(2, bucket); err != nil || wait != 0 {
+			t.Errorf("Take() after Give() = %v, %v; want no wait", wait, err)
+			return
+		}
+
+		if wait, err := limiter.Take(1, bucket); err != nil || wait == 0 {
+			t.Errorf("Take() = %v, %v; want to wait", wait, err)
+		}
+	})
+
+	t.Run("TestRetryAfter", func(t *testing.T) {
+		st, err := status.New(codes.ResourceExhausted, "Rate limit is exceeded").
+			WithDetails(&errdetails.RetryInfo{RetryDelay: ptypes.DurationProto(1500 * time.Millisecond)})
+		if err != nil {
+			t.Fatalf("WithDetails failed with %v", err)
+		}
+
+		w := httptest.NewRecorder()
+		req := httptest.NewRequest(http.MethodPost, "/sms/send/one", nil)
+		gateway.HTTPError(context.Background(), runtime.NewServeMux(), &runtime.JSONPb{}, w, req, st.Err())
+
+		if got, want := w.Code, http.StatusTooManyRequests; got != want {
+			t.Errorf("StatusCode = %d; want %d", got, want)
+		}
+
+		if got, want := w.Header().Get("Retry-After"), "2"; got != want {
+			t.Errorf("Retry-After = %q; want %q", got, want)
+		}
+
+		var errorMsg ErrorBody
+		if err := ReadError(w.Result().Body, &errorMsg); err != nil {
+			t.Errorf("failed to read error body %v; want success", err)
+		} else if got, want := errorMsg.Code, int(codes.ResourceExhausted); got != want {
+			t.Errorf("msg.Code = %d; want %d", got, want)
+		}
+	})
+}
@@ -0,0 +1,287 @@
+package tests
+
+import (
+	"context"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/OmarElGabry/go-textnow/internal/pkg/carrier"
+	"github.com/OmarElGabry/go-textnow/internal/pkg/gsm"
+	"github.com/OmarElGabry/go-textnow/internal/pkg/smpp"
+)
+
+// waitFor waits for the condition to be true, up to a second
+func waitFor(condition func() bool) bool {
+	for deadline := time.Now().Add(time.Second); time.Now().Before(deadline); {
+		if condition() {
+			return true
+		}
+
+		time.Sleep(10 * time.Millisecond)
+	}
+
+	return condition()
+}
+
+func TestSMPP(t *testing.T) {
+	sim := smpp.NewSimulator("textnow", "secret")
+	sim.Undeliverable = func(destination string) bool { return strings.HasPrefix(destination, "1999") }
+	sim.Reject = func(m *smpp.Message) bool { _, udh := m.UserData(); return string(udh) == string(gsm.UDH(9, 3, 2)) }
+	if err := sim.Start("127.0.0.1:0"); err != nil {
+		t.Fatalf("Failed to start the simulator: %v", err)
+	}
+	defer sim.Close()
+
+	config := smpp.Config{
+		Addr:           sim.Addr(),
+		SystemID:       "textnow",
+		Password:       "secret",
+		ReconnectDelay: 10 * time.Millisecond,
+	}
+
+	t.Run("TestBind", func(t *testing.T) {
+		wrong := config
+		wrong.Password = "wrong"
+
+		client := smpp.Dial(wrong)
+		defer client.Close()
+
+		time.Sleep(100 * time.Millisecond)
+		if _, err := client.Submit(context.Background(), &smpp.Message{}); err == nil || client.Bound() {
+			t.Errorf("Submit succeeded with a wrong password")
+		}
+	})
+
+	t.Run("TestReceipt", func(t *testing.T) {
+		r, err := smpp.ParseReceipt("id:sim-7 sub:001 dlvrd:000 submit date:1910011200 done date:1910011201 stat:UNDELIV err:001 text:hi there")
+		if err != nil {
+			t.Errorf("ParseReceipt failed with %v", err)
+			return
+		}
+
+		if r.ID != "sim-7" || r.Stat != "UNDELIV" || r.Err != "001" || r.Text != "hi there" || r.Delivered() {
+			t.Errorf("ParseReceipt = %+v", r)
+		}
+
+		if _, err := smpp.ParseReceipt("hi there"); err == nil {
+			t.Errorf("ParseReceipt succeeded with an invalid receipt")
+		}
+	})
+
+	var mu sync.Mutex
+	reports, inbound := []*carrier.Report{}, []*carrier.Inbound{}
+
+	c := carrier.NewSMPP("smpp", config)
+	defer c.Close()
+
+	c.Listen(func(r *carrier.Report) {
+		mu.Lock()
+		defer mu.Unlock()
+		reports = append(reports, r)
+	}, func(in *carrier.Inbound) {
+		mu.Lock()
+		defer mu.Unlock()
+		inbound = append(inbound, in)
+	})
+
+	reportOf := func(id string) *carrier.Report {
+		mu.Lock()
+		defer mu.Unlock()
+		for _, r := range reports {
+			if r.CarrierMessageID == id {
+				return r
+			}
+		}
+		return nil
+	}
+
+	t.Run("TestSend", func(t *testing.T) {
+		ping := &carrier.Message{ID: "0", From: "+16135550172", To: "+16135550173", Encoding: gsm.GSM7, Segments: []string{"ping"}}
+		if !waitFor(func() bool { _, err := c.Send(context.Background(), ping); return err == nil }) {
+			t.Errorf("SMPP carrier isn't bound")
+			return
+		}
+
+		text := strings.Repeat("ж", 71)
+		encoding, segments := gsm.Split(text)
+		before := len(sim.Submitted())
+
+		receipt, err := c.Send(context.Background(), &carrier.Message{
+			ID: "1", From: "+16135550172", To: "+16135550173", Encoding: encoding, Segments: segments, SegmentRef: 7,
+		})
+
+		if err != nil {
+			t.Errorf("Send failed with %v", err)
+			return
+		}
+
+		if receipt.Delivered {
+			t.Errorf("receipt is delivered; want it on its way")
+		}
+
+		submitted := sim.Submitted()[before:]
+		if len(submitted) != 2 {
+			t.Errorf("number of submit_sm = %d; want 2", len(submitted))
+			return
+		}
+
+		joined := ""
+		for i, m := range submitted {
+			data, udh := m.UserData()
+			if m.Destination != "16135550173" || m.DataCoding != smpp.CodingUCS2 || string(udh) != string(gsm.UDH(7, 2, i+1)) {
+				t.Errorf("segment %d = %+v", i+1, m)
+			}
+
+			// the delivery receipt is asked for the last segment only
+			if got, want := m.RegisteredDelivery, byte(i); got != want {
+				t.Errorf("segment %d: registered delivery = %d; want %d", i+1, got, want)
+			}
+
+			joined += gsm.Decode(data, gsm.UCS2)
+		}
+
+		if joined != text {
+			t.Errorf("content = %q; want %q", joined, text)
+		}
+
+		if !waitFor(func() bool { return reportOf(receipt.CarrierMessageID) != nil }) {
+			t.Errorf("no report of %s", receipt.CarrierMessageID)
+		} else if r := reportOf(receipt.CarrierMessageID); !r.Delivered || r.Carrier != "smpp" {
+			t.Errorf("report = %+v; want delivered", r)
+		}
+
+		// undeliverable
+		receipt, err = c.Send(context.Background(), &carrier.Message{
+			ID: "2", From: "+16135550172", To: "+19995550173", Encoding: gsm.GSM7, Segments: []string{"hi"},
+		})
+
+		if err != nil {
+			t.Errorf("Send failed with %v", err)
+		} else if !waitFor(func() bool { return reportOf(receipt.CarrierMessageID) != nil }) {
+			t.Errorf("no report of %s", receipt.CarrierMessageID)
+		} else if r := reportOf(receipt.CarrierMessageID); r.Delivered || r.Reason == "" {
+			t.Errorf("report = %+v; want undelivered with a reason", r)
+		}
+	})
+
+	t.Run("TestPartialSend", func(t *testing.T) {
+		// the second segment is rejected, after the first one was accepted
+		_, err := c.Send(context.Background(), &carrier.Message{
+			ID: "3", From: "+16135550172", To: "+16135550173", Encoding: gsm.GSM7, Segments: []string{"a", "b", "c"}, SegmentRef: 9,
+		})
+
+		partial, ok := err.(*carrier.PartialError)
+		if !ok {
+			t.Errorf("Send error = %v; want a partial error", err)
+			return
+		}
+
+		if partial.Sent != 1 || partial.Total != 3 {
+			t.Errorf("partial error = %v; want 1 of 3 segments sent", partial)
+		}
+	})
+
+	t.Run("TestInbound", func(t *testing.T) {
+		// the segments of a concatenated message are joined, even if they come out of order
+		for _, seq := range []int{2, 1} {
+			err := sim.Deliver(&smpp.Message{
+				Source:       "16135550199",
+				Destination:  "16135550172",
+				ESMClass:     smpp.ESMClassUDHI,
+				DataCoding:   smpp.CodingDefault,
+				ShortMessage: append(gsm.UDH(3, 2, seq), gsm.Encode([]string{"hi, ", "how are you? €"}[seq-1], gsm.GSM7)...),
+			})
+
+			if err != nil {
+				t.Errorf("Deliver failed with %v", err)
+				return
+			}
+		}
+
+		got := func() []*carrier.Inbound {
+			mu.Lock()
+			defer mu.Unlock()
+			return append([]*carrier.Inbound{}, inbound...)
+		}
+
+		if !waitFor(func() bool { return len(got()) == 1 }) {
+			t.Errorf("number of inbound messages = %d; want 1", len(got()))
+			return
+		}
+
+		if in := got()[0]; in.From != "+16135550199" || in.To != "+16135550172" || in.Content != "hi, how are you? €" {
+			t.Errorf("inbound = %+v", in)
+		}
+	})
+
+	t.Run("TestWindow", func(t *testing.T) {
+		slow := smpp.NewSimulator("textnow", "secret")
+		slow.ResponseDelay = 50 * time.Millisecond
+		if err := slow.Start("127.0.0.1:0"); err != nil {
+			t.Fatalf("Failed to start the simulator: %v", err)
+		}
+		defer slow.Close()
+
+		windowed := config
+		windowed.Addr, windowed.Window = slow.Addr(), 2
+
+		client := smpp.Dial(windowed)
+		defer client.Close()
+
+		if !waitFor(client.Bound) {
+			t.Errorf("client isn't bound")
+			return
+		}
+
+		var wg sync.WaitGroup
+		for i := 0; i < 6; i++ {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				if _, err := client.Submit(context.Background(), &smpp.Message{Destination: "16135550173"}); err != nil {
+					t.Errorf("Submit failed with %v", err)
+				}
+			}()
+		}
+		wg.Wait()
+
+		if got := slow.MaxOutstanding(); got != 2 {
+			t.Errorf("max outstanding submit_sm = %d; want 2", got)
+		}
+	})
+
+	t.Run("TestEnquireLink", func(t *testing.T) {
+		linked := config
+		linked.EnquireLinkInterval = 20 * time.Millisecond
+
+		client := smpp.Dial(linked)
+		defer client.Close()
+
+		before := sim.EnquireLinks()
+		if !waitFor(func() bool { return sim.EnquireLinks() >= before+3 }) {
+			t.Errorf("enquire_link wasn't sent")
+		}
+	})
+
+	t.Run("TestRebind", func(t *testing.T) {
+		client := smpp.Dial(config)
+		defer client.Close()
+
+		if !waitFor(client.Bound) {
+			t.Errorf("client isn't bound")
+			return
+		}
+
+		sim.Disconnect()
+
+		// it is bound again after the connection is lost
+		if !waitFor(func() bool {
+			_, err := client.Submit(context.Background(), &smpp.Message{Destination: "16135550173"})
+			return err == nil
+		}) {
+			t.Errorf("client isn't bound again")
+		}
+	})
+}
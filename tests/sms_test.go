@@ -3,12 +3,18 @@ package tests
 import (
 	"context"
 	"net/http"
+	"strings"
 	"testing"
+	"time"
 
 	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
 
+	"github.com/golang/protobuf/ptypes"
+	"google.golang.org/grpc"
 	"google.golang.org/grpc/codes"
 
+	"github.com/OmarElGabry/go-textnow/internal/pkg/config"
 	"github.com/OmarElGabry/go-textnow/internal/sms"
 	"github.com/OmarElGabry/go-textnow/tests/stubs"
 )
@@ -120,6 +126,16 @@ func TestSMS(t *testing.T) {
 			t.Errorf("Sent = %t; want %t", got, want)
 		}
 
+		if got, want := resData.Status, sms.Status_DELIVERED; got != want {
+			t.Errorf("Status = %s; want %s", got, want)
+		}
+
+		if got, want := resData.Route, sms.Route_ON_NET; got != want {
+			t.Errorf("Route = %s; want %s", got, want)
+		}
+
+		messageID := resData.MessageId
+
 		// check database
 		cunt, err = dbMongo.CountDocuments(context.TODO(), filter)
 		if err != nil {
@@ -166,10 +182,239 @@ func TestSMS(t *testing.T) {
 			return
 		}
 
-		if got, want := len(resData.Message) > 0, true; got != want {
-			t.Errorf("Message string is missing")
+		// the retry gets the response of the first request
+		if got, want := res.Header.Get("Idempotent-Replayed"), "true"; got != want {
+			t.Errorf("Idempotent-Replayed = %q; want %q", got, want)
+		}
+
+		if got, want := resData.Status, sms.Status_DELIVERED; got != want {
+			t.Errorf("Status = %s; want %s", got, want)
+		}
+
+		if got, want := resData.MessageId, messageID; got != want {
+			t.Errorf("MessageId = %s; want %s", got, want)
+			return
+		}
+
+		// 4) test with existing idempotency key, but a different content
+		postData, err = CreateRequest(&sms.SendOneRequest{
+			Sms: &sms.SMS{
+				IdempotencyKey:  idempotencyKey,
+				FromPhoneNumber: fromPhoneNumber,
+				ToPhoneNumber:   toPhoneNumber,
+				Content:         "another content of the sms",
+			},
+		})
+
+		if err != nil {
+			t.Fatalf("failed to write request body %v; want success", err)
+			return
+		}
+		res, err = http.Post(uri+"send/one", "application/json", postData)
+		if err != nil {
+			t.Errorf("http.Post failed with %v", err)
+			return
+		}
+		defer res.Body.Close()
+
+		if got, want := res.StatusCode, http.StatusConflict; got != want {
+			t.Errorf("status code = %d; want %d", got, want)
+		}
+
+		// 5) test the key can be used again once its window is over (expired, but not deleted by MongoDB yet)
+		// 	the key of the message, and the one of the idempotency interceptor
+		past := bson.M{"$set": bson.M{"expiresAt": time.Now().Add(-time.Second)}}
+		for collection, key := range map[string]string{
+			"messageKeys": idempotencyKey,
+			"idempotency": "/sms.SMSService/SendOne:" + idempotencyKey,
+		} {
+			_, err := dbMongo.Database().Collection(collection).UpdateOne(context.TODO(), bson.M{"_id": key}, past)
+			if err != nil {
+				t.Errorf("failed to expire the key %v; want success", err)
+				return
+			}
+		}
+
+		postData, err = CreateRequest(&sms.SendOneRequest{
+			Sms: &sms.SMS{
+				IdempotencyKey:  idempotencyKey,
+				FromPhoneNumber: fromPhoneNumber,
+				ToPhoneNumber:   toPhoneNumber,
+				Content:         "another content of the sms",
+			},
+		})
+
+		if err != nil {
+			t.Fatalf("failed to write request body %v; want success", err)
+			return
+		}
+		res, err = http.Post(uri+"send/one", "application/json", postData)
+		if err != nil {
+			t.Errorf("http.Post failed with %v", err)
+			return
+		}
+		defer res.Body.Close()
+
+		resData = sms.SendOneResponse{}
+		if err := ReadRespone(res.Body, &resData); err != nil {
+			t.Errorf("reading res.Body failed with %v", err)
+			return
+		}
+
+		if resData.MessageId == "" || resData.MessageId == messageID {
+			t.Errorf("MessageId = %s; want a new message", resData.MessageId)
+		}
+	})
+
+	t.Run("TestGetMessageStatus", func(t *testing.T) {
+		fromPhoneNumber := stubs.GetPhoneNumber()
+		toPhoneNumber := stubs.GetPhoneNumber()
+
+		for _, pNumber := range []string{fromPhoneNumber, toPhoneNumber} {
+			_, err := dbMySQL.Exec("INSERT INTO phonebook (user_id, phone_number) VALUES (?, ?)",
+				stubs.GetUserID(), pNumber)
+			if err != nil {
+				t.Errorf("couldn't insert phone number: %v", err)
+				return
+			}
+		}
+
+		postData, err := CreateRequest(&sms.SendOneRequest{
+			Sms: &sms.SMS{
+				IdempotencyKey:  stubs.GetIdempotencyKey(),
+				FromPhoneNumber: fromPhoneNumber,
+				ToPhoneNumber:   toPhoneNumber,
+				Content:         "content of the sms",
+			},
+		})
+
+		if err != nil {
+			t.Fatalf("failed to write request body %v; want success", err)
+			return
+		}
+
+		res, err := http.Post(uri+"send/one", "application/json", postData)
+		if err != nil {
+			t.Errorf("http.Post failed with %v", err)
+			return
+		}
+		defer res.Body.Close()
+
+		var sent sms.SendOneResponse
+		err = ReadRespone(res.Body, &sent)
+		if err != nil {
+			t.Errorf("reading res.Body failed with %v", err)
+			return
+		}
+
+		// 1) test with the id of the sent message
+		res, err = http.Get(uri + "status/" + sent.MessageId)
+		if err != nil {
+			t.Errorf("http.Get failed with %v", err)
+			return
+		}
+		defer res.Body.Close()
+
+		var resData sms.GetMessageStatusResponse
+		err = ReadRespone(res.Body, &resData)
+		if err != nil {
+			t.Errorf("reading res.Body failed with %v", err)
+			return
+		}
+
+		if got, want := resData.MessageId, sent.MessageId; got != want {
+			t.Errorf("MessageId = %s; want %s", got, want)
+		}
+
+		if got, want := resData.Status, sms.Status_DELIVERED; got != want {
+			t.Errorf("Status = %s; want %s", got, want)
+		}
+
+		if resData.CreatedAt == nil || resData.UpdatedAt == nil {
+			t.Errorf("CreatedAt or UpdatedAt is missing")
+		}
+
+		// 2) test with a message id that doesn't exist
+		res, err = http.Get(uri + "status/000000000000000000000000")
+		if err != nil {
+			t.Errorf("http.Get failed with %v", err)
+			return
+		}
+		defer res.Body.Close()
+
+		var errorMsg ErrorBody
+		err = ReadError(res.Body, &errorMsg)
+		if err != nil {
+			t.Errorf("failed to read error body %v; want success", err)
+			return
+		}
+
+		if got, want := errorMsg.Code, int(codes.NotFound); got != want {
+			t.Errorf("msg.Code = %d; want %d", got, want)
+		}
+	})
+
+	t.Run("TestRoutes", func(t *testing.T) {
+		fromPhoneNumber := stubs.GetPhoneNumber()
+
+		_, err := dbMySQL.Exec("INSERT INTO phonebook (user_id, phone_number) VALUES (?, ?)",
+			stubs.GetUserID(), fromPhoneNumber)
+		if err != nil {
+			t.Errorf("couldn't insert phone number: %v", err)
+			return
+		}
+
+		// the recipient isn't on the platform, and so it is sent by the (fake) carrier
+		postData, err := CreateRequest(&sms.SendOneRequest{
+			Sms: &sms.SMS{
+				IdempotencyKey:  stubs.GetIdempotencyKey(),
+				FromPhoneNumber: fromPhoneNumber,
+				ToPhoneNumber:   stubs.GetPhoneNumber(),
+				Content:         "content of the sms",
+			},
+		})
+
+		if err != nil {
+			t.Fatalf("failed to write request body %v; want success", err)
+			return
+		}
+
+		res, err := http.Post(uri+"send/one", "application/json", postData)
+		if err != nil {
+			t.Errorf("http.Post failed with %v", err)
+			return
+		}
+		defer res.Body.Close()
+
+		var resData sms.SendOneResponse
+		if err := ReadRespone(res.Body, &resData); err != nil {
+			t.Errorf("reading res.Body failed with %v", err)
+			return
+		}
+
+		if got, want := resData.Route, sms.Route_OFF_NET; got != want {
+			t.Errorf("Route = %s; want %s", got, want)
+		}
+
+		if got, want := resData.Status, sms.Status_DELIVERED; got != want {
+			t.Errorf("Status = %s; want %s", got, want)
+		}
+
+		// check database: the route is stored, along with the carrier that sent it
+		var stored struct {
+			Route   string `bson:"route"`
+			Carrier string `bson:"carrier"`
+		}
+
+		id, _ := primitive.ObjectIDFromHex(resData.MessageId)
+		if err := dbMongo.FindOne(context.TODO(), bson.M{"_id": id}).Decode(&stored); err != nil {
+			t.Errorf("failed to find the message %v; want success", err)
 			return
 		}
+
+		if stored.Route != sms.Route_OFF_NET.String() || stored.Carrier == "" {
+			t.Errorf("stored message = %+v; want an off-net message sent by a carrier", stored)
+		}
 	})
 
 	t.Run("TestSendMany", func(t *testing.T) {
@@ -236,10 +481,55 @@ func TestSMS(t *testing.T) {
 		err = ReadRespone(res.Body, &resData)
 		if err != nil {
 			t.Errorf("reading res.Body failed with %v", err)
+			return
+		}
+
+		if resData.TrackingId == "" {
+			t.Errorf("TrackingId is missing")
+			return
+		}
+
+		// the SMSs are sent in the background, and so poll until they are all sent
+		var tracking sms.GetTrackingResponse
+		for i := 0; i < 50; i++ {
+			res, err = http.Get(uri + "tracking/" + resData.TrackingId)
+			if err != nil {
+				t.Errorf("http.Get failed with %v", err)
+				return
+			}
+			defer res.Body.Close()
+
+			tracking = sms.GetTrackingResponse{}
+			err = ReadRespone(res.Body, &tracking)
+			if err != nil {
+				t.Errorf("reading res.Body failed with %v", err)
+				return
+			}
+
+			if tracking.State == sms.GetTrackingResponse_DONE {
+				break
+			}
+
+			time.Sleep(100 * time.Millisecond)
+		}
+
+		if got, want := tracking.State, sms.GetTrackingResponse_DONE; got != want {
+			t.Errorf("State = %s; want %s", got, want)
+			return
+		}
+
+		if got, want := tracking.Sent, int32(2); got != want {
+			t.Errorf("number of sent sms = %d; want %d", got, want)
+		}
+
+		if got, want := tracking.Failed, int32(0); got != want {
+			t.Errorf("number of failed sms = %d; want %d", got, want)
 		}
 
-		if got, want := len(resData.Errors), 0; got != want {
-			t.Errorf("number of errors = %d; want %d", got, want)
+		for _, m := range tracking.Messages {
+			if m.MessageId == "" {
+				t.Errorf("MessageId of %s is missing", m.IdempotencyKey)
+			}
 		}
 
 		// check database
@@ -255,4 +545,789 @@ func TestSMS(t *testing.T) {
 			return
 		}
 	})
+
+	t.Run("TestSegmentation", func(t *testing.T) {
+		fromPhoneNumber := stubs.GetPhoneNumber()
+		toPhoneNumber := stubs.GetPhoneNumber()
+
+		for _, pNumber := range []string{fromPhoneNumber, toPhoneNumber} {
+			_, err := dbMySQL.Exec("INSERT INTO phonebook (user_id, phone_number) VALUES (?, ?)",
+				stubs.GetUserID(), pNumber)
+			if err != nil {
+				t.Errorf("couldn't insert phone number: %v", err)
+				return
+			}
+		}
+
+		// 100 characters with an emoji, and so it is UCS2 and doesn't fit in one sms
+		content := strings.Repeat("a", 98) + "😀"
+		resData, err := SendSMS(uri, fromPhoneNumber, toPhoneNumber, content)
+		if err != nil {
+			t.Errorf("sending sms failed with %v", err)
+			return
+		}
+
+		if got, want := resData.Encoding, sms.Encoding_UCS2; got != want {
+			t.Errorf("Encoding = %s; want %s", got, want)
+		}
+
+		if got, want := resData.SegmentCount, int32(2); got != want {
+			t.Errorf("SegmentCount = %d; want %d", got, want)
+		}
+
+		// check database, the segments are stored with the message
+		var doc struct {
+			Segments []struct {
+				Seq     int    `bson:"seq"`
+				Content string `bson:"content"`
+			} `bson:"segments"`
+		}
+
+		err = dbMongo.FindOne(context.TODO(), bson.M{"from": fromPhoneNumber}).Decode(&doc)
+		if err != nil {
+			t.Errorf("failed to find the message in db %v; want success", err)
+			return
+		}
+
+		if got, want := len(doc.Segments), 2; got != want {
+			t.Errorf("number of segments in db = %d; want %d", got, want)
+			return
+		}
+
+		if got := doc.Segments[0].Content + doc.Segments[1].Content; got != content {
+			t.Errorf("joined segments = %q; want %q", got, content)
+		}
+	})
+
+	t.Run("TestSmartEncoding", func(t *testing.T) {
+		fromPhoneNumber := stubs.GetPhoneNumber()
+		toPhoneNumber := stubs.GetPhoneNumber()
+
+		for _, pNumber := range []string{fromPhoneNumber, toPhoneNumber} {
+			_, err := dbMySQL.Exec("INSERT INTO phonebook (user_id, phone_number) VALUES (?, ?)",
+				stubs.GetUserID(), pNumber)
+			if err != nil {
+				t.Errorf("couldn't insert phone number: %v", err)
+				return
+			}
+		}
+
+		// the curly quotes would force the sms into UCS2, and so into 2 segments
+		content := strings.Repeat("a", 96) + "“quoted”"
+		postData, err := CreateRequest(&sms.SendOneRequest{
+			Sms: &sms.SMS{
+				IdempotencyKey:  stubs.GetIdempotencyKey(),
+				FromPhoneNumber: fromPhoneNumber,
+				ToPhoneNumber:   toPhoneNumber,
+				Content:         content,
+				SmartEncoding:   true,
+			},
+		})
+
+		if err != nil {
+			t.Errorf("couldn't create request: %v", err)
+			return
+		}
+
+		res, err := http.Post(uri+"send/one", "application/json", postData)
+		if err != nil {
+			t.Errorf("sending sms failed with %v", err)
+			return
+		}
+		defer res.Body.Close()
+
+		var resData sms.SendOneResponse
+		if err := ReadRespone(res.Body, &resData); err != nil {
+			t.Errorf("couldn't read response: %v", err)
+			return
+		}
+
+		if got, want := resData.Encoding, sms.Encoding_GSM7; got != want {
+			t.Errorf("Encoding = %s; want %s", got, want)
+		}
+
+		if got, want := resData.SegmentCount, int32(1); got != want {
+			t.Errorf("SegmentCount = %d; want %d", got, want)
+		}
+
+		if got, want := len(resData.Substitutions), 2; got != want {
+			t.Errorf("number of substitutions = %d; want %d", got, want)
+		}
+
+		// check database, the sent content is stored along with the original one
+		var doc struct {
+			Content         string `bson:"content"`
+			OriginalContent string `bson:"originalContent"`
+		}
+
+		err = dbMongo.FindOne(context.TODO(), bson.M{"from": fromPhoneNumber}).Decode(&doc)
+		if err != nil {
+			t.Errorf("failed to find the message in db %v; want success", err)
+			return
+		}
+
+		if got, want := doc.Content, strings.Repeat("a", 96)+`"quoted"`; got != want {
+			t.Errorf("content = %q; want %q", got, want)
+		}
+
+		if got, want := doc.OriginalContent, content; got != want {
+			t.Errorf("original content = %q; want %q", got, want)
+		}
+	})
+
+	t.Run("TestScheduled", func(t *testing.T) {
+		fromPhoneNumber := stubs.GetPhoneNumber()
+		toPhoneNumber := stubs.GetPhoneNumber()
+
+		for _, pNumber := range []string{fromPhoneNumber, toPhoneNumber} {
+			_, err := dbMySQL.Exec("INSERT INTO phonebook (user_id, phone_number) VALUES (?, ?)",
+				stubs.GetUserID(), pNumber)
+			if err != nil {
+				t.Errorf("couldn't insert phone number: %v", err)
+				return
+			}
+		}
+
+		schedule := func(idempotencyKey string, sendAt time.Time) (*sms.SendOneResponse, error) {
+			ts, err := ptypes.TimestampProto(sendAt)
+			if err != nil {
+				return nil, err
+			}
+
+			postData, err := CreateRequest(&sms.SendOneRequest{
+				Sms: &sms.SMS{
+					IdempotencyKey:  idempotencyKey,
+					FromPhoneNumber: fromPhoneNumber,
+					ToPhoneNumber:   toPhoneNumber,
+					Content:         "content of the sms",
+					SendAt:          ts,
+				},
+			})
+
+			if err != nil {
+				return nil, err
+			}
+
+			res, err := http.Post(uri+"send/one", "application/json", postData)
+			if err != nil {
+				return nil, err
+			}
+			defer res.Body.Close()
+
+			var resData sms.SendOneResponse
+			if err := ReadRespone(res.Body, &resData); err != nil {
+				return nil, err
+			}
+
+			return &resData, nil
+		}
+
+		// 1) test scheduling a message in a few seconds
+		idempotencyKey := stubs.GetIdempotencyKey()
+		soon, err := schedule(idempotencyKey, time.Now().Add(3*time.Second))
+		if err != nil {
+			t.Errorf("scheduling sms failed with %v", err)
+			return
+		}
+
+		if got, want := soon.Status, sms.Status_SCHEDULED; got != want {
+			t.Errorf("Status = %s; want %s", got, want)
+		}
+
+		// 2) test scheduling the same message again
+		again, err := schedule(idempotencyKey, time.Now().Add(3*time.Second))
+		if err != nil {
+			t.Errorf("scheduling sms failed with %v", err)
+			return
+		}
+
+		if got, want := again.MessageId, soon.MessageId; got != want {
+			t.Errorf("MessageId = %s; want %s", got, want)
+		}
+
+		// 3) test scheduling a message in an hour, and listing both
+		later, err := schedule(stubs.GetIdempotencyKey(), time.Now().Add(time.Hour))
+		if err != nil {
+			t.Errorf("scheduling sms failed with %v", err)
+			return
+		}
+
+		res, err := http.Get(uri + "scheduled/" + fromPhoneNumber)
+		if err != nil {
+			t.Errorf("http.Get failed with %v", err)
+			return
+		}
+		defer res.Body.Close()
+
+		var list sms.ListScheduledResponse
+		if err := ReadRespone(res.Body, &list); err != nil {
+			t.Errorf("reading res.Body failed with %v", err)
+			return
+		}
+
+		if got, want := len(list.Messages), 2; got != want {
+			t.Errorf("number of scheduled messages = %d; want %d", got, want)
+		} else if got, want := list.Messages[0].MessageId, soon.MessageId; got != want {
+			t.Errorf("first scheduled message = %s; want %s", got, want)
+		}
+
+		// 4) test canceling the message in an hour, twice
+		for _, want := range []int{int(codes.OK), int(codes.FailedPrecondition)} {
+			req, _ := http.NewRequest(http.MethodDelete, uri+"scheduled/"+later.MessageId, nil)
+			res, err := http.DefaultClient.Do(req)
+			if err != nil {
+				t.Errorf("http.Do failed with %v", err)
+				return
+			}
+			defer res.Body.Close()
+
+			if want == int(codes.OK) {
+				var canceled sms.CancelScheduledResponse
+				if err := ReadRespone(res.Body, &canceled); err != nil {
+					t.Errorf("reading res.Body failed with %v", err)
+				} else if got, want := canceled.Status, sms.Status_CANCELED; got != want {
+					t.Errorf("Status = %s; want %s", got, want)
+				}
+
+				continue
+			}
+
+			var errorMsg ErrorBody
+			if err := ReadError(res.Body, &errorMsg); err != nil {
+				t.Errorf("failed to read error body %v; want success", err)
+			} else if got := errorMsg.Code; got != want {
+				t.Errorf("msg.Code = %d; want %d", got, want)
+			}
+		}
+
+		// 5) test the message in a few seconds is sent once it is due
+		var resData sms.GetMessageStatusResponse
+		for i := 0; i < 20; i++ {
+			time.Sleep(500 * time.Millisecond)
+
+			res, err := http.Get(uri + "status/" + soon.MessageId)
+			if err != nil {
+				t.Errorf("http.Get failed with %v", err)
+				return
+			}
+
+			err = ReadRespone(res.Body, &resData)
+			res.Body.Close()
+			if err != nil {
+				t.Errorf("reading res.Body failed with %v", err)
+				return
+			}
+
+			if resData.Status != sms.Status_SCHEDULED {
+				break
+			}
+		}
+
+		if got, want := resData.Status, sms.Status_DELIVERED; got != want {
+			t.Errorf("Status = %s; want %s", got, want)
+		}
+
+		// 6) test a message of SendMany in a few seconds is tracked once it is sent
+		ts, _ := ptypes.TimestampProto(time.Now().Add(2 * time.Second))
+		postData, err := CreateRequest(&sms.SendOneRequest{
+			Sms: &sms.SMS{
+				IdempotencyKey:  stubs.GetIdempotencyKey(),
+				FromPhoneNumber: fromPhoneNumber,
+				ToPhoneNumber:   toPhoneNumber,
+				Content:         "content of the sms",
+				SendAt:          ts,
+			},
+		})
+
+		if err != nil {
+			t.Fatalf("failed to write request body %v; want success", err)
+			return
+		}
+
+		res, err = http.Post(uri+"send/many", "application/json", postData)
+		if err != nil {
+			t.Errorf("http.Post failed with %v", err)
+			return
+		}
+		defer res.Body.Close()
+
+		var many sms.SendManyResponse
+		if err := ReadRespone(res.Body, &many); err != nil {
+			t.Errorf("reading res.Body failed with %v", err)
+			return
+		}
+
+		var tracking sms.GetTrackingResponse
+		for i := 0; i < 20; i++ {
+			time.Sleep(500 * time.Millisecond)
+
+			res, err := http.Get(uri + "tracking/" + many.TrackingId)
+			if err != nil {
+				t.Errorf("http.Get failed with %v", err)
+				return
+			}
+
+			tracking = sms.GetTrackingResponse{}
+			err = ReadRespone(res.Body, &tracking)
+			res.Body.Close()
+			if err != nil {
+				t.Errorf("reading res.Body failed with %v", err)
+				return
+			}
+
+			if tracking.Sent == 1 {
+				break
+			}
+		}
+
+		if len(tracking.Messages) != 1 {
+			t.Errorf("number of tracked messages = %d; want 1", len(tracking.Messages))
+		} else if got, want := tracking.Messages[0].Status, sms.Status_DELIVERED; got != want {
+			t.Errorf("tracked Status = %s; want %s", got, want)
+		}
+	})
+
+	t.Run("TestTemplates", func(t *testing.T) {
+		fromPhoneNumber := stubs.GetPhoneNumber()
+		toPhoneNumber := stubs.GetPhoneNumber()
+		userID := stubs.GetUserID()
+
+		for _, pNumber := range []string{fromPhoneNumber, toPhoneNumber} {
+			_, err := dbMySQL.Exec("INSERT INTO phonebook (user_id, phone_number) VALUES (?, ?)",
+				userID, pNumber)
+			if err != nil {
+				t.Errorf("couldn't insert phone number: %v", err)
+				return
+			}
+		}
+
+		createTemplate := &sms.CreateTemplateRequest{
+			UserId:  int32(userID),
+			Name:    "order-" + stubs.GetIdempotencyKey(),
+			Content: "Hi {{name}}, your order of {{count}} items ships on {{date}}",
+			Placeholders: []*sms.Placeholder{
+				{Name: "name", Type: sms.Placeholder_STRING},
+				{Name: "count", Type: sms.Placeholder_NUMBER},
+				{Name: "date", Type: sms.Placeholder_DATE},
+			},
+		}
+
+		// 1) test creating a template
+		postData, err := CreateRequest(createTemplate)
+		if err != nil {
+			t.Fatalf("failed to write request body %v; want success", err)
+			return
+		}
+
+		res, err := http.Post(uri+"templates", "application/json", postData)
+		if err != nil {
+			t.Errorf("http.Post failed with %v", err)
+			return
+		}
+		defer res.Body.Close()
+
+		var created sms.CreateTemplateResponse
+		if err := ReadRespone(res.Body, &created); err != nil {
+			t.Errorf("reading res.Body failed with %v", err)
+			return
+		}
+
+		templateID := created.Template.GetTemplateId()
+
+		// 2) test creating a template with the same name, or an undeclared placeholder
+		undeclared := *createTemplate
+		undeclared.Name, undeclared.Placeholders = "undeclared-"+stubs.GetIdempotencyKey(), nil
+
+		for _, test := range []struct {
+			req  *sms.CreateTemplateRequest
+			code codes.Code
+		}{
+			{createTemplate, codes.AlreadyExists},
+			{&undeclared, codes.InvalidArgument},
+		} {
+			postData, err := CreateRequest(test.req)
+			if err != nil {
+				t.Fatalf("failed to write request body %v; want success", err)
+				return
+			}
+
+			res, err := http.Post(uri+"templates", "application/json", postData)
+			if err != nil {
+				t.Errorf("http.Post failed with %v", err)
+				return
+			}
+			defer res.Body.Close()
+
+			var errorMsg ErrorBody
+			if err := ReadError(res.Body, &errorMsg); err != nil {
+				t.Errorf("failed to read error body %v; want success", err)
+			} else if got, want := errorMsg.Code, int(test.code); got != want {
+				t.Errorf("msg.Code = %d; want %d", got, want)
+			}
+		}
+
+		// the same name is taken by another account only
+		otherAccount := *createTemplate
+		otherAccount.UserId = createTemplate.UserId%1000 + 1
+
+		postData, err = CreateRequest(&otherAccount)
+		if err != nil {
+			t.Fatalf("failed to write request body %v; want success", err)
+			return
+		}
+
+		res, err = http.Post(uri+"templates", "application/json", postData)
+		if err != nil {
+			t.Errorf("http.Post failed with %v", err)
+			return
+		}
+		defer res.Body.Close()
+
+		var otherCreated sms.CreateTemplateResponse
+		if err := ReadRespone(res.Body, &otherCreated); err != nil {
+			t.Errorf("reading res.Body failed with %v", err)
+		} else if got, want := otherCreated.Template.GetName(), createTemplate.Name; got != want {
+			t.Errorf("template name = %q; want %q", got, want)
+		}
+
+		send := func(idempotencyKey string, variables map[string]string) (*http.Response, error) {
+			postData, err := CreateRequest(&sms.SendOneRequest{
+				Sms: &sms.SMS{
+					IdempotencyKey:  idempotencyKey,
+					FromPhoneNumber: fromPhoneNumber,
+					ToPhoneNumber:   toPhoneNumber,
+					TemplateId:      templateID,
+					Variables:       variables,
+				},
+			})
+
+			if err != nil {
+				return nil, err
+			}
+
+			return http.Post(uri+"send/one", "application/json", postData)
+		}
+
+		// 3) test sending an sms from the template
+		idempotencyKey := stubs.GetIdempotencyKey()
+		res, err = send(idempotencyKey, map[string]string{"name": "Omar", "count": "3", "date": "2019-10-01"})
+		if err != nil {
+			t.Errorf("http.Post failed with %v", err)
+			return
+		}
+		defer res.Body.Close()
+
+		var sent sms.SendOneResponse
+		if err := ReadRespone(res.Body, &sent); err != nil {
+			t.Errorf("reading res.Body failed with %v", err)
+			return
+		}
+
+		var doc struct {
+			Content string `bson:"content"`
+		}
+
+		err = dbMongo.FindOne(context.TODO(), bson.M{"idempotencyKey": idempotencyKey}).Decode(&doc)
+		if err != nil {
+			t.Errorf("failed to find the message in db %v; want success", err)
+		} else if got, want := doc.Content, "Hi Omar, your order of 3 items ships on 2019-10-01"; got != want {
+			t.Errorf("content = %q; want %q", got, want)
+		}
+
+		// 4) test sending with a missing variable, and a variable of the wrong type
+		for _, variables := range []map[string]string{
+			{"name": "Omar", "count": "3"},
+			{"name": "Omar", "count": "three", "date": "2019-10-01"},
+			{"name": "Omar", "count": "NaN", "date": "2019-10-01"},
+			{"name": "Omar", "count": "Inf", "date": "2019-10-01"},
+		} {
+			idempotencyKey := stubs.GetIdempotencyKey()
+			res, err := send(idempotencyKey, variables)
+			if err != nil {
+				t.Errorf("http.Post failed with %v", err)
+				return
+			}
+			defer res.Body.Close()
+
+			var errorMsg ErrorBody
+			if err := ReadError(res.Body, &errorMsg); err != nil {
+				t.Errorf("failed to read error body %v; want success", err)
+			} else if got, want := errorMsg.Code, int(codes.InvalidArgument); got != want {
+				t.Errorf("msg.Code = %d; want %d", got, want)
+			}
+
+			// nothing is stored
+			cunt, err := dbMongo.CountDocuments(context.TODO(), bson.M{"idempotencyKey": idempotencyKey})
+			if err != nil {
+				t.Errorf("failed to count documents %v; want success", err)
+			} else if cunt != 0 {
+				t.Errorf("number of documents = %d; want 0", cunt)
+			}
+		}
+
+		// 5) test deleting the template
+		req, _ := http.NewRequest(http.MethodDelete, uri+"templates/"+templateID, nil)
+		res, err = http.DefaultClient.Do(req)
+		if err != nil {
+			t.Errorf("http.Do failed with %v", err)
+			return
+		}
+		defer res.Body.Close()
+
+		res, err = http.Get(uri + "templates/" + templateID)
+		if err != nil {
+			t.Errorf("http.Get failed with %v", err)
+			return
+		}
+		defer res.Body.Close()
+
+		var errorMsg ErrorBody
+		if err := ReadError(res.Body, &errorMsg); err != nil {
+			t.Errorf("failed to read error body %v; want success", err)
+		} else if got, want := errorMsg.Code, int(codes.NotFound); got != want {
+			t.Errorf("msg.Code = %d; want %d", got, want)
+		}
+	})
+
+	t.Run("TestConversations", func(t *testing.T) {
+		DropMongoDB()
+
+		phoneNumber := stubs.GetPhoneNumber()
+		otherPhoneNumbers := []string{stubs.GetPhoneNumber(), stubs.GetPhoneNumber()}
+
+		for _, pNumber := range append(otherPhoneNumbers, phoneNumber) {
+			_, err := dbMySQL.Exec("INSERT INTO phonebook (user_id, phone_number) VALUES (?, ?)",
+				stubs.GetUserID(), pNumber)
+			if err != nil {
+				t.Errorf("couldn't insert phone number: %v", err)
+				return
+			}
+		}
+
+		// a conversation of two messages (one in each direction) with the first number,
+		// then one message to the second number, and so it is the most recent conversation
+		smss := []*sms.SMS{
+			{FromPhoneNumber: phoneNumber, ToPhoneNumber: otherPhoneNumbers[0], Content: "hi"},
+			{FromPhoneNumber: otherPhoneNumbers[0], ToPhoneNumber: phoneNumber, Content: "hello"},
+			{FromPhoneNumber: phoneNumber, ToPhoneNumber: otherPhoneNumbers[1], Content: "hey"},
+		}
+
+		for _, s := range smss {
+			s.IdempotencyKey = stubs.GetIdempotencyKey()
+
+			postData, err := CreateRequest(&sms.SendOneRequest{Sms: s})
+			if err != nil {
+				t.Fatalf("failed to write request body %v; want success", err)
+				return
+			}
+
+			res, err := http.Post(uri+"send/one", "application/json", postData)
+			if err != nil {
+				t.Errorf("http.Post failed with %v", err)
+				return
+			}
+			res.Body.Close()
+
+			// messages are ordered by their creation time, which has a millisecond precision
+			time.Sleep(10 * time.Millisecond)
+		}
+
+		// 1) list the conversations
+		res, err := http.Get(uri + "conversations/" + phoneNumber)
+		if err != nil {
+			t.Errorf("http.Get failed with %v", err)
+			return
+		}
+		defer res.Body.Close()
+
+		var conversations sms.ListConversationsResponse
+		err = ReadRespone(res.Body, &conversations)
+		if err != nil {
+			t.Errorf("reading res.Body failed with %v", err)
+			return
+		}
+
+		if got, want := len(conversations.Conversations), 2; got != want {
+			t.Errorf("number of conversations = %d; want %d", got, want)
+			return
+		}
+
+		if got, want := conversations.Conversations[0].PhoneNumber, otherPhoneNumbers[1]; got != want {
+			t.Errorf("PhoneNumber of the first conversation = %s; want %s", got, want)
+		}
+
+		if got, want := conversations.Conversations[1].LastMessage.Content, "hello"; got != want {
+			t.Errorf("Content of the last message = %s; want %s", got, want)
+		}
+
+		// 2) get the thread page by page, one message per page
+		contents := []string{}
+		cursor := ""
+		for i := 0; i < 3; i++ {
+			res, err := http.Get(uri + "threads/" + phoneNumber + "/" + otherPhoneNumbers[0] +
+				"?page_size=1&cursor=" + cursor)
+			if err != nil {
+				t.Errorf("http.Get failed with %v", err)
+				return
+			}
+			defer res.Body.Close()
+
+			var thread sms.GetThreadResponse
+			err = ReadRespone(res.Body, &thread)
+			if err != nil {
+				t.Errorf("reading res.Body failed with %v", err)
+				return
+			}
+
+			for _, m := range thread.Messages {
+				contents = append(contents, m.Content)
+			}
+
+			cursor = thread.NextCursor
+			if cursor == "" {
+				break
+			}
+		}
+
+		if got, want := strings.Join(contents, ","), "hello,hi"; got != want {
+			t.Errorf("messages of the thread = %s; want %s", got, want)
+		}
+	})
+
+	t.Run("TestSubscribe", func(t *testing.T) {
+		fromPhoneNumber := stubs.GetPhoneNumber()
+		toPhoneNumber := stubs.GetPhoneNumber()
+
+		for _, pNumber := range []string{fromPhoneNumber, toPhoneNumber} {
+			_, err := dbMySQL.Exec("INSERT INTO phonebook (user_id, phone_number) VALUES (?, ?)",
+				stubs.GetUserID(), pNumber)
+			if err != nil {
+				t.Errorf("couldn't insert phone number: %v", err)
+				return
+			}
+		}
+
+		// Subscribe is only reachable over gRPC, the clients of the gateway use the live endpoints
+		config, err := config.Load()
+		if err != nil {
+			t.Fatalf("config.Load failed with %v", err)
+		}
+
+		conn, err := grpc.Dial("sms-service:"+config("GRPC_SERVER_PORT"), grpc.WithInsecure())
+		if err != nil {
+			t.Fatalf("grpc.Dial failed with %v", err)
+		}
+		defer conn.Close()
+
+		client := sms.NewSMSServiceClient(conn)
+		ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+		defer cancel()
+
+		// 1) open the stream and wait for the first event
+		type result struct {
+			res *sms.SubscribeResponse
+			err error
+		}
+		resChan := make(chan result, 1)
+
+		stream, err := client.Subscribe(ctx, &sms.SubscribeRequest{PhoneNumber: toPhoneNumber})
+		if err != nil {
+			t.Errorf("Subscribe failed with %v", err)
+			return
+		}
+
+		go func() {
+			res, err := stream.Recv()
+			resChan <- result{res, err}
+		}()
+
+		// give the stream some time to subscribe, then send a message
+		time.Sleep(500 * time.Millisecond)
+
+		if _, err := SendSMS(uri, fromPhoneNumber, toPhoneNumber, "first"); err != nil {
+			t.Errorf("sending sms failed with %v", err)
+			return
+		}
+
+		r := <-resChan
+		if r.err != nil {
+			t.Errorf("reading the stream failed with %v", r.err)
+			return
+		}
+
+		if got, want := r.res.Type, sms.SubscribeResponse_MESSAGE; got != want {
+			t.Errorf("Type = %s; want %s", got, want)
+		}
+
+		if got, want := r.res.Message.GetContent(), "first"; got != want {
+			t.Errorf("Content = %s; want %s", got, want)
+		}
+
+		// 2) send while disconnected, then resume from the cursor of the last received event
+		for _, content := range []string{"second", "third"} {
+			if _, err := SendSMS(uri, fromPhoneNumber, toPhoneNumber, content); err != nil {
+				t.Errorf("sending sms failed with %v", err)
+				return
+			}
+		}
+
+		stream, err = client.Subscribe(ctx, &sms.SubscribeRequest{PhoneNumber: toPhoneNumber, Cursor: r.res.Cursor})
+		if err != nil {
+			t.Errorf("Subscribe failed with %v", err)
+			return
+		}
+
+		for _, want := range []string{"second", "third"} {
+			resData, err := stream.Recv()
+			if err != nil {
+				t.Errorf("reading the stream failed with %v", err)
+				return
+			}
+
+			if got := resData.Message.GetContent(); got != want {
+				t.Errorf("Content = %s; want %s", got, want)
+			}
+		}
+
+		// 3) test it isn't published by the gateway, where there is no authentication
+		res, err := http.Get(uri + "subscribe/" + toPhoneNumber)
+		if err != nil {
+			t.Errorf("http.Get failed with %v", err)
+			return
+		}
+		res.Body.Close()
+
+		if got, want := res.StatusCode, http.StatusNotFound; got != want {
+			t.Errorf("status code = %d; want %d", got, want)
+		}
+	})
+}
+
+// SendSMS sends an sms with a new idempotency key through the gateway
+func SendSMS(uri, fromPhoneNumber, toPhoneNumber, content string) (*sms.SendOneResponse, error) {
+	postData, err := CreateRequest(&sms.SendOneRequest{
+		Sms: &sms.SMS{
+			IdempotencyKey:  stubs.GetIdempotencyKey(),
+			FromPhoneNumber: fromPhoneNumber,
+			ToPhoneNumber:   toPhoneNumber,
+			Content:         content,
+		},
+	})
+
+	if err != nil {
+		return nil, err
+	}
+
+	res, err := http.Post(uri+"send/one", "application/json", postData)
+	if err != nil {
+		return nil, err
+	}
+	defer res.Body.Close()
+
+	var resData sms.SendOneResponse
+	if err := ReadRespone(res.Body, &resData); err != nil {
+		return nil, err
+	}
+
+	return &resData, nil
 }
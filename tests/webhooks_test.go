@@ -0,0 +1,231 @@
+package tests
+
+import (
+	"context"
+	"io/ioutil"
+	"net"
+	"net/http"
+	"strconv"
+	"sync"
+	"testing"
+	"time"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"github.com/OmarElGabry/go-textnow/internal/pkg/config"
+	"github.com/OmarElGabry/go-textnow/internal/pkg/signature"
+	"github.com/OmarElGabry/go-textnow/internal/sms"
+	"github.com/OmarElGabry/go-textnow/tests/stubs"
+	"github.com/golang/protobuf/jsonpb"
+)
+
+// webhookServer stands in for the endpoint of a customer.
+// It responds with the given status codes in order, then with 200.
+type webhookServer struct {
+	mu        sync.Mutex
+	secret    []byte
+	responses []int
+	received  chan *sms.WebhookPayload
+	errs      chan error
+}
+
+func (ws *webhookServer) ServeHTTP(w http.ResponseWriter, req *http.Request) {
+	body, err := ioutil.ReadAll(req.Body)
+	if err != nil {
+		ws.errs <- err
+		return
+	}
+
+	ws.mu.Lock()
+	code := http.StatusOK
+	if len(ws.responses) > 0 {
+		code, ws.responses = ws.responses[0], ws.responses[1:]
+	}
+
+	err = signature.Verify(ws.secret, req.Header.Get(signature.Header), body, time.Minute)
+	ws.mu.Unlock()
+
+	if err != nil {
+		ws.errs <- err
+		w.WriteHeader(http.StatusUnauthorized)
+		return
+	}
+
+	var payload sms.WebhookPayload
+	if err := jsonpb.UnmarshalString(string(body), &payload); err != nil {
+		ws.errs <- err
+		return
+	}
+
+	w.WriteHeader(code)
+	ws.received <- &payload
+}
+
+// respond sets the status codes to respond with
+func (ws *webhookServer) respond(codes ...int) {
+	ws.mu.Lock()
+	ws.responses = codes
+	ws.mu.Unlock()
+}
+
+// next waits for the next request, and returns its payload
+func (ws *webhookServer) next(t *testing.T) *sms.WebhookPayload {
+	select {
+	case payload := <-ws.received:
+		return payload
+	case err := <-ws.errs:
+		t.Errorf("webhook request is invalid: %v", err)
+	case <-time.After(10 * time.Second):
+		t.Errorf("no webhook request; want one")
+	}
+
+	return nil
+}
+
+func TestWebhooks(t *testing.T) {
+	uri := "http://gateway-service:8080/sms/"
+
+	// the local server is reached by SMS service at the hostname of the tests container
+	lis, err := net.Listen("tcp", ":0")
+	if err != nil {
+		t.Fatalf("couldn't listen: %v", err)
+	}
+
+	ws := &webhookServer{received: make(chan *sms.WebhookPayload, 10), errs: make(chan error, 10)}
+	go http.Serve(lis, ws)
+	defer lis.Close()
+
+	userID := stubs.GetUserID()
+	fromPhoneNumber := stubs.GetPhoneNumber()
+	toPhoneNumber := stubs.GetPhoneNumber()
+
+	for pNumber, id := range map[string]int{fromPhoneNumber: userID, toPhoneNumber: stubs.GetUserID()} {
+		_, err := dbMySQL.Exec("INSERT INTO phonebook (user_id, phone_number) VALUES (?, ?)", id, pNumber)
+		if err != nil {
+			t.Fatalf("couldn't insert phone number: %v", err)
+		}
+	}
+
+	// the webhook methods are only reachable over gRPC
+	config, err := config.Load()
+	if err != nil {
+		t.Fatalf("config.Load failed with %v", err)
+	}
+
+	conn, err := grpc.Dial("sms-service:"+config("GRPC_SERVER_PORT"), grpc.WithInsecure())
+	if err != nil {
+		t.Fatalf("grpc.Dial failed with %v", err)
+	}
+	defer conn.Close()
+
+	client := sms.NewSMSServiceClient(conn)
+	ctx, cancel := context.WithTimeout(context.Background(), time.Minute)
+	defer cancel()
+
+	// the account of the sender subscribes to the status changes,
+	// the tests container is reachable since its host is allowed (see WEBHOOK_ALLOWED_HOSTS)
+	created, err := client.CreateWebhook(ctx, &sms.CreateWebhookRequest{
+		UserId:     int32(userID),
+		Url:        "http://tests:" + strconv.Itoa(lis.Addr().(*net.TCPAddr).Port) + "/",
+		EventTypes: []sms.SubscribeResponse_Type{sms.SubscribeResponse_STATUS},
+	})
+
+	if err != nil {
+		t.Fatalf("CreateWebhook failed with %v; want success", err)
+	}
+
+	ws.secret = []byte(created.Webhook.GetSecret())
+	webhookID := created.Webhook.GetWebhookId()
+
+	t.Run("TestRetry", func(t *testing.T) {
+		// fails once, then succeeds on the retry
+		ws.respond(http.StatusServiceUnavailable)
+
+		sent, err := SendSMS(uri, fromPhoneNumber, toPhoneNumber, "content of the sms")
+		if err != nil {
+			t.Errorf("sending sms failed with %v", err)
+			return
+		}
+
+		first, retried := ws.next(t), ws.next(t)
+		if first == nil || retried == nil {
+			return
+		}
+
+		if got, want := first.Message.GetMessageId(), sent.MessageId; got != want {
+			t.Errorf("MessageId = %s; want %s", got, want)
+		}
+
+		if got, want := first.Type, sms.SubscribeResponse_STATUS; got != want {
+			t.Errorf("Type = %s; want %s", got, want)
+		}
+
+		if got, want := retried.DeliveryId, first.DeliveryId; got != want {
+			t.Errorf("DeliveryId of the retry = %s; want %s", got, want)
+		}
+	})
+
+	t.Run("TestReplay", func(t *testing.T) {
+		// a client error isn't retried, and so it is failed right away
+		ws.respond(http.StatusGone)
+
+		if _, err := SendSMS(uri, fromPhoneNumber, toPhoneNumber, "content of the sms"); err != nil {
+			t.Errorf("sending sms failed with %v", err)
+			return
+		}
+
+		failed := ws.next(t)
+		if failed == nil {
+			return
+		}
+
+		// wait for the failure to be recorded, then replay it
+		var count int32
+		for i := 0; i < 10 && count == 0; i++ {
+			time.Sleep(200 * time.Millisecond)
+
+			res, err := client.ReplayDeliveries(ctx, &sms.ReplayDeliveriesRequest{WebhookId: webhookID})
+			if err != nil {
+				t.Errorf("ReplayDeliveries failed with %v", err)
+				return
+			}
+
+			count = res.GetReplayed()
+		}
+
+		if got, want := count, int32(1); got != want {
+			t.Errorf("Replayed = %d; want %d", got, want)
+			return
+		}
+
+		if replayed := ws.next(t); replayed != nil && replayed.DeliveryId != failed.DeliveryId {
+			t.Errorf("DeliveryId of the replay = %s; want %s", replayed.DeliveryId, failed.DeliveryId)
+		}
+	})
+
+	t.Run("TestPrivateURL", func(t *testing.T) {
+		// loopback, and the metadata of a cloud instance (link-local)
+		for _, url := range []string{"http://127.0.0.1:8080/", "http://localhost/", "http://169.254.169.254/latest/meta-data/"} {
+			_, err := client.CreateWebhook(ctx, &sms.CreateWebhookRequest{
+				UserId:     int32(userID),
+				Url:        url,
+				EventTypes: []sms.SubscribeResponse_Type{sms.SubscribeResponse_STATUS},
+			})
+
+			if got, want := status.Code(err), codes.InvalidArgument; got != want {
+				t.Errorf("CreateWebhook(%s) code = %s; want %s", url, got, want)
+			}
+		}
+	})
+
+	t.Run("TestDeleteWebhook", func(t *testing.T) {
+		for _, want := range []codes.Code{codes.OK, codes.NotFound} {
+			_, err := client.DeleteWebhook(ctx, &sms.DeleteWebhookRequest{WebhookId: webhookID})
+			if got := status.Code(err); got != want {
+				t.Errorf("DeleteWebhook code = %s; want %s", got, want)
+			}
+		}
+	})
+}